@@ -26,6 +26,12 @@ type ArtifactNodeSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="nodeName is immutable"
 	NodeName string `json:"nodeName"`
+	// AllowedGeneration is the generation of the parent artifact the node is allowed to apply. It is set by
+	// the instance operator, which holds nodes back while the parent rolls out in batches. When unset, the
+	// node applies the latest generation.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	AllowedGeneration int64 `json:"allowedGeneration,omitempty"`
 }
 
 // ArtifactNodeStatus defines the per-node observed state of an artifact.
@@ -43,6 +49,18 @@ type ArtifactNodeStatus struct {
 	// +listType=map
 	// +listMapKey=medium
 	InstalledArtifacts []InstalledArtifact `json:"installedArtifacts,omitempty"`
+	// EffectiveConfig is the configuration Falco runs with on this node once the files of every
+	// Config are merged. Populated only for Config artifacts.
+	// +optional
+	EffectiveConfig *EffectiveConfig `json:"effectiveConfig,omitempty"`
+	// RulesIndex is the index of the rules files installed on this node. Populated only for Rulesfile
+	// artifacts.
+	// +optional
+	RulesIndex *RulesIndex `json:"rulesIndex,omitempty"`
+	// RevisionDigest is the manifest digest pulled on this node for the OCI artifact of the current
+	// revision. Populated only for Plugin and Rulesfile artifacts sourced from an OCI artifact.
+	// +optional
+	RevisionDigest *RevisionDigest `json:"revisionDigest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
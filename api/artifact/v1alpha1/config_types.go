@@ -24,6 +24,7 @@ import (
 )
 
 // ConfigSpec defines the desired state of Config.
+// +kubebuilder:validation:XValidation:rule="!has(self.falcoRef) || !has(self.instanceSelector)",message="falcoRef and instanceSelector are mutually exclusive"
 type ConfigSpec struct {
 	// Config is the configuration for Falco deployment, specified as a structured object.
 	Config *apiextensionsv1.JSON `json:"config,omitempty"`
@@ -37,6 +38,31 @@ type ConfigSpec struct {
 	Priority int32 `json:"priority,omitempty"`
 	// Selector is used to select the nodes where the config should be applied.
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// FalcoRef restricts the config to the Falco instance with the given name in its namespace.
+	// When neither falcoRef nor instanceSelector is set, every Falco instance of the namespace loads it.
+	// +optional
+	FalcoRef *commonv1alpha1.FalcoRef `json:"falcoRef,omitempty"`
+	// InstanceSelector restricts the config to the Falco instances of its namespace whose labels match.
+	// +optional
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+	// Suspend stops the artifact operator from writing or removing the files of the config on the nodes.
+	// The files already written are kept and the status keeps being reported. Deleting the config still
+	// removes its files.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Rollout rolls a new generation of the config out over the nodes in batches instead of on every node at
+	// once, halting when a node fails to program it. Nodes joining during a rollout are admitted in batches too.
+	// +optional
+	Rollout *commonv1alpha1.Rollout `json:"rollout,omitempty"`
+	// RevisionHistoryLimit is the number of previous revisions of the sources of the config kept for rollbacks.
+	// Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// RollbackTo makes the artifact operator install the sources recorded in a previous revision of the
+	// config instead of the current ones. The instance operator clears it when the sources change again.
+	// +optional
+	RollbackTo *commonv1alpha1.RollbackConfig `json:"rollbackTo,omitempty"`
 }
 
 // ConfigStatus defines the observed state of Config.
@@ -48,6 +74,9 @@ type ConfigStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// NodeSummary summarizes the state of the Config on the nodes it is assigned to.
+	// +optional
+	NodeSummary *NodeSummary `json:"nodeSummary,omitempty"`
 	// ObservedGeneration is the .metadata.generation that the instance operator has fully
 	// processed (node objects synced, status patched). Per-node artifact operators defer
 	// reconciliation until this equals metadata.generation.
@@ -60,6 +89,9 @@ type ConfigStatus struct {
 // +kubebuilder:resource:path=configs,categories=artifacts
 // +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority",description="The priority of the config"
 // +kubebuilder:printcolumn:name="Programmed",type="string",JSONPath=".status.conditions[?(@.type == 'Programmed')].status"
+// +kubebuilder:printcolumn:name="Nodes",type="integer",JSONPath=".status.nodeSummary.total",description="The number of nodes the config is assigned to"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.nodeSummary.failed",description="The number of nodes failing to program the config"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.nodeSummary.pending",description="The number of nodes not programmed yet"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Config is the Schema for the configs API.
@@ -16,6 +16,10 @@
 
 package v1alpha1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // InstalledArtifactConfig tracks a generated configuration file derived from an installed artifact.
 // Currently used for Plugin artifacts to record the plugins-config-inline.yaml entry that Falco
 // needs to discover and load the plugin. Binary and config are one lifecycle unit: the config is
@@ -55,3 +59,92 @@ type InstalledArtifact struct {
 	// +optional
 	Config *InstalledArtifactConfig `json:"config,omitempty"`
 }
+
+// EffectiveConfig references the effective Falco configuration of a node: the base falco.yaml with
+// every file of the configuration directory merged on top, as Falco loads them.
+type EffectiveConfig struct {
+	// ConfigMapName is the name of the ConfigMap the instance operator publishes Config and Provenance in,
+	// under the falco.yaml and provenance.yaml keys.
+	// +kubebuilder:validation:Required
+	ConfigMapName string `json:"configMapName"`
+	// Hash is the SHA-256 hex digest of the merged falco.yaml.
+	// +kubebuilder:validation:Required
+	Hash string `json:"hash"`
+	// Config is the merged falco.yaml.
+	// +optional
+	Config string `json:"config,omitempty"`
+	// Provenance lists, in YAML, the sources that set each top-level key of the merged falco.yaml.
+	// +optional
+	Provenance string `json:"provenance,omitempty"`
+	// AppliedKeys lists the top-level keys set by this Config that are in effect on the node.
+	// +optional
+	// +listType=set
+	AppliedKeys []string `json:"appliedKeys,omitempty"`
+	// OverriddenKeys lists the top-level keys set by this Config that a source loaded after it
+	// replaces on the node.
+	// +optional
+	// +listType=set
+	OverriddenKeys []string `json:"overriddenKeys,omitempty"`
+}
+
+// RulesIndex holds the index of the rules files installed on a node, in the order Falco loads them.
+type RulesIndex struct {
+	// ConfigMapName is the name of the ConfigMap the instance operator publishes Index in, under the
+	// index.yaml key.
+	// +kubebuilder:validation:Required
+	ConfigMapName string `json:"configMapName"`
+	// Index is the index of the rules files, in YAML.
+	// +optional
+	Index string `json:"index,omitempty"`
+}
+
+// RevisionDigest holds the manifest digest a node pulled for the OCI artifact of a revision. The instance
+// operator records it on the revision, so that a rollback to it pulls that digest rather than the tag.
+type RevisionDigest struct {
+	// Revision is the name of the ControllerRevision recording the sources the digest was pulled for.
+	// +kubebuilder:validation:Required
+	Revision string `json:"revision"`
+	// Digest is the manifest digest pulled for the OCI artifact of the revision.
+	// +kubebuilder:validation:Required
+	Digest string `json:"digest"`
+}
+
+// NodeSummary summarizes the per-node rollout of an artifact. It is computed by the instance operator
+// from the ArtifactNodes of the artifact, so that large clusters can be followed without listing them.
+type NodeSummary struct {
+	// Total is the number of nodes the artifact is assigned to.
+	Total int32 `json:"total"`
+	// Programmed is the number of nodes whose Programmed condition is True.
+	Programmed int32 `json:"programmed"`
+	// Failed is the number of nodes whose Programmed condition is False.
+	Failed int32 `json:"failed"`
+	// Pending is the number of nodes whose Programmed condition is Unknown or not reported yet.
+	Pending int32 `json:"pending"`
+	// Digests is the distribution of the installed content across the nodes, most widespread first.
+	// At most 10 entries are listed.
+	// +optional
+	// +listType=atomic
+	Digests []NodeDigestCount `json:"digests,omitempty"`
+	// OldestPending is the node pending for the longest time.
+	// +optional
+	OldestPending *PendingNode `json:"oldestPending,omitempty"`
+}
+
+// NodeDigestCount counts the nodes having the same content installed from a medium.
+type NodeDigestCount struct {
+	// Medium identifies the source: "oci", "inline", or "configmap".
+	Medium string `json:"medium"`
+	// Digest is the sha256 digest of the installed content.
+	Digest string `json:"digest"`
+	// Nodes is the number of nodes having this content installed.
+	Nodes int32 `json:"nodes"`
+}
+
+// PendingNode identifies a node that has not been programmed yet.
+type PendingNode struct {
+	// NodeName is the name of the node.
+	NodeName string `json:"nodeName"`
+	// Since is the last transition time of the node's Programmed condition, or the creation time of
+	// its ArtifactNode when the condition is not reported yet.
+	Since metav1.Time `json:"since"`
+}
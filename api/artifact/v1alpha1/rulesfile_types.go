@@ -24,6 +24,7 @@ import (
 )
 
 // RulesfileSpec defines the desired state of Rulesfile.
+// +kubebuilder:validation:XValidation:rule="!has(self.falcoRef) || !has(self.instanceSelector)",message="falcoRef and instanceSelector are mutually exclusive"
 type RulesfileSpec struct {
 	// OCIArtifact specifies the reference to an OCI artifact.
 	OCIArtifact *commonv1alpha1.OCIArtifact `json:"ociArtifact,omitempty"`
@@ -39,6 +40,31 @@ type RulesfileSpec struct {
 	Priority int32 `json:"priority,omitempty"`
 	// Selector is used to select the nodes where the rulesfile should be applied.
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// FalcoRef restricts the rulesfile to the Falco instance with the given name in its namespace.
+	// When neither falcoRef nor instanceSelector is set, every Falco instance of the namespace loads it.
+	// +optional
+	FalcoRef *commonv1alpha1.FalcoRef `json:"falcoRef,omitempty"`
+	// InstanceSelector restricts the rulesfile to the Falco instances of its namespace whose labels match.
+	// +optional
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+	// Suspend stops the artifact operator from writing or removing the files of the rulesfile on the nodes.
+	// The files already written are kept and the status keeps being reported. Deleting the rulesfile still
+	// removes its files.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Rollout rolls a new generation of the rulesfile out over the nodes in batches instead of on every node at
+	// once, halting when a node fails to program it. Nodes joining during a rollout are admitted in batches too.
+	// +optional
+	Rollout *commonv1alpha1.Rollout `json:"rollout,omitempty"`
+	// RevisionHistoryLimit is the number of previous revisions of the sources of the rulesfile kept for rollbacks.
+	// Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// RollbackTo makes the artifact operator install the sources recorded in a previous revision of the
+	// rulesfile instead of the current ones. The instance operator clears it when the sources change again.
+	// +optional
+	RollbackTo *commonv1alpha1.RollbackConfig `json:"rollbackTo,omitempty"`
 }
 
 // RulesfileStatus defines the observed state of Rulesfile.
@@ -50,6 +76,9 @@ type RulesfileStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// NodeSummary summarizes the state of the Rulesfile on the nodes it is assigned to.
+	// +optional
+	NodeSummary *NodeSummary `json:"nodeSummary,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -57,6 +86,9 @@ type RulesfileStatus struct {
 // +kubebuilder:resource:path=rulesfiles,categories=artifacts
 // +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority",description="The priority of the rulesfile"
 // +kubebuilder:printcolumn:name="Programmed",type="string",JSONPath=".status.conditions[?(@.type == 'Programmed')].status"
+// +kubebuilder:printcolumn:name="Nodes",type="integer",JSONPath=".status.nodeSummary.total",description="The number of nodes the rulesfile is assigned to"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.nodeSummary.failed",description="The number of nodes failing to program the rulesfile"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.nodeSummary.pending",description="The number of nodes not programmed yet"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Rulesfile is the Schema for the rulesfiles API.
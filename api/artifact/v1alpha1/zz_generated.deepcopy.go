@@ -120,6 +120,21 @@ func (in *ArtifactNodeStatus) DeepCopyInto(out *ArtifactNodeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(EffectiveConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RulesIndex != nil {
+		in, out := &in.RulesIndex, &out.RulesIndex
+		*out = new(RulesIndex)
+		**out = **in
+	}
+	if in.RevisionDigest != nil {
+		in, out := &in.RevisionDigest, &out.RevisionDigest
+		*out = new(RevisionDigest)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactNodeStatus.
@@ -209,6 +224,31 @@ func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FalcoRef != nil {
+		in, out := &in.FalcoRef, &out.FalcoRef
+		*out = new(commonv1alpha1.FalcoRef)
+		**out = **in
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(commonv1alpha1.Rollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(commonv1alpha1.RollbackConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSpec.
@@ -231,6 +271,11 @@ func (in *ConfigStatus) DeepCopyInto(out *ConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeSummary != nil {
+		in, out := &in.NodeSummary, &out.NodeSummary
+		*out = new(NodeSummary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigStatus.
@@ -243,6 +288,31 @@ func (in *ConfigStatus) DeepCopy() *ConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveConfig) DeepCopyInto(out *EffectiveConfig) {
+	*out = *in
+	if in.AppliedKeys != nil {
+		in, out := &in.AppliedKeys, &out.AppliedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OverriddenKeys != nil {
+		in, out := &in.OverriddenKeys, &out.OverriddenKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveConfig.
+func (in *EffectiveConfig) DeepCopy() *EffectiveConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstalledArtifact) DeepCopyInto(out *InstalledArtifact) {
 	*out = *in
@@ -278,6 +348,62 @@ func (in *InstalledArtifactConfig) DeepCopy() *InstalledArtifactConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDigestCount) DeepCopyInto(out *NodeDigestCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDigestCount.
+func (in *NodeDigestCount) DeepCopy() *NodeDigestCount {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDigestCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSummary) DeepCopyInto(out *NodeSummary) {
+	*out = *in
+	if in.Digests != nil {
+		in, out := &in.Digests, &out.Digests
+		*out = make([]NodeDigestCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.OldestPending != nil {
+		in, out := &in.OldestPending, &out.OldestPending
+		*out = new(PendingNode)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSummary.
+func (in *NodeSummary) DeepCopy() *NodeSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingNode) DeepCopyInto(out *PendingNode) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingNode.
+func (in *PendingNode) DeepCopy() *PendingNode {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Plugin) DeepCopyInto(out *Plugin) {
 	*out = *in
@@ -375,6 +501,31 @@ func (in *PluginSpec) DeepCopyInto(out *PluginSpec) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FalcoRef != nil {
+		in, out := &in.FalcoRef, &out.FalcoRef
+		*out = new(commonv1alpha1.FalcoRef)
+		**out = **in
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(commonv1alpha1.Rollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(commonv1alpha1.RollbackConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginSpec.
@@ -397,6 +548,11 @@ func (in *PluginStatus) DeepCopyInto(out *PluginStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeSummary != nil {
+		in, out := &in.NodeSummary, &out.NodeSummary
+		*out = new(NodeSummary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginStatus.
@@ -409,6 +565,36 @@ func (in *PluginStatus) DeepCopy() *PluginStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionDigest) DeepCopyInto(out *RevisionDigest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionDigest.
+func (in *RevisionDigest) DeepCopy() *RevisionDigest {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionDigest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesIndex) DeepCopyInto(out *RulesIndex) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesIndex.
+func (in *RulesIndex) DeepCopy() *RulesIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(RulesIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rulesfile) DeepCopyInto(out *Rulesfile) {
 	*out = *in
@@ -491,6 +677,31 @@ func (in *RulesfileSpec) DeepCopyInto(out *RulesfileSpec) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FalcoRef != nil {
+		in, out := &in.FalcoRef, &out.FalcoRef
+		*out = new(commonv1alpha1.FalcoRef)
+		**out = **in
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(commonv1alpha1.Rollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(commonv1alpha1.RollbackConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesfileSpec.
@@ -513,6 +724,11 @@ func (in *RulesfileStatus) DeepCopyInto(out *RulesfileStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeSummary != nil {
+		in, out := &in.NodeSummary, &out.NodeSummary
+		*out = new(NodeSummary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesfileStatus.
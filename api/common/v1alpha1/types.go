@@ -17,6 +17,13 @@
 // Package v1alpha1 contains common types used across apis.
 package v1alpha1
 
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
 // ConditionType represents a Falco condition type.
 // +kubebuilder:validation:MinLength=1
 type ConditionType string
@@ -47,6 +54,64 @@ const (
 	// - True: the artifact was programmed successfully.
 	// - False: the artifact could not be programmed.
 	ConditionProgrammed ConditionType = "Programmed"
+	// ConditionRestartRequired indicates whether a configuration change cannot be hot-reloaded
+	// by Falco and the affected Falco pods must be restarted.
+	// The possible status values for this condition type are:
+	// - True: a restart-requiring change was written and the Falco pod has not been restarted yet.
+	// The condition is removed once the Falco pod runs with the change.
+	ConditionRestartRequired ConditionType = "RestartRequired"
+	// ConditionRulesLoaded indicates whether every scraped Falco pod has rules loaded.
+	// The possible status values for this condition type are:
+	// - True: every scraped pod reports loaded rules.
+	// - False: one or more pods report no rules loaded.
+	// - Unknown: no pod could be scraped or Falco does not report the number of loaded rules.
+	ConditionRulesLoaded ConditionType = "RulesLoaded"
+	// ConditionEventDrops indicates whether Falco pods drop more kernel events than allowed.
+	// The possible status values for this condition type are:
+	// - True: one or more pods exceed the allowed drop rate.
+	// - False: every scraped pod is within the allowed drop rate.
+	// - Unknown: no pod could be scraped.
+	ConditionEventDrops ConditionType = "EventDrops"
+	// ConditionDegraded indicates whether one or more Falco pods are running but unhealthy.
+	// The possible status values for this condition type are:
+	// - True: one or more pods failed their health check, have no rules loaded or drop events.
+	// - False: every scraped pod is healthy.
+	// - Unknown: no pod could be scraped.
+	ConditionDegraded ConditionType = "Degraded"
+	// ConditionDrifted indicates whether fields of the generated resources were changed by
+	// other field managers than the operator.
+	// The possible status values for this condition type are:
+	// - True: one or more fields were changed by another field manager.
+	// - False: the generated resources match the desired state or were reverted to it.
+	ConditionDrifted ConditionType = "Drifted"
+	// ConditionSuspended indicates that reconciliation of the resource is suspended by its spec.suspend field.
+	// The condition is only present while the resource is suspended, with status True.
+	ConditionSuspended ConditionType = "Suspended"
+	// ConditionCollectorAvailable indicates whether the metacollector referenced by a Falco is available.
+	// The possible status values for this condition type are:
+	// - True: the metacollector Component is available.
+	// - False: the Component does not exist, is not a metacollector or is not available.
+	// The condition is only present while the Falco references a metacollector.
+	ConditionCollectorAvailable ConditionType = "CollectorAvailable"
+	// ConditionRolledOut indicates whether the current generation of an artifact with a rollout
+	// strategy has been programmed on every node it is assigned to.
+	// The possible status values for this condition type are:
+	// - True: every node has programmed the current generation.
+	// - False (reason: RolloutHalted): a node failed to program the current generation, no more nodes are admitted.
+	// - False (reason: RolloutInProgress): the current generation is being rolled out in batches.
+	// The condition is only present while the artifact has a rollout strategy.
+	ConditionRolledOut ConditionType = "RolledOut"
+	// ConditionRolledBack indicates whether the artifact operator installs a previous revision of an
+	// artifact instead of its current sources.
+	// The possible status values for this condition type are:
+	// - True: the sources recorded in the revision selected by rollbackTo are installed.
+	// - False (reason: RevisionNotFound): the revision selected by rollbackTo does not exist.
+	// The condition is only present while the artifact has rollbackTo set.
+	ConditionRolledBack ConditionType = "RolledBack"
+	// ConditionRulesConflict indicates that the rules files of an artifact define a rule, macro or list again
+	// after a rules file loaded before them, without override or append, replacing the earlier definition.
+	// The condition is only present while a conflict is detected, with status True.
+	ConditionRulesConflict ConditionType = "RulesConflict"
 )
 
 // String returns the string representation of the condition type.
@@ -78,6 +143,22 @@ type OCIArtifact struct {
 	// Registry contains inline registry configuration for authentication, TLS, and hostname.
 	// +optional
 	Registry *RegistryConfig `json:"registry,omitempty"`
+
+	// OCILayout installs the artifact from an OCI image layout mounted in the artifact operator container instead
+	// of pulling it from the registry. The artifact is looked up in the layout by its full reference, as built from
+	// registry.name, image.repository and image.tag, so that the same resource works with and without it.
+	// +optional
+	OCILayout *OCILayoutSource `json:"ociLayout,omitempty"`
+}
+
+// OCILayoutSource locates an OCI image layout, such as one exported with "kubectl falco bundle-export".
+// +kubebuilder:object:generate=true
+type OCILayoutSource struct {
+	// Path is the path of the OCI image layout in the artifact operator container, either a directory or a tar
+	// archive of it, typically on a PersistentVolumeClaim or hostPath volume.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
 }
 
 // ImageSpec specifies the OCI image coordinates.
@@ -146,3 +227,188 @@ type ConfigMapRef struct {
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
 }
+
+// FalcoRef references a Falco instance in the namespace of the referencing object.
+// +kubebuilder:object:generate=true
+type FalcoRef struct {
+	// Name is the name of the Falco instance.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// Rollout configures how a new generation of an artifact is rolled out over the nodes it is assigned to.
+// Nodes are admitted to the new generation in batches; a node failing to program it halts the rollout.
+// +kubebuilder:object:generate=true
+type Rollout struct {
+	// MaxUnavailable is the maximum number of nodes admitted to the new generation that have not
+	// programmed it yet. Value can be an absolute number (ex: 5) or a percentage of the nodes (ex: 10%),
+	// rounded down with a minimum of 1. Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// BatchSize is the maximum number of nodes admitted to the new generation at once.
+	// Defaults to MaxUnavailable.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BatchSize *int32 `json:"batchSize,omitempty"`
+	// PauseBetweenBatches is the minimum time between the admission of two batches.
+	// +optional
+	PauseBetweenBatches *metav1.Duration `json:"pauseBetweenBatches,omitempty"`
+}
+
+// PendingAction is the action the operator would take on a resource.
+// +kubebuilder:validation:Enum=Create;Update;Delete
+type PendingAction string
+
+const (
+	// PendingActionCreate means the resource does not exist and would be created.
+	PendingActionCreate PendingAction = "Create"
+	// PendingActionUpdate means the resource differs from the desired state and would be updated.
+	PendingActionUpdate PendingAction = "Update"
+	// PendingActionDelete means the resource is no longer desired and would be deleted.
+	PendingActionDelete PendingAction = "Delete"
+)
+
+// PendingChange describes a change to a generated resource that was computed but not applied
+// because the instance is reconciled in plan mode.
+// +kubebuilder:object:generate=true
+type PendingChange struct {
+	// Action is the action that would be taken on the resource.
+	Action PendingAction `json:"action"`
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// ChangedFields lists the fields that would be updated, when known.
+	// +optional
+	ChangedFields string `json:"changedFields,omitempty"`
+}
+
+// DriftPolicy defines what the operator does with the fields of the generated resources changed
+// by other field managers.
+// +kubebuilder:validation:Enum=revert;report
+type DriftPolicy string
+
+const (
+	// DriftPolicyRevert reports the drift and reverts the changed fields to the desired state.
+	DriftPolicyRevert DriftPolicy = "revert"
+	// DriftPolicyReport reports the drift and leaves the changed fields to the field managers that changed them.
+	DriftPolicyReport DriftPolicy = "report"
+)
+
+// OverlayPatchType is the format of an overlay patch.
+// +kubebuilder:validation:Enum=StrategicMerge;JSON6902
+type OverlayPatchType string
+
+const (
+	// OverlayPatchTypeStrategicMerge is a strategic merge patch, a partial object merged into the
+	// generated one.
+	OverlayPatchTypeStrategicMerge OverlayPatchType = "StrategicMerge"
+	// OverlayPatchTypeJSON6902 is a list of RFC 6902 JSON patch operations.
+	OverlayPatchTypeJSON6902 OverlayPatchType = "JSON6902"
+)
+
+// OverlayTarget selects the generated resources an overlay is applied to.
+// +kubebuilder:object:generate=true
+type OverlayTarget struct {
+	// Kind is the kind of the generated resources, e.g. ServiceAccount.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+	// Name is the name of the generated resource. When empty, the overlay is applied to all the
+	// generated resources of the kind.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// Overlay is a patch applied to generated resources before they are applied to the cluster.
+// +kubebuilder:object:generate=true
+type Overlay struct {
+	// Target selects the generated resources the patch is applied to.
+	Target OverlayTarget `json:"target"`
+	// Type is the format of the patch.
+	// +kubebuilder:default=StrategicMerge
+	// +optional
+	Type OverlayPatchType `json:"type,omitempty"`
+	// Patch is the patch: a partial object for StrategicMerge, a list of operations for JSON6902.
+	Patch apiextensionsv1.JSON `json:"patch"`
+}
+
+// MonitorSpec configures a prometheus-operator monitor generated for an instance.
+// +kubebuilder:object:generate=true
+type MonitorSpec struct {
+	// Enabled generates the monitor.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval is the interval between two scrapes, e.g. 30s. The Prometheus one is used when empty.
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// Labels are added to the monitor, e.g. to match the monitor selector of a Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// MonitoringSpec configures the prometheus-operator objects generated for an instance. They are only
+// generated when the prometheus-operator CRDs are installed in the cluster.
+// +kubebuilder:object:generate=true
+type MonitoringSpec struct {
+	// ServiceMonitor generates a ServiceMonitor scraping the metrics port of the Service.
+	// +optional
+	ServiceMonitor *MonitorSpec `json:"serviceMonitor,omitempty"`
+	// PodMonitor generates a PodMonitor scraping the metrics port of the pods.
+	// +optional
+	PodMonitor *MonitorSpec `json:"podMonitor,omitempty"`
+}
+
+// NetworkPolicySpec configures the NetworkPolicy generated for an instance. The policy allows the traffic
+// between the instance and its known peers, e.g. Falco reaching the http port of Falcosidekick.
+// +kubebuilder:object:generate=true
+type NetworkPolicySpec struct {
+	// Enabled generates the NetworkPolicy.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// From lists extra peers allowed to reach every port of the instance, e.g. Prometheus or an
+	// ingress controller.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	From []networkingv1.NetworkPolicyPeer `json:"from,omitempty"`
+	// To lists extra peers the instance is allowed to reach on every port. It only matters for the
+	// instance types whose egress is restricted by the generated policy.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	To []networkingv1.NetworkPolicyPeer `json:"to,omitempty"`
+}
+
+// TLSSpec configures the certificate the operator issues to an instance. The certificate is signed by the CA
+// the operator keeps in the namespace, and the instance uses it both to serve TLS and to authenticate to its peers.
+// +kubebuilder:object:generate=true
+type TLSSpec struct {
+	// Enabled issues the certificate and switches the instance to TLS.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// TLSStatus reports the certificate issued to an instance.
+// +kubebuilder:object:generate=true
+type TLSStatus struct {
+	// SecretName is the name of the Secret holding the certificate, its key and the trusted CAs.
+	SecretName string `json:"secretName"`
+	// SerialNumber is the serial number of the certificate, in hexadecimal.
+	SerialNumber string `json:"serialNumber"`
+	// NotAfter is the time the certificate expires at.
+	NotAfter metav1.Time `json:"notAfter"`
+	// RenewTime is the time the operator renews the certificate at.
+	RenewTime metav1.Time `json:"renewTime"`
+}
+
+// RollbackConfig selects a previous revision of an artifact to install instead of its current sources.
+// +kubebuilder:object:generate=true
+type RollbackConfig struct {
+	// Revision is the number of the revision to install, as recorded by the instance operator in the
+	// ControllerRevisions of the artifact.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
+}
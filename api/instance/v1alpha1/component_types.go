@@ -20,6 +20,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 )
 
 // ComponentType defines the type of component to deploy.
@@ -47,6 +49,7 @@ type ComponentInfo struct {
 }
 
 // ComponentSpec defines the desired state of a Component.
+// +kubebuilder:validation:XValidation:rule="!has(self.tls) || !self.tls.enabled || self.component.type == 'falcosidekick'",message="tls is only supported by the falcosidekick component"
 type ComponentSpec struct {
 	// Component identifies which component to deploy and at which version.
 	Component ComponentInfo `json:"component"`
@@ -66,6 +69,72 @@ type ComponentSpec struct {
 	// Strategy specifies the deployment strategy for the Deployment.
 	// +optional
 	Strategy *appsv1.DeploymentStrategy `json:"strategy,omitempty"`
+
+	// DriftPolicy defines what happens to the fields of the generated resources changed by other
+	// field managers: "revert" (the default) restores them, "report" leaves them as changed.
+	// The drift is reported in the Drifted condition in both cases.
+	// +optional
+	DriftPolicy *commonv1alpha1.DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// Suspend stops the operator from applying, deleting or restarting the resources generated for
+	// the Component, which keep running as they are. The status keeps being reported.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Overlays are patches applied to the resources generated for the Component before they are
+	// applied, to set what the other fields don't expose, e.g. annotations on the ServiceAccount.
+	// They are applied in order.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Overlays []commonv1alpha1.Overlay `json:"overlays,omitempty"`
+
+	// Monitoring configures the prometheus-operator objects generated for the Component.
+	// +optional
+	Monitoring *commonv1alpha1.MonitoringSpec `json:"monitoring,omitempty"`
+
+	// NetworkPolicy configures the NetworkPolicy generated for the Component.
+	// +optional
+	NetworkPolicy *commonv1alpha1.NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// Autoscaling configures the HorizontalPodAutoscaler generated for the Component. While it is
+	// enabled, the replicas of the Deployment are left to the autoscaler and Replicas is ignored.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+
+	// TLS issues a certificate to the Component, which serves TLS and requires the clients to
+	// authenticate with a certificate issued by the operator. Only falcosidekick supports it.
+	// +optional
+	TLS *commonv1alpha1.TLSSpec `json:"tls,omitempty"`
+}
+
+// AutoscalingSpec configures the HorizontalPodAutoscaler of a Component.
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas must not be greater than maxReplicas"
+type AutoscalingSpec struct {
+	// Enabled generates the HorizontalPodAutoscaler.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MinReplicas is the lower limit for the number of replicas. Default is 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the average CPU utilization of the pods, relative to their
+	// requests, the autoscaler targets. Default is 80 when no target is set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// TargetMemoryUtilizationPercentage is the average memory utilization of the pods, relative to
+	// their requests, the autoscaler targets.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
 }
 
 // ComponentStatus defines the observed state of a Component.
@@ -86,6 +155,15 @@ type ComponentStatus struct {
 	// +optional
 	UnavailableReplicas int32 `json:"unavailableReplicas,omitempty" protobuf:"varint,3,opt,name=unavailableReplicas"`
 
+	// TLS reports the certificate issued to the component when TLS is enabled.
+	// +optional
+	TLS *commonv1alpha1.TLSStatus `json:"tls,omitempty"`
+
+	// PendingChanges lists the changes computed but not applied while the instance is reconciled in plan mode.
+	// +optional
+	// +listType=atomic
+	PendingChanges []commonv1alpha1.PendingChange `json:"pendingChanges,omitempty"`
+
 	// Conditions represent the latest available observations of the component instance's state.
 	// +optional
 	// +patchMergeKey=type
@@ -19,12 +19,17 @@ package v1alpha1
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // FalcoSpec defines the desired state of Falco.
+// +kubebuilder:validation:XValidation:rule="!has(self.nodePools) || size(self.nodePools) == 0 || !has(self.type) || self.type == 'DaemonSet'",message="nodePools are only supported when type is DaemonSet"
+// +kubebuilder:validation:XValidation:rule="!has(self.upgradePolicy) || !has(self.type) || self.type == 'DaemonSet'",message="upgradePolicy is only supported when type is DaemonSet"
 type FalcoSpec struct {
 	// Type specifies the type of Kubernetes resource to deploy Falco.
 	// Allowed values: "DaemonSet" or "Deployment". Default value is DaemonSet.
@@ -64,6 +69,187 @@ type FalcoSpec struct {
 	// Only applicable when type is "Deployment".
 	// +optional
 	Strategy *appsv1.DeploymentStrategy `json:"strategy,omitempty"`
+
+	// HealthCheck configures the scraping of the Falco webserver of each pod to report
+	// whether Falco has rules loaded and how many kernel events it drops.
+	// +optional
+	HealthCheck *HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// NodePools splits the Falco DaemonSet into one DaemonSet per pool of nodes, each with its own
+	// resources, tolerations and configuration. A node belongs to the first pool whose node selector
+	// matches it; nodes matched by no pool run the default DaemonSet.
+	// Only applicable when type is "DaemonSet".
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	NodePools []NodePool `json:"nodePools,omitempty"`
+
+	// UpgradePolicy rolls a new Falco version out to canary nodes first, holds it there for a soak period
+	// while they stay healthy, and rolls it back when they do not.
+	// Only applicable when type is "DaemonSet".
+	// +optional
+	UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// DriftPolicy defines what happens to the fields of the generated resources changed by other
+	// field managers: "revert" (the default) restores them, "report" leaves them as changed.
+	// The drift is reported in the Drifted condition in both cases.
+	// +optional
+	DriftPolicy *commonv1alpha1.DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// Suspend stops the operator from applying, deleting or restarting the resources generated for
+	// the Falco, which keep running as they are. The status keeps being reported.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Overlays are patches applied to the resources generated for the Falco before they are
+	// applied, to set what the other fields don't expose, e.g. annotations on the ServiceAccount.
+	// They are applied in order.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Overlays []commonv1alpha1.Overlay `json:"overlays,omitempty"`
+
+	// Monitoring configures the prometheus-operator objects generated for the Falco.
+	// +optional
+	Monitoring *FalcoMonitoringSpec `json:"monitoring,omitempty"`
+
+	// NetworkPolicy configures the NetworkPolicy generated for the Falco.
+	// +optional
+	NetworkPolicy *commonv1alpha1.NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// TLS issues a certificate to the Falco: the webserver serves TLS and the http output authenticates
+	// with the certificate.
+	// +optional
+	TLS *commonv1alpha1.TLSSpec `json:"tls,omitempty"`
+
+	// Metadata configures the enrichment of the Falco events with Kubernetes metadata.
+	// +optional
+	Metadata *MetadataSpec `json:"metadata,omitempty"`
+}
+
+// MetadataSpec configures the enrichment of the Falco events with Kubernetes metadata.
+type MetadataSpec struct {
+	// CollectorRef references a Component of type metacollector in the namespace of the Falco. The operator
+	// generates the k8smeta Plugin connecting Falco to the metacollector and reports its availability in the
+	// CollectorAvailable condition.
+	// +optional
+	CollectorRef *CollectorRef `json:"collectorRef,omitempty"`
+}
+
+// CollectorRef references a metacollector Component.
+type CollectorRef struct {
+	// Name is the name of the Component.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// UpgradePolicy configures the orchestration of Falco version upgrades.
+type UpgradePolicy struct {
+	// Canary selects the nodes upgraded first.
+	Canary CanarySpec `json:"canary"`
+
+	// SoakDuration is how long the canary pods must stay ready and healthy before the new version is
+	// rolled out to every node.
+	// Default is 5m.
+	// +optional
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+
+	// ProgressDeadline is how long the canary pods may take to become ready and healthy before the
+	// upgrade is considered failed.
+	// Default is 10m.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+
+	// AutoRollback restores the previous version when the canary fails. When disabled, the upgrade is
+	// halted with the canary pods left on the new version.
+	// Default is true.
+	// +optional
+	AutoRollback *bool `json:"autoRollback,omitempty"`
+}
+
+// CanarySpec selects the canary nodes of an upgrade, either by count or by labels.
+// +kubebuilder:validation:XValidation:rule="has(self.nodes) != has(self.nodeSelector)",message="exactly one of nodes or nodeSelector must be set"
+type CanarySpec struct {
+	// Nodes is the number of nodes upgraded first, picked in node name order among the nodes
+	// running Falco.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Nodes *int32 `json:"nodes,omitempty"`
+
+	// NodeSelector selects the nodes upgraded first by their labels.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+// NodePool overrides the Falco pod settings for the nodes matched by its node selector.
+type NodePool struct {
+	// Name identifies the pool. The DaemonSet of the pool is named after the Falco instance
+	// suffixed with this name.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=30
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes of the pool by their labels.
+	// +kubebuilder:validation:XValidation:rule="(has(self.matchLabels) && size(self.matchLabels) > 0) || (has(self.matchExpressions) && size(self.matchExpressions) > 0)",message="nodeSelector must not be empty"
+	NodeSelector metav1.LabelSelector `json:"nodeSelector"`
+
+	// Resources replaces the resources of the Falco container on the nodes of the pool.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Tolerations are added to the tolerations of the Falco pods on the nodes of the pool.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Config is merged on top of the base Falco configuration on the nodes of the pool,
+	// specified as a structured object.
+	// +optional
+	Config *apiextensionsv1.JSON `json:"config,omitempty"`
+}
+
+// FalcoMonitoringSpec configures the prometheus-operator objects generated for a Falco instance.
+type FalcoMonitoringSpec struct {
+	commonv1alpha1.MonitoringSpec `json:",inline"`
+
+	// PrometheusRule generates a PrometheusRule alerting on event drops, restarts of the Falco
+	// containers and artifacts failing to be programmed.
+	// +optional
+	PrometheusRule *PrometheusRuleSpec `json:"prometheusRule,omitempty"`
+}
+
+// PrometheusRuleSpec configures the PrometheusRule generated for a Falco instance.
+type PrometheusRuleSpec struct {
+	// Enabled generates the PrometheusRule.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Labels are added to the PrometheusRule, e.g. to match the rule selector of a Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// HealthCheckSpec configures the Falco health checks performed by the operator.
+type HealthCheckSpec struct {
+	// Enabled turns on the scraping of the /healthz and /metrics endpoints of the Falco webserver.
+	// It requires the webserver and its Prometheus metrics to be enabled in the Falco configuration,
+	// which is the default.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval is the period between two scrapes.
+	// Default is 30s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// MaxDropRatePercent is the share of dropped kernel events, in percent of the events
+	// received since the previous scrape, above which a node is reported as dropping events.
+	// Default is 1.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxDropRatePercent *int32 `json:"maxDropRatePercent,omitempty"`
 }
 
 // FalcoStatus defines the observed state of Falco.
@@ -91,6 +277,40 @@ type FalcoStatus struct {
 	// +optional
 	UnavailableReplicas int32 `json:"unavailableReplicas,omitempty" protobuf:"varint,5,opt,name=unavailableReplicas"`
 
+	// ConfigRevision is the hash of the generated Falco configuration currently stamped on the pod template
+	// of the default workload. The revisions of the node pools are reported in NodePools.
+	// Pods are rolled according to the update strategy whenever it changes.
+	// +optional
+	ConfigRevision string `json:"configRevision,omitempty"`
+
+	// TLS reports the certificate issued to the Falco when TLS is enabled.
+	// +optional
+	TLS *commonv1alpha1.TLSStatus `json:"tls,omitempty"`
+
+	// NodePools reports the DaemonSet of each node pool. The replica counts above are the sums over
+	// the default DaemonSet and the DaemonSets of the pools.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	NodePools []NodePoolStatus `json:"nodePools,omitempty"`
+
+	// Upgrade reports the progress of the last version upgrade orchestrated by the upgrade policy.
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+
+	// PendingChanges lists the changes computed but not applied while the instance is reconciled in plan mode.
+	// +optional
+	// +listType=atomic
+	PendingChanges []commonv1alpha1.PendingChange `json:"pendingChanges,omitempty"`
+
+	// Nodes reports the health of each Falco pod and of the node it runs on. Entries are keyed by pod, since
+	// several pods of a Deployment can run on the same node.
+	// Only populated when health checks are enabled.
+	// +optional
+	// +listType=map
+	// +listMapKey=podName
+	Nodes []FalcoNodeHealth `json:"nodes,omitempty"`
+
 	// Conditions represent the latest available observations of the Falco instance's state.
 	// +optional
 	// +patchMergeKey=type
@@ -100,6 +320,87 @@ type FalcoStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
+// FalcoNodeHealth is the health of the Falco pod running on a node, as last scraped by the operator.
+type FalcoNodeHealth struct {
+	// NodeName is the name of the node.
+	NodeName string `json:"nodeName"`
+	// PodName is the name of the scraped Falco pod.
+	PodName string `json:"podName"`
+	// Healthy is true when the pod answered its health endpoint, has rules files loaded and
+	// drops no more events than allowed.
+	Healthy bool `json:"healthy"`
+	// RulesFilesLoaded is the number of rules files loaded by Falco, when reported.
+	// +optional
+	RulesFilesLoaded *int64 `json:"rulesFilesLoaded,omitempty"`
+	// DropRate is the share of dropped kernel events since the previous scrape, e.g. "0.25%".
+	// +optional
+	DropRate string `json:"dropRate,omitempty"`
+	// Message describes why the pod is unhealthy.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastScrapeTime is the time of the last scrape.
+	LastScrapeTime metav1.Time `json:"lastScrapeTime"`
+}
+
+// NodePoolStatus is the observed state of the DaemonSet of a node pool.
+type NodePoolStatus struct {
+	// Name is the name of the pool.
+	Name string `json:"name"`
+	// DesiredReplicas is the number of nodes of the pool that should run the Falco pod.
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+	// AvailableReplicas is the number of nodes of the pool running an available Falco pod.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+	// UnavailableReplicas is the number of nodes of the pool without an available Falco pod.
+	// +optional
+	UnavailableReplicas int32 `json:"unavailableReplicas,omitempty"`
+	// ConfigRevision is the hash of the Falco configuration currently stamped on the pod template of the pool.
+	// It differs from the one of the default DaemonSet when the pool overrides the configuration.
+	// +optional
+	ConfigRevision string `json:"configRevision,omitempty"`
+}
+
+// UpgradePhase is the phase of a Falco version upgrade.
+// +kubebuilder:validation:Enum=Canary;Soaking;RollingOut;Completed;RolledBack;Failed
+type UpgradePhase string
+
+const (
+	// UpgradePhaseCanary means the canary pods are being replaced with the new version.
+	UpgradePhaseCanary UpgradePhase = "Canary"
+	// UpgradePhaseSoaking means the canary pods are healthy and held on the new version for the soak period.
+	UpgradePhaseSoaking UpgradePhase = "Soaking"
+	// UpgradePhaseRollingOut means the new version is being rolled out to every node.
+	UpgradePhaseRollingOut UpgradePhase = "RollingOut"
+	// UpgradePhaseCompleted means every node runs the new version.
+	UpgradePhaseCompleted UpgradePhase = "Completed"
+	// UpgradePhaseRolledBack means the canary failed and the previous version was restored.
+	UpgradePhaseRolledBack UpgradePhase = "RolledBack"
+	// UpgradePhaseFailed means the canary failed and the upgrade is halted.
+	UpgradePhaseFailed UpgradePhase = "Failed"
+)
+
+// UpgradeStatus is the progress of a Falco version upgrade.
+type UpgradeStatus struct {
+	// Phase is the current phase of the upgrade.
+	Phase UpgradePhase `json:"phase"`
+	// FromVersion is the version running before the upgrade.
+	FromVersion string `json:"fromVersion"`
+	// ToVersion is the version being upgraded to.
+	ToVersion string `json:"toVersion"`
+	// CanaryNodes are the nodes upgraded first.
+	// +optional
+	// +listType=set
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+	// StartTime is the time the upgrade started.
+	StartTime metav1.Time `json:"startTime"`
+	// LastTransitionTime is the time the upgrade entered its current phase.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// Message describes the current phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // +kubebuilder:resource:path=falcos,categories=instances,shortName="falco"
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".status.resourceType",description="The type of Kubernetes resource to deploy Falco"
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="The version of Falco"
@@ -39,11 +39,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/controllers/artifact/config"
 	"github.com/falcosecurity/falco-operator/controllers/artifact/plugin"
 	"github.com/falcosecurity/falco-operator/controllers/artifact/rulesfile"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
 	"github.com/falcosecurity/falco-operator/internal/pkg/startupgate"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 	"github.com/falcosecurity/falco-operator/internal/pkg/version"
 )
 
@@ -56,6 +58,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(artifactv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(instancev1alpha1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -69,6 +72,7 @@ func main() {
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	var opts zap.Options
+	var tracingOpts tracing.Options
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -89,6 +93,7 @@ func main() {
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 
 	opts.BindFlags(flag.CommandLine)
+	tracingOpts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
@@ -110,6 +115,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Get the name of the Falco instance from environment variable. Without it, only the artifacts not
+	// restricted to some Falco instances are applied.
+	instanceName := os.Getenv("FALCO_INSTANCE")
+	if instanceName == "" {
+		setupLog.Info("FALCO_INSTANCE environment variable not set, skipping the artifacts restricted to some Falco instances")
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -228,7 +240,7 @@ func main() {
 		}
 	}
 
-	gate := startupgate.NewGate(mgr.GetClient(), nodeName, namespace)
+	gate := startupgate.NewGate(mgr.GetClient(), nodeName, namespace, instanceName)
 
 	if err = config.NewConfigReconciler(
 		mgr.GetClient(),
@@ -237,6 +249,7 @@ func main() {
 		gate,
 		nodeName,
 		namespace,
+		instanceName,
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Config")
 		os.Exit(1)
@@ -249,6 +262,7 @@ func main() {
 		gate,
 		nodeName,
 		namespace,
+		instanceName,
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Rulesfile")
 		os.Exit(1)
@@ -261,6 +275,7 @@ func main() {
 		gate,
 		nodeName,
 		namespace,
+		instanceName,
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Plugin")
 		os.Exit(1)
@@ -304,8 +319,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "falco-artifact-operator", tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if tracingOpts.Enabled() {
+		setupLog.Info("tracing enabled", "endpoint", tracingOpts.Endpoint, "sampleRatio", tracingOpts.SampleRatio)
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "unable to flush pending spans")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
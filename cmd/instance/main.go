@@ -17,8 +17,10 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -29,6 +31,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -37,14 +40,16 @@ import (
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
-	artifactconfigctr "github.com/falcosecurity/falco-operator/controllers/instance/artifact/config"
+	artifactrevisionctr "github.com/falcosecurity/falco-operator/controllers/instance/artifact/revision"
 	"github.com/falcosecurity/falco-operator/controllers/instance/component"
 	"github.com/falcosecurity/falco-operator/controllers/instance/falco"
 	configmapctr "github.com/falcosecurity/falco-operator/controllers/instance/reference/configmap"
 	secretctr "github.com/falcosecurity/falco-operator/controllers/instance/reference/secret"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
 	"github.com/falcosecurity/falco-operator/internal/pkg/instance"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 	"github.com/falcosecurity/falco-operator/internal/pkg/version"
 )
 
@@ -82,8 +87,10 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var excludedLabels stringSliceFlag
+	var reconcileMode string
 	var tlsOpts []func(*tls.Config)
 	var opts zap.Options
+	var tracingOpts tracing.Options
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -105,12 +112,21 @@ func main() {
 	flag.Var(&excludedLabels, "excluded-labels",
 		"A label key to exclude from propagation onto operator-generated resources. "+
 			"The '*' wildcard is supported (e.g. kustomize.toolkit.fluxcd.io/*). May be repeated.")
+	flag.StringVar(&reconcileMode, "reconcile-mode", instance.ReconcileModeApply,
+		"How instances without the "+instance.ReconcileModeAnnotation+" annotation are reconciled: "+
+			"'apply' applies the generated resources, 'plan' only reports the pending changes in their status.")
 
 	opts.BindFlags(flag.CommandLine)
+	tracingOpts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if !instance.ValidReconcileMode(reconcileMode) {
+		setupLog.Error(fmt.Errorf("unknown reconcile mode %q", reconcileMode), "invalid flag value", "flag", "reconcile-mode")
+		os.Exit(1)
+	}
+
 	setupLog.Info("Starting instance operator", "version", version.SemVersion, "commit", version.GitCommit,
 		"buildDate", version.BuildDate, "compiler", version.Compiler, "platform", version.Platform)
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
@@ -242,6 +258,7 @@ func main() {
 	if err = falco.NewReconciler(
 		mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorder("falco-controller"), sidecarEnabled,
 		falco.WithLabelFilter(labelFilter),
+		falco.WithReconcileMode(reconcileMode),
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Falco")
 		os.Exit(1)
@@ -250,6 +267,7 @@ func main() {
 	if err = component.NewReconciler(
 		mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorder("component-controller"),
 		component.WithLabelFilter(labelFilter),
+		component.WithReconcileMode(reconcileMode),
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Component")
 		os.Exit(1)
@@ -269,13 +287,32 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := artifactconfigctr.NewConfigAggregatorReconciler(
-		mgr.GetClient(), mgr.GetScheme(),
-	).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", artifactconfigctr.ControllerName)
+	if err := setupAggregator(mgr, &artifactv1alpha1.Config{}, &artifactv1alpha1.ConfigList{}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", controllerhelper.AggregatorControllerName, "kind", controllerhelper.KindConfig)
+		os.Exit(1)
+	}
+
+	if err := setupAggregator(mgr, &artifactv1alpha1.Plugin{}, &artifactv1alpha1.PluginList{}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", controllerhelper.AggregatorControllerName, "kind", controllerhelper.KindPlugin)
+		os.Exit(1)
+	}
+
+	if err := setupAggregator(mgr, &artifactv1alpha1.Rulesfile{}, &artifactv1alpha1.RulesfileList{}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", controllerhelper.AggregatorControllerName, "kind", controllerhelper.KindRulesfile)
 		os.Exit(1)
 	}
 
+	for _, kind := range []string{controllerhelper.KindConfig, controllerhelper.KindPlugin, controllerhelper.KindRulesfile} {
+		revisionReconciler, err := artifactrevisionctr.NewRevisionReconciler(mgr.GetClient(), mgr.GetScheme(), kind)
+		if err == nil {
+			err = revisionReconciler.SetupWithManager(mgr)
+		}
+		if err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", artifactrevisionctr.ControllerName, "kind", kind)
+			os.Exit(1)
+		}
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -303,9 +340,31 @@ func main() {
 		os.Exit(1)
 	}
 
+	shutdownTracing, err := tracing.Setup(ctx, "falco-operator", tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if tracingOpts.Enabled() {
+		setupLog.Info("tracing enabled", "endpoint", tracingOpts.Endpoint, "sampleRatio", tracingOpts.SampleRatio)
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctx); err != nil {
+	err = mgr.Start(ctx)
+	if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "unable to flush pending spans")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// setupAggregator registers the node-object aggregator controller of the artifacts of the type of object.
+func setupAggregator[T client.Object, L client.ObjectList](mgr ctrl.Manager, object T, list L) error {
+	aggregator, err := controllerhelper.NewAggregatorReconciler(mgr.GetClient(), mgr.GetScheme(), object, list)
+	if err != nil {
+		return err
+	}
+	return aggregator.SetupWithManager(mgr)
+}
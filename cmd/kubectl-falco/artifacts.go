@@ -0,0 +1,169 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+// artifactInfo is the part of a Config, Plugin or Rulesfile inspected by the commands.
+type artifactInfo struct {
+	object client.Object
+	// kind is the Kubernetes Kind of the artifact, e.g. Config.
+	kind string
+	// artifactKind is the artifact kind label value of the ArtifactNodes of the artifact, e.g. config.
+	artifactKind string
+	target       controllerhelper.Target
+	suspend      bool
+	rollout      *commonv1alpha1.Rollout
+	ociArtifact  *commonv1alpha1.OCIArtifact
+	conditions   []metav1.Condition
+	nodeSummary  *artifactv1alpha1.NodeSummary
+}
+
+func configInfo(config *artifactv1alpha1.Config) artifactInfo {
+	return artifactInfo{
+		object:       config,
+		kind:         controllerhelper.KindConfig,
+		artifactKind: controllerhelper.ArtifactKindConfig,
+		target: controllerhelper.Target{
+			NodeSelector:     config.Spec.Selector,
+			FalcoRef:         config.Spec.FalcoRef,
+			InstanceSelector: config.Spec.InstanceSelector,
+		},
+		suspend:     config.Spec.Suspend,
+		rollout:     config.Spec.Rollout,
+		conditions:  config.Status.Conditions,
+		nodeSummary: config.Status.NodeSummary,
+	}
+}
+
+func pluginInfo(plugin *artifactv1alpha1.Plugin) artifactInfo {
+	return artifactInfo{
+		object:       plugin,
+		kind:         controllerhelper.KindPlugin,
+		artifactKind: controllerhelper.ArtifactKindPlugin,
+		target: controllerhelper.Target{
+			NodeSelector:     plugin.Spec.Selector,
+			FalcoRef:         plugin.Spec.FalcoRef,
+			InstanceSelector: plugin.Spec.InstanceSelector,
+		},
+		suspend:     plugin.Spec.Suspend,
+		rollout:     plugin.Spec.Rollout,
+		ociArtifact: plugin.Spec.OCIArtifact,
+		conditions:  plugin.Status.Conditions,
+		nodeSummary: plugin.Status.NodeSummary,
+	}
+}
+
+func rulesfileInfo(rulesfile *artifactv1alpha1.Rulesfile) artifactInfo {
+	return artifactInfo{
+		object:       rulesfile,
+		kind:         controllerhelper.KindRulesfile,
+		artifactKind: controllerhelper.ArtifactKindRulesfile,
+		target: controllerhelper.Target{
+			NodeSelector:     rulesfile.Spec.Selector,
+			FalcoRef:         rulesfile.Spec.FalcoRef,
+			InstanceSelector: rulesfile.Spec.InstanceSelector,
+		},
+		suspend:     rulesfile.Spec.Suspend,
+		rollout:     rulesfile.Spec.Rollout,
+		ociArtifact: rulesfile.Spec.OCIArtifact,
+		conditions:  rulesfile.Status.Conditions,
+		nodeSummary: rulesfile.Status.NodeSummary,
+	}
+}
+
+// getArtifact fetches the artifact referenced as kind/name, e.g. rulesfile/falco-rules. The kind is case-insensitive
+// and may be plural.
+func (c *cli) getArtifact(ctx context.Context, ref string) (artifactInfo, error) {
+	kind, name, ok := strings.Cut(ref, "/")
+	if !ok || name == "" {
+		return artifactInfo{}, fmt.Errorf("%w: artifact %q is not of the form kind/name", errUsage, ref)
+	}
+	key := client.ObjectKey{Namespace: c.namespace, Name: name}
+	switch strings.TrimSuffix(strings.ToLower(kind), "s") {
+	case controllerhelper.ArtifactKindConfig:
+		config := &artifactv1alpha1.Config{}
+		if err := c.client.Get(ctx, key, config); err != nil {
+			return artifactInfo{}, err
+		}
+		return configInfo(config), nil
+	case controllerhelper.ArtifactKindPlugin:
+		plugin := &artifactv1alpha1.Plugin{}
+		if err := c.client.Get(ctx, key, plugin); err != nil {
+			return artifactInfo{}, err
+		}
+		return pluginInfo(plugin), nil
+	case controllerhelper.ArtifactKindRulesfile:
+		rulesfile := &artifactv1alpha1.Rulesfile{}
+		if err := c.client.Get(ctx, key, rulesfile); err != nil {
+			return artifactInfo{}, err
+		}
+		return rulesfileInfo(rulesfile), nil
+	default:
+		return artifactInfo{}, fmt.Errorf("%w: unknown artifact kind %q, expected config, plugin or rulesfile", errUsage, kind)
+	}
+}
+
+// listArtifacts lists the Configs, Plugins and Rulesfiles of the namespace, in this order.
+func (c *cli) listArtifacts(ctx context.Context) ([]artifactInfo, error) {
+	var artifacts []artifactInfo
+
+	configs := &artifactv1alpha1.ConfigList{}
+	if err := c.client.List(ctx, configs, client.InNamespace(c.namespace)); err != nil {
+		return nil, fmt.Errorf("listing Configs: %w", err)
+	}
+	for i := range configs.Items {
+		artifacts = append(artifacts, configInfo(&configs.Items[i]))
+	}
+
+	plugins := &artifactv1alpha1.PluginList{}
+	if err := c.client.List(ctx, plugins, client.InNamespace(c.namespace)); err != nil {
+		return nil, fmt.Errorf("listing Plugins: %w", err)
+	}
+	for i := range plugins.Items {
+		artifacts = append(artifacts, pluginInfo(&plugins.Items[i]))
+	}
+
+	rulesfiles := &artifactv1alpha1.RulesfileList{}
+	if err := c.client.List(ctx, rulesfiles, client.InNamespace(c.namespace)); err != nil {
+		return nil, fmt.Errorf("listing Rulesfiles: %w", err)
+	}
+	for i := range rulesfiles.Items {
+		artifacts = append(artifacts, rulesfileInfo(&rulesfiles.Items[i]))
+	}
+	return artifacts, nil
+}
+
+// conditionStatus formats the status of the condition of the given type, or "-" when it is not set.
+func conditionStatus(conditions []metav1.Condition, conditionType commonv1alpha1.ConditionType) string {
+	if condition := apimeta.FindStatusCondition(conditions, conditionType.String()); condition != nil {
+		return string(condition.Status)
+	}
+	return "-"
+}
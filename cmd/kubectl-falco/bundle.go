@@ -0,0 +1,238 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
+	"github.com/falcosecurity/falco-operator/internal/pkg/credentials"
+	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
+)
+
+// bundler saves OCI artifacts into an OCI image layout.
+type bundler interface {
+	Save(ctx context.Context, ref string, creds auth.CredentialFunc, opts *puller.RegistryOptions, dst oras.Target) (*puller.RegistryResult, error)
+}
+
+// manifests holds the Plugins and Rulesfiles with an OCI artifact, and the Secrets, read from manifest files.
+type manifests struct {
+	artifacts []artifactInfo
+	secrets   map[string]*corev1.Secret
+}
+
+// bundleExport saves the OCI artifacts of the Plugins and Rulesfiles of manifest files into an OCI image layout, so
+// that they can be installed in a disconnected cluster through the ociLayout field of their OCIArtifact.
+func (c *cli) bundleExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("bundle-export", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	output := fs.String("o", "falco-bundle.tar", "The path of the OCI image layout to write: a tar archive when it ends "+
+		"with .tar, a directory otherwise.")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: bundle-export expects at least one manifest file", errUsage)
+	}
+
+	m, err := readManifests(fs.Args())
+	if err != nil {
+		return err
+	}
+	if len(m.artifacts) == 0 {
+		return errors.New("no Plugin or Rulesfile with an OCI artifact found in the manifests")
+	}
+
+	layoutDir := *output
+	archive := strings.HasSuffix(*output, ".tar")
+	if archive {
+		if layoutDir, err = os.MkdirTemp("", "falco-bundle-"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(layoutDir)
+	}
+	layout, err := oci.NewWithContext(ctx, layoutDir)
+	if err != nil {
+		return fmt.Errorf("unable to create OCI layout %s: %w", layoutDir, err)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ARTIFACT\tREFERENCE\tTYPE\tDIGEST")
+	saved := map[string]string{}
+	for i := range m.artifacts {
+		a := &m.artifacts[i]
+		ref := artifact.ResolveReference(a.ociArtifact)
+		digest, ok := saved[ref]
+		if !ok {
+			creds, err := c.bundleCredentials(ctx, m, a)
+			if err != nil {
+				return err
+			}
+			res, err := c.bundler.Save(ctx, ref, creds, artifact.ResolveRegistryOptions(a.ociArtifact), layout)
+			if err != nil {
+				return fmt.Errorf("saving %s/%s: %w", a.kind, a.object.GetName(), err)
+			}
+			if string(res.Type) != a.artifactKind {
+				return fmt.Errorf("saving %s/%s: OCI artifact type %q does not match expected type %q",
+					a.kind, a.object.GetName(), res.Type, a.artifactKind)
+			}
+			digest = res.Digest
+			saved[ref] = digest
+		}
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\n", a.kind, a.object.GetName(), ref, a.artifactKind, digest)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if archive {
+		if err := writeArchive(*output, layoutDir); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(c.out, "\nWrote %d artifact(s) to %s.\n", len(saved), *output)
+	return nil
+}
+
+// bundleCredentials returns the credentials to pull the OCI artifact of a. The pull secret is looked up in the
+// manifests first, then in the cluster.
+func (c *cli) bundleCredentials(ctx context.Context, m *manifests, a *artifactInfo) (auth.CredentialFunc, error) {
+	ref := artifact.AuthSecretRef(a.ociArtifact)
+	if ref == nil {
+		return credentials.FromSecret(artifact.ResolveRegistryHost(a.ociArtifact), nil)
+	}
+
+	secret, ok := m.secrets[ref.Name]
+	if !ok {
+		if c.client == nil {
+			return nil, fmt.Errorf("pull secret %s of %s/%s is not in the manifests and the cluster is not reachable: %w",
+				ref.Name, a.kind, a.object.GetName(), c.clientErr)
+		}
+		secret = &corev1.Secret{}
+		if err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: ref.Name}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get pull secret %s: %w", ref.Name, err)
+		}
+	}
+	return credentials.FromSecret(artifact.ResolveRegistryHost(a.ociArtifact), secret)
+}
+
+// readManifests decodes the YAML or JSON documents of the given files, "-" standing for the standard input. Documents
+// of other kinds are ignored.
+func readManifests(paths []string) (*manifests, error) {
+	m := &manifests{secrets: map[string]*corev1.Secret{}}
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	for _, path := range paths {
+		data, err := readManifestFile(path)
+		if err != nil {
+			return nil, err
+		}
+		reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+		for {
+			doc, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", path, err)
+			}
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+			obj, _, err := decoder.Decode(doc, nil, nil)
+			if err != nil {
+				if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) {
+					continue
+				}
+				return nil, fmt.Errorf("decoding %s: %w", path, err)
+			}
+			m.add(obj)
+		}
+	}
+	return m, nil
+}
+
+func readManifestFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // the manifest files are chosen by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return data, nil
+}
+
+func (m *manifests) add(obj runtime.Object) {
+	switch o := obj.(type) {
+	case *artifactv1alpha1.Plugin:
+		if o.Spec.OCIArtifact != nil {
+			m.artifacts = append(m.artifacts, pluginInfo(o))
+		}
+	case *artifactv1alpha1.Rulesfile:
+		if o.Spec.OCIArtifact != nil {
+			m.artifacts = append(m.artifacts, rulesfileInfo(o))
+		}
+	case *corev1.Secret:
+		// The API server merges stringData into data on write; do the same for Secrets read from files.
+		for key, value := range o.StringData {
+			if o.Data == nil {
+				o.Data = map[string][]byte{}
+			}
+			o.Data[key] = []byte(value)
+		}
+		m.secrets[o.Name] = o
+	}
+}
+
+// writeArchive writes the OCI image layout directory dir as the tar archive path.
+func writeArchive(path, dir string) (err error) {
+	f, err := os.Create(path) //nolint:gosec // the output path is chosen by the user
+	if err != nil {
+		return fmt.Errorf("unable to create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	tw := tar.NewWriter(f)
+	if err := tw.AddFS(os.DirFS(dir)); err != nil {
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+	return tw.Close()
+}
@@ -0,0 +1,251 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote/auth"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/controllers/testutil"
+	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
+)
+
+// fakeBundler saves a single-layer artifact holding the reference it was asked for.
+type fakeBundler struct {
+	artifactType puller.ArtifactType
+	saved        []string
+	credentials  map[string]auth.Credential
+}
+
+func (b *fakeBundler) Save(ctx context.Context, ref string, creds auth.CredentialFunc, _ *puller.RegistryOptions,
+	dst oras.Target) (*puller.RegistryResult, error) {
+	b.saved = append(b.saved, ref)
+	credential, err := creds(ctx, "ghcr.io")
+	if err != nil {
+		return nil, err
+	}
+	b.credentials[ref] = credential
+
+	mediaType := puller.FalcoRulesfileLayerMediaType
+	if b.artifactType == puller.Plugin {
+		mediaType = puller.FalcoPluginLayerMediaType
+	}
+	layer := content.NewDescriptorFromBytes(mediaType, []byte(ref))
+	if err := dst.Push(ctx, layer, bytes.NewReader([]byte(ref))); err != nil {
+		return nil, err
+	}
+	manifest, err := oras.PackManifest(ctx, dst, oras.PackManifestVersion1_1, "application/vnd.cncf.falco.artifact",
+		oras.PackManifestOptions{Layers: []v1.Descriptor{layer}})
+	if err != nil {
+		return nil, err
+	}
+	if err := dst.Tag(ctx, manifest, ref); err != nil {
+		return nil, err
+	}
+	return &puller.RegistryResult{Digest: string(manifest.Digest), RootDigest: string(manifest.Digest), Type: b.artifactType}, nil
+}
+
+const testBundleManifests = `apiVersion: artifact.falcosecurity.dev/v1alpha1
+kind: Rulesfile
+metadata:
+  name: falco-rules
+spec:
+  ociArtifact:
+    image:
+      repository: falcosecurity/rules/falco-rules
+      tag: "3"
+---
+apiVersion: artifact.falcosecurity.dev/v1alpha1
+kind: Rulesfile
+metadata:
+  name: falco-rules-again
+spec:
+  ociArtifact:
+    image:
+      repository: falcosecurity/rules/falco-rules
+      tag: "3"
+---
+apiVersion: artifact.falcosecurity.dev/v1alpha1
+kind: Rulesfile
+metadata:
+  name: private-rules
+spec:
+  ociArtifact:
+    image:
+      repository: acme/rules
+      tag: "1"
+    registry:
+      auth:
+        secretRef:
+          name: registry-auth
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: registry-auth
+stringData:
+  username: user
+  password: pass
+---
+apiVersion: artifact.falcosecurity.dev/v1alpha1
+kind: Rulesfile
+metadata:
+  name: inline-rules
+spec:
+  inlineRules:
+    - rule: test
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: ignored
+`
+
+func writeManifests(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifests.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestBundleExport(t *testing.T) {
+	t.Run("tar archive", func(t *testing.T) {
+		manifests := writeManifests(t, testBundleManifests)
+		output := filepath.Join(t.TempDir(), "bundle.tar")
+		b := &fakeBundler{artifactType: puller.Rulesfile, credentials: map[string]auth.Credential{}}
+		c := &cli{namespace: testutil.TestNamespace, out: &bytes.Buffer{}, bundler: b}
+
+		require.NoError(t, c.run(context.Background(), []string{"bundle-export", "-o", output, manifests}))
+		assert.Equal(t, []string{"ghcr.io/falcosecurity/rules/falco-rules:3", "ghcr.io/acme/rules:1"}, b.saved)
+		assert.Equal(t, auth.EmptyCredential, b.credentials["ghcr.io/falcosecurity/rules/falco-rules:3"])
+		assert.Equal(t, auth.Credential{Username: "user", Password: "pass"}, b.credentials["ghcr.io/acme/rules:1"])
+		assert.Contains(t, c.out.(*bytes.Buffer).String(), "Wrote 2 artifact(s) to "+output+".\n")
+
+		var pulled bytes.Buffer
+		res, err := puller.NewOciPuller(nil).Pull(context.Background(), "ghcr.io/acme/rules:1", "linux", "amd64", nil,
+			&puller.RegistryOptions{LayoutPath: output}, &pulled)
+		require.NoError(t, err)
+		assert.Equal(t, puller.Rulesfile, res.Type)
+		assert.Equal(t, "ghcr.io/acme/rules:1", pulled.String())
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		manifests := writeManifests(t, testBundleManifests)
+		output := filepath.Join(t.TempDir(), "bundle")
+		b := &fakeBundler{artifactType: puller.Rulesfile, credentials: map[string]auth.Credential{}}
+		c := &cli{namespace: testutil.TestNamespace, out: &bytes.Buffer{}, bundler: b}
+
+		require.NoError(t, c.run(context.Background(), []string{"bundle-export", "-o", output, manifests}))
+		assert.FileExists(t, filepath.Join(output, "index.json"))
+		assert.FileExists(t, filepath.Join(output, "oci-layout"))
+	})
+
+	t.Run("pull secret from the cluster", func(t *testing.T) {
+		manifests := writeManifests(t, `apiVersion: artifact.falcosecurity.dev/v1alpha1
+kind: Plugin
+metadata:
+  name: k8smeta
+spec:
+  ociArtifact:
+    image:
+      repository: falcosecurity/plugins/plugin/k8smeta
+    registry:
+      auth:
+        secretRef:
+          name: registry-auth
+`)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-auth", Namespace: testutil.TestNamespace},
+			Data: map[string][]byte{
+				commonv1alpha1.SecretUsernameKey: []byte("cluster-user"),
+				commonv1alpha1.SecretPasswordKey: []byte("cluster-pass"),
+			},
+		}
+		c, _, _ := newTestCLI(t, secret)
+		b := &fakeBundler{artifactType: puller.Plugin, credentials: map[string]auth.Credential{}}
+		c.bundler = b
+
+		require.NoError(t, c.run(context.Background(), []string{"bundle-export", "-o", filepath.Join(t.TempDir(), "b.tar"), manifests}))
+		assert.Equal(t, auth.Credential{Username: "cluster-user", Password: "cluster-pass"},
+			b.credentials["ghcr.io/falcosecurity/plugins/plugin/k8smeta:latest"])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			manifests string
+			bundler   *fakeBundler
+			wantErr   string
+		}{
+			{
+				name:      "no OCI artifact",
+				manifests: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+				wantErr:   "no Plugin or Rulesfile with an OCI artifact found in the manifests",
+			},
+			{
+				name:      "type mismatch",
+				manifests: testBundleManifests,
+				bundler:   &fakeBundler{artifactType: puller.Plugin},
+				wantErr:   `saving Rulesfile/falco-rules: OCI artifact type "plugin" does not match expected type "rulesfile"`,
+			},
+			{
+				name: "missing pull secret without cluster",
+				manifests: `apiVersion: artifact.falcosecurity.dev/v1alpha1
+kind: Rulesfile
+metadata:
+  name: private-rules
+spec:
+  ociArtifact:
+    image:
+      repository: acme/rules
+    registry:
+      auth:
+        secretRef:
+          name: registry-auth
+`,
+				wantErr: "pull secret registry-auth of Rulesfile/private-rules is not in the manifests and the cluster is not reachable",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				b := tt.bundler
+				if b == nil {
+					b = &fakeBundler{artifactType: puller.Rulesfile}
+				}
+				b.credentials = map[string]auth.Credential{}
+				c := &cli{namespace: testutil.TestNamespace, out: &bytes.Buffer{}, bundler: b, clientErr: assert.AnError}
+				err := c.run(context.Background(), []string{"bundle-export", "-o", filepath.Join(t.TempDir(), "b.tar"),
+					writeManifests(t, tt.manifests)})
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
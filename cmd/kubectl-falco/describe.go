@@ -0,0 +1,96 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+// describeNode prints every ArtifactNode of a node with its conditions, installed files and effective configuration.
+func (c *cli) describeNode(ctx context.Context, args []string) error {
+	if err := expectArgs("describe-node", args, "<node>"); err != nil {
+		return err
+	}
+	nodeName := args[0]
+
+	nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+	if err := c.client.List(ctx, nodeObjects, client.InNamespace(c.namespace),
+		client.MatchingLabels{controllerhelper.LabelArtifactNode: nodeName}); err != nil {
+		return fmt.Errorf("listing ArtifactNodes: %w", err)
+	}
+	if len(nodeObjects.Items) == 0 {
+		fmt.Fprintf(c.out, "No ArtifactNodes found for node %s in namespace %s.\n", nodeName, c.namespace)
+		return nil
+	}
+	sort.Slice(nodeObjects.Items, func(i, j int) bool { return nodeObjects.Items[i].Name < nodeObjects.Items[j].Name })
+
+	fmt.Fprintf(c.out, "Node: %s\n", nodeName)
+	for i := range nodeObjects.Items {
+		if err := describeNodeObject(c.out, &nodeObjects.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeNodeObject prints a single ArtifactNode.
+func describeNodeObject(out io.Writer, nodeObject *artifactv1alpha1.ArtifactNode) error {
+	labels := nodeObject.Labels
+	fmt.Fprintf(out, "\n%s/%s (ArtifactNode %s)\n", labels[controllerhelper.LabelArtifactKind],
+		labels[controllerhelper.LabelArtifactParent], nodeObject.Name)
+	fmt.Fprintf(out, "  Allowed generation: %d\n", nodeObject.Spec.AllowedGeneration)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if len(nodeObject.Status.Conditions) > 0 {
+		fmt.Fprintln(w, "  Conditions:")
+		fmt.Fprintln(w, "    TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, condition := range nodeObject.Status.Conditions {
+			fmt.Fprintf(w, "    %s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+	if len(nodeObject.Status.InstalledArtifacts) > 0 {
+		fmt.Fprintln(w, "  Installed files:")
+		fmt.Fprintln(w, "    MEDIUM\tPRIORITY\tPATH\tCONTENT HASH\tSPEC HASH")
+		for _, installed := range nodeObject.Status.InstalledArtifacts {
+			specHash := installed.SpecHash
+			if specHash == "" {
+				specHash = "-"
+			}
+			fmt.Fprintf(w, "    %s\t%d\t%s\t%s\t%s\n", installed.Medium, installed.Priority, installed.Path,
+				installed.ContentHash, specHash)
+			if installed.Config != nil {
+				fmt.Fprintf(w, "    %s\t%d\t%s\t-\t-\n", installed.Medium, installed.Priority, installed.Config.Path)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if effective := nodeObject.Status.EffectiveConfig; effective != nil {
+		fmt.Fprintf(out, "  Effective config: %s (hash %s)\n", effective.ConfigMapName, effective.Hash)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+// effectiveConfig prints the effective Falco configuration published by the artifact operator of a node.
+func (c *cli) effectiveConfig(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("effective-config", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	instance := fs.String("instance", "", "The Falco instance running on the node. Required when the namespace has several.")
+	provenance := fs.Bool("provenance", false, "Print the files setting each top-level key instead of the configuration.")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	if err := expectArgs("effective-config", fs.Args(), "<node>"); err != nil {
+		return err
+	}
+	nodeName := fs.Arg(0)
+
+	instanceName, err := c.resolveInstance(ctx, *instance)
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: c.namespace, Name: controllerhelper.EffectiveConfigMapName(instanceName, nodeName)}
+	if err := c.client.Get(ctx, key, configMap); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("no effective configuration published for Falco %s on node %s", instanceName, nodeName)
+		}
+		return err
+	}
+
+	dataKey := resources.FalcoDefaults.ConfigMapVolume.SubPath
+	if *provenance {
+		dataKey = controllerhelper.EffectiveConfigProvenanceKey
+	}
+	fmt.Fprint(c.out, configMap.Data[dataKey])
+	return nil
+}
+
+// resolveInstance returns name, or the only Falco instance of the namespace when name is empty.
+func (c *cli) resolveInstance(ctx context.Context, name string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	falcos := &instancev1alpha1.FalcoList{}
+	if err := c.client.List(ctx, falcos, client.InNamespace(c.namespace)); err != nil {
+		return "", fmt.Errorf("listing Falco instances: %w", err)
+	}
+	switch len(falcos.Items) {
+	case 0:
+		return "", fmt.Errorf("no Falco instance found in namespace %s", c.namespace)
+	case 1:
+		return falcos.Items[0].Name, nil
+	default:
+		names := make([]string, 0, len(falcos.Items))
+		for i := range falcos.Items {
+			names = append(names, falcos.Items[i].Name)
+		}
+		return "", fmt.Errorf("%w: several Falco instances found in namespace %s, select one with --instance: %s",
+			errUsage, c.namespace, strings.Join(names, ", "))
+	}
+}
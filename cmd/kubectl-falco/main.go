@@ -0,0 +1,177 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+	utilruntime.Must(artifactv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(instancev1alpha1.AddToScheme(scheme))
+}
+
+// errUsage is returned when the command line is invalid.
+var errUsage = errors.New("invalid usage")
+
+const usage = `Usage: kubectl falco [flags] <command> [args]
+
+Commands:
+  status
+      Show the Falco instances and artifacts with their per-node programmed counts.
+  describe-node <node>
+      Show every ArtifactNode of a node with its conditions, installed files and digests.
+  effective-config [--instance <falco>] [--provenance] <node>
+      Print the effective Falco configuration of a node, or the files setting each top-level key.
+  why-not-applied <kind/name> <node>
+      Explain why an artifact is not applied on a node: selectors, Falco pods and rollout gate.
+  pull-test [--os <os>] [--arch <arch>] <kind/name>
+      Pull the OCI artifact of a Plugin or Rulesfile with its registry options and credentials.
+  bundle-export [-o <path>] <manifest>...
+      Save the OCI artifacts of the Plugins and Rulesfiles of manifest files into an OCI image layout,
+      a tar archive by default, to install them in a disconnected cluster with ociArtifact.ociLayout.
+
+Flags:
+`
+
+func main() {
+	var namespace string
+
+	flag.StringVar(&namespace, "namespace", "", "The namespace of the Falco instances and artifacts. "+
+		"Defaults to the namespace of the current kubeconfig context.")
+	flag.StringVar(&namespace, "n", "", "Shorthand for --namespace.")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	ociPuller := puller.NewOciPuller(nil)
+	c := &cli{namespace: namespace, out: os.Stdout, puller: ociPuller, bundler: ociPuller}
+	c.client, c.clientErr = newClient(&c.namespace)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := c.run(ctx, flag.Args())
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		if errors.Is(err, errUsage) {
+			flag.Usage()
+		}
+		os.Exit(1)
+	}
+}
+
+// newClient creates a client from the kubeconfig, defaulting namespace to the namespace of its current context.
+func newClient(namespace *string) (client.Client, error) {
+	if *namespace == "" {
+		var err error
+		if *namespace, err = defaultNamespace(); err != nil {
+			return nil, fmt.Errorf("unable to determine the namespace: %w", err)
+		}
+	}
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the kubeconfig: %w", err)
+	}
+	cl, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the client: %w", err)
+	}
+	return cl, nil
+}
+
+// defaultNamespace returns the namespace of the current kubeconfig context, honoring the --kubeconfig flag.
+func defaultNamespace() (string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if f := flag.Lookup(config.KubeconfigFlagName); f != nil {
+		rules.ExplicitPath = f.Value.String()
+	}
+	namespace, _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).Namespace()
+	return namespace, err
+}
+
+// cli runs the kubectl-falco commands against a cluster.
+type cli struct {
+	// client is nil when the cluster is not reachable, clientErr telling why.
+	client    client.Client
+	clientErr error
+	namespace string
+	out       io.Writer
+	puller    puller.Puller
+	bundler   bundler
+}
+
+// run dispatches args to the command they name.
+func (c *cli) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+	command, args := args[0], args[1:]
+	// Exporting a bundle from manifest files only needs the cluster for the pull secrets missing from them.
+	if c.client == nil && command != "bundle-export" {
+		return c.clientErr
+	}
+	switch command {
+	case "status":
+		return c.status(ctx, args)
+	case "describe-node":
+		return c.describeNode(ctx, args)
+	case "effective-config":
+		return c.effectiveConfig(ctx, args)
+	case "why-not-applied":
+		return c.whyNotApplied(ctx, args)
+	case "pull-test":
+		return c.pullTest(ctx, args)
+	case "bundle-export":
+		return c.bundleExport(ctx, args)
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, command)
+	}
+}
+
+// expectArgs returns an error unless args holds exactly the named positional arguments.
+func expectArgs(command string, args []string, names ...string) error {
+	if len(args) != len(names) {
+		return fmt.Errorf("%w: %s expects %d argument(s): %s", errUsage, command, len(names), strings.Join(names, " "))
+	}
+	return nil
+}
@@ -0,0 +1,446 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/controllers/testutil"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
+)
+
+const testFalcoName = "falco"
+
+func newTestCLI(t *testing.T, objs ...client.Object) (*cli, *bytes.Buffer, *puller.MockOCIPuller) {
+	t.Helper()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	out := &bytes.Buffer{}
+	mock := &puller.MockOCIPuller{}
+	return &cli{client: cl, namespace: testutil.TestNamespace, out: out, puller: mock}, out, mock
+}
+
+func newTestFalco(name string, labels map[string]string) *instancev1alpha1.Falco {
+	return &instancev1alpha1.Falco{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testutil.TestNamespace, Labels: labels},
+		Status: instancev1alpha1.FalcoStatus{
+			ResourceType:      "DaemonSet",
+			Version:           "0.41.0",
+			DesiredReplicas:   2,
+			AvailableReplicas: 1,
+		},
+	}
+}
+
+func newTestPod(instance, nodeName string, phase corev1.PodPhase, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance + "-pod",
+			Namespace: testutil.TestNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/instance": instance},
+		},
+		Spec: corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			Phase:      phase,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func newTestArtifactNode(kind, parent string, opts ...func(*artifactv1alpha1.ArtifactNode)) *artifactv1alpha1.ArtifactNode {
+	n := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(kind, parent, testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+			Labels:    controllerhelper.NodeObjectLabels(kind, parent, testutil.TestNodeName),
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName},
+	}
+	for _, o := range opts {
+		o(n)
+	}
+	return n
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no command", args: nil},
+		{name: "unknown command", args: []string{"unknown"}},
+		{name: "missing node", args: []string{"describe-node"}},
+		{name: "extra argument", args: []string{"status", "extra"}},
+		{name: "malformed artifact", args: []string{"why-not-applied", "rules", testutil.TestNodeName}},
+		{name: "unknown artifact kind", args: []string{"pull-test", "falco/rules"}},
+		{name: "unknown flag", args: []string{"effective-config", "--unknown", testutil.TestNodeName}},
+		{name: "no manifest", args: []string{"bundle-export", "-o", "bundle.tar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := newTestCLI(t)
+			err := c.run(context.Background(), tt.args)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errUsage)
+		})
+	}
+}
+
+func TestRun_NoCluster(t *testing.T) {
+	c := &cli{namespace: testutil.TestNamespace, out: &bytes.Buffer{}, clientErr: errors.New("unable to load the kubeconfig")}
+	err := c.run(context.Background(), []string{"status"})
+	require.EqualError(t, err, "unable to load the kubeconfig")
+}
+
+func TestStatus(t *testing.T) {
+	falco := newTestFalco(testFalcoName, nil)
+	falco.Status.Conditions = []metav1.Condition{{Type: commonv1alpha1.ConditionAvailable.String(), Status: metav1.ConditionTrue}}
+	config := &artifactv1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: testutil.TestNamespace},
+		Status: artifactv1alpha1.ConfigStatus{
+			Conditions:  []metav1.Condition{{Type: commonv1alpha1.ConditionProgrammed.String(), Status: metav1.ConditionFalse}},
+			NodeSummary: &artifactv1alpha1.NodeSummary{Total: 3, Programmed: 2, Failed: 1},
+		},
+	}
+	rulesfile := &artifactv1alpha1.Rulesfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: testutil.TestNamespace},
+		Spec:       artifactv1alpha1.RulesfileSpec{Suspend: true},
+	}
+	c, out, _ := newTestCLI(t, falco, config, rulesfile)
+
+	require.NoError(t, c.run(context.Background(), []string{"status"}))
+	assert.Equal(t, `FALCO  TYPE       VERSION  READY  AVAILABLE
+falco  DaemonSet  0.41.0   1/2    True
+
+ARTIFACT         PROGRAMMED  NODES  FAILED  PENDING  SUSPENDED
+Config/base      False       2/3    1       0        false
+Rulesfile/rules  -           -      -       -        true
+`, out.String())
+}
+
+func TestDescribeNode(t *testing.T) {
+	t.Run("no ArtifactNodes", func(t *testing.T) {
+		c, out, _ := newTestCLI(t)
+		require.NoError(t, c.run(context.Background(), []string{"describe-node", testutil.TestNodeName}))
+		assert.Equal(t, "No ArtifactNodes found for node test-node in namespace default.\n", out.String())
+	})
+
+	t.Run("installed files and effective configuration", func(t *testing.T) {
+		nodeObject := newTestArtifactNode(controllerhelper.ArtifactKindConfig, "base", func(n *artifactv1alpha1.ArtifactNode) {
+			n.Spec.AllowedGeneration = 2
+			n.Status.Conditions = []metav1.Condition{{
+				Type: commonv1alpha1.ConditionProgrammed.String(), Status: metav1.ConditionTrue, Reason: "Programmed", Message: "ok",
+			}}
+			n.Status.InstalledArtifacts = []artifactv1alpha1.InstalledArtifact{{
+				Path: "/etc/falco/config.d/50-01-base-inline.yaml", Medium: "inline", Priority: 50, ContentHash: "abc",
+			}}
+			n.Status.EffectiveConfig = &artifactv1alpha1.EffectiveConfig{ConfigMapName: "effective-config--falco--test-node", Hash: "def"}
+		})
+		other := newTestArtifactNode(controllerhelper.ArtifactKindConfig, "other", func(n *artifactv1alpha1.ArtifactNode) {
+			n.Name = controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, "other", "other-node")
+			n.Labels = controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindConfig, "other", "other-node")
+		})
+		c, out, _ := newTestCLI(t, nodeObject, other)
+
+		require.NoError(t, c.run(context.Background(), []string{"describe-node", testutil.TestNodeName}))
+		assert.Equal(t, `Node: test-node
+
+config/base (ArtifactNode config--base--test-node)
+  Allowed generation: 2
+  Conditions:
+    TYPE        STATUS  REASON      MESSAGE
+    Programmed  True    Programmed  ok
+  Installed files:
+    MEDIUM  PRIORITY  PATH                                        CONTENT HASH  SPEC HASH
+    inline  50        /etc/falco/config.d/50-01-base-inline.yaml  abc           -
+  Effective config: effective-config--falco--test-node (hash def)
+`, out.String())
+	})
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.EffectiveConfigMapName(testFalcoName, testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+		},
+		Data: map[string]string{
+			"falco.yaml": "json_output: true\n",
+			controllerhelper.EffectiveConfigProvenanceKey: "json_output:\n- Config/base\n",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "single instance",
+			objs: []client.Object{newTestFalco(testFalcoName, nil), configMap},
+			args: []string{testutil.TestNodeName},
+			want: "json_output: true\n",
+		},
+		{
+			name: "provenance",
+			objs: []client.Object{newTestFalco(testFalcoName, nil), configMap},
+			args: []string{"--provenance", testutil.TestNodeName},
+			want: "json_output:\n- Config/base\n",
+		},
+		{
+			name: "explicit instance",
+			objs: []client.Object{newTestFalco(testFalcoName, nil), newTestFalco("other", nil), configMap},
+			args: []string{"--instance", testFalcoName, testutil.TestNodeName},
+			want: "json_output: true\n",
+		},
+		{
+			name:    "several instances",
+			objs:    []client.Object{newTestFalco(testFalcoName, nil), newTestFalco("other", nil), configMap},
+			args:    []string{testutil.TestNodeName},
+			wantErr: "several Falco instances found in namespace default, select one with --instance: falco, other",
+		},
+		{
+			name:    "no instance",
+			args:    []string{testutil.TestNodeName},
+			wantErr: "no Falco instance found in namespace default",
+		},
+		{
+			name:    "not published",
+			objs:    []client.Object{newTestFalco(testFalcoName, nil)},
+			args:    []string{testutil.TestNodeName},
+			wantErr: "no effective configuration published for Falco falco on node test-node",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, out, _ := newTestCLI(t, tt.objs...)
+			err := c.run(context.Background(), append([]string{"effective-config"}, tt.args...))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestWhyNotApplied(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testutil.TestNodeName, Labels: map[string]string{"pool": "a"}}}
+	newRulesfile := func(opts ...func(*artifactv1alpha1.Rulesfile)) *artifactv1alpha1.Rulesfile {
+		r := &artifactv1alpha1.Rulesfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: testutil.TestNamespace, Generation: 3},
+		}
+		for _, o := range opts {
+			o(r)
+		}
+		return r
+	}
+	programmed := func(n *artifactv1alpha1.ArtifactNode) {
+		n.Spec.AllowedGeneration = 3
+		n.Status.Conditions = []metav1.Condition{{Type: commonv1alpha1.ConditionProgrammed.String(), Status: metav1.ConditionTrue}}
+	}
+
+	tests := []struct {
+		name   string
+		objs   []client.Object
+		checks []check
+	}{
+		{
+			name: "applied",
+			objs: []client.Object{
+				node, newRulesfile(), newTestFalco(testFalcoName, nil),
+				newTestPod(testFalcoName, testutil.TestNodeName, corev1.PodRunning, true),
+				newTestArtifactNode(controllerhelper.ArtifactKindRulesfile, "rules", programmed),
+			},
+			checks: []check{
+				{checkPass, "the artifact is not suspended"},
+				{checkPass, "the artifact has no node selector"},
+				{checkPass, "Falco falco pod falco-pod is running and ready on node test-node"},
+				{checkPass, "the artifact has no rollout strategy, every generation applies as soon as it is observed"},
+				{checkPass, "ArtifactNode rulesfile--rules--test-node is programmed"},
+			},
+		},
+		{
+			name: "blocked",
+			objs: []client.Object{
+				node,
+				newRulesfile(func(r *artifactv1alpha1.Rulesfile) {
+					r.Spec.Suspend = true
+					r.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "b"}}
+					r.Spec.InstanceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "edge"}}
+					r.Spec.Rollout = &commonv1alpha1.Rollout{}
+				}),
+				newTestFalco(testFalcoName, nil),
+				newTestFalco("edge", map[string]string{"tier": "edge"}),
+				newTestPod("edge", testutil.TestNodeName, corev1.PodRunning, false),
+				newTestArtifactNode(controllerhelper.ArtifactKindRulesfile, "rules", func(n *artifactv1alpha1.ArtifactNode) {
+					n.Spec.AllowedGeneration = 2
+				}),
+			},
+			checks: []check{
+				{checkFail, "the artifact is suspended (spec.suspend is true)"},
+				{checkFail, `node test-node does not match the node selector "pool=b"`},
+				{checkFail, "Falco edge pod edge-pod on node test-node is running but not ready, " +
+					"its artifact operator may still be waiting on its startup gate"},
+				{checkInfo, "Falco falco is not targeted by the artifact"},
+				{checkFail, "the rollout gate is closed: node test-node is admitted to generation 2, the artifact is at generation 3"},
+				{checkFail, "ArtifactNode rulesfile--rules--test-node has no Programmed condition yet"},
+			},
+		},
+		{
+			name: "no node, no instance, no ArtifactNode",
+			objs: []client.Object{
+				newRulesfile(func(r *artifactv1alpha1.Rulesfile) {
+					r.Spec.FalcoRef = &commonv1alpha1.FalcoRef{Name: "missing"}
+					r.Spec.Rollout = &commonv1alpha1.Rollout{}
+				}),
+				newTestFalco(testFalcoName, nil),
+			},
+			checks: []check{
+				{checkPass, "the artifact is not suspended"},
+				{checkPass, "the artifact has no node selector"},
+				{checkInfo, "Falco falco is not targeted by the artifact"},
+				{checkFail, "no Falco instance of namespace default is targeted by the artifact"},
+				{checkFail, "the rollout gate is closed: no ArtifactNode tracks node test-node yet"},
+				{checkInfo, "no ArtifactNode reports the programmed state of the node"},
+			},
+		},
+		{
+			name: "Falco pod missing",
+			objs: []client.Object{
+				node, newRulesfile(), newTestFalco(testFalcoName, nil),
+				newTestPod(testFalcoName, "other-node", corev1.PodRunning, true),
+			},
+			checks: []check{
+				{checkPass, "the artifact is not suspended"},
+				{checkPass, "the artifact has no node selector"},
+				{checkFail, "Falco falco has no pod on node test-node"},
+				{checkPass, "the artifact has no rollout strategy, every generation applies as soon as it is observed"},
+				{checkInfo, "no ArtifactNode reports the programmed state of the node"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := newTestCLI(t, tt.objs...)
+			a, err := c.getArtifact(context.Background(), "rulesfiles/rules")
+			require.NoError(t, err)
+			checks, err := c.explain(context.Background(), a, testutil.TestNodeName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.checks, checks)
+		})
+	}
+
+	t.Run("node does not exist", func(t *testing.T) {
+		c, out, _ := newTestCLI(t, newRulesfile(func(r *artifactv1alpha1.Rulesfile) {
+			r.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}}
+		}))
+		require.NoError(t, c.run(context.Background(), []string{"why-not-applied", "Rulesfile/rules", testutil.TestNodeName}))
+		assert.Contains(t, out.String(), "[FAIL] node test-node does not exist\n")
+		assert.Contains(t, out.String(), "blocking reason(s) found.\n")
+	})
+}
+
+func TestPullTest(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-auth", Namespace: testutil.TestNamespace},
+		Data:       map[string][]byte{commonv1alpha1.SecretUsernameKey: []byte("user"), commonv1alpha1.SecretPasswordKey: []byte("pass")},
+	}
+	plugin := &artifactv1alpha1.Plugin{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8smeta", Namespace: testutil.TestNamespace},
+		Spec: artifactv1alpha1.PluginSpec{OCIArtifact: &commonv1alpha1.OCIArtifact{
+			Image: commonv1alpha1.ImageSpec{Repository: "falcosecurity/plugins/plugin/k8smeta", Tag: "0.3.0"},
+			Registry: &commonv1alpha1.RegistryConfig{
+				Name: "ghcr.io",
+				Auth: &commonv1alpha1.RegistryAuth{SecretRef: &commonv1alpha1.SecretRef{Name: secret.Name}},
+			},
+		}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		c, out, mock := newTestCLI(t, plugin, secret)
+		mock.Result = &puller.RegistryResult{RootDigest: "sha256:root", Digest: "sha256:leaf", Type: puller.Plugin, Filename: "libk8smeta.so"}
+		mock.LayerContent = []byte("layer")
+
+		require.NoError(t, c.run(context.Background(), []string{"pull-test", "--arch", "arm64", "plugin/k8smeta"}))
+		require.Len(t, mock.PullCalls, 1)
+		assert.Equal(t, "ghcr.io/falcosecurity/plugins/plugin/k8smeta:0.3.0", mock.PullCalls[0].Ref)
+		assert.Equal(t, "linux", mock.PullCalls[0].OS)
+		assert.Equal(t, "arm64", mock.PullCalls[0].Arch)
+		assert.Equal(t, `Reference: ghcr.io/falcosecurity/plugins/plugin/k8smeta:0.3.0
+Credentials: secret registry-auth
+Digest: sha256:leaf
+Index digest: sha256:root
+Type: plugin
+Filename: libk8smeta.so
+Layer size: 5 bytes
+Pull succeeded.
+`, out.String())
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		c, _, mock := newTestCLI(t, plugin, secret)
+		mock.Result = &puller.RegistryResult{Digest: "sha256:leaf", Type: puller.Rulesfile}
+		err := c.run(context.Background(), []string{"pull-test", "plugin/k8smeta"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `pulled OCI artifact type "rulesfile" does not match expected type "plugin"`)
+	})
+
+	t.Run("pull error", func(t *testing.T) {
+		c, _, mock := newTestCLI(t, plugin, secret)
+		mock.PullErr = errors.New("unauthorized")
+		err := c.run(context.Background(), []string{"pull-test", "plugin/k8smeta"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pull failed: unauthorized")
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		c, _, _ := newTestCLI(t, plugin)
+		err := c.run(context.Background(), []string{"pull-test", "plugin/k8smeta"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get pull secret registry-auth")
+	})
+
+	t.Run("no OCI artifact", func(t *testing.T) {
+		config := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: testutil.TestNamespace}}
+		c, _, _ := newTestCLI(t, config)
+		err := c.run(context.Background(), []string{"pull-test", "config/base"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Config/base has no OCI artifact")
+	})
+}
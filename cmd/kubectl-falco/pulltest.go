@@ -0,0 +1,107 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/credentials"
+	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
+)
+
+// byteCounter is an io.Writer discarding what it is written while counting it.
+type byteCounter int64
+
+func (b *byteCounter) Write(p []byte) (int, error) {
+	*b += byteCounter(len(p))
+	return len(p), nil
+}
+
+// pullTest pulls the OCI artifact of a Plugin or Rulesfile with the registry options and credentials of the resource,
+// as the artifact operator does, and reports what the registry returned.
+func (c *cli) pullTest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pull-test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	goos := fs.String("os", "linux", "The operating system of the platform to pull from multi-platform artifacts.")
+	arch := fs.String("arch", runtime.GOARCH, "The architecture of the platform to pull from multi-platform artifacts.")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	if err := expectArgs("pull-test", fs.Args(), "<kind/name>"); err != nil {
+		return err
+	}
+	a, err := c.getArtifact(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if a.ociArtifact == nil {
+		return fmt.Errorf("%s/%s has no OCI artifact", a.kind, a.object.GetName())
+	}
+
+	var secret *corev1.Secret
+	if ref := artifact.AuthSecretRef(a.ociArtifact); ref != nil {
+		secret = &corev1.Secret{}
+		if err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: ref.Name}, secret); err != nil {
+			return fmt.Errorf("failed to get pull secret %s: %w", ref.Name, err)
+		}
+	}
+	creds, err := credentials.FromSecret(artifact.ResolveRegistryHost(a.ociArtifact), secret)
+	if err != nil {
+		return fmt.Errorf("unable to derive credentials from pull secret: %w", err)
+	}
+
+	ref := artifact.ResolveReference(a.ociArtifact)
+	fmt.Fprintf(c.out, "Reference: %s\n", ref)
+	if secret != nil {
+		fmt.Fprintf(c.out, "Credentials: secret %s\n", secret.Name)
+	} else {
+		fmt.Fprintln(c.out, "Credentials: anonymous")
+	}
+
+	var size byteCounter
+	res, err := c.puller.Pull(ctx, ref, *goos, *arch, creds, artifact.ResolveRegistryOptions(a.ociArtifact), &size)
+	if err != nil {
+		return fmt.Errorf("pull failed: %w", err)
+	}
+	if res == nil {
+		return fmt.Errorf("puller returned nil result for reference %q", ref)
+	}
+	fmt.Fprintf(c.out, "Digest: %s\n", res.Digest)
+	if res.RootDigest != res.Digest {
+		fmt.Fprintf(c.out, "Index digest: %s\n", res.RootDigest)
+	}
+	fmt.Fprintf(c.out, "Type: %s\nFilename: %s\nLayer size: %d bytes\n", res.Type, res.Filename, size)
+
+	expected := puller.Rulesfile
+	if a.artifactKind == controllerhelper.ArtifactKindPlugin {
+		expected = puller.Plugin
+	}
+	if res.Type != expected {
+		return fmt.Errorf("pulled OCI artifact type %q does not match expected type %q", res.Type, expected)
+	}
+	fmt.Fprintln(c.out, "Pull succeeded.")
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+)
+
+// status prints the Falco instances and the artifacts of the namespace. The per-node counts come from the node
+// summary the instance operator maintains, and are "-" for artifacts without one.
+func (c *cli) status(ctx context.Context, args []string) error {
+	if err := expectArgs("status", args); err != nil {
+		return err
+	}
+
+	falcos := &instancev1alpha1.FalcoList{}
+	if err := c.client.List(ctx, falcos, client.InNamespace(c.namespace)); err != nil {
+		return fmt.Errorf("listing Falco instances: %w", err)
+	}
+	artifacts, err := c.listArtifacts(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FALCO\tTYPE\tVERSION\tREADY\tAVAILABLE")
+	for i := range falcos.Items {
+		falco := &falcos.Items[i]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%s\n", falco.Name, falco.Status.ResourceType, falco.Status.Version,
+			falco.Status.AvailableReplicas, falco.Status.DesiredReplicas,
+			conditionStatus(falco.Status.Conditions, commonv1alpha1.ConditionAvailable))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "ARTIFACT\tPROGRAMMED\tNODES\tFAILED\tPENDING\tSUSPENDED")
+	for i := range artifacts {
+		a := &artifacts[i]
+		nodes, failed, pending := "-", "-", "-"
+		if s := a.nodeSummary; s != nil {
+			nodes = fmt.Sprintf("%d/%d", s.Programmed, s.Total)
+			failed = fmt.Sprint(s.Failed)
+			pending = fmt.Sprint(s.Pending)
+		}
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\t%s\t%t\n", a.kind, a.object.GetName(),
+			conditionStatus(a.conditions, commonv1alpha1.ConditionProgrammed), nodes, failed, pending, a.suspend)
+	}
+	return w.Flush()
+}
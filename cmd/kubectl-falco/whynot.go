@@ -0,0 +1,243 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+// checkResult is the outcome of a single why-not-applied check.
+type checkResult string
+
+const (
+	checkPass checkResult = "ok"
+	checkFail checkResult = "FAIL"
+	checkInfo checkResult = "--"
+)
+
+// check is a single step of the explanation of why-not-applied.
+type check struct {
+	result  checkResult
+	message string
+}
+
+// whyNotApplied explains why an artifact is not applied on a node by walking through everything the artifact
+// operator of the node checks before applying it.
+func (c *cli) whyNotApplied(ctx context.Context, args []string) error {
+	if err := expectArgs("why-not-applied", args, "<kind/name>", "<node>"); err != nil {
+		return err
+	}
+	a, err := c.getArtifact(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	nodeName := args[1]
+
+	checks, err := c.explain(ctx, a, nodeName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.out, "Artifact: %s/%s (generation %d)\nNode: %s\n\n", a.kind, a.object.GetName(), a.object.GetGeneration(), nodeName)
+	failed := 0
+	for _, ch := range checks {
+		fmt.Fprintf(c.out, "[%-4s] %s\n", ch.result, ch.message)
+		if ch.result == checkFail {
+			failed++
+		}
+	}
+	if failed == 0 {
+		fmt.Fprintf(c.out, "\nNo blocking reason found: %s/%s applies to node %s.\n", a.kind, a.object.GetName(), nodeName)
+	} else {
+		fmt.Fprintf(c.out, "\n%d blocking reason(s) found.\n", failed)
+	}
+	return nil
+}
+
+// explain runs the why-not-applied checks of artifact a on nodeName.
+func (c *cli) explain(ctx context.Context, a artifactInfo, nodeName string) ([]check, error) {
+	var checks []check
+
+	if a.suspend {
+		checks = append(checks, check{checkFail, "the artifact is suspended (spec.suspend is true)"})
+	} else {
+		checks = append(checks, check{checkPass, "the artifact is not suspended"})
+	}
+
+	nodeCheck, err := c.checkNodeSelector(ctx, a, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	checks = append(checks, nodeCheck)
+
+	instanceChecks, err := c.checkInstances(ctx, a, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	checks = append(checks, instanceChecks...)
+
+	nodeObject := &artifactv1alpha1.ArtifactNode{}
+	key := client.ObjectKey{Namespace: c.namespace, Name: controllerhelper.NodeObjectName(a.artifactKind, a.object.GetName(), nodeName)}
+	if err := c.client.Get(ctx, key, nodeObject); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("fetching ArtifactNode %s: %w", key.Name, err)
+		}
+		nodeObject = nil
+	}
+
+	gateCheck, err := c.checkRolloutGate(ctx, a, nodeName, nodeObject)
+	if err != nil {
+		return nil, err
+	}
+	checks = append(checks, gateCheck)
+	return append(checks, checkProgrammed(nodeObject)), nil
+}
+
+// checkNodeSelector checks that the node exists and matches the node selector of the artifact.
+func (c *cli) checkNodeSelector(ctx context.Context, a artifactInfo, nodeName string) (check, error) {
+	matches, err := controllerhelper.NodeMatchesSelector(ctx, c.client, nodeName, a.target.NodeSelector)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return check{checkFail, fmt.Sprintf("node %s does not exist", nodeName)}, nil
+		}
+		return check{}, err
+	}
+	if a.target.NodeSelector == nil {
+		return check{checkPass, "the artifact has no node selector"}, nil
+	}
+	selector := metav1.FormatLabelSelector(a.target.NodeSelector)
+	if !matches {
+		return check{checkFail, fmt.Sprintf("node %s does not match the node selector %q", nodeName, selector)}, nil
+	}
+	return check{checkPass, fmt.Sprintf("node %s matches the node selector %q", nodeName, selector)}, nil
+}
+
+// checkInstances checks that the artifact targets at least one Falco instance, and that every targeted instance has a
+// running and ready pod on the node. A pod stays unready until the startup gate of its artifact operator opens.
+func (c *cli) checkInstances(ctx context.Context, a artifactInfo, nodeName string) ([]check, error) {
+	falcos := &instancev1alpha1.FalcoList{}
+	if err := c.client.List(ctx, falcos, client.InNamespace(c.namespace)); err != nil {
+		return nil, fmt.Errorf("listing Falco instances: %w", err)
+	}
+
+	var checks []check
+	for i := range falcos.Items {
+		falco := &falcos.Items[i]
+		if !a.target.InstanceTargeted(falco.Name, falco.Labels) {
+			checks = append(checks, check{checkInfo, fmt.Sprintf("Falco %s is not targeted by the artifact", falco.Name)})
+			continue
+		}
+		podCheck, err := c.checkFalcoPod(ctx, falco.Name, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, podCheck)
+	}
+	if !hasTargetedInstance(checks) {
+		checks = append(checks, check{checkFail, fmt.Sprintf("no Falco instance of namespace %s is targeted by the artifact", c.namespace)})
+	}
+	return checks, nil
+}
+
+func hasTargetedInstance(checks []check) bool {
+	for _, ch := range checks {
+		if ch.result != checkInfo {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFalcoPod checks that the Falco instance has a running and ready pod on the node.
+func (c *cli) checkFalcoPod(ctx context.Context, instance, nodeName string) (check, error) {
+	pods := &corev1.PodList{}
+	if err := c.client.List(ctx, pods, client.InNamespace(c.namespace),
+		client.MatchingLabels{"app.kubernetes.io/instance": instance}); err != nil {
+		return check{}, fmt.Errorf("listing pods of Falco %s: %w", instance, err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return check{checkFail, fmt.Sprintf("Falco %s pod %s on node %s is %s", instance, pod.Name, nodeName, pod.Status.Phase)}, nil
+		}
+		if !podReady(pod) {
+			return check{checkFail, fmt.Sprintf("Falco %s pod %s on node %s is running but not ready, "+
+				"its artifact operator may still be waiting on its startup gate", instance, pod.Name, nodeName)}, nil
+		}
+		return check{checkPass, fmt.Sprintf("Falco %s pod %s is running and ready on node %s", instance, pod.Name, nodeName)}, nil
+	}
+	return check{checkFail, fmt.Sprintf("Falco %s has no pod on node %s", instance, nodeName)}, nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// checkRolloutGate checks that the rollout of the artifact admitted the node to its current generation.
+func (c *cli) checkRolloutGate(ctx context.Context, a artifactInfo, nodeName string, nodeObject *artifactv1alpha1.ArtifactNode) (check, error) {
+	admitted, err := controllerhelper.RolloutAdmitted(ctx, c.client, a.artifactKind, a.object, a.rollout, nodeName)
+	if err != nil {
+		return check{}, err
+	}
+	switch {
+	case a.rollout == nil:
+		return check{checkPass, "the artifact has no rollout strategy, every generation applies as soon as it is observed"}, nil
+	case admitted:
+		return check{checkPass, fmt.Sprintf("the rollout admitted node %s to generation %d", nodeName, a.object.GetGeneration())}, nil
+	case nodeObject == nil:
+		return check{checkFail, fmt.Sprintf("the rollout gate is closed: no ArtifactNode tracks node %s yet", nodeName)}, nil
+	default:
+		return check{checkFail, fmt.Sprintf("the rollout gate is closed: node %s is admitted to generation %d, the artifact is at generation %d",
+			nodeName, nodeObject.Spec.AllowedGeneration, a.object.GetGeneration())}, nil
+	}
+}
+
+// checkProgrammed reports the Programmed condition the artifact operator of the node recorded on the ArtifactNode.
+func checkProgrammed(nodeObject *artifactv1alpha1.ArtifactNode) check {
+	if nodeObject == nil {
+		return check{checkInfo, "no ArtifactNode reports the programmed state of the node"}
+	}
+	condition := apimeta.FindStatusCondition(nodeObject.Status.Conditions, commonv1alpha1.ConditionProgrammed.String())
+	switch {
+	case condition == nil:
+		return check{checkFail, fmt.Sprintf("ArtifactNode %s has no Programmed condition yet", nodeObject.Name)}
+	case condition.Status != metav1.ConditionTrue:
+		return check{checkFail, fmt.Sprintf("ArtifactNode %s is not programmed: %s: %s", nodeObject.Name, condition.Reason, condition.Message)}
+	default:
+		return check{checkPass, fmt.Sprintf("ArtifactNode %s is programmed", nodeObject.Name)}
+	}
+}
@@ -19,7 +19,11 @@ package config
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -28,18 +32,22 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
 	"github.com/falcosecurity/falco-operator/internal/pkg/startupgate"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 const (
@@ -53,7 +61,7 @@ func NewConfigReconciler(
 	scheme *runtime.Scheme,
 	recorder events.EventRecorder,
 	gate startupgate.Recorder,
-	nodeName, namespace string,
+	nodeName, namespace, instanceName string,
 ) *ConfigReconciler {
 	return &ConfigReconciler{
 		Client:          cl,
@@ -63,7 +71,9 @@ func NewConfigReconciler(
 		finalizer:       common.FormatFinalizerName(configFinalizerPrefix, nodeName),
 		artifactManager: artifact.NewManager(cl, namespace),
 		nodeName:        nodeName,
+		instance:        client.ObjectKey{Namespace: namespace, Name: instanceName},
 		namespace:       namespace,
+		startedAt:       time.Now(),
 	}
 }
 
@@ -76,7 +86,11 @@ type ConfigReconciler struct {
 	finalizer       string
 	artifactManager *artifact.Manager
 	nodeName        string
-	namespace       string
+	// instance is the Falco instance whose pods run this artifact operator.
+	instance  client.ObjectKey
+	namespace string
+	// startedAt is the start time of this artifact operator, and thus of the Falco pod it runs in.
+	startedAt time.Time
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -96,11 +110,32 @@ func (r *ConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ c
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Report the effective configuration of this node once the local files are up to date, whatever the outcome.
+	defer func() {
+		if err := r.ensureEffectiveConfig(ctx); err != nil {
+			logger.Error(err, "unable to report effective configuration")
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// While suspended, leave the local files as they are and only report the suspension.
+	// A suspended Config does not hold back the startup gate.
+	if config.Spec.Suspend && config.DeletionTimestamp.IsZero() {
+		logger.V(2).Info("Config instance is suspended, skipping local resources")
+		r.gate.Forget(startupgate.KindConfig, config.Namespace, config.Name)
+		artifact.RecordSuspended(r.recorder, config, &config.Status.Conditions, true)
+		return ctrl.Result{}, r.patchStatus(ctx, config)
+	}
+
 	// Check if the Config instance is for the current node.
-	if ok, err := controllerhelper.NodeMatchesSelector(ctx, r.Client, r.nodeName, config.Spec.Selector); err != nil {
+	if ok, err := controllerhelper.TargetMatches(ctx, r.Client, r.nodeName, r.instance, controllerhelper.Target{
+		NodeSelector:     config.Spec.Selector,
+		FalcoRef:         config.Spec.FalcoRef,
+		InstanceSelector: config.Spec.InstanceSelector,
+	}); err != nil {
 		return ctrl.Result{}, err
 	} else if !ok {
-		logger.Info("Config instance does not match node selector, will remove local resources if any")
+		logger.Info("Config instance does not target this node or Falco instance, will remove local resources if any")
 		r.gate.Forget(startupgate.KindConfig, config.Namespace, config.Name)
 
 		// Handle case where config selector no longer matches the node.
@@ -124,17 +159,52 @@ func (r *ConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ c
 		return ctrl.Result{}, err
 	}
 
+	// With a rollout strategy, keep the files of the previous generation until the instance operator
+	// admits this node to the current one.
+	if ok, err := controllerhelper.RolloutAdmitted(
+		ctx, r.Client, controllerhelper.ArtifactKindConfig, config, config.Spec.Rollout, r.nodeName,
+	); err != nil {
+		return ctrl.Result{}, err
+	} else if !ok {
+		logger.Info("Config generation not admitted on this node yet, keeping local resources", "generation", config.Generation)
+		r.gate.Forget(startupgate.KindConfig, config.Namespace, config.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// Continue the aggregator's trace for this generation, so that programming the node shows up
+	// in the same trace as the aggregation that scheduled it.
+	if remoteCtx, ok := controllerhelper.NodeObjectTraceContext(ctx, r.Client, config, controllerhelper.ArtifactKindConfig, r.nodeName); ok {
+		var span trace.Span
+		ctx, span = tracing.StartLinked(remoteCtx, ctx, "Program node", attribute.String("k8s.node.name", r.nodeName))
+		defer func() { tracing.End(span, reterr) }()
+	}
+
 	defer r.gate.MarkReconciled(startupgate.KindConfig, config.Namespace, config.Name, config.Generation)
 
-	// Patch status via defer to ensure it's always called.
+	artifact.RecordSuspended(r.recorder, config, &config.Status.Conditions, false)
+
+	// Patch status via defer to ensure it's always called. Every path below sets the Programmed condition,
+	// which is also reported on the ArtifactNode of this node for the rollout to follow.
 	defer func() {
 		patchErr := r.patchStatus(ctx, config)
 		if patchErr != nil {
 			logger.Error(patchErr, "unable to patch status")
 		}
-		reterr = kerrors.NewAggregate([]error{reterr, patchErr})
+		syncErr := controllerhelper.SyncNodeProgrammed(ctx, r.Client, r.Scheme, controllerhelper.ArtifactKindConfig,
+			config, config.Status.Conditions, r.nodeName, fieldManager)
+		if syncErr != nil {
+			logger.Error(syncErr, "unable to report Programmed condition on ArtifactNode")
+		}
+		reterr = kerrors.NewAggregate([]error{reterr, patchErr, syncErr})
 	}()
 
+	// Install the sources of a previous revision while a rollback is requested.
+	if _, err := controllerhelper.ApplyRollback(
+		ctx, r.Client, controllerhelper.ArtifactKindConfig, config, &config.Status.Conditions,
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Enforce reference resolution.
 	if err := r.enforceReferenceResolution(ctx, config); err != nil {
 		return ctrl.Result{}, err
@@ -152,12 +222,36 @@ func (r *ConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ c
 func (r *ConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&artifactv1alpha1.Config{}).
+		Watches(
+			&instancev1alpha1.Falco{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, falco client.Object) []reconcile.Request {
+				return controllerhelper.EnqueueAllOfType(ctx, r.Client, &artifactv1alpha1.ConfigList{}, client.InNamespace(falco.GetNamespace()))
+			}),
+			builder.WithPredicates(controllerhelper.InstanceLabelsChangedPredicate(r.instance)),
+		).
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(r.findConfigsForConfigMap),
 		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findConfigsForEffectiveConfig),
+		).
+		Watches(
+			&artifactv1alpha1.Plugin{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, plugin client.Object) []reconcile.Request {
+				return controllerhelper.EnqueueAllOfType(ctx, r.Client, &artifactv1alpha1.ConfigList{}, client.InNamespace(plugin.GetNamespace()))
+			}),
+		).
+		Watches(
+			&artifactv1alpha1.ArtifactNode{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &artifactv1alpha1.Config{}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetLabels()[controllerhelper.LabelArtifactNode] == r.nodeName
+			})),
+		).
 		Named("artifact-config").
-		Complete(r)
+		Complete(tracing.Reconciler(controllerhelper.KindConfig, r))
 }
 
 // findConfigsForConfigMap finds all Configs that reference a given ConfigMap using the index.
@@ -225,6 +319,10 @@ func (r *ConfigReconciler) ensureConfig(ctx context.Context, config *artifactv1a
 	logger := log.FromContext(ctx)
 	p := config.Spec.Priority
 
+	// Keep the previously stored content around to classify the change once the new one is written.
+	prevInline := r.artifactManager.Content(config.Name, artifact.MediumInline)
+	prevConfigMap := r.artifactManager.Content(config.Name, artifact.MediumConfigMap)
+
 	// Store inline config if specified.
 	// spec.config is stored as JSON by the API server; convert to YAML before writing to disk.
 	configData, err := common.JSONRawToYAML(config.Spec.Config)
@@ -268,7 +366,29 @@ func (r *ConfigReconciler) ensureConfig(ctx context.Context, config *artifactv1a
 	apimeta.SetStatusCondition(&config.Status.Conditions, common.NewProgrammedCondition(
 		metav1.ConditionTrue, artifact.ReasonProgrammed, artifact.MessageProgrammed, gen,
 	))
-	return nil
+
+	restartKeys := append(
+		r.restartRequiredKeys(ctx, config.Name, artifact.MediumInline, prevInline),
+		r.restartRequiredKeys(ctx, config.Name, artifact.MediumConfigMap, prevConfigMap)...,
+	)
+	slices.Sort(restartKeys)
+	return controllerhelper.SyncRestartRequired(ctx, r.Client, r.Scheme, controllerhelper.ArtifactKindConfig,
+		config, r.nodeName, slices.Compact(restartKeys), r.startedAt, fieldManager)
+}
+
+// restartRequiredKeys returns the keys Falco cannot hot-reload that differ between previous and the content
+// now stored for medium. Changes written before the startup gate opens are loaded by Falco when it starts,
+// so they never require a restart.
+func (r *ConfigReconciler) restartRequiredKeys(ctx context.Context, name string, medium artifact.Medium, previous []byte) []string {
+	if !r.gate.Ready() {
+		return nil
+	}
+	keys, err := artifact.RestartRequiredKeys(previous, r.artifactManager.Content(name, medium))
+	if err != nil {
+		log.FromContext(ctx).Error(err, "unable to classify configuration change", "medium", medium)
+		return nil
+	}
+	return keys
 }
 
 // patchStatus patches the Config status using server-side apply.
@@ -38,11 +38,14 @@ import (
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/controllers/testutil"
 	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/filesystem"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
+	"github.com/falcosecurity/falco-operator/internal/pkg/priority"
 	"github.com/falcosecurity/falco-operator/internal/pkg/startupgate"
 )
 
@@ -94,10 +97,11 @@ func newTestReconciler(t *testing.T, objs ...client.Object) (*ConfigReconciler,
 func TestNewConfigReconciler(t *testing.T) {
 	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
 	cl := fake.NewClientBuilder().WithScheme(s).Build()
-	r := NewConfigReconciler(cl, s, events.NewFakeRecorder(10), startupgate.NoopGateRecorder{}, "my-node", "my-namespace")
+	r := NewConfigReconciler(cl, s, events.NewFakeRecorder(10), startupgate.NoopGateRecorder{}, "my-node", "my-namespace", "my-falco")
 
 	require.NotNil(t, r)
 	assert.Equal(t, "my-node", r.nodeName)
+	assert.Equal(t, client.ObjectKey{Namespace: "my-namespace", Name: "my-falco"}, r.instance)
 	assert.Equal(t, "my-namespace", r.namespace)
 	assert.Equal(t, common.FormatFinalizerName(configFinalizerPrefix, "my-node"), r.finalizer)
 	assert.NotNil(t, r.artifactManager)
@@ -554,6 +558,266 @@ func TestReconcile_GateInteraction(t *testing.T) {
 	}
 }
 
+func TestReconcile_Suspended(t *testing.T) {
+	config := &artifactv1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testConfigName,
+			Namespace:  testutil.TestNamespace,
+			Generation: 1,
+			Finalizers: []string{testFinalizerName()},
+		},
+		Spec: artifactv1alpha1.ConfigSpec{
+			Config:  &apiextensionsv1.JSON{Raw: []byte(testConfigJSON)},
+			Suspend: true,
+		},
+	}
+	r, cl := newTestReconciler(t, config)
+	mockFS := filesystem.NewMockFileSystem()
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace,
+		artifact.WithFS(mockFS),
+	)
+	rec := &startupgate.FakeGateRecorder{}
+	r.gate = rec
+
+	_, err := r.Reconcile(context.Background(), testutil.Request(testConfigName))
+	require.NoError(t, err)
+
+	assert.Empty(t, mockFS.WriteCalls, "no file must be written while suspended")
+	assert.Empty(t, rec.Reconciled)
+	assert.Equal(t, []startupgate.FakeGateCall{{Kind: "Config", Namespace: testutil.TestNamespace, Name: testConfigName}}, rec.Forgotten)
+	got := &artifactv1alpha1.Config{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(config), got))
+	testutil.RequireCondition(t, got.Status.Conditions, commonv1alpha1.ConditionSuspended.String(),
+		metav1.ConditionTrue, artifact.ReasonSuspended)
+
+	// Resuming reconciles the config again and clears the condition.
+	got.Spec.Suspend = false
+	require.NoError(t, cl.Update(context.Background(), got))
+
+	_, err = r.Reconcile(context.Background(), testutil.Request(testConfigName))
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, mockFS.WriteCalls)
+
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(config), got))
+	assert.Nil(t, apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionSuspended.String()))
+}
+
+func TestReconcile_RolloutNotAdmitted(t *testing.T) {
+	config := &artifactv1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testConfigName,
+			Namespace:  testutil.TestNamespace,
+			Generation: 2,
+			Finalizers: []string{testFinalizerName()},
+		},
+		Spec: artifactv1alpha1.ConfigSpec{
+			Config:  &apiextensionsv1.JSON{Raw: []byte(testConfigJSON)},
+			Rollout: &commonv1alpha1.Rollout{},
+		},
+	}
+	nodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, testConfigName, testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName, AllowedGeneration: 1},
+	}
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(config, nodeObject).
+		WithStatusSubresource(&artifactv1alpha1.Config{}, &artifactv1alpha1.ArtifactNode{}).Build()
+	r, _ := newTestReconciler(t)
+	r.Client, r.Scheme = cl, s
+	mockFS := filesystem.NewMockFileSystem()
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace, artifact.WithFS(mockFS))
+	rec := &startupgate.FakeGateRecorder{}
+	r.gate = rec
+
+	// The node is still allowed the previous generation only.
+	_, err := r.Reconcile(context.Background(), testutil.Request(testConfigName))
+	require.NoError(t, err)
+	assert.Empty(t, mockFS.WriteCalls, "no file must be written before the node is admitted")
+	assert.Equal(t, []startupgate.FakeGateCall{{Kind: "Config", Namespace: testutil.TestNamespace, Name: testConfigName}}, rec.Forgotten)
+
+	// Once admitted, the generation is programmed and reported on the ArtifactNode.
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), nodeObject))
+	nodeObject.Spec.AllowedGeneration = 2
+	require.NoError(t, cl.Update(context.Background(), nodeObject))
+
+	_, err = r.Reconcile(context.Background(), testutil.Request(testConfigName))
+	require.NoError(t, err)
+	assert.NotEmpty(t, mockFS.WriteCalls)
+
+	got := &artifactv1alpha1.ArtifactNode{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), got))
+	cond := apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionProgrammed.String())
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+}
+
+func TestReconcile_RollbackTo(t *testing.T) {
+	config := &artifactv1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testConfigName,
+			Namespace:  testutil.TestNamespace,
+			UID:        "config-uid",
+			Generation: 1,
+			Finalizers: []string{testFinalizerName()},
+		},
+		Spec: artifactv1alpha1.ConfigSpec{Config: &apiextensionsv1.JSON{Raw: []byte(testConfigJSON)}},
+	}
+	r, cl := newTestReconciler(t, config)
+	ctx := context.Background()
+
+	// The instance operator recorded the current sources as revision 1, then the config changed.
+	_, _, err := controllerhelper.EnsureRevision(ctx, cl, r.Scheme, controllerhelper.ArtifactKindConfig, config)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(config), config))
+	config.Spec.Config = &apiextensionsv1.JSON{Raw: []byte(`{"engine":{"kind":"kmod"}}`)}
+	config.Spec.RollbackTo = &commonv1alpha1.RollbackConfig{Revision: 1}
+	require.NoError(t, cl.Update(ctx, config))
+
+	_, err = r.Reconcile(ctx, testutil.Request(testConfigName))
+	require.NoError(t, err)
+	assert.Equal(t, testConfigYAML, string(r.artifactManager.Content(testConfigName, artifact.MediumInline)))
+
+	got := &artifactv1alpha1.Config{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(config), got))
+	testutil.RequireCondition(t, got.Status.Conditions, commonv1alpha1.ConditionRolledBack.String(),
+		metav1.ConditionTrue, artifact.ReasonRolledBack)
+	assert.Equal(t, `{"engine":{"kind":"kmod"}}`, string(got.Spec.Config.Raw), "the rollback must not change the spec")
+
+	// Without rollbackTo, the current sources are installed again.
+	got.Spec.RollbackTo = nil
+	require.NoError(t, cl.Update(ctx, got))
+	_, err = r.Reconcile(ctx, testutil.Request(testConfigName))
+	require.NoError(t, err)
+	assert.Equal(t, "engine:\n  kind: kmod\n", string(r.artifactManager.Content(testConfigName, artifact.MediumInline)))
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(config), got))
+	assert.Nil(t, apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionRolledBack.String()))
+}
+
+func TestReconcile_EffectiveConfig(t *testing.T) {
+	falco := &instancev1alpha1.Falco{
+		ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: testutil.TestNamespace, UID: "falco-uid"},
+	}
+	baseConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: testutil.TestNamespace},
+		Data:       map[string]string{"falco.yaml": "engine:\n  kind: kmod\nload_plugins: []\nwatch_config_files: true\n"},
+	}
+	config := &artifactv1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testConfigName,
+			Namespace:  testutil.TestNamespace,
+			Generation: 1,
+			Finalizers: []string{testFinalizerName()},
+		},
+		Spec: artifactv1alpha1.ConfigSpec{Config: &apiextensionsv1.JSON{Raw: []byte(testConfigJSON)}},
+	}
+	nodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, testConfigName, testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+			Labels:    controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindConfig, testConfigName, testutil.TestNodeName),
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName},
+	}
+	// The ArtifactNode of a Config restricted to another instance is left alone.
+	otherConfig := &artifactv1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testutil.TestNamespace},
+		Spec:       artifactv1alpha1.ConfigSpec{FalcoRef: &commonv1alpha1.FalcoRef{Name: "other"}},
+	}
+	otherNodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, "other", testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+			Labels:    controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindConfig, "other", testutil.TestNodeName),
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName},
+	}
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme, instancev1alpha1.AddToScheme)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(falco, baseConfigMap, config, nodeObject, otherConfig, otherNodeObject).
+		WithStatusSubresource(&artifactv1alpha1.Config{}, &artifactv1alpha1.ArtifactNode{}).Build()
+	r, _ := newTestReconciler(t)
+	r.Client, r.Scheme = cl, s
+	r.instance = client.ObjectKeyFromObject(falco)
+	mockFS := filesystem.NewMockFileSystem()
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace, artifact.WithFS(mockFS))
+
+	// The plugin controller wrote the configuration of the Plugins.
+	pluginsPath := r.artifactManager.Path(artifact.PluginsConfigName, priority.MaxPriority, artifact.MediumInline, artifact.TypeConfig)
+	mockFS.Files[pluginsPath] = []byte("load_plugins:\n- k8saudit\n")
+
+	_, err := r.Reconcile(context.Background(), testutil.Request(testConfigName))
+	require.NoError(t, err)
+
+	// The effective configuration is reported on the ArtifactNode, from which the instance operator publishes it.
+	got := &artifactv1alpha1.ArtifactNode{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), got))
+	require.NotNil(t, got.Status.EffectiveConfig)
+	assert.Equal(t, controllerhelper.EffectiveConfigMapName("falco", testutil.TestNodeName), got.Status.EffectiveConfig.ConfigMapName)
+	assert.Equal(t, "engine:\n  kind: modern_ebpf\nfalco_libs:\n  thread_table_size: 262144\nload_plugins:\n- k8saudit\n"+
+		"watch_config_files: true\n", got.Status.EffectiveConfig.Config)
+	assert.Equal(t, "engine:\n- Config/test-config\nfalco_libs:\n- Config/test-config\nload_plugins:\n- falco.yaml\n"+
+		"- 99-03-plugins-config-inline.yaml\nwatch_config_files:\n- falco.yaml\n", got.Status.EffectiveConfig.Provenance)
+	assert.Len(t, got.Status.EffectiveConfig.Hash, 64)
+	assert.Equal(t, []string{"engine", "falco_libs"}, got.Status.EffectiveConfig.AppliedKeys)
+	assert.Empty(t, got.Status.EffectiveConfig.OverriddenKeys)
+
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(otherNodeObject), got))
+	assert.Nil(t, got.Status.EffectiveConfig)
+
+	configMaps := &corev1.ConfigMapList{}
+	require.NoError(t, cl.List(context.Background(), configMaps))
+	assert.Len(t, configMaps.Items, 1, "the artifact operator no longer writes ConfigMaps")
+}
+
+func TestFindConfigsForEffectiveConfig(t *testing.T) {
+	config := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: testConfigName, Namespace: testutil.TestNamespace}}
+	r, _ := newTestReconciler(t, config)
+	r.instance = client.ObjectKey{Namespace: testutil.TestNamespace, Name: "falco"}
+
+	tests := []struct {
+		name      string
+		configMap *corev1.ConfigMap
+		want      int
+	}{
+		{
+			name:      "instance ConfigMap",
+			configMap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: testutil.TestNamespace}},
+			want:      1,
+		},
+		{
+			name: "node pool ConfigMap",
+			configMap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: "falco-gpu", Namespace: testutil.TestNamespace, Labels: map[string]string{"instance.falcosecurity.dev/node-pool": "gpu"},
+			}},
+			want: 1,
+		},
+		{
+			name: "effective configuration of this node",
+			configMap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: controllerhelper.EffectiveConfigMapName("falco", testutil.TestNodeName), Namespace: testutil.TestNamespace,
+			}},
+		},
+		{
+			name:      "unrelated ConfigMap",
+			configMap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testutil.TestNamespace}},
+		},
+		{
+			name:      "instance ConfigMap in another namespace",
+			configMap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: "other"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, r.findConfigsForEffectiveConfig(context.Background(), tt.configMap), tt.want)
+		})
+	}
+}
+
 func TestReconcile_GateForgetsOnDeletionCleanupFailure(t *testing.T) {
 	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
 	finalizer := testFinalizerName()
@@ -992,6 +1256,78 @@ func TestEnsureConfig_ProgrammedLastTransitionTime(t *testing.T) {
 	}
 }
 
+func TestEnsureConfig_RestartRequired(t *testing.T) {
+	tests := []struct {
+		name      string
+		gateReady bool
+		updated   string
+		wantKeys  string
+	}{
+		{
+			name:      "engine change after startup signals a restart",
+			gateReady: true,
+			updated:   `{"engine":{"kind":"kmod"},"falco_libs":{"thread_table_size":262144}}`,
+			wantKeys:  "engine.kind",
+		},
+		{
+			name:      "hot-reloadable change after startup does not signal a restart",
+			gateReady: true,
+			updated:   `{"engine":{"kind":"modern_ebpf"},"falco_libs":{"thread_table_size":1024}}`,
+		},
+		{
+			name:    "engine change before startup completes does not signal a restart",
+			updated: `{"engine":{"kind":"kmod"},"falco_libs":{"thread_table_size":262144}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeObject := &artifactv1alpha1.ArtifactNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, testConfigName, testutil.TestNodeName),
+					Namespace: testutil.TestNamespace,
+				},
+				Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName},
+			}
+			s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
+			cl := fake.NewClientBuilder().WithScheme(s).WithObjects(nodeObject).
+				WithStatusSubresource(&artifactv1alpha1.Config{}, &artifactv1alpha1.ArtifactNode{}).Build()
+			r, _ := newTestReconciler(t)
+			r.Client, r.Scheme = cl, s
+			gate := &startupgate.FakeGateRecorder{}
+			r.gate = gate
+			r.startedAt = time.Now().Add(-time.Hour)
+
+			config := &artifactv1alpha1.Config{
+				ObjectMeta: metav1.ObjectMeta{Name: testConfigName, Namespace: testutil.TestNamespace, Generation: 1},
+				Spec: artifactv1alpha1.ConfigSpec{
+					Config:   &apiextensionsv1.JSON{Raw: []byte(testConfigJSON)},
+					Priority: 50,
+				},
+			}
+			// The initial content is stored during startup.
+			require.NoError(t, r.ensureConfig(context.Background(), config))
+
+			gate.IsReady = tt.gateReady
+			config.Generation = 2
+			config.Spec.Config = &apiextensionsv1.JSON{Raw: []byte(tt.updated)}
+			require.NoError(t, r.ensureConfig(context.Background(), config))
+
+			got := &artifactv1alpha1.ArtifactNode{}
+			require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), got))
+			cond := apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionRestartRequired.String())
+			if tt.wantKeys == "" {
+				assert.Nil(t, cond)
+				return
+			}
+			require.NotNil(t, cond)
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, artifact.ReasonRestartRequired, cond.Reason)
+			assert.Equal(t, fmt.Sprintf(artifact.MessageFormatRestartRequired, testutil.TestNodeName, tt.wantKeys), cond.Message)
+		})
+	}
+}
+
 func TestFindConfigsForConfigMap(t *testing.T) {
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,177 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+// configSourceName returns the name identifying the files of a Config in the provenance of the effective configuration.
+func configSourceName(name string) string {
+	return controllerhelper.KindConfig + "/" + name
+}
+
+// ensureEffectiveConfig reports the effective configuration of this node on the ArtifactNodes tracking the Configs of
+// the Falco instance on this node, from which the instance operator publishes it.
+func (r *ConfigReconciler) ensureEffectiveConfig(ctx context.Context) error {
+	falco, err := controllerhelper.GetInstance(ctx, r.Client, r.instance)
+	if err != nil || falco == nil {
+		return err
+	}
+	effective, sources, err := r.mergeEffectiveConfig(ctx, falco)
+	if err != nil {
+		return err
+	}
+	return r.syncEffectiveConfig(ctx, falco, effective, sources)
+}
+
+// mergeEffectiveConfig merges the configuration files on this node on top of the falco.yaml of the Falco instance. It
+// also returns the sources merged, in order.
+func (r *ConfigReconciler) mergeEffectiveConfig(
+	ctx context.Context,
+	falco *instancev1alpha1.Falco,
+) (*artifact.EffectiveConfig, []artifact.Source, error) {
+	base, err := r.baseConfig(ctx, falco)
+	if err != nil {
+		return nil, nil, err
+	}
+	sources := r.artifactManager.Sources(artifact.TypeConfig)
+	for i := range sources {
+		sources[i].Name = configSourceName(sources[i].Name)
+	}
+	pluginsConfig, err := r.artifactManager.PluginsConfigSource()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading plugins configuration: %w", err)
+	}
+	if pluginsConfig != nil {
+		sources = append(sources, *pluginsConfig)
+	}
+
+	effective, err := artifact.MergeEffectiveConfig(base, sources)
+	if err != nil {
+		return nil, nil, err
+	}
+	return effective, sources, nil
+}
+
+// baseConfig returns the falco.yaml the Falco instance runs with on this node: the one of the node pool the node
+// belongs to when the pool overrides the configuration, the one of the instance otherwise. A missing ConfigMap yields
+// an empty configuration.
+func (r *ConfigReconciler) baseConfig(ctx context.Context, falco *instancev1alpha1.Falco) ([]byte, error) {
+	name := falco.Name
+	if len(falco.Spec.NodePools) > 0 {
+		node := &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{Kind: "Node", APIVersion: "v1"}}
+		if err := r.Get(ctx, client.ObjectKey{Name: r.nodeName}, node); err != nil {
+			return nil, fmt.Errorf("fetching node %s: %w", r.nodeName, err)
+		}
+		for i := range falco.Spec.NodePools {
+			pool := &falco.Spec.NodePools[i]
+			selector, err := metav1.LabelSelectorAsSelector(&pool.NodeSelector)
+			if err != nil {
+				return nil, fmt.Errorf("node pool %q: %w", pool.Name, err)
+			}
+			if !selector.Matches(labels.Set(node.Labels)) {
+				continue
+			}
+			if pool.Config != nil && len(pool.Config.Raw) > 0 {
+				name = resources.NodePoolName(falco.Name, pool.Name)
+			}
+			break
+		}
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: falco.Namespace, Name: name}, configMap); err != nil {
+		if k8serrors.IsNotFound(err) {
+			log.FromContext(ctx).V(2).Info("Falco ConfigMap not found, using an empty base configuration", "configMap", name)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fetching ConfigMap %s: %w", name, err)
+	}
+	return []byte(configMap.Data[resources.FalcoDefaults.ConfigMapVolume.SubPath]), nil
+}
+
+// syncEffectiveConfig sets the effective configuration on every ArtifactNode tracking a Config of falco on this node,
+// with the keys of its Config in effect and those overridden. Keeping them all up to date spares the instance operator
+// from telling which one is the latest.
+func (r *ConfigReconciler) syncEffectiveConfig(
+	ctx context.Context,
+	falco *instancev1alpha1.Falco,
+	effective *artifact.EffectiveConfig,
+	sources []artifact.Source,
+) error {
+	provenance, err := yaml.Marshal(effective.Provenance)
+	if err != nil {
+		return fmt.Errorf("marshaling provenance: %w", err)
+	}
+	nodeObjects, err := controllerhelper.ListInstanceNodeObjects(ctx, r.Client, falco, controllerhelper.ArtifactKindConfig, r.nodeName)
+	if err != nil {
+		return err
+	}
+
+	for i := range nodeObjects {
+		nodeObject := &nodeObjects[i]
+		applied, overridden := effective.SourceKeys(configSourceName(nodeObject.Labels[controllerhelper.LabelArtifactParent]), sources)
+		desired := &artifactv1alpha1.EffectiveConfig{
+			ConfigMapName:  controllerhelper.EffectiveConfigMapName(falco.Name, r.nodeName),
+			Hash:           effective.Hash,
+			Config:         string(effective.Data),
+			Provenance:     string(provenance),
+			AppliedKeys:    applied,
+			OverriddenKeys: overridden,
+		}
+		if equality.Semantic.DeepEqual(nodeObject.Status.EffectiveConfig, desired) {
+			continue
+		}
+		log.FromContext(ctx).V(2).Info("Reporting effective configuration", "artifactNode", nodeObject.Name, "hash", effective.Hash)
+		nodeObject.Status.EffectiveConfig = desired
+		if err := controllerhelper.PatchStatusSSA(ctx, r.Client, r.Scheme, nodeObject, fieldManager); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findConfigsForEffectiveConfig enqueues every Config when the configuration merged on this node may have changed, that
+// is when the ConfigMap of the Falco instance or of one of its node pools changes.
+func (r *ConfigReconciler) findConfigsForEffectiveConfig(ctx context.Context, configMap client.Object) []reconcile.Request {
+	if r.instance.Name == "" || configMap.GetNamespace() != r.instance.Namespace {
+		return nil
+	}
+	_, poolConfig := configMap.GetLabels()[resources.NodePoolLabel]
+	if configMap.GetName() != r.instance.Name && !poolConfig {
+		return nil
+	}
+	return controllerhelper.EnqueueAllOfType(ctx, r.Client, &artifactv1alpha1.ConfigList{}, client.InNamespace(configMap.GetNamespace()))
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"time"
 
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
@@ -35,27 +36,31 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
 	"github.com/falcosecurity/falco-operator/internal/pkg/priority"
 	"github.com/falcosecurity/falco-operator/internal/pkg/startupgate"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 const (
 	// pluginFinalizerPrefix is the prefix for the finalizer name.
 	pluginFinalizerPrefix = "plugin.artifact.falcosecurity.dev/finalizer"
 	// pluginConfigFileName is the name of the plugin configuration file.
-	pluginConfigFileName = "plugins-config"
+	pluginConfigFileName = artifact.PluginsConfigName
 	// fieldManager is the name used to identify the controller's managed fields.
 	fieldManager = "artifact-plugin"
 )
@@ -66,7 +71,7 @@ func NewPluginReconciler(
 	scheme *runtime.Scheme,
 	recorder events.EventRecorder,
 	gate startupgate.Recorder,
-	nodeName, namespace string,
+	nodeName, namespace, instanceName string,
 ) *PluginReconciler {
 	return &PluginReconciler{
 		Client:          cl,
@@ -77,7 +82,9 @@ func NewPluginReconciler(
 		artifactManager: artifact.NewManager(cl, namespace),
 		PluginsConfig:   &PluginsConfig{},
 		nodeName:        nodeName,
+		instance:        client.ObjectKey{Namespace: namespace, Name: instanceName},
 		crToConfigName:  make(map[string]string),
+		startedAt:       time.Now(),
 	}
 }
 
@@ -91,7 +98,11 @@ type PluginReconciler struct {
 	artifactManager *artifact.Manager
 	PluginsConfig   *PluginsConfig
 	nodeName        string
-	crToConfigName  map[string]string
+	// instance is the Falco instance whose pods run this artifact operator.
+	instance       client.ObjectKey
+	crToConfigName map[string]string
+	// startedAt is the start time of this artifact operator, and thus of the Falco pod it runs in.
+	startedAt time.Time
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -110,11 +121,24 @@ func (r *PluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ c
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// While suspended, leave the local files as they are and only report the suspension.
+	// A suspended Plugin does not hold back the startup gate.
+	if plugin.Spec.Suspend && plugin.DeletionTimestamp.IsZero() {
+		logger.V(2).Info("Plugin instance is suspended, skipping local resources")
+		r.gate.Forget(startupgate.KindPlugin, plugin.Namespace, plugin.Name)
+		artifact.RecordSuspended(r.recorder, plugin, &plugin.Status.Conditions, true)
+		return ctrl.Result{}, r.patchStatus(ctx, plugin)
+	}
+
 	// Check if the Plugin instance is for the current node.
-	if ok, err := controllerhelper.NodeMatchesSelector(ctx, r.Client, r.nodeName, plugin.Spec.Selector); err != nil {
+	if ok, err := controllerhelper.TargetMatches(ctx, r.Client, r.nodeName, r.instance, controllerhelper.Target{
+		NodeSelector:     plugin.Spec.Selector,
+		FalcoRef:         plugin.Spec.FalcoRef,
+		InstanceSelector: plugin.Spec.InstanceSelector,
+	}); err != nil {
 		return ctrl.Result{}, err
 	} else if !ok {
-		logger.Info("Plugin instance does not match node selector, will remove local resources if any")
+		logger.Info("Plugin instance does not target this node or Falco instance, will remove local resources if any")
 		r.gate.Forget(startupgate.KindPlugin, plugin.Namespace, plugin.Name)
 
 		// Here we handle the case where the plugin was created with a selector that matched the node, but now it doesn't.
@@ -138,17 +162,43 @@ func (r *PluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ c
 		return ctrl.Result{}, err
 	}
 
+	// With a rollout strategy, keep the files of the previous generation until the instance operator
+	// admits this node to the current one.
+	if ok, err := controllerhelper.RolloutAdmitted(
+		ctx, r.Client, controllerhelper.ArtifactKindPlugin, plugin, plugin.Spec.Rollout, r.nodeName,
+	); err != nil {
+		return ctrl.Result{}, err
+	} else if !ok {
+		logger.Info("Plugin generation not admitted on this node yet, keeping local resources", "generation", plugin.Generation)
+		r.gate.Forget(startupgate.KindPlugin, plugin.Namespace, plugin.Name)
+		return ctrl.Result{}, nil
+	}
+
 	defer r.gate.MarkReconciled(startupgate.KindPlugin, plugin.Namespace, plugin.Name, plugin.Generation)
 
-	// Patch status via defer to ensure it's always called.
+	artifact.RecordSuspended(r.recorder, plugin, &plugin.Status.Conditions, false)
+
+	// Patch status via defer to ensure it's always called. The Programmed condition is also reported
+	// on the ArtifactNode of this node, next to the restart signal.
 	defer func() {
 		patchErr := r.patchStatus(ctx, plugin)
 		if patchErr != nil {
 			logger.Error(patchErr, "unable to patch status")
 		}
-		reterr = kerrors.NewAggregate([]error{reterr, patchErr})
+		syncErr := controllerhelper.SyncNodeProgrammed(ctx, r.Client, r.Scheme, controllerhelper.ArtifactKindPlugin,
+			plugin, plugin.Status.Conditions, r.nodeName, fieldManager)
+		if syncErr != nil {
+			logger.Error(syncErr, "unable to report Programmed condition on ArtifactNode")
+		}
+		reterr = kerrors.NewAggregate([]error{reterr, patchErr, syncErr})
 	}()
 
+	// Install the sources of a previous revision while a rollback is requested.
+	rolledBack, err := controllerhelper.ApplyRollback(ctx, r.Client, controllerhelper.ArtifactKindPlugin, plugin, &plugin.Status.Conditions)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Enforce reference resolution.
 	if err := r.enforceReferenceResolution(ctx, plugin); err != nil {
 		return ctrl.Result{}, err
@@ -164,6 +214,15 @@ func (r *PluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ c
 		return ctrl.Result{}, err
 	}
 
+	// Report the digest pulled for the current sources, for the instance operator to record on their
+	// revision, so that rolling back to them pulls the same content.
+	if !rolledBack && plugin.Spec.OCIArtifact != nil {
+		if err := controllerhelper.ReportRevisionDigest(ctx, r.Client, r.Scheme, controllerhelper.ArtifactKindPlugin, plugin,
+			r.nodeName, r.artifactManager.Digest(plugin.Name, artifact.MediumOCI), fieldManager); err != nil {
+			logger.Error(err, "unable to report the OCI digest on ArtifactNode")
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -171,12 +230,26 @@ func (r *PluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ c
 func (r *PluginReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&artifactv1alpha1.Plugin{}).
+		Watches(
+			&instancev1alpha1.Falco{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, falco client.Object) []reconcile.Request {
+				return controllerhelper.EnqueueAllOfType(ctx, r.Client, &artifactv1alpha1.PluginList{}, client.InNamespace(falco.GetNamespace()))
+			}),
+			builder.WithPredicates(controllerhelper.InstanceLabelsChangedPredicate(r.instance)),
+		).
 		Watches(
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.findPluginsForSecret),
 		).
+		Watches(
+			&artifactv1alpha1.ArtifactNode{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &artifactv1alpha1.Plugin{}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetLabels()[controllerhelper.LabelArtifactNode] == r.nodeName
+			})),
+		).
 		Named("artifact-plugin").
-		Complete(r)
+		Complete(tracing.Reconciler(controllerhelper.KindPlugin, r))
 }
 
 // findPluginsForSecret finds all Plugins that reference a given Secret using the index.
@@ -228,7 +301,19 @@ func (r *PluginReconciler) ensurePlugin(ctx context.Context, plugin *artifactv1a
 	apimeta.SetStatusCondition(&plugin.Status.Conditions, common.NewProgrammedCondition(
 		metav1.ConditionTrue, artifact.ReasonProgrammed, artifact.MessageProgrammed, gen,
 	))
-	return nil
+
+	// Falco loads plugin libraries only at start: replacing the library behind library_path needs a restart.
+	var restartKeys []string
+	if ociAction == artifact.StoreActionUpdated && r.gate.Ready() {
+		restartKeys = []string{artifact.PluginLibraryPathKey(resolveConfigName(plugin))}
+	}
+	return r.syncRestartRequired(ctx, plugin, restartKeys)
+}
+
+// syncRestartRequired reconciles the RestartRequired condition on the ArtifactNode of the plugin for this node.
+func (r *PluginReconciler) syncRestartRequired(ctx context.Context, plugin *artifactv1alpha1.Plugin, keys []string) error {
+	return controllerhelper.SyncRestartRequired(ctx, r.Client, r.Scheme, controllerhelper.ArtifactKindPlugin,
+		plugin, r.nodeName, keys, r.startedAt, fieldManager)
 }
 
 func (r *PluginReconciler) enforceReferenceResolution(ctx context.Context, plugin *artifactv1alpha1.Plugin) error {
@@ -324,6 +409,9 @@ func (r *PluginReconciler) ensurePluginConfig(ctx context.Context, plugin *artif
 
 	r.PluginsConfig.addConfig(r.artifactManager, plugin)
 
+	// Keep the previously stored configuration around to classify the change once the new one is written.
+	prevConfig := r.artifactManager.Content(pluginConfigFileName, artifact.MediumInline)
+
 	pluginConfigString, err := r.PluginsConfig.toString()
 	if err != nil {
 		logger.Error(err, "unable to convert plugin config to string")
@@ -352,7 +440,16 @@ func (r *PluginReconciler) ensurePluginConfig(ctx context.Context, plugin *artif
 	apimeta.SetStatusCondition(&plugin.Status.Conditions, common.NewProgrammedCondition(
 		metav1.ConditionTrue, artifact.ReasonProgrammed, artifact.MessageProgrammed, gen,
 	))
-	return nil
+
+	// Changes written before the startup gate opens are loaded by Falco when it starts.
+	var restartKeys []string
+	if r.gate.Ready() {
+		restartKeys, err = artifact.RestartRequiredKeys(prevConfig, []byte(pluginConfigString))
+		if err != nil {
+			logger.Error(err, "unable to classify plugin configuration change")
+		}
+	}
+	return r.syncRestartRequired(ctx, plugin, restartKeys)
 }
 
 // removePluginConfig removes the plugin configuration from the configuration file.
@@ -39,9 +39,11 @@ import (
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/controllers/testutil"
 	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/filesystem"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
 	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
@@ -49,7 +51,10 @@ import (
 	"github.com/falcosecurity/falco-operator/internal/pkg/startupgate"
 )
 
-const testPluginName = "test-plugin"
+const (
+	testPluginName   = "test-plugin"
+	testInstanceName = "falco"
+)
 
 func testFinalizerName() string {
 	return common.FormatFinalizerName(pluginFinalizerPrefix, testutil.TestNodeName)
@@ -70,7 +75,7 @@ func findPluginConfig(configs []PluginConfig, name string) *PluginConfig {
 
 func newTestReconciler(t *testing.T, objs ...client.Object) (*PluginReconciler, client.Client) {
 	t.Helper()
-	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme, instancev1alpha1.AddToScheme)
 	cl := fake.NewClientBuilder().
 		WithScheme(s).
 		WithObjects(objs...).
@@ -92,6 +97,7 @@ func newTestReconciler(t *testing.T, objs ...client.Object) (*PluginReconciler,
 		artifactManager: am,
 		PluginsConfig:   &PluginsConfig{},
 		nodeName:        testutil.TestNodeName,
+		instance:        client.ObjectKey{Namespace: testutil.TestNamespace, Name: testInstanceName},
 		crToConfigName:  make(map[string]string),
 	}, cl
 }
@@ -99,10 +105,11 @@ func newTestReconciler(t *testing.T, objs ...client.Object) (*PluginReconciler,
 func TestNewPluginReconciler(t *testing.T) {
 	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
 	cl := fake.NewClientBuilder().WithScheme(s).Build()
-	r := NewPluginReconciler(cl, s, events.NewFakeRecorder(10), startupgate.NoopGateRecorder{}, "my-node", "my-namespace")
+	r := NewPluginReconciler(cl, s, events.NewFakeRecorder(10), startupgate.NoopGateRecorder{}, "my-node", "my-namespace", "my-falco")
 
 	require.NotNil(t, r)
 	assert.Equal(t, "my-node", r.nodeName)
+	assert.Equal(t, client.ObjectKey{Namespace: "my-namespace", Name: "my-falco"}, r.instance)
 	assert.Equal(t, common.FormatFinalizerName(pluginFinalizerPrefix, "my-node"), r.finalizer)
 	assert.NotNil(t, r.PluginsConfig)
 	assert.NotNil(t, r.crToConfigName)
@@ -288,6 +295,56 @@ func TestReconcile(t *testing.T) {
 			req:     testutil.Request(testPluginName),
 			wantErr: true,
 		},
+		{
+			name: "falcoRef matches this instance proceeds normally",
+			objects: []client.Object{
+				&instancev1alpha1.Falco{
+					ObjectMeta: metav1.ObjectMeta{Name: testInstanceName, Namespace: testutil.TestNamespace},
+				},
+				&artifactv1alpha1.Plugin{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       testPluginName,
+						Namespace:  testutil.TestNamespace,
+						Finalizers: []string{testFinalizerName()},
+					},
+					Spec: artifactv1alpha1.PluginSpec{
+						FalcoRef: &commonv1alpha1.FalcoRef{Name: testInstanceName},
+					},
+				},
+			},
+			req:             testutil.Request(testPluginName),
+			wantConfigEmpty: new(false),
+			wantConditions: []testutil.ConditionExpect{
+				{Type: commonv1alpha1.ConditionProgrammed.String(), Status: metav1.ConditionTrue, Reason: artifact.ReasonProgrammed},
+			},
+		},
+		{
+			name: "instanceSelector does not match this instance removes local resources",
+			objects: []client.Object{
+				&instancev1alpha1.Falco{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testInstanceName,
+						Namespace: testutil.TestNamespace,
+						Labels:    map[string]string{"source": "syscall"},
+					},
+				},
+				&artifactv1alpha1.Plugin{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       testPluginName,
+						Namespace:  testutil.TestNamespace,
+						Finalizers: []string{testFinalizerName()},
+					},
+					Spec: artifactv1alpha1.PluginSpec{
+						InstanceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"source": "k8saudit"},
+						},
+					},
+				},
+			},
+			req:             testutil.Request(testPluginName),
+			wantConfigEmpty: new(true),
+			wantFinalizer:   new(false),
+		},
 		{
 			name: "OCI store failure sets error conditions on status",
 			objects: []client.Object{
@@ -541,6 +598,106 @@ func TestReconcile_GateInteraction(t *testing.T) {
 	}
 }
 
+func TestReconcile_Suspended(t *testing.T) {
+	plugin := &artifactv1alpha1.Plugin{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testPluginName,
+			Namespace:  testutil.TestNamespace,
+			Generation: 1,
+			Finalizers: []string{testFinalizerName()},
+		},
+		Spec: artifactv1alpha1.PluginSpec{
+			Suspend: true,
+		},
+	}
+	r, cl := newTestReconciler(t, plugin)
+	mockFS := filesystem.NewMockFileSystem()
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace,
+		artifact.WithFS(mockFS),
+		artifact.WithOCIPuller(&puller.MockOCIPuller{}),
+	)
+	rec := &startupgate.FakeGateRecorder{}
+	r.gate = rec
+
+	_, err := r.Reconcile(context.Background(), testutil.Request(testPluginName))
+	require.NoError(t, err)
+
+	assert.Empty(t, mockFS.WriteCalls, "no file must be written while suspended")
+	assert.Empty(t, rec.Reconciled)
+	assert.Equal(t, []startupgate.FakeGateCall{{Kind: "Plugin", Namespace: testutil.TestNamespace, Name: testPluginName}}, rec.Forgotten)
+	got := &artifactv1alpha1.Plugin{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(plugin), got))
+	testutil.RequireCondition(t, got.Status.Conditions, commonv1alpha1.ConditionSuspended.String(),
+		metav1.ConditionTrue, artifact.ReasonSuspended)
+
+	// Resuming reconciles the plugin again and clears the condition.
+	got.Spec.Suspend = false
+	require.NoError(t, cl.Update(context.Background(), got))
+
+	_, err = r.Reconcile(context.Background(), testutil.Request(testPluginName))
+	require.NoError(t, err)
+
+	assert.Len(t, rec.Reconciled, 1)
+
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(plugin), got))
+	assert.Nil(t, apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionSuspended.String()))
+}
+
+func TestReconcile_RolloutNotAdmitted(t *testing.T) {
+	plugin := &artifactv1alpha1.Plugin{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testPluginName,
+			Namespace:  testutil.TestNamespace,
+			Generation: 2,
+			Finalizers: []string{testFinalizerName()},
+		},
+		Spec: artifactv1alpha1.PluginSpec{
+			Rollout: &commonv1alpha1.Rollout{},
+		},
+	}
+	nodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindPlugin, testPluginName, testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName, AllowedGeneration: 1},
+	}
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(plugin, nodeObject).
+		WithStatusSubresource(&artifactv1alpha1.Plugin{}, &artifactv1alpha1.ArtifactNode{}).Build()
+	r, _ := newTestReconciler(t)
+	r.Client, r.Scheme = cl, s
+	mockFS := filesystem.NewMockFileSystem()
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace,
+		artifact.WithFS(mockFS),
+		artifact.WithOCIPuller(&puller.MockOCIPuller{}),
+	)
+	rec := &startupgate.FakeGateRecorder{}
+	r.gate = rec
+
+	// The node is still allowed the previous generation only.
+	_, err := r.Reconcile(context.Background(), testutil.Request(testPluginName))
+	require.NoError(t, err)
+	assert.Empty(t, mockFS.WriteCalls, "no file must be written before the node is admitted")
+	assert.Equal(t, []startupgate.FakeGateCall{{Kind: "Plugin", Namespace: testutil.TestNamespace, Name: testPluginName}}, rec.Forgotten)
+
+	// Once admitted, the generation is programmed and reported on the ArtifactNode.
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), nodeObject))
+	nodeObject.Spec.AllowedGeneration = 2
+	require.NoError(t, cl.Update(context.Background(), nodeObject))
+
+	_, err = r.Reconcile(context.Background(), testutil.Request(testPluginName))
+	require.NoError(t, err)
+	assert.NotEmpty(t, mockFS.WriteCalls)
+
+	got := &artifactv1alpha1.ArtifactNode{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), got))
+	cond := apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionProgrammed.String())
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+}
+
 func TestReconcile_GateForgetsOnDeletionCleanupFailure(t *testing.T) {
 	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
 	finalizer := testFinalizerName()
@@ -950,6 +1107,76 @@ func TestEnsurePluginConfig(t *testing.T) {
 	}
 }
 
+func TestEnsurePluginConfig_RestartRequired(t *testing.T) {
+	tests := []struct {
+		name      string
+		gateReady bool
+		updated   *artifactv1alpha1.PluginConfig
+		wantKeys  string
+	}{
+		{
+			name:      "library path change after startup signals a restart",
+			gateReady: true,
+			updated:   &artifactv1alpha1.PluginConfig{LibraryPath: "/usr/share/falco/plugins/custom.so"},
+			wantKeys:  "plugins.container.library_path",
+		},
+		{
+			name:      "init config change after startup does not signal a restart",
+			gateReady: true,
+			updated: &artifactv1alpha1.PluginConfig{
+				InitConfig: &apiextensionsv1.JSON{Raw: []byte(`{"engines":{"containerd":{"enabled":true}}}`)},
+			},
+		},
+		{
+			name:    "library path change before startup completes does not signal a restart",
+			updated: &artifactv1alpha1.PluginConfig{LibraryPath: "/usr/share/falco/plugins/custom.so"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The ArtifactNode the Plugin aggregator of the instance operator creates for this node.
+			nodeObject := &artifactv1alpha1.ArtifactNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindPlugin, "container", testutil.TestNodeName),
+					Namespace: testutil.TestNamespace,
+				},
+				Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName},
+			}
+			s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
+			cl := fake.NewClientBuilder().WithScheme(s).WithObjects(nodeObject).
+				WithStatusSubresource(&artifactv1alpha1.Plugin{}, &artifactv1alpha1.ArtifactNode{}).Build()
+			r, _ := newTestReconciler(t)
+			r.Client, r.Scheme = cl, s
+			gate := &startupgate.FakeGateRecorder{}
+			r.gate = gate
+			r.startedAt = time.Now().Add(-time.Hour)
+
+			plugin := &artifactv1alpha1.Plugin{
+				ObjectMeta: metav1.ObjectMeta{Name: "container", Namespace: testutil.TestNamespace, Generation: 1},
+			}
+			// The initial configuration is stored during startup.
+			require.NoError(t, r.ensurePluginConfig(context.Background(), plugin))
+
+			gate.IsReady = tt.gateReady
+			plugin.Generation = 2
+			plugin.Spec.Config = tt.updated
+			require.NoError(t, r.ensurePluginConfig(context.Background(), plugin))
+
+			got := &artifactv1alpha1.ArtifactNode{}
+			require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), got))
+			cond := apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionRestartRequired.String())
+			if tt.wantKeys == "" {
+				assert.Nil(t, cond)
+				return
+			}
+			require.NotNil(t, cond)
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, fmt.Sprintf(artifact.MessageFormatRestartRequired, testutil.TestNodeName, tt.wantKeys), cond.Message)
+		})
+	}
+}
+
 func TestRemovePluginConfig(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -28,18 +28,22 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
 	"github.com/falcosecurity/falco-operator/internal/pkg/startupgate"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 const (
@@ -55,7 +59,7 @@ func NewRulesfileReconciler(
 	scheme *runtime.Scheme,
 	recorder events.EventRecorder,
 	gate startupgate.Recorder,
-	nodeName, namespace string,
+	nodeName, namespace, instanceName string,
 ) *RulesfileReconciler {
 	return &RulesfileReconciler{
 		Client:          cl,
@@ -65,6 +69,7 @@ func NewRulesfileReconciler(
 		finalizer:       common.FormatFinalizerName(rulesfileFinalizerPrefix, nodeName),
 		artifactManager: artifact.NewManager(cl, namespace),
 		nodeName:        nodeName,
+		instance:        client.ObjectKey{Namespace: namespace, Name: instanceName},
 		namespace:       namespace,
 	}
 }
@@ -78,7 +83,9 @@ type RulesfileReconciler struct {
 	finalizer       string
 	artifactManager *artifact.Manager
 	nodeName        string
-	namespace       string
+	// instance is the Falco instance whose pods run this artifact operator.
+	instance  client.ObjectKey
+	namespace string
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -98,11 +105,32 @@ func (r *RulesfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Report the rules index of this node once the local files are up to date, whatever the outcome.
+	defer func() {
+		if err := r.publishRulesIndex(ctx); err != nil {
+			logger.Error(err, "unable to report rules index")
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// While suspended, leave the local files as they are and only report the suspension.
+	// A suspended Rulesfile does not hold back the startup gate.
+	if rulesfile.Spec.Suspend && rulesfile.DeletionTimestamp.IsZero() {
+		logger.V(2).Info("Rulesfile instance is suspended, skipping local resources")
+		r.gate.Forget(startupgate.KindRulesfile, rulesfile.Namespace, rulesfile.Name)
+		artifact.RecordSuspended(r.recorder, rulesfile, &rulesfile.Status.Conditions, true)
+		return ctrl.Result{}, r.patchStatus(ctx, rulesfile)
+	}
+
 	// Check if the Rulesfile instance is for the current node.
-	if ok, err := controllerhelper.NodeMatchesSelector(ctx, r.Client, r.nodeName, rulesfile.Spec.Selector); err != nil {
+	if ok, err := controllerhelper.TargetMatches(ctx, r.Client, r.nodeName, r.instance, controllerhelper.Target{
+		NodeSelector:     rulesfile.Spec.Selector,
+		FalcoRef:         rulesfile.Spec.FalcoRef,
+		InstanceSelector: rulesfile.Spec.InstanceSelector,
+	}); err != nil {
 		return ctrl.Result{}, err
 	} else if !ok {
-		logger.Info("Rulesfile instance does not match node selector, will remove local resources if any")
+		logger.Info("Rulesfile instance does not target this node or Falco instance, will remove local resources if any")
 		r.gate.Forget(startupgate.KindRulesfile, rulesfile.Namespace, rulesfile.Name)
 
 		// Handle case where rulesfile selector no longer matches the node.
@@ -126,17 +154,43 @@ func (r *RulesfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// With a rollout strategy, keep the files of the previous generation until the instance operator
+	// admits this node to the current one.
+	if ok, err := controllerhelper.RolloutAdmitted(
+		ctx, r.Client, controllerhelper.ArtifactKindRulesfile, rulesfile, rulesfile.Spec.Rollout, r.nodeName,
+	); err != nil {
+		return ctrl.Result{}, err
+	} else if !ok {
+		logger.Info("Rulesfile generation not admitted on this node yet, keeping local resources", "generation", rulesfile.Generation)
+		r.gate.Forget(startupgate.KindRulesfile, rulesfile.Namespace, rulesfile.Name)
+		return ctrl.Result{}, nil
+	}
+
 	defer r.gate.MarkReconciled(startupgate.KindRulesfile, rulesfile.Namespace, rulesfile.Name, rulesfile.Generation)
 
-	// Patch status via defer to ensure it's always called.
+	artifact.RecordSuspended(r.recorder, rulesfile, &rulesfile.Status.Conditions, false)
+
+	// Patch status via defer to ensure it's always called. The Programmed condition is also reported
+	// on the ArtifactNode of this node, for the instance operator to aggregate.
 	defer func() {
 		patchErr := r.patchStatus(ctx, rulesfile)
 		if patchErr != nil {
 			logger.Error(patchErr, "unable to patch status")
 		}
-		reterr = kerrors.NewAggregate([]error{reterr, patchErr})
+		syncErr := controllerhelper.SyncNodeProgrammed(ctx, r.Client, r.Scheme, controllerhelper.ArtifactKindRulesfile,
+			rulesfile, rulesfile.Status.Conditions, r.nodeName, fieldManager)
+		if syncErr != nil {
+			logger.Error(syncErr, "unable to report Programmed condition on ArtifactNode")
+		}
+		reterr = kerrors.NewAggregate([]error{reterr, patchErr, syncErr})
 	}()
 
+	// Install the sources of a previous revision while a rollback is requested.
+	rolledBack, err := controllerhelper.ApplyRollback(ctx, r.Client, controllerhelper.ArtifactKindRulesfile, rulesfile, &rulesfile.Status.Conditions)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Enforce reference resolution.
 	if err := r.enforceReferenceResolution(ctx, rulesfile); err != nil {
 		return ctrl.Result{}, err
@@ -147,6 +201,20 @@ func (r *RulesfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Report the definitions of earlier rules files replaced by the files of this Rulesfile.
+	if err := r.syncRulesConflicts(ctx, rulesfile); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report the digest pulled for the current sources, for the instance operator to record on their
+	// revision, so that rolling back to them pulls the same content.
+	if !rolledBack && rulesfile.Spec.OCIArtifact != nil {
+		if err := controllerhelper.ReportRevisionDigest(ctx, r.Client, r.Scheme, controllerhelper.ArtifactKindRulesfile, rulesfile,
+			r.nodeName, r.artifactManager.Digest(rulesfile.Name, artifact.MediumOCI), fieldManager); err != nil {
+			logger.Error(err, "unable to report the OCI digest on ArtifactNode")
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -154,6 +222,13 @@ func (r *RulesfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 func (r *RulesfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&artifactv1alpha1.Rulesfile{}).
+		Watches(
+			&instancev1alpha1.Falco{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, falco client.Object) []reconcile.Request {
+				return controllerhelper.EnqueueAllOfType(ctx, r.Client, &artifactv1alpha1.RulesfileList{}, client.InNamespace(falco.GetNamespace()))
+			}),
+			builder.WithPredicates(controllerhelper.InstanceLabelsChangedPredicate(r.instance)),
+		).
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(r.findRulesfilesForConfigMap),
@@ -162,8 +237,19 @@ func (r *RulesfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.findRulesfilesForSecret),
 		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findRulesfilesForRulesIndex),
+		).
+		Watches(
+			&artifactv1alpha1.ArtifactNode{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &artifactv1alpha1.Rulesfile{}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetLabels()[controllerhelper.LabelArtifactNode] == r.nodeName
+			})),
+		).
 		Named("artifact-rulesfile").
-		Complete(r)
+		Complete(tracing.Reconciler(controllerhelper.KindRulesfile, r))
 }
 
 // findRulesfilesForConfigMap finds all Rulesfiles that reference a given ConfigMap using the index.
@@ -39,9 +39,11 @@ import (
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/controllers/testutil"
 	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/filesystem"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
 	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
@@ -93,10 +95,11 @@ func newTestReconciler(t *testing.T, objs ...client.Object) (*RulesfileReconcile
 func TestNewRulesfileReconciler(t *testing.T) {
 	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
 	cl := fake.NewClientBuilder().WithScheme(s).Build()
-	r := NewRulesfileReconciler(cl, s, events.NewFakeRecorder(10), startupgate.NoopGateRecorder{}, "my-node", "my-namespace")
+	r := NewRulesfileReconciler(cl, s, events.NewFakeRecorder(10), startupgate.NoopGateRecorder{}, "my-node", "my-namespace", "my-falco")
 
 	require.NotNil(t, r)
 	assert.Equal(t, "my-node", r.nodeName)
+	assert.Equal(t, client.ObjectKey{Namespace: "my-namespace", Name: "my-falco"}, r.instance)
 	assert.Equal(t, "my-namespace", r.namespace)
 	assert.Equal(t, common.FormatFinalizerName(rulesfileFinalizerPrefix, "my-node"), r.finalizer)
 	assert.NotNil(t, r.artifactManager)
@@ -527,6 +530,267 @@ func TestReconcile_GateInteraction(t *testing.T) {
 	}
 }
 
+func TestReconcile_Suspended(t *testing.T) {
+	rulesfile := &artifactv1alpha1.Rulesfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testRulesfileName,
+			Namespace:  testutil.TestNamespace,
+			Generation: 1,
+			Finalizers: []string{testFinalizerName()},
+		},
+		Spec: artifactv1alpha1.RulesfileSpec{
+			InlineRules: &apiextensionsv1.JSON{Raw: []byte(testInlineRulesJSON)},
+			Suspend:     true,
+		},
+	}
+	r, cl := newTestReconciler(t, rulesfile)
+	mockFS := filesystem.NewMockFileSystem()
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace,
+		artifact.WithFS(mockFS),
+		artifact.WithOCIPuller(&puller.MockOCIPuller{}),
+	)
+	rec := &startupgate.FakeGateRecorder{}
+	r.gate = rec
+
+	_, err := r.Reconcile(context.Background(), testutil.Request(testRulesfileName))
+	require.NoError(t, err)
+
+	assert.Empty(t, mockFS.WriteCalls, "no file must be written while suspended")
+	assert.Empty(t, rec.Reconciled)
+	assert.Equal(t, []startupgate.FakeGateCall{{Kind: "Rulesfile", Namespace: testutil.TestNamespace, Name: testRulesfileName}}, rec.Forgotten)
+	got := &artifactv1alpha1.Rulesfile{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(rulesfile), got))
+	testutil.RequireCondition(t, got.Status.Conditions, commonv1alpha1.ConditionSuspended.String(),
+		metav1.ConditionTrue, artifact.ReasonSuspended)
+
+	// Resuming writes the files and clears the condition.
+	got.Spec.Suspend = false
+	require.NoError(t, cl.Update(context.Background(), got))
+
+	_, err = r.Reconcile(context.Background(), testutil.Request(testRulesfileName))
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, mockFS.WriteCalls)
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(rulesfile), got))
+	assert.Nil(t, apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionSuspended.String()))
+}
+
+func TestReconcile_RolloutNotAdmitted(t *testing.T) {
+	rulesfile := &artifactv1alpha1.Rulesfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testRulesfileName,
+			Namespace:  testutil.TestNamespace,
+			Generation: 2,
+			Finalizers: []string{testFinalizerName()},
+		},
+		Spec: artifactv1alpha1.RulesfileSpec{
+			InlineRules: &apiextensionsv1.JSON{Raw: []byte(testInlineRulesJSON)},
+			Rollout:     &commonv1alpha1.Rollout{},
+		},
+	}
+	nodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindRulesfile, testRulesfileName, testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName, AllowedGeneration: 1},
+	}
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(rulesfile, nodeObject).
+		WithStatusSubresource(&artifactv1alpha1.Rulesfile{}, &artifactv1alpha1.ArtifactNode{}).Build()
+	r, _ := newTestReconciler(t)
+	r.Client, r.Scheme = cl, s
+	mockFS := filesystem.NewMockFileSystem()
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace,
+		artifact.WithFS(mockFS),
+		artifact.WithOCIPuller(&puller.MockOCIPuller{}),
+	)
+	rec := &startupgate.FakeGateRecorder{}
+	r.gate = rec
+
+	// The node is still allowed the previous generation only.
+	_, err := r.Reconcile(context.Background(), testutil.Request(testRulesfileName))
+	require.NoError(t, err)
+	assert.Empty(t, mockFS.WriteCalls, "no file must be written before the node is admitted")
+	assert.Equal(t, []startupgate.FakeGateCall{{Kind: "Rulesfile", Namespace: testutil.TestNamespace, Name: testRulesfileName}}, rec.Forgotten)
+
+	// Once admitted, the generation is programmed and reported on the ArtifactNode.
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), nodeObject))
+	nodeObject.Spec.AllowedGeneration = 2
+	require.NoError(t, cl.Update(context.Background(), nodeObject))
+
+	_, err = r.Reconcile(context.Background(), testutil.Request(testRulesfileName))
+	require.NoError(t, err)
+	assert.NotEmpty(t, mockFS.WriteCalls)
+
+	got := &artifactv1alpha1.ArtifactNode{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), got))
+	cond := apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionProgrammed.String())
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+}
+
+func TestReconcile_RulesConflicts(t *testing.T) {
+	falco := &instancev1alpha1.Falco{
+		ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: testutil.TestNamespace, UID: "falco-uid"},
+	}
+	newRulesfile := func(name string, priority int32) *artifactv1alpha1.Rulesfile {
+		return &artifactv1alpha1.Rulesfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  testutil.TestNamespace,
+				Generation: 1,
+				Finalizers: []string{testFinalizerName()},
+			},
+			Spec: artifactv1alpha1.RulesfileSpec{
+				InlineRules: &apiextensionsv1.JSON{Raw: []byte(testInlineRulesJSON)},
+				Priority:    priority,
+			},
+		}
+	}
+	early, late := newRulesfile("early", 10), newRulesfile("late", 50)
+	nodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindRulesfile, "late", testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+			Labels:    controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindRulesfile, "late", testutil.TestNodeName),
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName},
+	}
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme, instancev1alpha1.AddToScheme)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(falco, early, late, nodeObject).
+		WithStatusSubresource(&artifactv1alpha1.Rulesfile{}, &artifactv1alpha1.ArtifactNode{}).Build()
+	r, _ := newTestReconciler(t)
+	r.Client, r.Scheme = cl, s
+	r.instance = client.ObjectKeyFromObject(falco)
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace, artifact.WithFS(filesystem.NewMockFileSystem()))
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, testutil.Request("early"))
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, testutil.Request("late"))
+	require.NoError(t, err)
+
+	got := &artifactv1alpha1.Rulesfile{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(late), got))
+	testutil.RequireCondition(t, got.Status.Conditions, commonv1alpha1.ConditionRulesConflict.String(),
+		metav1.ConditionTrue, artifact.ReasonRulesConflict)
+	cond := apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionRulesConflict.String())
+	assert.Equal(t, `Rules files replace earlier definitions: rule "test_rule" already defined by Rulesfile/early`, cond.Message)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(early), got))
+	assert.Nil(t, apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionRulesConflict.String()),
+		"the conflict is reported on the later definition only")
+
+	gotNode := &artifactv1alpha1.ArtifactNode{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(nodeObject), gotNode))
+	testutil.RequireCondition(t, gotNode.Status.Conditions, commonv1alpha1.ConditionRulesConflict.String(),
+		metav1.ConditionTrue, artifact.ReasonRulesConflict)
+
+	// The index is reported on the ArtifactNode, from which the instance operator publishes it.
+	require.NotNil(t, gotNode.Status.RulesIndex)
+	assert.Equal(t, controllerhelper.RulesIndexConfigMapName("falco", testutil.TestNodeName), gotNode.Status.RulesIndex.ConfigMapName)
+	assert.Equal(t, `conflicts:
+- definedBy: Rulesfile/early
+  kind: rule
+  name: test_rule
+  redefinedBy: Rulesfile/late
+files:
+- path: /etc/falco/rules.d/10-03-early-inline.yaml
+  rules:
+  - test_rule
+  source: Rulesfile/early
+- path: /etc/falco/rules.d/50-03-late-inline.yaml
+  rules:
+  - test_rule
+  source: Rulesfile/late
+`, gotNode.Status.RulesIndex.Index)
+
+	// Overriding the rule instead of redefining it clears the conflict.
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(late), got))
+	got.Spec.InlineRules = &apiextensionsv1.JSON{Raw: []byte(`[{"rule":"test_rule","condition":"and never_true","override":{"condition":"append"}}]`)}
+	require.NoError(t, cl.Update(ctx, got))
+	_, err = r.Reconcile(ctx, testutil.Request("late"))
+	require.NoError(t, err)
+
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(late), got))
+	assert.Nil(t, apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionRulesConflict.String()))
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(nodeObject), gotNode))
+	assert.Nil(t, apimeta.FindStatusCondition(gotNode.Status.Conditions, commonv1alpha1.ConditionRulesConflict.String()))
+}
+
+func TestReconcile_RulesConflictsNodeObjectCreatedLater(t *testing.T) {
+	falco := &instancev1alpha1.Falco{
+		ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: testutil.TestNamespace, UID: "falco-uid"},
+	}
+	newRulesfile := func(name string, priority int32) *artifactv1alpha1.Rulesfile {
+		return &artifactv1alpha1.Rulesfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  testutil.TestNamespace,
+				Generation: 1,
+				Finalizers: []string{testFinalizerName()},
+			},
+			Spec: artifactv1alpha1.RulesfileSpec{
+				InlineRules: &apiextensionsv1.JSON{Raw: []byte(testInlineRulesJSON)},
+				Priority:    priority,
+			},
+		}
+	}
+	early, late := newRulesfile("early", 10), newRulesfile("late", 50)
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme, instancev1alpha1.AddToScheme)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(falco, early, late).
+		WithStatusSubresource(&artifactv1alpha1.Rulesfile{}, &artifactv1alpha1.ArtifactNode{}).Build()
+	r, _ := newTestReconciler(t)
+	r.Client, r.Scheme = cl, s
+	r.instance = client.ObjectKeyFromObject(falco)
+	r.artifactManager = artifact.NewManagerWithOptions(cl, testutil.TestNamespace, artifact.WithFS(filesystem.NewMockFileSystem()))
+	ctx := context.Background()
+
+	// The conflict is reported on the Rulesfile before the instance operator created the ArtifactNode.
+	_, err := r.Reconcile(ctx, testutil.Request("early"))
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, testutil.Request("late"))
+	require.NoError(t, err)
+	got := &artifactv1alpha1.Rulesfile{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(late), got))
+	testutil.RequireCondition(t, got.Status.Conditions, commonv1alpha1.ConditionRulesConflict.String(),
+		metav1.ConditionTrue, artifact.ReasonRulesConflict)
+
+	// Creating the ArtifactNode enqueues the Rulesfile, which then reports the conflict on it too.
+	nodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindRulesfile, "late", testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName},
+	}
+	require.NoError(t, cl.Create(ctx, nodeObject))
+	_, err = r.Reconcile(ctx, testutil.Request("late"))
+	require.NoError(t, err)
+
+	gotNode := &artifactv1alpha1.ArtifactNode{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(nodeObject), gotNode))
+	testutil.RequireCondition(t, gotNode.Status.Conditions, commonv1alpha1.ConditionRulesConflict.String(),
+		metav1.ConditionTrue, artifact.ReasonRulesConflict)
+}
+
+func TestFindRulesfilesForRulesIndex(t *testing.T) {
+	rulesfile := &artifactv1alpha1.Rulesfile{ObjectMeta: metav1.ObjectMeta{Name: testRulesfileName, Namespace: testutil.TestNamespace}}
+	r, _ := newTestReconciler(t, rulesfile)
+	r.instance = client.ObjectKey{Namespace: testutil.TestNamespace, Name: "falco"}
+
+	index := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name: controllerhelper.RulesIndexConfigMapName("falco", testutil.TestNodeName), Namespace: testutil.TestNamespace,
+	}}
+	assert.Len(t, r.findRulesfilesForRulesIndex(context.Background(), index), 1)
+
+	otherNode := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name: controllerhelper.RulesIndexConfigMapName("falco", "other-node"), Namespace: testutil.TestNamespace,
+	}}
+	assert.Empty(t, r.findRulesfilesForRulesIndex(context.Background(), otherNode))
+}
+
 func TestReconcile_GateForgetsOnDeletionCleanupFailure(t *testing.T) {
 	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
 	finalizer := testFinalizerName()
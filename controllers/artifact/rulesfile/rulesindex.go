@@ -0,0 +1,165 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rulesfile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+// rulesfileSourceName returns the name identifying the files of a Rulesfile in the rules index.
+func rulesfileSourceName(name string) string {
+	return controllerhelper.KindRulesfile + "/" + name
+}
+
+// rulesIndex indexes the rules files stored on this node.
+func (r *RulesfileReconciler) rulesIndex() *artifact.RulesIndex {
+	sources := r.artifactManager.Sources(artifact.TypeRulesfile)
+	for i := range sources {
+		sources[i].Name = rulesfileSourceName(sources[i].Name)
+	}
+	return artifact.IndexRules(sources)
+}
+
+// publishRulesIndex reports the index of the rules files on this node on every ArtifactNode tracking a Rulesfile of the
+// Falco instance on this node, from which the instance operator publishes it. Nothing is reported when the Falco
+// instance is unknown or gone.
+func (r *RulesfileReconciler) publishRulesIndex(ctx context.Context) error {
+	falco, err := controllerhelper.GetInstance(ctx, r.Client, r.instance)
+	if err != nil || falco == nil {
+		return err
+	}
+	data, err := yaml.Marshal(r.rulesIndex())
+	if err != nil {
+		return fmt.Errorf("marshaling rules index: %w", err)
+	}
+	nodeObjects, err := controllerhelper.ListInstanceNodeObjects(ctx, r.Client, falco, controllerhelper.ArtifactKindRulesfile, r.nodeName)
+	if err != nil {
+		return err
+	}
+
+	desired := &artifactv1alpha1.RulesIndex{
+		ConfigMapName: controllerhelper.RulesIndexConfigMapName(falco.Name, r.nodeName),
+		Index:         string(data),
+	}
+	for i := range nodeObjects {
+		nodeObject := &nodeObjects[i]
+		if equality.Semantic.DeepEqual(nodeObject.Status.RulesIndex, desired) {
+			continue
+		}
+		log.FromContext(ctx).V(2).Info("Reporting rules index", "artifactNode", nodeObject.Name)
+		nodeObject.Status.RulesIndex = desired
+		if err := controllerhelper.PatchStatusSSA(ctx, r.Client, r.Scheme, nodeObject, fieldManager); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncRulesConflicts reports the definitions of earlier rules files that the files of rulesfile replace on this
+// node: as a RulesConflict condition on rulesfile, recorded with a warning event, and on the ArtifactNode tracking
+// rulesfile on this node.
+func (r *RulesfileReconciler) syncRulesConflicts(ctx context.Context, rulesfile *artifactv1alpha1.Rulesfile) error {
+	conflicts := r.rulesIndex().ConflictsOf(rulesfileSourceName(rulesfile.Name))
+	conditionType := commonv1alpha1.ConditionRulesConflict.String()
+
+	var condition *metav1.Condition
+	if len(conflicts) == 0 {
+		apimeta.RemoveStatusCondition(&rulesfile.Status.Conditions, conditionType)
+	} else {
+		descriptions := make([]string, len(conflicts))
+		for i := range conflicts {
+			descriptions[i] = conflicts[i].String()
+		}
+		message := fmt.Sprintf(artifact.MessageFormatRulesConflict, strings.Join(descriptions, "; "))
+		if current := apimeta.FindStatusCondition(rulesfile.Status.Conditions, conditionType); current == nil || current.Message != message {
+			artifact.RecordWarning(r.recorder, rulesfile, artifact.ReasonRulesConflict, artifact.MessageFormatRulesConflict,
+				strings.Join(descriptions, "; "))
+		}
+		c := common.NewRulesConflictCondition(metav1.ConditionTrue, artifact.ReasonRulesConflict, message, rulesfile.Generation)
+		apimeta.SetStatusCondition(&rulesfile.Status.Conditions, c)
+		condition = &c
+	}
+
+	return r.syncNodeRulesConflict(ctx, rulesfile, condition)
+}
+
+// syncNodeRulesConflict sets condition on the ArtifactNode tracking rulesfile on this node, or removes the
+// RulesConflict condition when condition is nil. The instance operator creates the ArtifactNode for every node
+// running Falco, and its creation enqueues rulesfile again, so a missing ArtifactNode is logged rather than
+// returned as an error.
+func (r *RulesfileReconciler) syncNodeRulesConflict(
+	ctx context.Context,
+	rulesfile *artifactv1alpha1.Rulesfile,
+	condition *metav1.Condition,
+) error {
+	nodeObject := &artifactv1alpha1.ArtifactNode{}
+	key := client.ObjectKey{
+		Namespace: rulesfile.Namespace,
+		Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindRulesfile, rulesfile.Name, r.nodeName),
+	}
+	if err := r.Get(ctx, key, nodeObject); err != nil {
+		if k8serrors.IsNotFound(err) {
+			if condition != nil {
+				log.FromContext(ctx).Info("ArtifactNode not created yet, rules conflicts will be reported on it once it exists",
+					"artifactNode", key.Name)
+			}
+			return nil
+		}
+		return fmt.Errorf("fetching ArtifactNode %s: %w", key.Name, err)
+	}
+
+	conditionType := commonv1alpha1.ConditionRulesConflict.String()
+	current := apimeta.FindStatusCondition(nodeObject.Status.Conditions, conditionType)
+	switch {
+	case condition == nil && current == nil:
+		return nil
+	case condition == nil:
+		apimeta.RemoveStatusCondition(&nodeObject.Status.Conditions, conditionType)
+	case current != nil && current.Message == condition.Message && current.ObservedGeneration == condition.ObservedGeneration:
+		return nil
+	default:
+		apimeta.SetStatusCondition(&nodeObject.Status.Conditions, *condition)
+	}
+	return controllerhelper.PatchStatusSSA(ctx, r.Client, r.Scheme, nodeObject, fieldManager)
+}
+
+// findRulesfilesForRulesIndex enqueues every Rulesfile when the instance operator publishes a new rules index for this
+// node, so that each of them reports the conflicts of its files against the files installed since.
+func (r *RulesfileReconciler) findRulesfilesForRulesIndex(ctx context.Context, configMap client.Object) []reconcile.Request {
+	if r.instance.Name == "" || configMap.GetNamespace() != r.instance.Namespace ||
+		configMap.GetName() != controllerhelper.RulesIndexConfigMapName(r.instance.Name, r.nodeName) {
+		return nil
+	}
+	return controllerhelper.EnqueueAllOfType(ctx, r.Client, &artifactv1alpha1.RulesfileList{}, client.InNamespace(configMap.GetNamespace()))
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package revision implements the controller recording the revision history of artifacts.
+// It runs in the instance operator and, for one artifact kind, is responsible for:
+//   - Recording the sources of each artifact in a ControllerRevision whenever they change.
+//   - Recording on each revision the OCI digest the artifact operators reported pulling for it.
+//   - Pruning the revisions beyond the revisionHistoryLimit of the artifact.
+//   - Clearing rollbackTo once the sources of the artifact change again.
+package revision
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
+)
+
+// ControllerName identifies this controller in logs, suffixed with the artifact kind.
+const ControllerName = "instance-artifact-revision"
+
+// NewRevisionReconciler returns a new RevisionReconciler for the artifacts of kind, one of
+// controllerhelper.KindConfig, controllerhelper.KindPlugin or controllerhelper.KindRulesfile.
+func NewRevisionReconciler(cl client.Client, scheme *runtime.Scheme, kind string) (*RevisionReconciler, error) {
+	r := &RevisionReconciler{Client: cl, Scheme: scheme, kind: kind}
+	switch kind {
+	case controllerhelper.KindConfig:
+		r.artifactKind = controllerhelper.ArtifactKindConfig
+	case controllerhelper.KindPlugin:
+		r.artifactKind = controllerhelper.ArtifactKindPlugin
+	case controllerhelper.KindRulesfile:
+		r.artifactKind = controllerhelper.ArtifactKindRulesfile
+	default:
+		return nil, fmt.Errorf("unsupported artifact kind %q", kind)
+	}
+	return r, nil
+}
+
+// RevisionReconciler records the revision history of the artifacts of one kind.
+type RevisionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// kind is the API Kind of the artifacts, artifactKind the matching label value.
+	kind         string
+	artifactKind string
+}
+
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=configs;plugins;rulesfiles,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=artifactnodes,verbs=get;list;watch
+
+// Reconcile records the current sources of an artifact as its latest revision, records the OCI digests
+// reported by the nodes, prunes the old revisions and clears rollbackTo when the sources changed.
+func (r *RevisionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(1).Info("Reconciling revisions")
+
+	obj := r.newObject()
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// Revisions are owned by the artifact and garbage collected with it.
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	revisions, changed, err := controllerhelper.EnsureRevision(ctx, r.Client, r.Scheme, r.artifactKind, obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if changed && controllerhelper.HasRollback(obj) {
+		if err := controllerhelper.ClearRollback(ctx, r.Client, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if err := controllerhelper.RecordRevisionDigests(ctx, r.Client, r.artifactKind, obj, revisions); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, controllerhelper.PruneRevisions(ctx, r.Client, obj, revisions)
+}
+
+func (r *RevisionReconciler) newObject() client.Object {
+	switch r.kind {
+	case controllerhelper.KindPlugin:
+		return &artifactv1alpha1.Plugin{}
+	case controllerhelper.KindRulesfile:
+		return &artifactv1alpha1.Rulesfile{}
+	default:
+		return &artifactv1alpha1.Config{}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RevisionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := ControllerName + "-" + r.artifactKind
+	return ctrl.NewControllerManagedBy(mgr).
+		For(r.newObject(), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&appsv1.ControllerRevision{}).
+		Watches(&artifactv1alpha1.ArtifactNode{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), r.newObject()),
+			builder.WithPredicates(revisionDigestChangedPredicate()),
+		).
+		Named(name).
+		WithLogConstructor(controllerhelper.LogConstructorFor(mgr.GetLogger(), mgr.GetScheme(), name, r.newObject())).
+		Complete(tracing.Reconciler(r.kind, r))
+}
+
+// revisionDigestChangedPredicate passes the ArtifactNodes reporting a new OCI digest.
+func revisionDigestChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			nodeObject, ok := e.Object.(*artifactv1alpha1.ArtifactNode)
+			return ok && nodeObject.Status.RevisionDigest != nil
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObject, ok := e.ObjectOld.(*artifactv1alpha1.ArtifactNode)
+			if !ok {
+				return false
+			}
+			newObject, ok := e.ObjectNew.(*artifactv1alpha1.ArtifactNode)
+			if !ok {
+				return false
+			}
+			return newObject.Status.RevisionDigest != nil &&
+				!equality.Semantic.DeepEqual(oldObject.Status.RevisionDigest, newObject.Status.RevisionDigest)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/controllers/testutil"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+const testConfigName = "test-config"
+
+func newTestReconciler(t *testing.T, objs ...client.Object) (*RevisionReconciler, client.Client) {
+	t.Helper()
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+	r, err := NewRevisionReconciler(cl, s, controllerhelper.KindConfig)
+	require.NoError(t, err)
+	return r, cl
+}
+
+func listRevisions(t *testing.T, cl client.Client) []appsv1.ControllerRevision {
+	t.Helper()
+	config := &artifactv1alpha1.Config{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: testutil.TestNamespace, Name: testConfigName}, config))
+	revisions, err := controllerhelper.ListRevisions(context.Background(), cl, controllerhelper.ArtifactKindConfig, config)
+	require.NoError(t, err)
+	return revisions
+}
+
+func TestNewRevisionReconciler_UnsupportedKind(t *testing.T) {
+	_, err := NewRevisionReconciler(nil, nil, "Falco")
+	require.Error(t, err)
+}
+
+func TestReconcile_NotFound(t *testing.T) {
+	r, _ := newTestReconciler(t)
+	_, err := r.Reconcile(context.Background(), testutil.Request(testConfigName))
+	require.NoError(t, err)
+}
+
+func TestReconcile_RecordsRevisionsAndClearsRollback(t *testing.T) {
+	config := &artifactv1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{Name: testConfigName, Namespace: testutil.TestNamespace},
+		Spec:       artifactv1alpha1.ConfigSpec{Config: &apiextensionsv1.JSON{Raw: []byte(`{"engine":{"kind":"kmod"}}`)}},
+	}
+	r, cl := newTestReconciler(t, config)
+	ctx := context.Background()
+	update := func(mutate func(*artifactv1alpha1.Config)) {
+		t.Helper()
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(config), config))
+		mutate(config)
+		require.NoError(t, cl.Update(ctx, config))
+		_, err := r.Reconcile(ctx, testutil.Request(testConfigName))
+		require.NoError(t, err)
+	}
+
+	_, err := r.Reconcile(ctx, testutil.Request(testConfigName))
+	require.NoError(t, err)
+	require.Len(t, listRevisions(t, cl), 1)
+
+	update(func(c *artifactv1alpha1.Config) {
+		c.Spec.Config = &apiextensionsv1.JSON{Raw: []byte(`{"engine":{"kind":"modern_ebpf"}}`)}
+	})
+	require.Len(t, listRevisions(t, cl), 2)
+
+	// Rolling back keeps the rollback and records no revision.
+	update(func(c *artifactv1alpha1.Config) { c.Spec.RollbackTo = &commonv1alpha1.RollbackConfig{Revision: 1} })
+	require.Len(t, listRevisions(t, cl), 2)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(config), config))
+	require.NotNil(t, config.Spec.RollbackTo)
+
+	// Changing the sources again ends the rollback.
+	update(func(c *artifactv1alpha1.Config) {
+		c.Spec.Config = &apiextensionsv1.JSON{Raw: []byte(`{"engine":{"kind":"ebpf"}}`)}
+	})
+	revisions := listRevisions(t, cl)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, int64(3), revisions[2].Revision)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(config), config))
+	assert.Nil(t, config.Spec.RollbackTo)
+}
+
+func TestReconcile_PrunesRevisions(t *testing.T) {
+	config := &artifactv1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{Name: testConfigName, Namespace: testutil.TestNamespace},
+		Spec:       artifactv1alpha1.ConfigSpec{RevisionHistoryLimit: new(int32)},
+	}
+	r, cl := newTestReconciler(t, config)
+	ctx := context.Background()
+
+	for _, kind := range []string{"kmod", "ebpf", "modern_ebpf"} {
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(config), config))
+		config.Spec.Config = &apiextensionsv1.JSON{Raw: []byte(`{"engine":{"kind":"` + kind + `"}}`)}
+		require.NoError(t, cl.Update(ctx, config))
+		_, err := r.Reconcile(ctx, testutil.Request(testConfigName))
+		require.NoError(t, err)
+	}
+
+	revisions := listRevisions(t, cl)
+	require.Len(t, revisions, 1, "only the latest revision is kept with a zero history limit")
+	assert.Equal(t, int64(3), revisions[0].Revision)
+}
+
+func TestReconcile_RecordsRevisionDigests(t *testing.T) {
+	rulesfile := &artifactv1alpha1.Rulesfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-rules", Namespace: testutil.TestNamespace, UID: "rulesfile-uid"},
+		Spec: artifactv1alpha1.RulesfileSpec{OCIArtifact: &commonv1alpha1.OCIArtifact{
+			Image: commonv1alpha1.ImageSpec{Repository: "falcosecurity/rules/falco-rules", Tag: "4"},
+		}},
+	}
+	s := testutil.Scheme(t, artifactv1alpha1.AddToScheme)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(rulesfile).Build()
+	r, err := NewRevisionReconciler(cl, s, controllerhelper.KindRulesfile)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = r.Reconcile(ctx, testutil.Request(rulesfile.Name))
+	require.NoError(t, err)
+	revisions, err := controllerhelper.ListRevisions(ctx, cl, controllerhelper.ArtifactKindRulesfile, rulesfile)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+
+	require.NoError(t, cl.Create(ctx, &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindRulesfile, rulesfile.Name, "node-1"),
+			Namespace: testutil.TestNamespace,
+			Labels:    controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindRulesfile, rulesfile.Name, "node-1"),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: artifactv1alpha1.GroupVersion.String(), Kind: "Rulesfile", Name: rulesfile.Name,
+				UID: rulesfile.UID, Controller: ptr.To(true),
+			}},
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: "node-1"},
+		Status: artifactv1alpha1.ArtifactNodeStatus{
+			RevisionDigest: &artifactv1alpha1.RevisionDigest{Revision: revisions[0].Name, Digest: "sha256:aaa"},
+		},
+	}))
+	_, err = r.Reconcile(ctx, testutil.Request(rulesfile.Name))
+	require.NoError(t, err)
+
+	revisions, err = controllerhelper.ListRevisions(ctx, cl, controllerhelper.ArtifactKindRulesfile, rulesfile)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "sha256:aaa", revisions[0].Annotations[controllerhelper.AnnotationRevisionDigest])
+}
+
+func TestRevisionDigestChangedPredicate(t *testing.T) {
+	p := revisionDigestChangedPredicate()
+	withDigest := func(digest string) *artifactv1alpha1.ArtifactNode {
+		nodeObject := &artifactv1alpha1.ArtifactNode{}
+		if digest != "" {
+			nodeObject.Status.RevisionDigest = &artifactv1alpha1.RevisionDigest{Revision: "rulesfile--custom--abc", Digest: digest}
+		}
+		return nodeObject
+	}
+
+	assert.False(t, p.Create(event.CreateEvent{Object: withDigest("")}))
+	assert.True(t, p.Create(event.CreateEvent{Object: withDigest("sha256:aaa")}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: withDigest(""), ObjectNew: withDigest("")}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: withDigest(""), ObjectNew: withDigest("sha256:aaa")}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: withDigest("sha256:aaa"), ObjectNew: withDigest("sha256:aaa")}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: withDigest("sha256:aaa"), ObjectNew: withDigest("sha256:bbb")}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: withDigest("sha256:aaa"), ObjectNew: withDigest("")}))
+	assert.False(t, p.Delete(event.DeleteEvent{Object: withDigest("sha256:aaa")}))
+}
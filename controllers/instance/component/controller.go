@@ -20,10 +20,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -38,16 +42,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/instance"
 	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 const (
 	finalizer    = "component.instance.falcosecurity.dev/finalizer"
 	fieldManager = "component-controller"
+	// replicasHandoverManager takes the replicas over from fieldManager when autoscaling is enabled.
+	replicasHandoverManager = fieldManager + "-replicas-handover"
 )
 
 // clusterScopedGVKs are the GVKs of cluster-scoped resources managed by the Component controller.
@@ -59,9 +67,10 @@ var clusterScopedGVKs = []schema.GroupVersionKind{
 // Reconciler reconciles a Component object.
 type Reconciler struct {
 	client.Client
-	Scheme      *runtime.Scheme
-	recorder    events.EventRecorder
-	labelFilter instance.LabelFilter
+	Scheme        *runtime.Scheme
+	recorder      events.EventRecorder
+	labelFilter   instance.LabelFilter
+	reconcileMode string
 }
 
 // Option configures a Reconciler.
@@ -74,6 +83,13 @@ func WithLabelFilter(f instance.LabelFilter) Option {
 	}
 }
 
+// WithReconcileMode sets the reconcile mode of the instances without a reconcile mode annotation.
+func WithReconcileMode(mode string) Option {
+	return func(r *Reconciler) {
+		r.reconcileMode = mode
+	}
+}
+
 // NewReconciler creates a new Reconciler.
 func NewReconciler(cl client.Client, scheme *runtime.Scheme, recorder events.EventRecorder, opts ...Option) *Reconciler {
 	r := &Reconciler{
@@ -91,6 +107,8 @@ func NewReconciler(cl client.Client, scheme *runtime.Scheme, recorder events.Eve
 // +kubebuilder:rbac:groups="",resources=endpoints;namespaces;replicationcontrollers,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=create;delete;get;list;patch;update;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -103,6 +121,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		logger.Error(err, "unable to fetch component instance")
 		return ctrl.Result{}, err
 	} else if k8serrors.IsNotFound(err) {
+		instance.ForgetReplicas("Component", req.NamespacedName)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -123,8 +142,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	// resource on the API server keeps its labels.
 	comp.SetLabels(r.labelFilter.Apply(comp.GetLabels()))
 
+	// In plan mode, collect the changes to the generated resources instead of applying them.
+	var plan *instance.Plan
+	if instance.ResolveReconcileMode(comp, r.reconcileMode) == instance.ReconcileModePlan {
+		plan = &instance.Plan{}
+		ctx = instance.PlanIntoContext(ctx, plan)
+	}
+
+	// Detect the fields of the generated resources changed by other field managers.
+	drift := instance.NewDrift(comp.Spec.DriftPolicy)
+	ctx = instance.DriftIntoContext(ctx, drift)
+
 	// Patch status via defer to ensure it's always called.
 	defer func() {
+		// An invalid overlay is only fixed by changing the spec: report it instead of retrying.
+		if errors.Is(reterr, instance.ErrInvalidOverlay) {
+			instance.RecordInvalidOverlay(r.recorder, comp, &comp.Status.Conditions, reterr)
+			reterr = nil
+		}
+		comp.Status.PendingChanges = instance.RecordPlan(r.recorder, comp, &comp.Status.Conditions,
+			comp.Status.PendingChanges, plan)
 		computeErr := r.computeAvailableCondition(ctx, comp)
 		if computeErr != nil {
 			logger.Error(computeErr, "unable to compute available condition")
@@ -140,6 +177,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	comp.Status.Version = resolvedImage
 	comp.Status.ResourceType = defs.ResourceType
 
+	// While suspended, keep reporting the status without touching the generated resources.
+	instance.RecordSuspended(r.recorder, comp, &comp.Status.Conditions, comp.Spec.Suspend)
+	if comp.Spec.Suspend {
+		logger.V(2).Info("Reconciliation suspended, skipping the generated resources")
+		return ctrl.Result{}, nil
+	}
+
+	// Check the overlays before applying any of the generated resources.
+	if err := instance.ValidateOverlays(comp.Spec.Overlays); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Ensure the service account is created.
 	if err := r.ensureServiceAccount(ctx, comp); err != nil {
 		return ctrl.Result{}, err
@@ -170,6 +219,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Ensure the prometheus-operator objects are created.
+	if err := r.ensureMonitoring(ctx, comp, defs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the networkpolicy is created.
+	if err := r.ensureNetworkPolicy(ctx, comp, defs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the certificate is issued when TLS is enabled.
+	if err := r.ensureCertificate(ctx, comp, time.Now()); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Set the finalizer if needed.
 	if ok, err := r.ensureFinalizer(ctx, comp); ok || err != nil {
 		return ctrl.Result{}, err
@@ -180,7 +244,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Ensure the poddisruptionbudget is created when the deployment runs several replicas.
+	if err := r.ensurePodDisruptionBudget(ctx, comp, defs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the horizontalpodautoscaler is created when autoscaling is enabled.
+	if err := r.ensureHorizontalPodAutoscaler(ctx, comp); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report the drift once every generated resource has been checked.
+	instance.RecordDrift(r.recorder, comp, &comp.Status.Conditions, drift)
+
+	// Renew the certificate in time.
+	return ctrl.Result{RequeueAfter: instance.CertificateRenewAfter(comp.Status.TLS, time.Now())}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -190,10 +268,14 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
 		Watches(&rbacv1.ClusterRole{}, handler.EnqueueRequestsFromMapFunc(instance.ClusterScopedResourceHandler)).
 		Watches(&rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestsFromMapFunc(instance.ClusterScopedResourceHandler)).
 		Named("component").
-		Complete(r)
+		Complete(tracing.Reconciler("Component", r))
 }
 
 // ensureDeployment ensures the Component Deployment is created or updated.
@@ -223,6 +305,20 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, comp *instancev1alpha
 		return err
 	}
 
+	// The autoscaler manages the replicas while enabled: leave them out so that both don't fight over them.
+	if autoscalingEnabled(comp) {
+		unstructured.RemoveNestedField(applyConfig.Object, "spec", "replicas")
+	}
+
+	applyConfig, err = instance.ApplyOverlays(r.Scheme, applyConfig, comp.Spec.Overlays)
+	if err != nil {
+		logger.Error(err, "unable to apply the overlays")
+		conditionStatus = metav1.ConditionFalse
+		conditionReason = instance.ReasonInvalidOverlay
+		conditionMessage = fmt.Sprintf(instance.MessageFormatInvalidOverlay, err.Error())
+		return err
+	}
+
 	applyConfigYaml, err := yaml.Marshal(applyConfig.Object)
 	if err != nil {
 		logger.Error(err, "unable to marshal apply configuration")
@@ -263,6 +359,13 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, comp *instancev1alpha
 
 	var changedFields string
 	if resourceExists {
+		if applyConfig, err = instance.CheckDrift(ctx, existingResource, applyConfig, fieldManager); err != nil {
+			logger.Error(err, "unable to detect drift of existing resource")
+			conditionStatus = metav1.ConditionFalse
+			conditionReason = instance.ReasonResourceComparisonError
+			conditionMessage = fmt.Sprintf(instance.MessageFormatResourceComparisonError, err.Error())
+			return err
+		}
 		comparison, err := controllerhelper.Diff(existingResource, applyConfig, fieldManager)
 		if err != nil {
 			if !errors.Is(err, controllerhelper.ErrNoManagedFields) {
@@ -284,8 +387,29 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, comp *instancev1alpha
 		}
 	}
 
+	// In plan mode, record the change instead of applying it.
+	if plan := instance.PlanFromContext(ctx); plan != nil {
+		action := commonv1alpha1.PendingActionUpdate
+		if !resourceExists {
+			action = commonv1alpha1.PendingActionCreate
+		}
+		logger.Info("Component resource change planned", "type", comp.Spec.Component.Type, "action", action,
+			"changedFields", changedFields)
+		plan.Add(action, resources.ResourceTypeDeployment, applyConfig.GetName(), changedFields)
+		conditionReason = instance.ReasonChangesPending
+		return nil
+	}
+
 	if !resourceExists {
 		logger.Info("Creating Component resource", "type", comp.Spec.Component.Type)
+	} else if autoscalingEnabled(comp) {
+		if err = instance.HandOverReplicas(ctx, r.Client, existingResource, fieldManager, replicasHandoverManager); err != nil {
+			logger.Error(err, "unable to hand the replicas over to the autoscaler")
+			conditionStatus = metav1.ConditionFalse
+			conditionReason = instance.ReasonApplyPatchErrorOnUpdate
+			conditionMessage = fmt.Sprintf(instance.MessageFormatApplyPatchErrorOnUpdate, err.Error())
+			return err
+		}
 	}
 
 	applyOpts := []client.ApplyOption{client.ForceOwnership, client.FieldOwner(fieldManager)}
@@ -332,11 +456,18 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, comp *instancev1alpha
 
 // computeAvailableCondition queries the live Deployment state.
 func (r *Reconciler) computeAvailableCondition(ctx context.Context, comp *instancev1alpha1.Component) error {
-	result, err := instance.ComputeDeploymentAvailability(ctx, r.Client, client.ObjectKeyFromObject(comp), comp.Spec.Replicas)
+	// The replicas set by the autoscaler are the desired ones while it is enabled.
+	specReplicas := comp.Spec.Replicas
+	if autoscalingEnabled(comp) {
+		specReplicas = nil
+	}
+	result, err := instance.ComputeDeploymentAvailability(ctx, r.Client, client.ObjectKeyFromObject(comp), specReplicas)
 
 	comp.Status.DesiredReplicas = result.DesiredReplicas
 	comp.Status.AvailableReplicas = result.AvailableReplicas
 	comp.Status.UnavailableReplicas = result.UnavailableReplicas
+	instance.RecordReplicas("Component", client.ObjectKeyFromObject(comp),
+		result.DesiredReplicas, result.AvailableReplicas, result.UnavailableReplicas)
 
 	apimeta.SetStatusCondition(&comp.Status.Conditions, common.NewAvailableCondition(
 		result.ConditionStatus, result.Reason, result.Message, comp.GetGeneration()))
@@ -373,40 +504,134 @@ func (r *Reconciler) handleDeletion(ctx context.Context, comp *instancev1alpha1.
 func (r *Reconciler) ensureServiceAccount(ctx context.Context, comp *instancev1alpha1.Component) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, comp, fieldManager,
 		resources.GenerateServiceAccount(comp),
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays})
 }
 
 // ensureRole ensures the namespace-scoped Role is created or updated.
 func (r *Reconciler) ensureRole(ctx context.Context, comp *instancev1alpha1.Component, defs *resources.InstanceDefaults) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, comp, fieldManager,
 		resources.GenerateRole(comp, defs),
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays})
 }
 
 // ensureRoleBinding ensures the namespace-scoped RoleBinding is created or updated.
 func (r *Reconciler) ensureRoleBinding(ctx context.Context, comp *instancev1alpha1.Component) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, comp, fieldManager,
 		resources.GenerateRoleBinding(comp),
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays})
 }
 
 // ensureClusterRole ensures the ClusterRole is created or updated.
 func (r *Reconciler) ensureClusterRole(ctx context.Context, comp *instancev1alpha1.Component, defs *resources.InstanceDefaults) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, comp, fieldManager,
 		resources.GenerateClusterRole(comp, defs),
-		instance.GenerateOptions{SetControllerRef: false, IsClusterScoped: true})
+		instance.GenerateOptions{SetControllerRef: false, IsClusterScoped: true, Overlays: comp.Spec.Overlays})
 }
 
 // ensureClusterRoleBinding ensures the ClusterRoleBinding is created or updated.
 func (r *Reconciler) ensureClusterRoleBinding(ctx context.Context, comp *instancev1alpha1.Component) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, comp, fieldManager,
 		resources.GenerateClusterRoleBinding(comp),
-		instance.GenerateOptions{SetControllerRef: false, IsClusterScoped: true})
+		instance.GenerateOptions{SetControllerRef: false, IsClusterScoped: true, Overlays: comp.Spec.Overlays})
 }
 
 // ensureService ensures the Service is created or updated.
 func (r *Reconciler) ensureService(ctx context.Context, comp *instancev1alpha1.Component, defs *resources.InstanceDefaults) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, comp, fieldManager,
 		resources.GenerateService(comp, defs),
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays})
+}
+
+// ensureNetworkPolicy ensures the NetworkPolicy is created or updated when enabled, and deleted otherwise.
+func (r *Reconciler) ensureNetworkPolicy(ctx context.Context, comp *instancev1alpha1.Component, defs *resources.InstanceDefaults) error {
+	return instance.EnsureNetworkPolicy(ctx, r.Client, r.recorder, comp, fieldManager,
+		resources.GenerateNetworkPolicy(comp, defs, comp.Spec.NetworkPolicy),
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays})
+}
+
+// ensurePodDisruptionBudget ensures the PodDisruptionBudget is created or updated when the Deployment runs
+// several replicas, and deleted otherwise.
+func (r *Reconciler) ensurePodDisruptionBudget(ctx context.Context, comp *instancev1alpha1.Component, defs *resources.InstanceDefaults) error {
+	var pdb *policyv1.PodDisruptionBudget
+	if minReplicas(comp, defs) > 1 {
+		pdb = resources.GeneratePodDisruptionBudget(comp)
+	}
+	return instance.EnsurePodDisruptionBudget(ctx, r.Client, r.recorder, comp, fieldManager, pdb,
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays})
+}
+
+// ensureHorizontalPodAutoscaler ensures the HorizontalPodAutoscaler is created or updated when autoscaling is
+// enabled, and deleted otherwise.
+func (r *Reconciler) ensureHorizontalPodAutoscaler(ctx context.Context, comp *instancev1alpha1.Component) error {
+	return instance.EnsureHorizontalPodAutoscaler(ctx, r.Client, r.recorder, comp, fieldManager,
+		resources.GenerateHorizontalPodAutoscaler(comp, comp.Spec.Autoscaling),
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays})
+}
+
+// autoscalingEnabled returns whether the replicas of the Component are managed by its HorizontalPodAutoscaler.
+func autoscalingEnabled(comp *instancev1alpha1.Component) bool {
+	return comp.Spec.Autoscaling != nil && comp.Spec.Autoscaling.Enabled
+}
+
+// minReplicas returns the number of replicas the Deployment of the Component runs at least: the minimum of the
+// autoscaler when enabled, the replicas of the spec or the defaults of the component type otherwise.
+func minReplicas(comp *instancev1alpha1.Component, defs *resources.InstanceDefaults) int32 {
+	switch {
+	case autoscalingEnabled(comp):
+		if comp.Spec.Autoscaling.MinReplicas != nil {
+			return *comp.Spec.Autoscaling.MinReplicas
+		}
+		return 1
+	case comp.Spec.Replicas != nil:
+		return *comp.Spec.Replicas
+	case defs.Replicas != nil:
+		return *defs.Replicas
+	default:
+		return 1
+	}
+}
+
+// ensureCertificate ensures the certificate of the component is issued when TLS is enabled, and deleted otherwise.
+func (r *Reconciler) ensureCertificate(ctx context.Context, comp *instancev1alpha1.Component, now time.Time) error {
+	status, err := instance.EnsureCertificate(ctx, r.Client, r.recorder, comp, fieldManager, tlsEnabled(comp),
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays}, now)
+	if err != nil {
+		return err
+	}
+	comp.Status.TLS = status
+	return nil
+}
+
+// tlsEnabled reports whether TLS is enabled for the component.
+func tlsEnabled(comp *instancev1alpha1.Component) bool {
+	return comp.Spec.TLS != nil && comp.Spec.TLS.Enabled
+}
+
+// ensureMonitoring ensures the prometheus-operator objects enabled for the component are created or updated,
+// and deletes the ones no longer enabled.
+func (r *Reconciler) ensureMonitoring(ctx context.Context, comp *instancev1alpha1.Component, defs *resources.InstanceDefaults) error {
+	monitoring := comp.Spec.Monitoring
+	if monitoring == nil {
+		monitoring = &commonv1alpha1.MonitoringSpec{}
+	}
+
+	objects := []struct {
+		gvk     schema.GroupVersionKind
+		desired *unstructured.Unstructured
+	}{
+		{resources.ServiceMonitorGVK, resources.GenerateServiceMonitor(comp, defs, monitoring.ServiceMonitor)},
+		{resources.PodMonitorGVK, resources.GeneratePodMonitor(comp, defs, monitoring.PodMonitor)},
+	}
+	for _, obj := range objects {
+		if tlsEnabled(comp) {
+			if err := resources.EnableMonitorTLS(obj.desired, comp, defs.MetricsPort); err != nil {
+				return err
+			}
+		}
+		if err := instance.EnsureMonitoringResource(ctx, r.Client, r.recorder, comp, fieldManager, obj.gvk, obj.desired,
+			instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: comp.Spec.Overlays}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -19,16 +19,24 @@ package component
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/managedfields"
 	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -806,3 +814,312 @@ func TestReconcileLabelExclusion(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilePlanMode(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+
+	tests := []struct {
+		name        string
+		annotation  string
+		mode        string
+		wantApplied bool
+	}{
+		{name: "apply mode applies the resources", wantApplied: true},
+		{name: "annotation plans the resources", annotation: instance.ReconcileModePlan},
+		{name: "operator-wide plan mode plans the resources", mode: instance.ReconcileModePlan},
+		{
+			name:        "annotation applies the resources in operator-wide plan mode",
+			annotation:  instance.ReconcileModeApply,
+			mode:        instance.ReconcileModePlan,
+			wantApplied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comp := newMetacollectorComponent(defaultName).Build()
+			// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+			comp.Finalizers = []string{finalizer}
+			if tt.annotation != "" {
+				comp.Annotations = map[string]string{instance.ReconcileModeAnnotation: tt.annotation}
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(comp).
+				WithStatusSubresource(comp).Build()
+			r := NewReconciler(cl, scheme, events.NewFakeRecorder(50), WithReconcileMode(tt.mode))
+
+			_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(comp)})
+			require.NoError(t, err)
+
+			depErr := cl.Get(context.Background(), client.ObjectKeyFromObject(comp), &appsv1.Deployment{})
+			srv := &instancev1alpha1.Component{}
+			require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(comp), srv))
+			if tt.wantApplied {
+				require.NoError(t, depErr)
+				assert.Empty(t, srv.Status.PendingChanges)
+				return
+			}
+			assert.True(t, k8serrors.IsNotFound(depErr), "deployment must not be created in plan mode")
+			assert.Contains(t, srv.Status.PendingChanges, commonv1alpha1.PendingChange{
+				Action: commonv1alpha1.PendingActionCreate, Kind: resources.ResourceTypeDeployment, Name: defaultName,
+			})
+			assert.Contains(t, srv.Status.PendingChanges, commonv1alpha1.PendingChange{
+				Action: commonv1alpha1.PendingActionCreate, Kind: "ClusterRole",
+				Name: resources.GenerateUniqueName(comp.Name, comp.Namespace),
+			})
+			testutil.RequireCondition(t, srv.Status.Conditions, commonv1alpha1.ConditionReconciled.String(),
+				metav1.ConditionFalse, instance.ReasonChangesPending)
+		})
+	}
+}
+
+func TestReconcileSuspended(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	comp := newMetacollectorComponent(defaultName).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	comp.Finalizers = []string{finalizer}
+	comp.Spec.Suspend = true
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(comp).WithStatusSubresource(comp).Build()
+	recorder := events.NewFakeRecorder(50)
+	r := NewReconciler(cl, scheme, recorder)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(comp)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	depErr := cl.Get(context.Background(), req.NamespacedName, &appsv1.Deployment{})
+	assert.True(t, k8serrors.IsNotFound(depErr), "deployment must not be created while suspended")
+	srv := &instancev1alpha1.Component{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	testutil.RequireCondition(t, srv.Status.Conditions, commonv1alpha1.ConditionSuspended.String(),
+		metav1.ConditionTrue, instance.ReasonSuspended)
+	found := false
+	for len(recorder.Events) > 0 {
+		event := <-recorder.Events
+		found = found || strings.Contains(event, instance.ReasonSuspended)
+	}
+	assert.True(t, found, "a Suspended event must be recorded")
+
+	srv.Spec.Suspend = false
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.Deployment{}))
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	assert.Nil(t, apimeta.FindStatusCondition(srv.Status.Conditions, commonv1alpha1.ConditionSuspended.String()))
+}
+
+func TestReconcileOverlays(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	comp := newMetacollectorComponent(defaultName).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	comp.Finalizers = []string{finalizer}
+	comp.Spec.Overlays = []commonv1alpha1.Overlay{{
+		Target: commonv1alpha1.OverlayTarget{Kind: "Deployment"},
+		Patch:  apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"annotations":{"team":"security"}}}`)},
+	}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(comp).WithStatusSubresource(comp).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(50))
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(comp)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	dep := &appsv1.Deployment{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dep))
+	assert.Equal(t, "security", dep.Annotations["team"])
+
+	srv := &instancev1alpha1.Component{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	srv.Spec.Overlays[0].Type = commonv1alpha1.OverlayPatchTypeJSON6902
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	testutil.RequireCondition(t, srv.Status.Conditions, commonv1alpha1.ConditionReconciled.String(),
+		metav1.ConditionFalse, instance.ReasonInvalidOverlay)
+}
+
+func TestReconcileMonitoring(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	comp := newMetacollectorComponent(defaultName).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	comp.Finalizers = []string{finalizer}
+	comp.Spec.Monitoring = &commonv1alpha1.MonitoringSpec{
+		ServiceMonitor: &commonv1alpha1.MonitorSpec{Enabled: true, Interval: "30s", Labels: map[string]string{"release": "prom"}},
+	}
+	mapper := testutil.RESTMapper(scheme, resources.ServiceMonitorGVK, resources.PodMonitorGVK)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).
+		WithObjects(comp).WithStatusSubresource(comp).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(50))
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(comp)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(resources.ServiceMonitorGVK)
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, sm))
+	assert.Equal(t, "prom", sm.GetLabels()["release"])
+	endpoints, _, _ := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, resources.MetacollectorDefaults.MetricsPort, endpoints[0].(map[string]any)["port"])
+	assert.Equal(t, "30s", endpoints[0].(map[string]any)["interval"])
+
+	pm := &unstructured.Unstructured{}
+	pm.SetGroupVersionKind(resources.PodMonitorGVK)
+	assert.True(t, k8serrors.IsNotFound(cl.Get(context.Background(), req.NamespacedName, pm)))
+}
+
+func TestReconcileNetworkPolicy(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	comp := builders.NewComponent().WithName(defaultName).WithNamespace(testutil.TestNamespace).
+		WithComponentType(instancev1alpha1.ComponentTypeFalcosidekickUI).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	comp.Finalizers = []string{finalizer}
+	comp.Spec.NetworkPolicy = &commonv1alpha1.NetworkPolicySpec{Enabled: true}
+	// The fake client still handles NetworkPolicy as having a status, which its typed converter
+	// cannot parse: use the deduced one only.
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithTypeConverters(managedfields.NewDeducedTypeConverter()).
+		WithObjects(comp).WithStatusSubresource(comp).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(50))
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(comp)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	// The UI is reached by Falcosidekick and only reaches Redis.
+	policy := &networkingv1.NetworkPolicy{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, policy))
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+	require.Len(t, policy.Spec.Ingress, 1)
+	require.Len(t, policy.Spec.Ingress[0].From, 1)
+	assert.Equal(t, resources.FalcosidekickTypeName,
+		policy.Spec.Ingress[0].From[0].PodSelector.MatchLabels[resources.InstanceTypeLabel])
+	assert.NotEmpty(t, policy.Spec.Egress)
+
+	srv := &instancev1alpha1.Component{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	srv.Spec.NetworkPolicy = nil
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.Background(), req.NamespacedName, &networkingv1.NetworkPolicy{})
+	assert.True(t, k8serrors.IsNotFound(err), "disabled NetworkPolicy must be deleted")
+}
+
+func TestReconcilePodDisruptionBudget(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	// Falcosidekick runs 2 replicas by default.
+	comp := newFalcosidekickComponent(defaultName).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	comp.Finalizers = []string{finalizer}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(comp).WithStatusSubresource(comp).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(50))
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(comp)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, pdb))
+	assert.Equal(t, 1, pdb.Spec.MaxUnavailable.IntValue())
+	assert.True(t, metav1.IsControlledBy(pdb, comp))
+
+	srv := &instancev1alpha1.Component{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	srv.Spec.Replicas = new(int32(1))
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.Background(), req.NamespacedName, &policyv1.PodDisruptionBudget{})
+	assert.True(t, k8serrors.IsNotFound(err), "PodDisruptionBudget of a single replica must be deleted")
+}
+
+func TestReconcileAutoscaling(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	comp := newFalcosidekickComponent(defaultName).WithReplicas(1).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	comp.Finalizers = []string{finalizer}
+	comp.Spec.Autoscaling = &instancev1alpha1.AutoscalingSpec{Enabled: true, MinReplicas: new(int32(2)), MaxReplicas: 6}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(comp).WithStatusSubresource(comp).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(50))
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(comp)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, hpa))
+	assert.Equal(t, defaultName, hpa.Spec.ScaleTargetRef.Name)
+	assert.Equal(t, int32(6), hpa.Spec.MaxReplicas)
+
+	// The replicas are left to the autoscaler, and its minimum needs a PodDisruptionBudget.
+	dep := &appsv1.Deployment{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dep))
+	assert.Nil(t, dep.Spec.Replicas)
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &policyv1.PodDisruptionBudget{}))
+
+	srv := &instancev1alpha1.Component{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	srv.Spec.Autoscaling.Enabled = false
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.Background(), req.NamespacedName, &autoscalingv2.HorizontalPodAutoscaler{})
+	assert.True(t, k8serrors.IsNotFound(err), "disabled HorizontalPodAutoscaler must be deleted")
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dep))
+	assert.Equal(t, new(int32(1)), dep.Spec.Replicas)
+}
+
+func TestReconcileTLS(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	comp := builders.NewComponent().WithName(defaultName).WithNamespace(testutil.TestNamespace).
+		WithComponentType(instancev1alpha1.ComponentTypeFalcosidekick).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	comp.Finalizers = []string{finalizer}
+	comp.Spec.TLS = &commonv1alpha1.TLSSpec{Enabled: true}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(comp).WithStatusSubresource(comp).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(50))
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(comp)}
+
+	res, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Positive(t, res.RequeueAfter, "the reconciliation must be requeued to renew the certificate")
+
+	secretKey := client.ObjectKey{Namespace: testutil.TestNamespace, Name: resources.CertificateSecretName(defaultName)}
+	secret := &corev1.Secret{}
+	require.NoError(t, cl.Get(context.Background(), secretKey, secret))
+	assert.True(t, metav1.IsControlledBy(secret, comp))
+
+	srv := &instancev1alpha1.Component{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	require.NotNil(t, srv.Status.TLS)
+
+	// Falcosidekick serves TLS and keeps a plain port for the probes.
+	dep := &appsv1.Deployment{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dep))
+	assert.Equal(t, srv.Status.TLS.SerialNumber, dep.Spec.Template.Annotations[resources.CertificateSerialAnnotation])
+	require.NotEmpty(t, dep.Spec.Template.Spec.Containers)
+	assert.Contains(t, dep.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "TLSSERVER_DEPLOY", Value: "true"})
+	require.NotNil(t, dep.Spec.Template.Spec.Containers[0].ReadinessProbe)
+	assert.Equal(t, "http-notls", dep.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet.Port.String())
+
+	srv.Spec.TLS = nil
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.Background(), secretKey, &corev1.Secret{})
+	assert.True(t, k8serrors.IsNotFound(err), "disabled certificate Secret must be deleted")
+}
@@ -24,13 +24,25 @@ import (
 	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
 )
 
+// generateApplyConfiguration generates the workload of the component. When TLS is enabled, the certificate is
+// mounted and its serial number stamped on the pod template, to roll the pods when it is renewed.
 func generateApplyConfiguration(comp *instancev1alpha1.Component, defs *resources.InstanceDefaults) (*unstructured.Unstructured, error) {
 	baseResource, err := resources.GenerateWorkload(defs.ResourceType, &comp.ObjectMeta, defs, false)
 	if err != nil {
 		return nil, err
 	}
 
-	userOverlay, err := resources.GenerateUserOverlay(defs.ResourceType, comp.Name, defs, resources.GenerateOverlayOptions(comp)...)
+	overlayOpts := resources.GenerateOverlayOptions(comp)
+	if tlsEnabled(comp) {
+		resources.EnableTLS(baseResource, comp.Name, defs)
+		if comp.Status.TLS != nil {
+			overlayOpts = append(overlayOpts, resources.WithOverlayPodAnnotations(map[string]string{
+				resources.CertificateSerialAnnotation: comp.Status.TLS.SerialNumber,
+			}))
+		}
+	}
+
+	userOverlay, err := resources.GenerateUserOverlay(defs.ResourceType, comp.Name, defs, overlayOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -20,11 +20,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,17 +37,26 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/instance"
 	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 const (
@@ -66,6 +80,10 @@ type Reconciler struct {
 	NativeSidecar bool
 	// labelFilter excludes label keys from propagation onto generated resources.
 	labelFilter instance.LabelFilter
+	// healthChecker scrapes the Falco pods when health checks are enabled.
+	healthChecker *instance.HealthChecker
+	// reconcileMode is the reconcile mode of the instances without a reconcile mode annotation.
+	reconcileMode string
 }
 
 // Option configures a Reconciler.
@@ -78,6 +96,20 @@ func WithLabelFilter(f instance.LabelFilter) Option {
 	}
 }
 
+// WithHealthScraper sets the scraper used to read the health of the Falco pods.
+func WithHealthScraper(scraper instance.HealthScraper) Option {
+	return func(r *Reconciler) {
+		r.healthChecker = instance.NewHealthChecker(scraper)
+	}
+}
+
+// WithReconcileMode sets the reconcile mode of the instances without a reconcile mode annotation.
+func WithReconcileMode(mode string) Option {
+	return func(r *Reconciler) {
+		r.reconcileMode = mode
+	}
+}
+
 // NewReconciler creates a new Reconciler.
 func NewReconciler(cl client.Client, scheme *runtime.Scheme, recorder events.EventRecorder,
 	nativeSidecar bool, opts ...Option) *Reconciler {
@@ -86,6 +118,7 @@ func NewReconciler(cl client.Client, scheme *runtime.Scheme, recorder events.Eve
 		Scheme:        scheme,
 		recorder:      recorder,
 		NativeSidecar: nativeSidecar,
+		healthChecker: instance.NewHealthChecker(instance.NewHTTPHealthScraper()),
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -96,16 +129,20 @@ func NewReconciler(cl client.Client, scheme *runtime.Scheme, recorder events.Eve
 // +kubebuilder:rbac:groups=instance.falcosecurity.dev,resources=falcos;falcos/status,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=rulesfiles;rulesfiles/status,verbs=get;list;patch;update;watch
 // +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=configs;configs/status,verbs=get;list;patch;update;watch
-// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=plugins;plugins/status,verbs=get;list;patch;update;watch
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=plugins;plugins/status,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=instance.falcosecurity.dev,resources=components,verbs=get;list;watch
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=artifactnodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups="",resources=pods;services;configmaps;serviceaccounts,verbs=create;delete;get;list;patch;update;watch
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;patch;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch;update
 // +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=create;get;update
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors;prometheusrules,verbs=create;delete;get;patch;update
 // +kubebuilder:rbac:urls=/metrics,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -121,6 +158,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		logger.Error(err, "unable to fetch falco instance")
 		return ctrl.Result{}, err
 	} else if k8serrors.IsNotFound(err) {
+		instance.ForgetReplicas("Falco", req.NamespacedName)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -134,8 +172,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	// resource on the API server keeps its labels.
 	falco.SetLabels(r.labelFilter.Apply(falco.GetLabels()))
 
+	// In plan mode, collect the changes to the generated resources instead of applying them.
+	var plan *instance.Plan
+	if instance.ResolveReconcileMode(falco, r.reconcileMode) == instance.ReconcileModePlan {
+		plan = &instance.Plan{}
+		ctx = instance.PlanIntoContext(ctx, plan)
+	}
+
+	// Detect the fields of the generated resources changed by other field managers.
+	drift := instance.NewDrift(falco.Spec.DriftPolicy)
+	ctx = instance.DriftIntoContext(ctx, drift)
+
 	// Patch status via defer to ensure it's always called.
 	defer func() {
+		// An invalid overlay is only fixed by changing the spec: report it instead of retrying.
+		if errors.Is(reterr, instance.ErrInvalidOverlay) {
+			instance.RecordInvalidOverlay(r.recorder, falco, &falco.Status.Conditions, reterr)
+			reterr = nil
+		}
+		falco.Status.PendingChanges = instance.RecordPlan(r.recorder, falco, &falco.Status.Conditions,
+			falco.Status.PendingChanges, plan)
+		healthErr := r.computeHealthConditions(ctx, falco)
+		if healthErr != nil {
+			logger.Error(healthErr, "unable to compute health conditions")
+		}
 		computeErr := r.computeAvailableCondition(ctx, falco)
 		if computeErr != nil {
 			logger.Error(computeErr, "unable to compute available condition")
@@ -144,14 +204,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		if patchErr != nil {
 			logger.Error(patchErr, "unable to patch Falco status")
 		}
-		reterr = kerrors.NewAggregate([]error{reterr, computeErr, patchErr})
+		reterr = kerrors.NewAggregate([]error{reterr, healthErr, computeErr, patchErr})
 	}()
 
-	resolvedImage := instance.ResolveVersion(falco, resources.FalcoDefaults)
+	resolvedVersion := instance.ResolveVersion(falco, resources.FalcoDefaults)
 	resourceType := resolveResourceType(falco.Spec.Type)
-	falco.Status.Version = resolvedImage
 	falco.Status.ResourceType = resourceType
 
+	// While suspended, keep reporting the status without touching the generated resources.
+	instance.RecordSuspended(r.recorder, falco, &falco.Status.Conditions, falco.Spec.Suspend)
+	if falco.Spec.Suspend {
+		logger.V(2).Info("Reconciliation suspended, skipping the generated resources")
+		requeueAfter, _ := healthCheckRequeue(falco, time.Now())
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// Check the overlays before applying any of the generated resources.
+	if err := instance.ValidateOverlays(falco.Spec.Overlays); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Move the version upgrade forward, pinning the version the workloads run meanwhile.
+	upgradeRequeue, err := r.reconcileUpgrade(ctx, falco, resolvedVersion, metav1.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Ensure the service account is created.
 	if err := r.ensureServiceAccount(ctx, falco); err != nil {
 		return ctrl.Result{}, err
@@ -182,16 +260,46 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Ensure the prometheus-operator objects are created.
+	if err := r.ensureMonitoring(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the networkpolicy is created.
+	if err := r.ensureNetworkPolicy(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the certificate is issued when TLS is enabled.
+	if err := r.ensureCertificate(ctx, falco, time.Now()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the k8smeta plugin connects Falco to the referenced metacollector.
+	if err := r.ensureCollector(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Ensure the configmap is created
 	if err := r.ensureConfigMap(ctx, falco); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Ensure the configmaps of the node pools are created.
+	if err := r.ensureNodePoolConfigMaps(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Cleanup dual deployments.
 	if err := r.cleanupDualDeployments(ctx, falco); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Cleanup the resources of removed node pools.
+	if err := r.cleanupNodePools(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Set the finalizer if needed.
 	if ok, err := r.ensureFinalizer(ctx, falco); ok || err != nil {
 		return ctrl.Result{}, err
@@ -202,13 +310,53 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Replace the pods of the canary nodes so that they run the version being upgraded to.
+	if err := r.replaceCanaryPods(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Restart the pods that are running with artifacts they cannot hot-reload.
+	if err := r.restartPods(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Publish the state the artifact operator of each node reports, and delete it for nodes no longer running Falco.
+	if err := r.publishNodeConfigMaps(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.cleanupNodeConfigMaps(ctx, falco); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report the drift once every generated resource has been checked.
+	instance.RecordDrift(r.recorder, falco, &falco.Status.Conditions, drift)
+
+	// Scrape the pods again when the health check interval has elapsed, and check the upgrade again.
+	requeueAfter := upgradeRequeue
+	if scrapeAfter, enabled := healthCheckRequeue(falco, time.Now()); enabled && (requeueAfter == 0 || scrapeAfter < requeueAfter) {
+		requeueAfter = scrapeAfter
+	}
+	// Renew the certificate in time.
+	if renewAfter := instance.CertificateRenewAfter(falco.Status.TLS, time.Now()); renewAfter > 0 &&
+		(requeueAfter == 0 || renewAfter < requeueAfter) {
+		requeueAfter = renewAfter
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&instancev1alpha1.Falco{}).
+		// Status updates, such as the health reported after each scrape, do not trigger a reconcile.
+		For(&instancev1alpha1.Falco{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.LabelChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+			predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return !obj.GetDeletionTimestamp().IsZero()
+			}),
+		))).
 		Owns(&appsv1.DaemonSet{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ServiceAccount{}).
@@ -216,27 +364,32 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&artifactv1alpha1.Plugin{}).
+		Watches(&instancev1alpha1.Component{}, handler.EnqueueRequestsFromMapFunc(r.falcosReferencingCollector)).
 		Watches(&rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestsFromMapFunc(instance.ClusterScopedResourceHandler)).
 		Watches(&rbacv1.ClusterRole{}, handler.EnqueueRequestsFromMapFunc(instance.ClusterScopedResourceHandler)).
+		Watches(&artifactv1alpha1.ArtifactNode{}, handler.EnqueueRequestsFromMapFunc(r.falcosTargetedByArtifactNode),
+			builder.WithPredicates(nodeObjectChangedPredicate())).
 		Named("falco").
-		Complete(r)
+		Complete(tracing.Reconciler("Falco", r))
 }
 
-// ensureDeployment ensures the Falco deployment or daemonset is created or updated.
+// ensureDeployment ensures the Falco deployment or daemonset is created or updated, along with the
+// daemonsets of the node pools.
 func (r *Reconciler) ensureDeployment(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	logger := log.FromContext(ctx)
 
 	// Condition values to be set during reconciliation.
-	conditionStatus := metav1.ConditionTrue
-	conditionReason := ""
-	conditionMessage := ""
+	outcome := workloadOutcome{status: metav1.ConditionTrue}
 
 	// Ensure the reconcile status is saved.
 	defer func() {
 		apimeta.SetStatusCondition(&falco.Status.Conditions, common.NewReconciledCondition(
-			conditionStatus,
-			conditionReason,
-			conditionMessage,
+			outcome.status,
+			outcome.reason,
+			outcome.message,
 			falco.GetGeneration(),
 		))
 	}()
@@ -244,22 +397,96 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, falco *instancev1alph
 	resourceType := resolveResourceType(falco.Spec.Type)
 
 	logger.V(2).Info("Generating apply configuration from user input")
-	applyConfig, err := generateApplyConfiguration(falco, resourceType, r.NativeSidecar)
+	applyConfigs, err := r.generateWorkloads(falco, resourceType)
 	if err != nil {
 		logger.Error(err, "unable to generate apply configuration")
-		conditionStatus = metav1.ConditionFalse
-		conditionReason = instance.ReasonApplyConfigurationError
-		conditionMessage = fmt.Sprintf(instance.MessageFormatApplyConfigurationError, err.Error())
+		outcome = workloadOutcome{
+			status:  metav1.ConditionFalse,
+			reason:  instance.ReasonApplyConfigurationError,
+			message: fmt.Sprintf(instance.MessageFormatApplyConfigurationError, err.Error()),
+		}
+		if errors.Is(err, instance.ErrInvalidOverlay) {
+			outcome.reason = instance.ReasonInvalidOverlay
+			outcome.message = fmt.Sprintf(instance.MessageFormatInvalidOverlay, err.Error())
+		}
 		return err
 	}
 
+	// Report the most significant outcome: an update over a creation over an up-to-date workload.
+	for i, applyConfig := range applyConfigs {
+		result, err := r.applyWorkload(ctx, falco, applyConfig, resourceType)
+		if err != nil || result.status != metav1.ConditionTrue {
+			outcome = result
+			return err
+		}
+		if i == 0 || workloadOutcomeRank[result.reason] > workloadOutcomeRank[outcome.reason] {
+			outcome = result
+		}
+	}
+
+	if instance.PlanFromContext(ctx) == nil {
+		revision, err := configRevision(falco, resourceType)
+		if err != nil {
+			return err
+		}
+		falco.Status.ConfigRevision = revision
+	}
+	return nil
+}
+
+// workloadOutcome is the Reconciled condition resulting from applying a workload.
+type workloadOutcome struct {
+	status  metav1.ConditionStatus
+	reason  string
+	message string
+}
+
+// workloadOutcomeRank orders the successful outcomes of applying the workloads of a Falco instance.
+var workloadOutcomeRank = map[string]int{
+	instance.ReasonResourceUpToDate: 0,
+	instance.ReasonResourceCreated:  1,
+	instance.ReasonResourceUpdated:  2,
+	instance.ReasonChangesPending:   3,
+}
+
+// generateWorkloads generates the apply configurations of the default workload and of the node pool daemonsets.
+func (r *Reconciler) generateWorkloads(falco *instancev1alpha1.Falco, resourceType string) ([]*unstructured.Unstructured, error) {
+	applyConfig, err := generateApplyConfiguration(falco, resourceType, r.NativeSidecar)
+	if err != nil {
+		return nil, err
+	}
+	applyConfigs := []*unstructured.Unstructured{applyConfig}
+
+	for i := range nodePools(falco, resourceType) {
+		applyConfig, err := generateNodePoolApplyConfiguration(falco, i, r.NativeSidecar)
+		if err != nil {
+			return nil, err
+		}
+		applyConfigs = append(applyConfigs, applyConfig)
+	}
+
+	for i := range applyConfigs {
+		if applyConfigs[i], err = instance.ApplyOverlays(r.Scheme, applyConfigs[i], falco.Spec.Overlays); err != nil {
+			return nil, err
+		}
+	}
+
+	return applyConfigs, nil
+}
+
+// applyWorkload creates or updates a Falco deployment or daemonset from its apply configuration.
+func (r *Reconciler) applyWorkload(ctx context.Context, falco *instancev1alpha1.Falco,
+	applyConfig *unstructured.Unstructured, resourceType string) (workloadOutcome, error) {
+	logger := log.FromContext(ctx).WithValues("name", applyConfig.GetName())
+
 	applyConfigYaml, err := yaml.Marshal(applyConfig.Object)
 	if err != nil {
 		logger.Error(err, "unable to marshal apply configuration")
-		conditionStatus = metav1.ConditionFalse
-		conditionReason = instance.ReasonMarshalConfigurationError
-		conditionMessage = fmt.Sprintf(instance.MessageFormatMarshalConfigurationError, err.Error())
-		return err
+		return workloadOutcome{
+			status:  metav1.ConditionFalse,
+			reason:  instance.ReasonMarshalConfigurationError,
+			message: fmt.Sprintf(instance.MessageFormatMarshalConfigurationError, err.Error()),
+		}, err
 	}
 
 	logger.V(4).Info("Generated apply configuration", "yaml", string(applyConfigYaml))
@@ -267,10 +494,11 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, falco *instancev1alph
 	// Set owner reference.
 	if err = ctrl.SetControllerReference(falco, applyConfig, r.Scheme); err != nil {
 		logger.Error(err, "unable to set owner reference")
-		conditionStatus = metav1.ConditionFalse
-		conditionReason = instance.ReasonOwnerReferenceError
-		conditionMessage = fmt.Sprintf(instance.MessageFormatOwnerReferenceError, err.Error())
-		return err
+		return workloadOutcome{
+			status:  metav1.ConditionFalse,
+			reason:  instance.ReasonOwnerReferenceError,
+			message: fmt.Sprintf(instance.MessageFormatOwnerReferenceError, err.Error()),
+		}, err
 	}
 
 	// Check if the resource already exists.
@@ -281,15 +509,16 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, falco *instancev1alph
 		Kind:    resourceType,
 	})
 	resourceExists := true
-	if err = r.Get(ctx, client.ObjectKeyFromObject(falco), existingResource); err != nil {
+	if err = r.Get(ctx, client.ObjectKeyFromObject(applyConfig), existingResource); err != nil {
 		if k8serrors.IsNotFound(err) {
 			resourceExists = false
 		} else {
 			logger.Error(err, "unable to fetch existing resource")
-			conditionStatus = metav1.ConditionFalse
-			conditionReason = instance.ReasonExistingResourceError
-			conditionMessage = fmt.Sprintf(instance.MessageFormatExistingResourceError, err.Error())
-			return err
+			return workloadOutcome{
+				status:  metav1.ConditionFalse,
+				reason:  instance.ReasonExistingResourceError,
+				message: fmt.Sprintf(instance.MessageFormatExistingResourceError, err.Error()),
+			}, err
 		}
 	}
 
@@ -298,43 +527,68 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, falco *instancev1alph
 	// See: https://github.com/kubernetes/kubernetes/issues/124605
 	var changedFields string
 	if resourceExists {
+		if applyConfig, err = instance.CheckDrift(ctx, existingResource, applyConfig, fieldManager); err != nil {
+			logger.Error(err, "unable to detect drift of existing resource")
+			return workloadOutcome{
+				status:  metav1.ConditionFalse,
+				reason:  instance.ReasonResourceComparisonError,
+				message: fmt.Sprintf(instance.MessageFormatResourceComparisonError, err.Error()),
+			}, err
+		}
 		comparison, err := controllerhelper.Diff(existingResource, applyConfig, fieldManager)
 		if err != nil {
 			if !errors.Is(err, controllerhelper.ErrNoManagedFields) {
 				logger.Error(err, "unable to compare existing resource with desired state")
-				conditionStatus = metav1.ConditionFalse
-				conditionReason = instance.ReasonResourceComparisonError
-				conditionMessage = fmt.Sprintf(instance.MessageFormatResourceComparisonError, err.Error())
-				return err
+				return workloadOutcome{
+					status:  metav1.ConditionFalse,
+					reason:  instance.ReasonResourceComparisonError,
+					message: fmt.Sprintf(instance.MessageFormatResourceComparisonError, err.Error()),
+				}, err
 			}
 			logger.V(2).Info("No managed fields found, proceeding with apply to take ownership", "kind", falco.Spec.Type)
 		} else {
 			if comparison.IsSame() {
 				logger.V(2).Info("Falco resource is up to date, skipping apply", "kind", falco.Spec.Type)
-				conditionReason = instance.ReasonResourceUpToDate
-				conditionMessage = instance.MessageResourceUpToDate
-				return nil
+				return workloadOutcome{
+					status:  metav1.ConditionTrue,
+					reason:  instance.ReasonResourceUpToDate,
+					message: instance.MessageResourceUpToDate,
+				}, nil
 			}
 			changedFields = controllerhelper.FormatChangedFields(comparison)
 		}
 	}
 
+	// In plan mode, record the change instead of applying it.
+	if plan := instance.PlanFromContext(ctx); plan != nil {
+		action := commonv1alpha1.PendingActionUpdate
+		if !resourceExists {
+			action = commonv1alpha1.PendingActionCreate
+		}
+		logger.Info("Falco resource change planned", "kind", resourceType, "action", action, "changedFields", changedFields)
+		plan.Add(action, resourceType, applyConfig.GetName(), changedFields)
+		return workloadOutcome{
+			status:  metav1.ConditionTrue,
+			reason:  instance.ReasonChangesPending,
+			message: fmt.Sprintf(instance.MessageFormatChangesPending, 1, fmt.Sprintf("%s %s %s", action, resourceType, applyConfig.GetName())),
+		}, nil
+	}
+
 	if !resourceExists {
 		logger.Info("Creating Falco resource", "kind", falco.Spec.Type)
 	}
 
 	applyOpts := []client.ApplyOption{client.ForceOwnership, client.FieldOwner(fieldManager)}
 	if err = r.Apply(ctx, client.ApplyConfigurationFromUnstructured(applyConfig), applyOpts...); err != nil {
+		outcome := workloadOutcome{status: metav1.ConditionFalse}
 		if !resourceExists {
-			conditionStatus = metav1.ConditionFalse
-			conditionReason = instance.ReasonApplyPatchErrorOnCreate
-			conditionMessage = fmt.Sprintf(instance.MessageFormatApplyPatchErrorOnCreate, err.Error())
+			outcome.reason = instance.ReasonApplyPatchErrorOnCreate
+			outcome.message = fmt.Sprintf(instance.MessageFormatApplyPatchErrorOnCreate, err.Error())
 			r.recorder.Eventf(falco, nil, corev1.EventTypeWarning, instance.ReasonApplyPatchErrorOnCreate,
 				instance.ReasonApplyPatchErrorOnCreate, instance.MessageFormatApplyPatchErrorOnCreate, err.Error())
 		} else {
-			conditionStatus = metav1.ConditionFalse
-			conditionReason = instance.ReasonApplyPatchErrorOnUpdate
-			conditionMessage = fmt.Sprintf(instance.MessageFormatApplyPatchErrorOnUpdate, err.Error())
+			outcome.reason = instance.ReasonApplyPatchErrorOnUpdate
+			outcome.message = fmt.Sprintf(instance.MessageFormatApplyPatchErrorOnUpdate, err.Error())
 			r.recorder.Eventf(falco, nil, corev1.EventTypeWarning, instance.ReasonApplyPatchErrorOnUpdate,
 				instance.ReasonApplyPatchErrorOnUpdate, instance.MessageFormatApplyPatchErrorOnUpdate, err.Error())
 		}
@@ -342,27 +596,31 @@ func (r *Reconciler) ensureDeployment(ctx context.Context, falco *instancev1alph
 		// Don't requeue; the next reconciliation will be triggered by the CR update.
 		if k8serrors.IsInvalid(err) {
 			logger.Info("Apply rejected by API server due to invalid input", "kind", falco.Spec.Type, "error", err.Error())
-			return nil
+			return outcome, nil
 		}
 		logger.Error(err, "unable to apply resource", "kind", falco.Spec.Type)
-		return err
+		return outcome, err
 	}
 
 	if !resourceExists {
 		logger.Info("Falco resource created", "kind", falco.Spec.Type)
-		conditionReason = instance.ReasonResourceCreated
-		conditionMessage = instance.MessageResourceCreated
 		r.recorder.Eventf(falco, nil, corev1.EventTypeNormal, instance.ReasonResourceCreated,
 			instance.ReasonResourceCreated, instance.MessageResourceCreated)
-	} else {
-		logger.Info("Falco resource updated", "kind", falco.Spec.Type, "changedFields", changedFields)
-		conditionReason = instance.ReasonResourceUpdated
-		conditionMessage = fmt.Sprintf(instance.MessageFormatResourceUpdated, changedFields)
-		r.recorder.Eventf(falco, nil, corev1.EventTypeNormal, instance.ReasonResourceUpdated,
-			instance.ReasonResourceUpdated, instance.MessageFormatResourceUpdated, changedFields)
+		return workloadOutcome{
+			status:  metav1.ConditionTrue,
+			reason:  instance.ReasonResourceCreated,
+			message: instance.MessageResourceCreated,
+		}, nil
 	}
 
-	return nil
+	logger.Info("Falco resource updated", "kind", falco.Spec.Type, "changedFields", changedFields)
+	r.recorder.Eventf(falco, nil, corev1.EventTypeNormal, instance.ReasonResourceUpdated,
+		instance.ReasonResourceUpdated, instance.MessageFormatResourceUpdated, changedFields)
+	return workloadOutcome{
+		status:  metav1.ConditionTrue,
+		reason:  instance.ReasonResourceUpdated,
+		message: fmt.Sprintf(instance.MessageFormatResourceUpdated, changedFields),
+	}, nil
 }
 
 // cleanupDualDeployments ensures there is no dual deployment for the given Falco instance.
@@ -392,6 +650,12 @@ func (r *Reconciler) cleanupDualDeployments(ctx context.Context, falco *instance
 			return err
 		}
 
+		// In plan mode, record the deletion instead of deleting it.
+		if plan := instance.PlanFromContext(ctx); err == nil && plan != nil {
+			plan.Add(commonv1alpha1.PendingActionDelete, t, existingResource.GetName(), "")
+			continue
+		}
+
 		// If the resource exists, delete it.
 		if err == nil {
 			logger.Info("Deleting dual deployment resource", "kind", t)
@@ -407,21 +671,416 @@ func (r *Reconciler) cleanupDualDeployments(ctx context.Context, falco *instance
 	return nil
 }
 
+// cleanupNodePools deletes the daemonsets and configmaps of the node pools that are no longer in the spec,
+// or of every node pool when Falco is not deployed as a daemonset.
+func (r *Reconciler) cleanupNodePools(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	logger := log.FromContext(ctx)
+
+	pools := map[string]*instancev1alpha1.NodePool{}
+	for i, pool := range nodePools(falco, resolveResourceType(falco.Spec.Type)) {
+		pools[pool.Name] = &falco.Spec.NodePools[i]
+	}
+
+	deleteStale := func(obj client.Object, kind string) error {
+		if !metav1.IsControlledBy(obj, falco) {
+			return nil
+		}
+		if plan := instance.PlanFromContext(ctx); plan != nil {
+			plan.Add(commonv1alpha1.PendingActionDelete, kind, obj.GetName(), "")
+			return nil
+		}
+		poolName := obj.GetLabels()[resources.NodePoolLabel]
+		logger.Info("Deleting resource of removed node pool", "kind", kind, "name", obj.GetName(), "pool", poolName)
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "unable to delete resource of removed node pool", "kind", kind, "name", obj.GetName())
+			return err
+		}
+		r.recorder.Eventf(falco, nil, corev1.EventTypeNormal, instance.ReasonNodePoolCleanup,
+			instance.ReasonNodePoolCleanup, instance.MessageFormatNodePoolCleanup, kind, obj.GetName(), poolName)
+		return nil
+	}
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := r.List(ctx, daemonSets, client.InNamespace(falco.Namespace), client.HasLabels{resources.NodePoolLabel}); err != nil {
+		return fmt.Errorf("listing node pool daemonsets: %w", err)
+	}
+	for i := range daemonSets.Items {
+		ds := &daemonSets.Items[i]
+		if _, ok := pools[ds.Labels[resources.NodePoolLabel]]; ok {
+			continue
+		}
+		if err := deleteStale(ds, resources.ResourceTypeDaemonSet); err != nil {
+			return err
+		}
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.List(ctx, configMaps, client.InNamespace(falco.Namespace), client.HasLabels{resources.NodePoolLabel}); err != nil {
+		return fmt.Errorf("listing node pool configmaps: %w", err)
+	}
+	for i := range configMaps.Items {
+		cm := &configMaps.Items[i]
+		if pool, ok := pools[cm.Labels[resources.NodePoolLabel]]; ok && pool.Config != nil {
+			continue
+		}
+		if err := deleteStale(cm, "ConfigMap"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restartPods deletes the Falco pods running on nodes whose ArtifactNodes carry a RestartRequired condition
+// newer than the pod itself. Only the ArtifactNodes of artifacts targeting this instance are considered, so
+// that an artifact restricted to another instance sharing the nodes does not restart this one. The workload
+// controller recreates the pods with the changed artifacts loaded.
+//
+// Restarts honor the maxUnavailable of the workload update strategy: pods that are not ready count against
+// it, and the pods left over are restarted by the reconciles triggered as the workload status catches up.
+// They are suspended during the canary and the soak of an upgrade, which only replace the pods of the canary
+// nodes, and resume with the rollout.
+func (r *Reconciler) restartPods(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	logger := log.FromContext(ctx)
+
+	if upgrade := falco.Status.Upgrade; upgrade != nil && (upgrade.Phase == instancev1alpha1.UpgradePhaseCanary ||
+		upgrade.Phase == instancev1alpha1.UpgradePhaseSoaking) {
+		logger.V(2).Info("Upgrade canary in progress, postponing pod restarts", "phase", upgrade.Phase)
+		return nil
+	}
+
+	nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+	if err := r.List(ctx, nodeObjects, client.InNamespace(falco.Namespace)); err != nil {
+		return fmt.Errorf("listing ArtifactNodes: %w", err)
+	}
+
+	// Keep the most recent signal for each node.
+	signals := map[string]*metav1.Condition{}
+	for i := range nodeObjects.Items {
+		nodeObject := &nodeObjects.Items[i]
+		cond := apimeta.FindStatusCondition(nodeObject.Status.Conditions, commonv1alpha1.ConditionRestartRequired.String())
+		if cond == nil || cond.Status != metav1.ConditionTrue {
+			continue
+		}
+		target, found, err := controllerhelper.NodeObjectTarget(ctx, r.Client, nodeObject)
+		if err != nil {
+			return err
+		}
+		if !found || !target.InstanceTargeted(falco.Name, falco.Labels) {
+			continue
+		}
+		if prev, ok := signals[nodeObject.Spec.NodeName]; !ok || prev.LastTransitionTime.Before(&cond.LastTransitionTime) {
+			signals[nodeObject.Spec.NodeName] = cond
+		}
+	}
+	if len(signals) == 0 {
+		return nil
+	}
+
+	pods, err := r.listPods(ctx, falco)
+	if err != nil {
+		return err
+	}
+
+	// Restarting a pod that is not ready does not take anything more down, so those go first and
+	// the ready ones share what is left of the budget.
+	budget := restartBudget(falco, len(pods.Items))
+	var candidates []*corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || !instance.IsPodReady(pod) {
+			budget--
+		}
+		cond, ok := signals[pod.Spec.NodeName]
+		if !ok || pod.DeletionTimestamp != nil || !pod.CreationTimestamp.Before(&cond.LastTransitionTime) {
+			continue
+		}
+		candidates = append(candidates, pod)
+	}
+	slices.SortStableFunc(candidates, func(a, b *corev1.Pod) int {
+		if ra, rb := instance.IsPodReady(a), instance.IsPodReady(b); ra != rb {
+			if ra {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.Spec.NodeName, b.Spec.NodeName)
+	})
+
+	var errs []error
+	for i, pod := range candidates {
+		if instance.IsPodReady(pod) {
+			if budget <= 0 {
+				logger.Info("Deferring Falco pod restarts until restarted pods are ready", "pending", len(candidates)-i)
+				break
+			}
+			budget--
+		}
+		cond := signals[pod.Spec.NodeName]
+		if plan := instance.PlanFromContext(ctx); plan != nil {
+			plan.Add(commonv1alpha1.PendingActionDelete, "Pod", pod.Name, "")
+			continue
+		}
+		if err := r.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "unable to restart Falco pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+			r.recorder.Eventf(falco, nil, corev1.EventTypeWarning, instance.ReasonFalcoPodRestartError, instance.ReasonFalcoPodRestartError,
+				instance.MessageFormatFalcoPodRestartError, pod.Name, pod.Spec.NodeName, err.Error())
+			errs = append(errs, err)
+			continue
+		}
+		logger.Info("Restarted Falco pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+		r.recorder.Eventf(falco, nil, corev1.EventTypeNormal, instance.ReasonFalcoPodRestarted, instance.ReasonFalcoPodRestarted,
+			instance.MessageFormatFalcoPodRestarted, pod.Name, pod.Spec.NodeName, cond.Message)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// restartBudget resolves the maxUnavailable of the update strategy of the Falco workload against its
+// number of pods, rounding percentages down with a minimum of 1. It falls back to the Kubernetes
+// defaults: 1 for a DaemonSet, 25% for a Deployment.
+func restartBudget(falco *instancev1alpha1.Falco, total int) int {
+	var maxUnavailable *intstr.IntOrString
+	if resolveResourceType(falco.Spec.Type) == resources.ResourceTypeDeployment {
+		maxUnavailable = ptr.To(intstr.FromString("25%"))
+		if s := falco.Spec.Strategy; s != nil && s.RollingUpdate != nil && s.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = s.RollingUpdate.MaxUnavailable
+		}
+	} else {
+		maxUnavailable = ptr.To(intstr.FromInt32(1))
+		if s := falco.Spec.UpdateStrategy; s != nil && s.RollingUpdate != nil && s.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = s.RollingUpdate.MaxUnavailable
+		}
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, total, false)
+	if err != nil || value < 1 {
+		return 1
+	}
+	return value
+}
+
+// publishNodeConfigMaps publishes the effective configuration and the rules index that the artifact operator of each
+// node running the instance reports on its ArtifactNodes, in ConfigMaps owned by the instance. The artifact operator
+// reports the same state on every ArtifactNode of the node, so the first one by name is used. In plan mode nothing is
+// published: these ConfigMaps describe the nodes and do not change the workload.
+func (r *Reconciler) publishNodeConfigMaps(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	if instance.PlanFromContext(ctx) != nil {
+		return nil
+	}
+
+	nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+	if err := r.List(ctx, nodeObjects, client.InNamespace(falco.Namespace)); err != nil {
+		return fmt.Errorf("listing ArtifactNodes: %w", err)
+	}
+	if len(nodeObjects.Items) == 0 {
+		return nil
+	}
+	slices.SortFunc(nodeObjects.Items, func(a, b artifactv1alpha1.ArtifactNode) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	pods, err := r.listPods(ctx, falco)
+	if err != nil {
+		return err
+	}
+	nodes := map[string]bool{}
+	for i := range pods.Items {
+		nodes[pods.Items[i].Spec.NodeName] = true
+	}
+
+	published := map[string]bool{}
+	publish := func(artifactKind, name, nodeName string, data map[string]string) error {
+		if published[name] {
+			return nil
+		}
+		published[name] = true
+		return controllerhelper.PublishNodeConfigMap(ctx, r.Client, falco, artifactKind, name, nodeName, data, fieldManager)
+	}
+	for i := range nodeObjects.Items {
+		nodeName := nodeObjects.Items[i].Spec.NodeName
+		status := &nodeObjects.Items[i].Status
+		if !nodes[nodeName] {
+			continue
+		}
+		if c := status.EffectiveConfig; c != nil && c.Config != "" && c.ConfigMapName == controllerhelper.EffectiveConfigMapName(falco.Name, nodeName) {
+			if err := publish(controllerhelper.ArtifactKindConfig, c.ConfigMapName, nodeName, map[string]string{
+				resources.FalcoDefaults.ConfigMapVolume.SubPath: c.Config,
+				controllerhelper.EffectiveConfigProvenanceKey:   c.Provenance,
+			}); err != nil {
+				return err
+			}
+		}
+		if index := status.RulesIndex; index != nil && index.ConfigMapName == controllerhelper.RulesIndexConfigMapName(falco.Name, nodeName) {
+			if err := publish(controllerhelper.ArtifactKindRulesfile, index.ConfigMapName, nodeName, map[string]string{
+				controllerhelper.RulesIndexKey: index.Index,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cleanupNodeConfigMaps deletes the effective configuration and rules index ConfigMaps published for nodes where no
+// pod of the instance is scheduled anymore, such as nodes removed from the cluster or no longer selected by the
+// workload. A pod recreated on such a node reports them again.
+func (r *Reconciler) cleanupNodeConfigMaps(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	logger := log.FromContext(ctx)
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.List(ctx, configMaps, client.InNamespace(falco.Namespace),
+		client.HasLabels{controllerhelper.LabelArtifactKind, controllerhelper.LabelArtifactNode}); err != nil {
+		return fmt.Errorf("listing node configmaps: %w", err)
+	}
+	if len(configMaps.Items) == 0 {
+		return nil
+	}
+
+	pods, err := r.listPods(ctx, falco)
+	if err != nil {
+		return err
+	}
+	nodes := map[string]bool{}
+	for i := range pods.Items {
+		nodes[pods.Items[i].Spec.NodeName] = true
+	}
+
+	for i := range configMaps.Items {
+		cm := &configMaps.Items[i]
+		nodeName := cm.Labels[controllerhelper.LabelArtifactNode]
+		if nodes[nodeName] || !ownedBy(cm, falco) ||
+			(cm.Name != controllerhelper.EffectiveConfigMapName(falco.Name, nodeName) &&
+				cm.Name != controllerhelper.RulesIndexConfigMapName(falco.Name, nodeName)) {
+			continue
+		}
+		if plan := instance.PlanFromContext(ctx); plan != nil {
+			plan.Add(commonv1alpha1.PendingActionDelete, "ConfigMap", cm.Name, "")
+			continue
+		}
+		logger.Info("Deleting ConfigMap of node no longer running Falco", "configMap", cm.Name, "node", nodeName)
+		if err := r.Delete(ctx, cm); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("deleting node configmap %s: %w", cm.Name, err)
+		}
+	}
+	return nil
+}
+
+// ownedBy reports whether obj has an owner reference to falco.
+func ownedBy(obj client.Object, falco *instancev1alpha1.Falco) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == falco.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// listPods lists the pods of the Falco instance.
+func (r *Reconciler) listPods(ctx context.Context, falco *instancev1alpha1.Falco) (*corev1.PodList, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(falco.Namespace),
+		client.MatchingLabels{"app.kubernetes.io/instance": falco.Name}); err != nil {
+		return nil, fmt.Errorf("listing Falco pods: %w", err)
+	}
+	return pods, nil
+}
+
+// computeHealthConditions scrapes the Falco pods and sets the per-node health and the RulesLoaded,
+// EventDrops and Degraded conditions. The pods are only scraped once the health check interval has elapsed
+// since the last scrape; meanwhile the conditions are computed from the last one. When health checks are
+// disabled, they are cleared.
+func (r *Reconciler) computeHealthConditions(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	now := metav1.Now()
+	scrapeAfter, enabled := nextHealthCheck(falco, now.Time)
+	if !enabled {
+		falco.Status.Nodes = nil
+		instance.RemoveHealthConditions(&falco.Status.Conditions)
+		return nil
+	}
+
+	maxDropRate := instance.DefaultMaxDropRatePercent
+	if falco.Spec.HealthCheck.MaxDropRatePercent != nil {
+		maxDropRate = *falco.Spec.HealthCheck.MaxDropRatePercent
+	}
+	if scrapeAfter == 0 {
+		pods, err := r.listPods(ctx, falco)
+		if err != nil {
+			return err
+		}
+		falco.Status.Nodes = r.healthChecker.Check(ctx, pods.Items, maxDropRate, now)
+	}
+	instance.SetHealthConditions(&falco.Status.Conditions, falco.Status.Nodes, maxDropRate, falco.GetGeneration())
+	return nil
+}
+
+// healthCheckInterval returns the scrape interval and whether health checks are enabled.
+func healthCheckInterval(falco *instancev1alpha1.Falco) (time.Duration, bool) {
+	hc := falco.Spec.HealthCheck
+	if hc == nil || !hc.Enabled {
+		return 0, false
+	}
+	if hc.Interval != nil && hc.Interval.Duration > 0 {
+		return hc.Interval.Duration, true
+	}
+	return instance.DefaultHealthCheckInterval, true
+}
+
+// nextHealthCheck returns the time left before the pods are due for another scrape, zero when they are due now,
+// and whether health checks are enabled. Pods never scraped are due.
+func nextHealthCheck(falco *instancev1alpha1.Falco, now time.Time) (time.Duration, bool) {
+	interval, enabled := healthCheckInterval(falco)
+	if !enabled {
+		return 0, false
+	}
+	var last time.Time
+	for _, node := range falco.Status.Nodes {
+		if node.LastScrapeTime.After(last) {
+			last = node.LastScrapeTime.Time
+		}
+	}
+	if last.IsZero() {
+		return 0, true
+	}
+	return max(interval-now.Sub(last), 0), true
+}
+
+// healthCheckRequeue returns when to reconcile again to scrape the pods, and whether health checks are enabled.
+// When a scrape is due, it happens at the end of the current reconcile: the next one is a full interval later.
+func healthCheckRequeue(falco *instancev1alpha1.Falco, now time.Time) (time.Duration, bool) {
+	scrapeAfter, enabled := nextHealthCheck(falco, now)
+	if enabled && scrapeAfter == 0 {
+		scrapeAfter, _ = healthCheckInterval(falco)
+	}
+	return scrapeAfter, enabled
+}
+
 // computeAvailableCondition queries live deployment/daemonset state.
 func (r *Reconciler) computeAvailableCondition(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	var result instance.Availability
 	var err error
 
+	falco.Status.NodePools = nil
 	switch resolveResourceType(falco.Spec.Type) {
 	case resources.ResourceTypeDeployment:
 		result, err = instance.ComputeDeploymentAvailability(ctx, r.Client, client.ObjectKeyFromObject(falco), falco.Spec.Replicas)
 	case resources.ResourceTypeDaemonSet:
-		result, err = instance.ComputeDaemonSetAvailability(ctx, r.Client, client.ObjectKeyFromObject(falco))
+		result, err = r.computeDaemonSetAvailability(ctx, falco)
+	}
+
+	// Ready pods are not available when Falco is unhealthy on some of them.
+	if result.ConditionStatus == metav1.ConditionTrue {
+		if degraded := apimeta.FindStatusCondition(falco.Status.Conditions,
+			commonv1alpha1.ConditionDegraded.String()); degraded != nil && degraded.Status == metav1.ConditionTrue {
+			result.ConditionStatus = metav1.ConditionFalse
+			result.Reason = instance.ReasonFalcoDegraded
+			result.Message = degraded.Message
+		}
 	}
 
 	falco.Status.DesiredReplicas = result.DesiredReplicas
 	falco.Status.AvailableReplicas = result.AvailableReplicas
 	falco.Status.UnavailableReplicas = result.UnavailableReplicas
+	instance.RecordReplicas("Falco", client.ObjectKeyFromObject(falco),
+		result.DesiredReplicas, result.AvailableReplicas, result.UnavailableReplicas)
 
 	apimeta.SetStatusCondition(&falco.Status.Conditions, common.NewAvailableCondition(
 		result.ConditionStatus, result.Reason, result.Message, falco.GetGeneration()))
@@ -438,6 +1097,36 @@ func (r *Reconciler) computeAvailableCondition(ctx context.Context, falco *insta
 	return err
 }
 
+// computeDaemonSetAvailability computes the availability of the default daemonset and of the node pool daemonsets,
+// and reports the node pool daemonsets in the status.
+func (r *Reconciler) computeDaemonSetAvailability(ctx context.Context, falco *instancev1alpha1.Falco) (instance.Availability, error) {
+	result, err := instance.ComputeDaemonSetAvailability(ctx, r.Client, client.ObjectKeyFromObject(falco))
+	if err != nil || len(falco.Spec.NodePools) == 0 {
+		return result, err
+	}
+
+	results := []instance.Availability{result}
+	statuses := make([]instancev1alpha1.NodePoolStatus, 0, len(falco.Spec.NodePools))
+	for _, pool := range falco.Spec.NodePools {
+		key := client.ObjectKey{Namespace: falco.Namespace, Name: resources.NodePoolName(falco.Name, pool.Name)}
+		poolResult, err := instance.ComputeDaemonSetAvailability(ctx, r.Client, key)
+		if err != nil {
+			return poolResult, err
+		}
+		results = append(results, poolResult)
+		statuses = append(statuses, instancev1alpha1.NodePoolStatus{
+			Name:                pool.Name,
+			DesiredReplicas:     poolResult.DesiredReplicas,
+			AvailableReplicas:   poolResult.AvailableReplicas,
+			UnavailableReplicas: poolResult.UnavailableReplicas,
+			ConfigRevision:      poolResult.ConfigRevision,
+		})
+	}
+	falco.Status.NodePools = statuses
+
+	return instance.MergeAvailability(results...), nil
+}
+
 // patchStatus patches the Falco status using server-side apply.
 func (r *Reconciler) patchStatus(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	return controllerhelper.PatchStatusSSA(ctx, r.Client, r.Scheme, falco, fieldManager)
@@ -457,54 +1146,273 @@ func (r *Reconciler) handleDeletion(ctx context.Context, falco *instancev1alpha1
 func (r *Reconciler) ensureServiceAccount(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, falco, fieldManager,
 		resources.GenerateServiceAccount(falco),
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays})
 }
 
 // ensureRole ensures the Role is created or updated.
 func (r *Reconciler) ensureRole(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, falco, fieldManager,
 		resources.GenerateRole(falco, resources.FalcoDefaults),
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays})
 }
 
 // ensureRoleBinding ensures the RoleBinding is created or updated.
 func (r *Reconciler) ensureRoleBinding(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, falco, fieldManager,
 		resources.GenerateRoleBinding(falco),
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays})
 }
 
 // ensureClusterRole ensures the ClusterRole is created or updated.
 func (r *Reconciler) ensureClusterRole(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, falco, fieldManager,
 		resources.GenerateClusterRole(falco, resources.FalcoDefaults),
-		instance.GenerateOptions{SetControllerRef: false, IsClusterScoped: true})
+		instance.GenerateOptions{SetControllerRef: false, IsClusterScoped: true, Overlays: falco.Spec.Overlays})
 }
 
 // ensureClusterRoleBinding ensures the ClusterRoleBinding is created or updated.
 func (r *Reconciler) ensureClusterRoleBinding(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, falco, fieldManager,
 		resources.GenerateClusterRoleBinding(falco),
-		instance.GenerateOptions{SetControllerRef: false, IsClusterScoped: true})
+		instance.GenerateOptions{SetControllerRef: false, IsClusterScoped: true, Overlays: falco.Spec.Overlays})
 }
 
 // ensureService ensures the Service is created or updated.
 func (r *Reconciler) ensureService(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	return instance.EnsureResource(ctx, r.Client, r.recorder, falco, fieldManager,
 		resources.GenerateService(falco, resources.FalcoDefaults),
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays})
+}
+
+// ensureMonitoring ensures the prometheus-operator objects enabled for the Falco instance are created or updated,
+// and deletes the ones no longer enabled.
+func (r *Reconciler) ensureMonitoring(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	monitoring := falco.Spec.Monitoring
+	if monitoring == nil {
+		monitoring = &instancev1alpha1.FalcoMonitoringSpec{}
+	}
+
+	var rule *unstructured.Unstructured
+	if monitoring.PrometheusRule != nil && monitoring.PrometheusRule.Enabled {
+		rule = resources.GenerateFalcoPrometheusRule(falco, monitoring.PrometheusRule.Labels)
+	}
+
+	objects := []struct {
+		gvk     schema.GroupVersionKind
+		desired *unstructured.Unstructured
+	}{
+		{resources.ServiceMonitorGVK, resources.GenerateServiceMonitor(falco, resources.FalcoDefaults, monitoring.ServiceMonitor)},
+		{resources.PodMonitorGVK, resources.GeneratePodMonitor(falco, resources.FalcoDefaults, monitoring.PodMonitor,
+			resources.ArtifactOperatorMetricsPort)},
+		{resources.PrometheusRuleGVK, rule},
+	}
+	for _, obj := range objects {
+		if tlsEnabled(falco) && obj.gvk != resources.PrometheusRuleGVK {
+			if err := resources.EnableMonitorTLS(obj.desired, falco, resources.FalcoDefaults.MetricsPort); err != nil {
+				return err
+			}
+		}
+		if err := instance.EnsureMonitoringResource(ctx, r.Client, r.recorder, falco, fieldManager, obj.gvk, obj.desired,
+			instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureNetworkPolicy ensures the NetworkPolicy is created or updated when enabled, and deleted otherwise.
+func (r *Reconciler) ensureNetworkPolicy(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	return instance.EnsureNetworkPolicy(ctx, r.Client, r.recorder, falco, fieldManager,
+		resources.GenerateNetworkPolicy(falco, resources.FalcoDefaults, falco.Spec.NetworkPolicy),
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays})
+}
+
+// ensureCertificate ensures the certificate of the Falco instance is issued when TLS is enabled, and deleted otherwise.
+func (r *Reconciler) ensureCertificate(ctx context.Context, falco *instancev1alpha1.Falco, now time.Time) error {
+	status, err := instance.EnsureCertificate(ctx, r.Client, r.recorder, falco, fieldManager, tlsEnabled(falco),
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays}, now)
+	if err != nil {
+		return err
+	}
+	falco.Status.TLS = status
+	return nil
+}
+
+// ensureCollector ensures the k8smeta Plugin connects Falco to the metacollector referenced by the instance,
+// and reports whether the metacollector is available in the CollectorAvailable condition. The Plugin is deleted
+// when no metacollector is referenced, or when the referenced Component is missing or not a metacollector.
+func (r *Reconciler) ensureCollector(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	options := instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays}
+	ref := collectorRef(falco)
+	if ref == nil {
+		apimeta.RemoveStatusCondition(&falco.Status.Conditions, commonv1alpha1.ConditionCollectorAvailable.String())
+		return instance.EnsureCollectorPlugin(ctx, r.Client, r.recorder, falco, fieldManager, nil, options)
+	}
+
+	status, reason, message := metav1.ConditionTrue, instance.ReasonCollectorAvailable,
+		fmt.Sprintf(instance.MessageFormatCollectorAvailable, ref.Name)
+	defer func() {
+		apimeta.SetStatusCondition(&falco.Status.Conditions, common.NewCollectorAvailableCondition(
+			status, reason, message, falco.GetGeneration()))
+	}()
+
+	collector := &instancev1alpha1.Component{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: falco.Namespace, Name: ref.Name}, collector); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			status, reason, message = metav1.ConditionUnknown, instance.ReasonCollectorUnavailable, err.Error()
+			return fmt.Errorf("unable to fetch the metacollector: %w", err)
+		}
+		status, reason, message = metav1.ConditionFalse, instance.ReasonCollectorNotFound,
+			fmt.Sprintf(instance.MessageFormatCollectorNotFound, ref.Name)
+		return instance.EnsureCollectorPlugin(ctx, r.Client, r.recorder, falco, fieldManager, nil, options)
+	}
+	if collector.Spec.Component.Type != instancev1alpha1.ComponentTypeMetacollector {
+		status, reason, message = metav1.ConditionFalse, instance.ReasonCollectorInvalidType,
+			fmt.Sprintf(instance.MessageFormatCollectorInvalidType, ref.Name, collector.Spec.Component.Type)
+		return instance.EnsureCollectorPlugin(ctx, r.Client, r.recorder, falco, fieldManager, nil, options)
+	}
+	if !apimeta.IsStatusConditionTrue(collector.Status.Conditions, commonv1alpha1.ConditionAvailable.String()) {
+		status, reason, message = metav1.ConditionFalse, instance.ReasonCollectorUnavailable,
+			fmt.Sprintf(instance.MessageFormatCollectorUnavailable, ref.Name)
+	}
+
+	// The plugin is generated while the metacollector is not available yet: the k8smeta plugin keeps
+	// connecting to it until it is.
+	plugin, err := resources.GenerateK8smetaPlugin(falco, collector)
+	if err != nil {
+		return err
+	}
+	return instance.EnsureCollectorPlugin(ctx, r.Client, r.recorder, falco, fieldManager, plugin, options)
+}
+
+// falcosReferencingCollector maps a Component to the Falco instances of its namespace referencing it as their
+// metacollector.
+func (r *Reconciler) falcosReferencingCollector(ctx context.Context, obj client.Object) []reconcile.Request {
+	falcos := &instancev1alpha1.FalcoList{}
+	if err := r.List(ctx, falcos, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Falco instances")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range falcos.Items {
+		if ref := collectorRef(&falcos.Items[i]); ref != nil && ref.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&falcos.Items[i])})
+		}
+	}
+	return requests
+}
+
+// falcosTargetedByArtifactNode maps an ArtifactNode to the Falco instances of its namespace targeted by its parent
+// artifact, the only ones whose pods restartPods considers for it and whose node state it reports.
+func (r *Reconciler) falcosTargetedByArtifactNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	nodeObject, ok := obj.(*artifactv1alpha1.ArtifactNode)
+	if !ok {
+		return nil
+	}
+	target, found, err := controllerhelper.NodeObjectTarget(ctx, r.Client, nodeObject)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "unable to resolve the target of ArtifactNode", "artifactNode", nodeObject.Name)
+		return nil
+	}
+	if !found {
+		return nil
+	}
+
+	falcos := &instancev1alpha1.FalcoList{}
+	if err := r.List(ctx, falcos, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Falco instances")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range falcos.Items {
+		if target.InstanceTargeted(falcos.Items[i].Name, falcos.Items[i].Labels) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&falcos.Items[i])})
+		}
+	}
+	return requests
+}
+
+// nodeObjectChangedPredicate filters the ArtifactNode events to the ones changing what the Falco controller acts upon:
+// setting, changing or clearing their RestartRequired condition, and reporting a new effective configuration or rules
+// index for their node.
+func nodeObjectChangedPredicate() predicate.Predicate {
+	restartRequired := func(nodeObject *artifactv1alpha1.ArtifactNode) *metav1.Condition {
+		return apimeta.FindStatusCondition(nodeObject.Status.Conditions, commonv1alpha1.ConditionRestartRequired.String())
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			nodeObject, ok := e.Object.(*artifactv1alpha1.ArtifactNode)
+			return ok && (restartRequired(nodeObject) != nil || nodeObject.Status.EffectiveConfig != nil || nodeObject.Status.RulesIndex != nil)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObject, ok := e.ObjectOld.(*artifactv1alpha1.ArtifactNode)
+			if !ok {
+				return false
+			}
+			newObject, ok := e.ObjectNew.(*artifactv1alpha1.ArtifactNode)
+			if !ok {
+				return false
+			}
+			if !equality.Semantic.DeepEqual(oldObject.Status.EffectiveConfig, newObject.Status.EffectiveConfig) ||
+				!equality.Semantic.DeepEqual(oldObject.Status.RulesIndex, newObject.Status.RulesIndex) {
+				return true
+			}
+			previous, current := restartRequired(oldObject), restartRequired(newObject)
+			if previous == nil || current == nil {
+				return previous != current
+			}
+			return previous.Status != current.Status || !previous.LastTransitionTime.Equal(&current.LastTransitionTime)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// collectorRef returns the reference to the metacollector of the Falco instance, nil when there is none.
+func collectorRef(falco *instancev1alpha1.Falco) *instancev1alpha1.CollectorRef {
+	if falco.Spec.Metadata == nil {
+		return nil
+	}
+	return falco.Spec.Metadata.CollectorRef
 }
 
 // ensureConfigMap ensures the ConfigMap is created or updated.
 func (r *Reconciler) ensureConfigMap(ctx context.Context, falco *instancev1alpha1.Falco) error {
 	resourceType := resolveResourceType(falco.Spec.Type)
-	configMapResource, err := resources.GenerateConfigMap(falco, resources.FalcoDefaults, resourceType)
+	data, err := configMapData(falco, resourceType)
 	if err != nil {
+		return err
+	}
+	if data == nil {
 		return fmt.Errorf("unsupported falco type: %s", resourceType)
 	}
 	return instance.EnsureResource(ctx, r.Client, r.recorder, falco, fieldManager,
-		configMapResource,
-		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false})
+		resources.GenerateConfigMapWithData(falco, data),
+		instance.GenerateOptions{SetControllerRef: true, IsClusterScoped: false, Overlays: falco.Spec.Overlays})
+}
+
+// ensureNodePoolConfigMaps ensures the ConfigMaps of the node pools overriding the configuration are created or updated.
+// Pools whose configuration cannot be merged are skipped; the error is reported when generating their daemonsets.
+func (r *Reconciler) ensureNodePoolConfigMaps(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	for i := range nodePools(falco, resolveResourceType(falco.Spec.Type)) {
+		pool := &falco.Spec.NodePools[i]
+		data, err := nodePoolConfigMapData(falco, pool)
+		if err != nil || data == nil {
+			continue
+		}
+		if err := instance.EnsureResource(ctx, r.Client, r.recorder, falco, fieldManager,
+			resources.GenerateNodePoolConfigMap(falco, pool.Name, data),
+			instance.GenerateOptions{
+				SetControllerRef: true, IsClusterScoped: false, KeepName: true, Overlays: falco.Spec.Overlays,
+			}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // resolveResourceType returns the resource type from the spec, falling back to the default.
@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/controllers/testutil"
 	"github.com/falcosecurity/falco-operator/internal/pkg/builders"
@@ -54,6 +55,10 @@ func TestMain(m *testing.M) {
 		ctrllog.Log.Error(err, "Failed to add scheme")
 		os.Exit(1)
 	}
+	if err := artifactv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		ctrllog.Log.Error(err, "Failed to add scheme")
+		os.Exit(1)
+	}
 
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths:     []string{testutil.CRDDirPath()},
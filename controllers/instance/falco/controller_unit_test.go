@@ -19,27 +19,41 @@ package falco
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/managedfields"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/controllers/testutil"
 	"github.com/falcosecurity/falco-operator/internal/pkg/builders"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/image"
 	"github.com/falcosecurity/falco-operator/internal/pkg/instance"
 	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
@@ -296,6 +310,23 @@ func TestComputeAvailableCondition(t *testing.T) {
 			wantConditionReason: instance.ReasonDaemonSetAvailable,
 			wantEventMessage:    instance.MessageDaemonSetAvailable,
 		},
+		{
+			name: "daemonset ready but Falco degraded — not available",
+			falco: func() *instancev1alpha1.Falco {
+				f := builders.NewFalco().WithName("test").WithNamespace(testutil.TestNamespace).WithType(resources.ResourceTypeDaemonSet).Build()
+				f.Status.Conditions = []metav1.Condition{common.NewDegradedCondition(metav1.ConditionTrue,
+					instance.ReasonFalcoDegraded, "Falco is unhealthy on nodes: node-1", 1)}
+				return f
+			}(),
+			workload: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: testutil.TestNamespace},
+				Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberAvailable: 3},
+			},
+			wantDesired: 3, wantAvailable: 3,
+			wantConditionStatus: metav1.ConditionFalse,
+			wantConditionReason: instance.ReasonFalcoDegraded,
+			wantEventMessage:    "Falco is unhealthy on nodes: node-1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -588,11 +619,13 @@ func TestEnsureDeployment(t *testing.T) {
 			testutil.RequireCondition(t, tt.falco.Status.Conditions,
 				commonv1alpha1.ConditionReconciled.String(),
 				tt.wantConditionStatus, tt.wantConditionReason)
+			assert.Equal(t, mustConfigRevision(t, tt.falco, tt.wantKind), tt.falco.Status.ConfigRevision)
 
 			switch tt.wantKind {
 			case resources.ResourceTypeDaemonSet:
 				ds := &appsv1.DaemonSet{}
 				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(tt.falco), ds))
+				assert.Equal(t, tt.falco.Status.ConfigRevision, ds.Spec.Template.Annotations[resources.ConfigHashAnnotation])
 				require.NotEmpty(t, ds.Spec.Template.Spec.Containers)
 				assert.Contains(t, ds.Spec.Template.Spec.Containers[0].Image, "falco")
 				require.Len(t, ds.GetOwnerReferences(), 1)
@@ -620,12 +653,181 @@ func TestEnsureDeployment(t *testing.T) {
 	}
 }
 
+// TestEnsureDeploymentWithNodePools verifies that a DaemonSet is applied for the default nodes and for each node pool,
+// all owned by the Falco instance.
+func TestEnsureDeploymentWithNodePools(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName("test").WithNamespace(testutil.TestNamespace).
+		WithNodePools(testNodePools()...).Build()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+	require.NoError(t, r.ensureDeployment(context.Background(), falco))
+
+	testutil.RequireCondition(t, falco.Status.Conditions, commonv1alpha1.ConditionReconciled.String(),
+		metav1.ConditionTrue, instance.ReasonResourceCreated)
+	for _, name := range []string{"test", "test-gpu", "test-arm"} {
+		ds := &appsv1.DaemonSet{}
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: testutil.TestNamespace, Name: name}, ds))
+		require.Len(t, ds.GetOwnerReferences(), 1)
+		assert.Equal(t, falco.Name, ds.GetOwnerReferences()[0].Name)
+	}
+
+	// An invalid pool fails the reconciliation before anything is applied.
+	falco.Spec.NodePools = append(falco.Spec.NodePools, instancev1alpha1.NodePool{Name: "empty"})
+	require.Error(t, r.ensureDeployment(context.Background(), falco))
+	testutil.RequireCondition(t, falco.Status.Conditions, commonv1alpha1.ConditionReconciled.String(),
+		metav1.ConditionFalse, instance.ReasonApplyConfigurationError)
+}
+
+func TestEnsureNodePoolConfigMaps(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName("test").WithNamespace(testutil.TestNamespace).
+		WithNodePools(testNodePools()...).Build()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+	require.NoError(t, r.ensureNodePoolConfigMaps(context.Background(), falco))
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: testutil.TestNamespace, Name: "test-gpu"}, cm))
+	assert.Equal(t, "gpu", cm.Labels[resources.NodePoolLabel])
+	assert.Contains(t, cm.Data[resources.FalcoDefaults.ConfigMapVolume.SubPath], "kind: kmod")
+	require.Len(t, cm.GetOwnerReferences(), 1)
+
+	err := cl.Get(context.Background(), client.ObjectKey{Namespace: testutil.TestNamespace, Name: "test-arm"}, &corev1.ConfigMap{})
+	assert.True(t, k8serrors.IsNotFound(err), "no ConfigMap is generated for a pool without config")
+}
+
+func TestCleanupNodePools(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+
+	poolObjects := func(owner *instancev1alpha1.Falco, pool string) []client.Object {
+		name := resources.NodePoolName(owner.Name, pool)
+		ds := builders.NewDaemonSet().WithName(name).WithNamespace(testutil.TestNamespace).Build()
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testutil.TestNamespace}}
+		objs := []client.Object{ds, cm}
+		for _, obj := range objs {
+			obj.SetLabels(map[string]string{resources.NodePoolLabel: pool})
+			require.NoError(t, ctrl.SetControllerReference(owner, obj, scheme))
+		}
+		return objs
+	}
+
+	tests := []struct {
+		name           string
+		falco          *instancev1alpha1.Falco
+		wantDaemonSets []string
+		wantConfigMaps []string
+		wantEvents     int
+	}{
+		{
+			name: "keeps the resources of configured pools and the ConfigMaps of pools with a config",
+			falco: builders.NewFalco().WithName("test").WithNamespace(testutil.TestNamespace).
+				WithNodePools(testNodePools()...).Build(),
+			wantDaemonSets: []string{"other-gpu", "test-arm", "test-gpu"},
+			wantConfigMaps: []string{"other-gpu", "test-gpu"},
+			wantEvents:     1,
+		},
+		{
+			name: "deletes the resources of removed pools",
+			falco: builders.NewFalco().WithName("test").WithNamespace(testutil.TestNamespace).
+				WithNodePools(testNodePools()[1]).Build(),
+			wantDaemonSets: []string{"other-gpu", "test-arm"},
+			wantConfigMaps: []string{"other-gpu"},
+			wantEvents:     3,
+		},
+		{
+			name: "deletes every pool when deployed as a Deployment",
+			falco: builders.NewFalco().WithName("test").WithNamespace(testutil.TestNamespace).
+				WithType(resources.ResourceTypeDeployment).WithNodePools(testNodePools()...).Build(),
+			wantDaemonSets: []string{"other-gpu"},
+			wantConfigMaps: []string{"other-gpu"},
+			wantEvents:     4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.falco.UID = "test-uid"
+			other := builders.NewFalco().WithName("other").WithNamespace(testutil.TestNamespace).Build()
+			other.UID = "other-uid"
+
+			objs := []client.Object{tt.falco, other}
+			objs = append(objs, poolObjects(tt.falco, "gpu")...)
+			objs = append(objs, poolObjects(tt.falco, "arm")...)
+			objs = append(objs, poolObjects(other, "gpu")...)
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			recorder := events.NewFakeRecorder(10)
+			r := NewReconciler(cl, scheme, recorder, false)
+
+			require.NoError(t, r.cleanupNodePools(context.Background(), tt.falco))
+
+			daemonSets := &appsv1.DaemonSetList{}
+			require.NoError(t, cl.List(context.Background(), daemonSets))
+			var dsNames []string
+			for _, ds := range daemonSets.Items {
+				dsNames = append(dsNames, ds.Name)
+			}
+			assert.ElementsMatch(t, tt.wantDaemonSets, dsNames)
+
+			configMaps := &corev1.ConfigMapList{}
+			require.NoError(t, cl.List(context.Background(), configMaps))
+			var cmNames []string
+			for _, cm := range configMaps.Items {
+				cmNames = append(cmNames, cm.Name)
+			}
+			assert.ElementsMatch(t, tt.wantConfigMaps, cmNames)
+
+			assert.Len(t, recorder.Events, tt.wantEvents)
+		})
+	}
+}
+
+func TestComputeAvailableConditionWithNodePools(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName("test").WithNamespace(testutil.TestNamespace).
+		WithNodePools(testNodePools()...).Build()
+	daemonSet := func(name string, desired, available int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testutil.TestNamespace},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{resources.ConfigHashAnnotation: "rev-" + name},
+			}}},
+			Status: appsv1.DaemonSetStatus{
+				DesiredNumberScheduled: desired, NumberAvailable: available, NumberUnavailable: desired - available,
+			},
+		}
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco,
+		daemonSet("test", 3, 3), daemonSet("test-gpu", 2, 1), daemonSet("test-arm", 4, 4)).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+	require.NoError(t, r.computeAvailableCondition(context.Background(), falco))
+
+	assert.Equal(t, int32(9), falco.Status.DesiredReplicas)
+	assert.Equal(t, int32(8), falco.Status.AvailableReplicas)
+	assert.Equal(t, int32(1), falco.Status.UnavailableReplicas)
+	assert.Equal(t, []instancev1alpha1.NodePoolStatus{
+		{Name: "gpu", DesiredReplicas: 2, AvailableReplicas: 1, UnavailableReplicas: 1, ConfigRevision: "rev-test-gpu"},
+		{Name: "arm", DesiredReplicas: 4, AvailableReplicas: 4, ConfigRevision: "rev-test-arm"},
+	}, falco.Status.NodePools)
+	testutil.RequireCondition(t, falco.Status.Conditions, commonv1alpha1.ConditionAvailable.String(),
+		metav1.ConditionFalse, instance.ReasonDaemonSetUnavailable)
+
+	// Removing the pools clears their status.
+	falco.Spec.NodePools = nil
+	require.NoError(t, r.computeAvailableCondition(context.Background(), falco))
+	assert.Nil(t, falco.Status.NodePools)
+	assert.Equal(t, int32(3), falco.Status.DesiredReplicas)
+}
+
 // TestReconcileLabelExclusion verifies the contract of the label filter: keys
 // propagated from the Falco resource's metadata are dropped from generated
 // resources (cluster-scoped, workload metadata and pod template), pod selector
 // labels are always preserved, and the Falco resource itself is never mutated.
 func TestReconcileLabelExclusion(t *testing.T) {
-	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
 
 	tests := []struct {
 		name        string
@@ -703,6 +905,1131 @@ func TestReconcileLabelExclusion(t *testing.T) {
 	}
 }
 
+func TestReconcilePlanMode(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+
+	staleDeployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: defaultName, Namespace: testutil.TestNamespace}}
+
+	tests := []struct {
+		name        string
+		annotation  string
+		mode        string
+		objs        []client.Object
+		wantApplied bool
+		wantChanges []commonv1alpha1.PendingChange
+	}{
+		{
+			name:        "apply mode applies the resources",
+			wantApplied: true,
+		},
+		{
+			name:       "annotation plans the resources",
+			annotation: instance.ReconcileModePlan,
+			wantChanges: []commonv1alpha1.PendingChange{
+				{Action: commonv1alpha1.PendingActionCreate, Kind: "DaemonSet", Name: defaultName},
+				{Action: commonv1alpha1.PendingActionCreate, Kind: "ServiceAccount", Name: defaultName},
+			},
+		},
+		{
+			name: "operator-wide plan mode plans the resources",
+			mode: instance.ReconcileModePlan,
+			objs: []client.Object{staleDeployment},
+			wantChanges: []commonv1alpha1.PendingChange{
+				{Action: commonv1alpha1.PendingActionDelete, Kind: "Deployment", Name: defaultName},
+				{Action: commonv1alpha1.PendingActionCreate, Kind: "DaemonSet", Name: defaultName},
+			},
+		},
+		{
+			name:        "annotation applies the resources in operator-wide plan mode",
+			annotation:  instance.ReconcileModeApply,
+			mode:        instance.ReconcileModePlan,
+			wantApplied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+			// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+			falco.Finalizers = []string{finalizer}
+			if tt.annotation != "" {
+				falco.Annotations = map[string]string{instance.ReconcileModeAnnotation: tt.annotation}
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append([]client.Object{falco}, tt.objs...)...).
+				WithStatusSubresource(falco).Build()
+			recorder := events.NewFakeRecorder(50)
+			r := NewReconciler(cl, scheme, recorder, false, WithReconcileMode(tt.mode))
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)})
+			require.NoError(t, err)
+
+			err = cl.Get(context.Background(), client.ObjectKeyFromObject(falco), &appsv1.DaemonSet{})
+			srv := &instancev1alpha1.Falco{}
+			require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(falco), srv))
+			if tt.wantApplied {
+				require.NoError(t, err)
+				assert.Empty(t, srv.Status.PendingChanges)
+				assert.NotEmpty(t, srv.Status.ConfigRevision)
+				return
+			}
+			assert.True(t, k8serrors.IsNotFound(err), "daemonset must not be created in plan mode")
+			for _, change := range tt.wantChanges {
+				assert.Contains(t, srv.Status.PendingChanges, change)
+			}
+			for _, obj := range tt.objs {
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(obj), obj),
+					"%s must not be deleted in plan mode", obj.GetName())
+			}
+			assert.Empty(t, srv.Status.ConfigRevision)
+			testutil.RequireCondition(t, srv.Status.Conditions, commonv1alpha1.ConditionReconciled.String(),
+				metav1.ConditionFalse, instance.ReasonChangesPending)
+
+			found := false
+			for len(recorder.Events) > 0 {
+				event := <-recorder.Events
+				assert.NotContains(t, event, instance.ReasonResourceCreated)
+				found = found || strings.Contains(event, instance.ReasonChangesPending)
+			}
+			assert.True(t, found, "a ChangesPending event must be recorded")
+		})
+	}
+}
+
+func TestReconcileDrift(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).
+		WithDriftPolicy(commonv1alpha1.DriftPolicyReport).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	falco.Finalizers = []string{finalizer}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).WithStatusSubresource(falco).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(50), false)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)})
+	require.NoError(t, err)
+
+	srv := &instancev1alpha1.Falco{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(falco), srv))
+	testutil.RequireCondition(t, srv.Status.Conditions, commonv1alpha1.ConditionDrifted.String(),
+		metav1.ConditionFalse, instance.ReasonNoDrift)
+}
+
+func TestReconcileSuspended(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	falco.Finalizers = []string{finalizer}
+	falco.Spec.Suspend = true
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).WithStatusSubresource(falco).Build()
+	recorder := events.NewFakeRecorder(50)
+	r := NewReconciler(cl, scheme, recorder, false)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	dsErr := cl.Get(context.Background(), req.NamespacedName, &appsv1.DaemonSet{})
+	assert.True(t, k8serrors.IsNotFound(dsErr), "daemonset must not be created while suspended")
+	saErr := cl.Get(context.Background(), req.NamespacedName, &corev1.ServiceAccount{})
+	assert.True(t, k8serrors.IsNotFound(saErr), "service account must not be created while suspended")
+	srv := &instancev1alpha1.Falco{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	testutil.RequireCondition(t, srv.Status.Conditions, commonv1alpha1.ConditionSuspended.String(),
+		metav1.ConditionTrue, instance.ReasonSuspended)
+	found := false
+	for len(recorder.Events) > 0 {
+		event := <-recorder.Events
+		found = found || strings.Contains(event, instance.ReasonSuspended)
+	}
+	assert.True(t, found, "a Suspended event must be recorded")
+
+	srv.Spec.Suspend = false
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.DaemonSet{}))
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	assert.Nil(t, apimeta.FindStatusCondition(srv.Status.Conditions, commonv1alpha1.ConditionSuspended.String()))
+}
+
+func TestReconcileInvalidOverlay(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	tests := []struct {
+		name    string
+		overlay commonv1alpha1.Overlay
+	}{
+		{
+			name: "patch that cannot be decoded",
+			overlay: commonv1alpha1.Overlay{
+				Target: commonv1alpha1.OverlayTarget{Kind: "Service"},
+				Type:   commonv1alpha1.OverlayPatchTypeJSON6902,
+				Patch:  apiextensionsv1.JSON{Raw: []byte(`{"op":"add"}`)},
+			},
+		},
+		{
+			name: "patch that cannot be applied to the daemonset",
+			overlay: commonv1alpha1.Overlay{
+				Target: commonv1alpha1.OverlayTarget{Kind: "DaemonSet"},
+				Type:   commonv1alpha1.OverlayPatchTypeJSON6902,
+				Patch:  apiextensionsv1.JSON{Raw: []byte(`[{"op":"replace","path":"/spec/missing/field","value":1}]`)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).
+				WithOverlays(tt.overlay).Build()
+			// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+			falco.Finalizers = []string{finalizer}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).WithStatusSubresource(falco).Build()
+			recorder := events.NewFakeRecorder(50)
+			r := NewReconciler(cl, scheme, recorder, false)
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)}
+
+			_, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			dsErr := cl.Get(context.Background(), req.NamespacedName, &appsv1.DaemonSet{})
+			assert.True(t, k8serrors.IsNotFound(dsErr), "daemonset must not be applied with an invalid overlay")
+			srv := &instancev1alpha1.Falco{}
+			require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+			testutil.RequireCondition(t, srv.Status.Conditions, commonv1alpha1.ConditionReconciled.String(),
+				metav1.ConditionFalse, instance.ReasonInvalidOverlay)
+			found := false
+			for len(recorder.Events) > 0 {
+				event := <-recorder.Events
+				found = found || strings.Contains(event, instance.ReasonInvalidOverlay)
+			}
+			assert.True(t, found, "an InvalidOverlay event must be recorded")
+		})
+	}
+}
+
+func TestReconcileOverlays(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).WithOverlays(
+		commonv1alpha1.Overlay{
+			Target: commonv1alpha1.OverlayTarget{Kind: "ServiceAccount", Name: defaultName},
+			Patch:  apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"annotations":{"eks.amazonaws.com/role-arn":"arn:aws:iam::1:role/falco"}}}`)},
+		},
+		commonv1alpha1.Overlay{
+			Target: commonv1alpha1.OverlayTarget{Kind: "DaemonSet"},
+			Patch:  apiextensionsv1.JSON{Raw: []byte(`{"spec":{"template":{"spec":{"priorityClassName":"system-node-critical"}}}}`)},
+		},
+	).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	falco.Finalizers = []string{finalizer}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).WithStatusSubresource(falco).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(50), false)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	sa := &corev1.ServiceAccount{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, sa))
+	assert.Equal(t, "arn:aws:iam::1:role/falco", sa.Annotations["eks.amazonaws.com/role-arn"])
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, ds))
+	assert.Equal(t, "system-node-critical", ds.Spec.Template.Spec.PriorityClassName)
+}
+
+func TestReconcileMonitoring(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	falco.Finalizers = []string{finalizer}
+	falco.Spec.Monitoring = &instancev1alpha1.FalcoMonitoringSpec{
+		MonitoringSpec: commonv1alpha1.MonitoringSpec{
+			ServiceMonitor: &commonv1alpha1.MonitorSpec{Enabled: true},
+			PodMonitor:     &commonv1alpha1.MonitorSpec{Enabled: true},
+		},
+		PrometheusRule: &instancev1alpha1.PrometheusRuleSpec{Enabled: true},
+	}
+	mapper := testutil.RESTMapper(scheme, resources.ServiceMonitorGVK, resources.PodMonitorGVK, resources.PrometheusRuleGVK)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).
+		WithObjects(falco).WithStatusSubresource(falco).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(100), false)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)}
+
+	getMonitoring := func(gvk schema.GroupVersionKind) error {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		return cl.Get(context.Background(), req.NamespacedName, obj)
+	}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	require.NoError(t, getMonitoring(resources.ServiceMonitorGVK))
+	require.NoError(t, getMonitoring(resources.PodMonitorGVK))
+	require.NoError(t, getMonitoring(resources.PrometheusRuleGVK))
+
+	srv := &instancev1alpha1.Falco{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	srv.Spec.Monitoring.PodMonitor = nil
+	srv.Spec.Monitoring.PrometheusRule.Enabled = false
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	require.NoError(t, getMonitoring(resources.ServiceMonitorGVK))
+	assert.True(t, k8serrors.IsNotFound(getMonitoring(resources.PodMonitorGVK)), "disabled PodMonitor must be deleted")
+	assert.True(t, k8serrors.IsNotFound(getMonitoring(resources.PrometheusRuleGVK)), "disabled PrometheusRule must be deleted")
+}
+
+func TestReconcileMonitoringNotInstalled(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	falco.Finalizers = []string{finalizer}
+	falco.Spec.Monitoring = &instancev1alpha1.FalcoMonitoringSpec{
+		MonitoringSpec: commonv1alpha1.MonitoringSpec{ServiceMonitor: &commonv1alpha1.MonitorSpec{Enabled: true}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).WithStatusSubresource(falco).Build()
+	recorder := events.NewFakeRecorder(100)
+	r := NewReconciler(cl, scheme, recorder, false)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)})
+	require.NoError(t, err)
+
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(falco), &appsv1.DaemonSet{}))
+	found := false
+	for len(recorder.Events) > 0 {
+		event := <-recorder.Events
+		found = found || strings.Contains(event, instance.ReasonMonitoringNotInstalled)
+	}
+	assert.True(t, found, "a MonitoringNotInstalled event must be recorded")
+}
+
+func TestReconcileNetworkPolicy(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	falco.Finalizers = []string{finalizer}
+	prometheus := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"}},
+	}
+	falco.Spec.NetworkPolicy = &commonv1alpha1.NetworkPolicySpec{Enabled: true, From: []networkingv1.NetworkPolicyPeer{prometheus}}
+	// The fake client still handles NetworkPolicy as having a status, which its typed converter
+	// cannot parse: use the deduced one only.
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithTypeConverters(managedfields.NewDeducedTypeConverter()).
+		WithObjects(falco).WithStatusSubresource(falco).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(100), false)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	policy := &networkingv1.NetworkPolicy{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, policy))
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+	require.Len(t, policy.Spec.Ingress, 2)
+	assert.Equal(t, []networkingv1.NetworkPolicyPeer{prometheus}, policy.Spec.Ingress[1].From)
+	assert.True(t, metav1.IsControlledBy(policy, falco))
+
+	// The pods carry the type label selected by the policies of the components.
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, ds))
+	assert.Equal(t, resources.FalcoTypeName, ds.Spec.Template.Labels[resources.InstanceTypeLabel])
+
+	srv := &instancev1alpha1.Falco{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	srv.Spec.NetworkPolicy.Enabled = false
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.Background(), req.NamespacedName, &networkingv1.NetworkPolicy{})
+	assert.True(t, k8serrors.IsNotFound(err), "disabled NetworkPolicy must be deleted")
+}
+
+func TestReconcileTLS(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+	// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+	falco.Finalizers = []string{finalizer}
+	falco.Spec.TLS = &commonv1alpha1.TLSSpec{Enabled: true}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).WithStatusSubresource(falco).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(100), false)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)}
+
+	res, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Positive(t, res.RequeueAfter, "the reconciliation must be requeued to renew the certificate")
+
+	secretKey := client.ObjectKey{Namespace: testutil.TestNamespace, Name: resources.CertificateSecretName(defaultName)}
+	secret := &corev1.Secret{}
+	require.NoError(t, cl.Get(context.Background(), secretKey, secret))
+	assert.True(t, metav1.IsControlledBy(secret, falco))
+	require.NoError(t, cl.Get(context.Background(),
+		client.ObjectKey{Namespace: testutil.TestNamespace, Name: resources.CASecretName}, &corev1.Secret{}))
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, cm))
+	assert.Contains(t, cm.Data["falco.yaml"], "ssl_enabled: true")
+
+	srv := &instancev1alpha1.Falco{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	require.NotNil(t, srv.Status.TLS)
+	assert.Equal(t, secretKey.Name, srv.Status.TLS.SecretName)
+
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, ds))
+	assert.Equal(t, srv.Status.TLS.SerialNumber, ds.Spec.Template.Annotations[resources.CertificateSerialAnnotation])
+
+	srv.Spec.TLS.Enabled = false
+	require.NoError(t, cl.Update(context.Background(), srv))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.Background(), secretKey, &corev1.Secret{})
+	assert.True(t, k8serrors.IsNotFound(err), "disabled certificate Secret must be deleted")
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+	assert.Nil(t, srv.Status.TLS)
+}
+
+func TestReconcileCollector(t *testing.T) {
+	collector := func(componentType instancev1alpha1.ComponentType, available metav1.ConditionStatus) *instancev1alpha1.Component {
+		comp := builders.NewComponent().WithName("collector").WithNamespace(testutil.TestNamespace).
+			WithComponentType(componentType).Build()
+		comp.Status.Conditions = []metav1.Condition{{Type: commonv1alpha1.ConditionAvailable.String(), Status: available}}
+		return comp
+	}
+
+	tests := []struct {
+		name       string
+		collector  *instancev1alpha1.Component
+		wantPlugin bool
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "available metacollector",
+			collector:  collector(instancev1alpha1.ComponentTypeMetacollector, metav1.ConditionTrue),
+			wantPlugin: true,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: instance.ReasonCollectorAvailable,
+		},
+		{
+			name:       "unavailable metacollector",
+			collector:  collector(instancev1alpha1.ComponentTypeMetacollector, metav1.ConditionFalse),
+			wantPlugin: true,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: instance.ReasonCollectorUnavailable,
+		},
+		{
+			name:       "component not a metacollector",
+			collector:  collector(instancev1alpha1.ComponentTypeFalcosidekick, metav1.ConditionTrue),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: instance.ReasonCollectorInvalidType,
+		},
+		{
+			name:       "component not found",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: instance.ReasonCollectorNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+			falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+			// Pre-set the finalizer so Reconcile proceeds past ensureFinalizer in one pass.
+			falco.Finalizers = []string{finalizer}
+			falco.Spec.Metadata = &instancev1alpha1.MetadataSpec{CollectorRef: &instancev1alpha1.CollectorRef{Name: "collector"}}
+			objs := []client.Object{falco}
+			if tt.collector != nil {
+				objs = append(objs, tt.collector)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(falco).Build()
+			r := NewReconciler(cl, scheme, events.NewFakeRecorder(100), false)
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(falco)}
+
+			_, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			srv := &instancev1alpha1.Falco{}
+			require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+			cond := apimeta.FindStatusCondition(srv.Status.Conditions, commonv1alpha1.ConditionCollectorAvailable.String())
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.wantStatus, cond.Status)
+			assert.Equal(t, tt.wantReason, cond.Reason)
+
+			pluginKey := client.ObjectKey{Namespace: testutil.TestNamespace, Name: resources.K8smetaPluginName(defaultName)}
+			plugin := &artifactv1alpha1.Plugin{}
+			err = cl.Get(context.Background(), pluginKey, plugin)
+			if !tt.wantPlugin {
+				assert.True(t, k8serrors.IsNotFound(err), "k8smeta Plugin must not exist, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, metav1.IsControlledBy(plugin, srv))
+			assert.Equal(t, resources.K8smetaPluginConfigName, plugin.Spec.Config.Name)
+			assert.Contains(t, string(plugin.Spec.Config.InitConfig.Raw), "collector."+testutil.TestNamespace+".svc")
+
+			// Removing the reference deletes the plugin and the condition.
+			srv.Spec.Metadata = nil
+			require.NoError(t, cl.Update(context.Background(), srv))
+			_, err = r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			assert.True(t, k8serrors.IsNotFound(cl.Get(context.Background(), pluginKey, &artifactv1alpha1.Plugin{})))
+			require.NoError(t, cl.Get(context.Background(), req.NamespacedName, srv))
+			assert.Nil(t, apimeta.FindStatusCondition(srv.Status.Conditions, commonv1alpha1.ConditionCollectorAvailable.String()))
+		})
+	}
+}
+
+func TestFalcosReferencingCollector(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	referencing := builders.NewFalco().WithName("referencing").WithNamespace(testutil.TestNamespace).Build()
+	referencing.Spec.Metadata = &instancev1alpha1.MetadataSpec{CollectorRef: &instancev1alpha1.CollectorRef{Name: "collector"}}
+	other := builders.NewFalco().WithName("other").WithNamespace(testutil.TestNamespace).Build()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(referencing, other).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+	collector := builders.NewComponent().WithName("collector").WithNamespace(testutil.TestNamespace).Build()
+	assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(referencing)}},
+		r.falcosReferencingCollector(context.Background(), collector))
+
+	unrelated := builders.NewComponent().WithName("unrelated").WithNamespace(testutil.TestNamespace).Build()
+	assert.Empty(t, r.falcosReferencingCollector(context.Background(), unrelated))
+}
+
+func TestFalcosTargetedByArtifactNode(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	targeted := builders.NewFalco().WithName("targeted").WithNamespace(testutil.TestNamespace).Build()
+	other := builders.NewFalco().WithName("other").WithNamespace(testutil.TestNamespace).Build()
+	restricted := builders.NewConfig().WithName("restricted").WithNamespace(testutil.TestNamespace).Build()
+	restricted.Spec.FalcoRef = &commonv1alpha1.FalcoRef{Name: "targeted"}
+	shared := builders.NewConfig().WithName("shared").WithNamespace(testutil.TestNamespace).Build()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(targeted, other, restricted, shared).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+	nodeObject := func(parent string) *artifactv1alpha1.ArtifactNode {
+		return &artifactv1alpha1.ArtifactNode{ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, parent, "node-1"),
+			Namespace: testutil.TestNamespace,
+			Labels:    controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindConfig, parent, "node-1"),
+		}}
+	}
+
+	assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(targeted)}},
+		r.falcosTargetedByArtifactNode(context.Background(), nodeObject("restricted")))
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: client.ObjectKeyFromObject(targeted)}, {NamespacedName: client.ObjectKeyFromObject(other)},
+	}, r.falcosTargetedByArtifactNode(context.Background(), nodeObject("shared")))
+	assert.Empty(t, r.falcosTargetedByArtifactNode(context.Background(), nodeObject("deleted")))
+}
+
+func TestNodeObjectChangedPredicate(t *testing.T) {
+	signaledAt := metav1.NewTime(time.Now().Truncate(time.Second))
+	nodeObject := func(status metav1.ConditionStatus, at metav1.Time, ready bool) *artifactv1alpha1.ArtifactNode {
+		obj := &artifactv1alpha1.ArtifactNode{}
+		if status != "" {
+			obj.Status.Conditions = append(obj.Status.Conditions, metav1.Condition{
+				Type: commonv1alpha1.ConditionRestartRequired.String(), Status: status, LastTransitionTime: at,
+			})
+		}
+		if ready {
+			obj.Status.Conditions = append(obj.Status.Conditions, metav1.Condition{
+				Type: commonv1alpha1.ConditionReconciled.String(), Status: metav1.ConditionTrue, LastTransitionTime: at,
+			})
+		}
+		return obj
+	}
+	withEffectiveConfig := func(obj *artifactv1alpha1.ArtifactNode, hash string) *artifactv1alpha1.ArtifactNode {
+		obj.Status.EffectiveConfig = &artifactv1alpha1.EffectiveConfig{ConfigMapName: "effective-config--falco--node-1", Hash: hash}
+		return obj
+	}
+	withRulesIndex := func(obj *artifactv1alpha1.ArtifactNode, index string) *artifactv1alpha1.ArtifactNode {
+		obj.Status.RulesIndex = &artifactv1alpha1.RulesIndex{ConfigMapName: "rules-index--falco--node-1", Index: index}
+		return obj
+	}
+	p := nodeObjectChangedPredicate()
+
+	assert.True(t, p.Create(event.CreateEvent{Object: nodeObject(metav1.ConditionTrue, signaledAt, false)}))
+	assert.True(t, p.Create(event.CreateEvent{Object: withEffectiveConfig(nodeObject("", signaledAt, true), "a")}))
+	assert.True(t, p.Create(event.CreateEvent{Object: withRulesIndex(nodeObject("", signaledAt, true), "a")}))
+	assert.False(t, p.Create(event.CreateEvent{Object: nodeObject("", signaledAt, true)}))
+	assert.False(t, p.Delete(event.DeleteEvent{Object: nodeObject(metav1.ConditionTrue, signaledAt, false)}))
+
+	tests := []struct {
+		name    string
+		old     *artifactv1alpha1.ArtifactNode
+		new     *artifactv1alpha1.ArtifactNode
+		wantHit bool
+	}{
+		{name: "signal set", old: nodeObject("", signaledAt, false), new: nodeObject(metav1.ConditionTrue, signaledAt, false), wantHit: true},
+		{name: "signal cleared", old: nodeObject(metav1.ConditionTrue, signaledAt, false), new: nodeObject("", signaledAt, false), wantHit: true},
+		{name: "new signal", old: nodeObject(metav1.ConditionTrue, signaledAt, false),
+			new: nodeObject(metav1.ConditionTrue, metav1.NewTime(signaledAt.Add(time.Minute)), false), wantHit: true},
+		{name: "other condition changed", old: nodeObject(metav1.ConditionTrue, signaledAt, false),
+			new: nodeObject(metav1.ConditionTrue, signaledAt, true)},
+		{name: "no signal", old: nodeObject("", signaledAt, false), new: nodeObject("", signaledAt, true)},
+		{name: "effective configuration reported", old: nodeObject("", signaledAt, false),
+			new: withEffectiveConfig(nodeObject("", signaledAt, false), "a"), wantHit: true},
+		{name: "effective configuration changed", old: withEffectiveConfig(nodeObject("", signaledAt, false), "a"),
+			new: withEffectiveConfig(nodeObject("", signaledAt, false), "b"), wantHit: true},
+		{name: "rules index changed", old: withRulesIndex(nodeObject("", signaledAt, false), "a"),
+			new: withRulesIndex(nodeObject("", signaledAt, false), "b"), wantHit: true},
+		{name: "reported state unchanged", old: withRulesIndex(withEffectiveConfig(nodeObject("", signaledAt, false), "a"), "a"),
+			new: withRulesIndex(withEffectiveConfig(nodeObject("", signaledAt, true), "a"), "a")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantHit, p.Update(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new}))
+		})
+	}
+}
+
+func TestRestartPods(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	signaledAt := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+
+	newPod := func(name, node string, created time.Time, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: testutil.TestNamespace,
+				Labels:            labels,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+				Type: corev1.PodReady, Status: corev1.ConditionTrue,
+			}}},
+		}
+	}
+	notReady := func(pod *corev1.Pod) *corev1.Pod {
+		pod.Status.Conditions = nil
+		return pod
+	}
+	newNodeObject := func(parent, node string, status metav1.ConditionStatus) *artifactv1alpha1.ArtifactNode {
+		return &artifactv1alpha1.ArtifactNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, parent, node),
+				Namespace: testutil.TestNamespace,
+				Labels:    controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindConfig, parent, node),
+			},
+			Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: node},
+			Status: artifactv1alpha1.ArtifactNodeStatus{Conditions: []metav1.Condition{{
+				Type:               commonv1alpha1.ConditionRestartRequired.String(),
+				Status:             status,
+				Reason:             "RestartRequired",
+				Message:            "Falco on node " + node + " must be restarted to apply: engine.kind",
+				LastTransitionTime: signaledAt,
+			}}},
+		}
+	}
+	newConfig := func(name string, target func(*artifactv1alpha1.Config)) *artifactv1alpha1.Config {
+		config := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testutil.TestNamespace}}
+		if target != nil {
+			target(config)
+		}
+		return config
+	}
+	falcoLabels := map[string]string{"app.kubernetes.io/instance": defaultName}
+	older := signaledAt.Add(-time.Hour)
+	newer := signaledAt.Add(time.Second)
+
+	tests := []struct {
+		name           string
+		objs           []client.Object
+		updateStrategy *appsv1.DaemonSetUpdateStrategy
+		deleteErr      error
+		wantDeleted    []string
+		wantKept       []string
+		wantErr        string
+	}{
+		{
+			name:     "no restart signal keeps pods",
+			objs:     []client.Object{newPod("falco-a", "node-1", older, falcoLabels)},
+			wantKept: []string{"falco-a"},
+		},
+		{
+			name: "restarts the pod started before the signal on the signaled node",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+				newPod("falco-b", "node-2", older, falcoLabels),
+			},
+			wantDeleted: []string{"falco-a"},
+			wantKept:    []string{"falco-b"},
+		},
+		{
+			name: "pod started after the signal is kept",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", newer, falcoLabels),
+			},
+			wantKept: []string{"falco-a"},
+		},
+		{
+			name: "pods of other instances are kept",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionTrue),
+				newPod("other-a", "node-1", older, map[string]string{"app.kubernetes.io/instance": "other"}),
+			},
+			wantKept: []string{"other-a"},
+		},
+		{
+			name: "condition not True is ignored",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionFalse),
+				newPod("falco-a", "node-1", older, falcoLabels),
+			},
+			wantKept: []string{"falco-a"},
+		},
+		{
+			name: "restarts one ready pod at a time by default",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionTrue),
+				newNodeObject("engine", "node-2", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+				newPod("falco-b", "node-2", older, falcoLabels),
+			},
+			wantDeleted: []string{"falco-a"},
+			wantKept:    []string{"falco-b"},
+		},
+		{
+			name: "restarts as many ready pods as maxUnavailable allows",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionTrue),
+				newNodeObject("engine", "node-2", metav1.ConditionTrue),
+				newNodeObject("engine", "node-3", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+				newPod("falco-b", "node-2", older, falcoLabels),
+				newPod("falco-c", "node-3", older, falcoLabels),
+			},
+			updateStrategy: &appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: ptr.To(intstr.FromString("67%"))},
+			},
+			wantDeleted: []string{"falco-a", "falco-b"},
+			wantKept:    []string{"falco-c"},
+		},
+		{
+			name: "pods that are not ready use up the budget",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+				notReady(newPod("falco-b", "node-2", older, falcoLabels)),
+			},
+			wantKept: []string{"falco-a", "falco-b"},
+		},
+		{
+			name: "signaled pods that are not ready are restarted first",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionTrue),
+				newNodeObject("engine", "node-2", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+				notReady(newPod("falco-b", "node-2", older, falcoLabels)),
+			},
+			wantDeleted: []string{"falco-b"},
+			wantKept:    []string{"falco-a"},
+		},
+		{
+			name: "signals of artifacts restricted to another instance are ignored",
+			objs: []client.Object{
+				newConfig("other-only", func(c *artifactv1alpha1.Config) {
+					c.Spec.FalcoRef = &commonv1alpha1.FalcoRef{Name: "other"}
+				}),
+				newConfig("other-selected", func(c *artifactv1alpha1.Config) {
+					c.Spec.InstanceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "other"}}
+				}),
+				newNodeObject("other-only", "node-1", metav1.ConditionTrue),
+				newNodeObject("other-selected", "node-1", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+				newPod("other-a", "node-1", older, map[string]string{"app.kubernetes.io/instance": "other"}),
+			},
+			wantKept: []string{"falco-a", "other-a"},
+		},
+		{
+			name: "signals of artifacts targeting this instance are honored",
+			objs: []client.Object{
+				newConfig("this-only", func(c *artifactv1alpha1.Config) {
+					c.Spec.FalcoRef = &commonv1alpha1.FalcoRef{Name: defaultName}
+				}),
+				newNodeObject("this-only", "node-1", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+				newPod("other-a", "node-1", older, map[string]string{"app.kubernetes.io/instance": "other"}),
+			},
+			wantDeleted: []string{"falco-a"},
+			wantKept:    []string{"other-a"},
+		},
+		{
+			name: "signals of deleted artifacts are ignored",
+			objs: []client.Object{
+				newNodeObject("gone", "node-1", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+			},
+			wantKept: []string{"falco-a"},
+		},
+		{
+			name: "returns error when Delete fails",
+			objs: []client.Object{
+				newNodeObject("engine", "node-1", metav1.ConditionTrue),
+				newPod("falco-a", "node-1", older, falcoLabels),
+			},
+			deleteErr: fmt.Errorf("injected delete error"),
+			wantErr:   "injected delete error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+			falco.Spec.UpdateStrategy = tt.updateStrategy
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append([]client.Object{falco, newConfig("engine", nil)}, tt.objs...)...)
+			if tt.deleteErr != nil {
+				builder = builder.WithInterceptorFuncs(interceptor.Funcs{
+					Delete: func(context.Context, client.WithWatch, client.Object, ...client.DeleteOption) error {
+						return tt.deleteErr
+					},
+				})
+			}
+			cl := builder.Build()
+			recorder := events.NewFakeRecorder(10)
+			r := NewReconciler(cl, scheme, recorder, false)
+
+			err := r.restartPods(context.Background(), falco)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				require.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, instance.ReasonFalcoPodRestartError)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, name := range tt.wantDeleted {
+				err := cl.Get(context.Background(), client.ObjectKey{Namespace: testutil.TestNamespace, Name: name}, &corev1.Pod{})
+				assert.Error(t, err, "pod %s should be deleted", name)
+			}
+			for _, name := range tt.wantKept {
+				err := cl.Get(context.Background(), client.ObjectKey{Namespace: testutil.TestNamespace, Name: name}, &corev1.Pod{})
+				assert.NoError(t, err, "pod %s should be kept", name)
+			}
+			require.Len(t, recorder.Events, len(tt.wantDeleted))
+			for range tt.wantDeleted {
+				assert.Contains(t, <-recorder.Events, instance.ReasonFalcoPodRestarted)
+			}
+		})
+	}
+}
+
+func TestPublishNodeConfigMaps(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+	falco.UID = "falco-uid"
+
+	newNodeObject := func(kind, parent, node string, status artifactv1alpha1.ArtifactNodeStatus) *artifactv1alpha1.ArtifactNode {
+		return &artifactv1alpha1.ArtifactNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controllerhelper.NodeObjectName(kind, parent, node),
+				Namespace: testutil.TestNamespace,
+				Labels:    controllerhelper.NodeObjectLabels(kind, parent, node),
+			},
+			Spec:   artifactv1alpha1.ArtifactNodeSpec{NodeName: node},
+			Status: status,
+		}
+	}
+	effective := func(instance, node, config string) artifactv1alpha1.ArtifactNodeStatus {
+		return artifactv1alpha1.ArtifactNodeStatus{EffectiveConfig: &artifactv1alpha1.EffectiveConfig{
+			ConfigMapName: controllerhelper.EffectiveConfigMapName(instance, node),
+			Config:        config,
+			Provenance:    "engine:\n- falco.yaml\n",
+		}}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "falco-a", Namespace: testutil.TestNamespace,
+			Labels: map[string]string{"app.kubernetes.io/instance": defaultName},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-1"},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco, pod,
+		newNodeObject(controllerhelper.ArtifactKindConfig, "a", "node-1", effective(defaultName, "node-1", "engine:\n  kind: kmod\n")),
+		// Reported by the same artifact operator, so the first ArtifactNode by name is enough.
+		newNodeObject(controllerhelper.ArtifactKindConfig, "b", "node-1", effective(defaultName, "node-1", "engine:\n  kind: ebpf\n")),
+		newNodeObject(controllerhelper.ArtifactKindConfig, "c", "node-1", effective("other", "node-1", "engine:\n  kind: ebpf\n")),
+		newNodeObject(controllerhelper.ArtifactKindConfig, "a", "node-2", effective(defaultName, "node-2", "engine:\n  kind: kmod\n")),
+		newNodeObject(controllerhelper.ArtifactKindRulesfile, "a", "node-1", artifactv1alpha1.ArtifactNodeStatus{
+			RulesIndex: &artifactv1alpha1.RulesIndex{
+				ConfigMapName: controllerhelper.RulesIndexConfigMapName(defaultName, "node-1"),
+				Index:         "files: []\n",
+			},
+		}),
+	).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+	require.NoError(t, r.publishNodeConfigMaps(context.Background(), falco))
+
+	configMaps := &corev1.ConfigMapList{}
+	require.NoError(t, cl.List(context.Background(), configMaps))
+	data := map[string]map[string]string{}
+	for _, cm := range configMaps.Items {
+		data[cm.Name] = cm.Data
+		require.Len(t, cm.OwnerReferences, 1)
+		assert.Equal(t, falco.UID, cm.OwnerReferences[0].UID)
+		assert.Equal(t, "node-1", cm.Labels[controllerhelper.LabelArtifactNode])
+	}
+	assert.Equal(t, map[string]map[string]string{
+		controllerhelper.EffectiveConfigMapName(defaultName, "node-1"): {
+			"falco.yaml":      "engine:\n  kind: kmod\n",
+			"provenance.yaml": "engine:\n- falco.yaml\n",
+		},
+		controllerhelper.RulesIndexConfigMapName(defaultName, "node-1"): {"index.yaml": "files: []\n"},
+	}, data, "only the nodes running the instance are published, from the ArtifactNodes reporting for it")
+}
+
+func TestCleanupNodeConfigMaps(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+	falco.UID = "falco-uid"
+	other := builders.NewFalco().WithName("other").WithNamespace(testutil.TestNamespace).Build()
+	other.UID = "other-uid"
+
+	newNodeConfigMap := func(owner *instancev1alpha1.Falco, name, kind, node string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testutil.TestNamespace,
+			Labels:    map[string]string{controllerhelper.LabelArtifactKind: kind, controllerhelper.LabelArtifactNode: node},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: instancev1alpha1.GroupVersion.String(), Kind: "Falco", Name: owner.Name, UID: owner.UID,
+			}},
+		}}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "falco-a", Namespace: testutil.TestNamespace,
+			Labels: map[string]string{"app.kubernetes.io/instance": defaultName},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-1"},
+	}
+	objs := []client.Object{falco, other, pod,
+		newNodeConfigMap(falco, controllerhelper.EffectiveConfigMapName(defaultName, "node-1"), controllerhelper.ArtifactKindConfig, "node-1"),
+		newNodeConfigMap(falco, controllerhelper.RulesIndexConfigMapName(defaultName, "node-1"), controllerhelper.ArtifactKindRulesfile, "node-1"),
+		newNodeConfigMap(falco, controllerhelper.EffectiveConfigMapName(defaultName, "node-2"), controllerhelper.ArtifactKindConfig, "node-2"),
+		newNodeConfigMap(falco, controllerhelper.RulesIndexConfigMapName(defaultName, "node-2"), controllerhelper.ArtifactKindRulesfile, "node-2"),
+		newNodeConfigMap(other, controllerhelper.EffectiveConfigMapName("other", "node-2"), controllerhelper.ArtifactKindConfig, "node-2"),
+		newNodeConfigMap(falco, "user-config", controllerhelper.ArtifactKindConfig, "node-2"),
+	}
+
+	t.Run("deletes the ConfigMaps of nodes without a Falco pod", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+		require.NoError(t, r.cleanupNodeConfigMaps(context.Background(), falco))
+
+		configMaps := &corev1.ConfigMapList{}
+		require.NoError(t, cl.List(context.Background(), configMaps))
+		var names []string
+		for _, cm := range configMaps.Items {
+			names = append(names, cm.Name)
+		}
+		assert.ElementsMatch(t, []string{
+			controllerhelper.EffectiveConfigMapName(defaultName, "node-1"),
+			controllerhelper.RulesIndexConfigMapName(defaultName, "node-1"),
+			controllerhelper.EffectiveConfigMapName("other", "node-2"),
+			"user-config",
+		}, names)
+	})
+
+	t.Run("returns delete errors", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(context.Context, client.WithWatch, client.Object, ...client.DeleteOption) error {
+					return fmt.Errorf("injected delete error")
+				},
+			}).Build()
+		r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+		err := r.cleanupNodeConfigMaps(context.Background(), falco)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "injected delete error")
+	})
+}
+
+// stubHealthScraper returns the same sample for every pod.
+type stubHealthScraper struct {
+	sample instance.HealthSample
+}
+
+func (s stubHealthScraper) Scrape(context.Context, *corev1.Pod) (instance.HealthSample, error) {
+	return s.sample, nil
+}
+
+func TestComputeHealthConditions(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-abc", Namespace: testutil.TestNamespace,
+			Labels: map[string]string{"app.kubernetes.io/instance": defaultName},
+		},
+		Spec:   corev1.PodSpec{NodeName: "node-1"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+
+	tests := []struct {
+		name         string
+		healthCheck  *instancev1alpha1.HealthCheckSpec
+		sample       instance.HealthSample
+		wantNodes    int
+		wantDegraded *metav1.ConditionStatus
+		wantDrops    *metav1.ConditionStatus
+	}{
+		{
+			name:      "disabled clears health status",
+			wantNodes: 0,
+		},
+		{
+			name:         "healthy node",
+			healthCheck:  &instancev1alpha1.HealthCheckSpec{Enabled: true},
+			sample:       instance.HealthSample{EventsTotal: 1000, DropsTotal: 1, RulesFilesLoaded: new(int64(1))},
+			wantNodes:    1,
+			wantDegraded: new(metav1.ConditionFalse),
+			wantDrops:    new(metav1.ConditionFalse),
+		},
+		{
+			name:         "custom threshold reports drops",
+			healthCheck:  &instancev1alpha1.HealthCheckSpec{Enabled: true, MaxDropRatePercent: new(int32(0))},
+			sample:       instance.HealthSample{EventsTotal: 1000, DropsTotal: 1, RulesFilesLoaded: new(int64(1))},
+			wantNodes:    1,
+			wantDegraded: new(metav1.ConditionTrue),
+			wantDrops:    new(metav1.ConditionTrue),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+			falco.Spec.HealthCheck = tt.healthCheck
+			// Stale health status from a previous reconcile.
+			falco.Status.Nodes = []instancev1alpha1.FalcoNodeHealth{{NodeName: "stale"}}
+			falco.Status.Conditions = []metav1.Condition{common.NewDegradedCondition(metav1.ConditionTrue, "Stale", "stale", 1)}
+
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco, pod).Build()
+			r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false,
+				WithHealthScraper(stubHealthScraper{sample: tt.sample}))
+
+			require.NoError(t, r.computeHealthConditions(context.Background(), falco))
+
+			assert.Len(t, falco.Status.Nodes, tt.wantNodes)
+			degraded := apimeta.FindStatusCondition(falco.Status.Conditions, commonv1alpha1.ConditionDegraded.String())
+			drops := apimeta.FindStatusCondition(falco.Status.Conditions, commonv1alpha1.ConditionEventDrops.String())
+			if tt.wantDegraded == nil {
+				assert.Nil(t, degraded)
+				assert.Nil(t, drops)
+				return
+			}
+			require.NotNil(t, degraded)
+			require.NotNil(t, drops)
+			assert.Equal(t, *tt.wantDegraded, degraded.Status)
+			assert.Equal(t, *tt.wantDrops, drops.Status)
+			assert.Equal(t, "node-1", falco.Status.Nodes[0].NodeName)
+		})
+	}
+}
+
+func TestComputeHealthConditionsWithinInterval(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+	falco.Spec.HealthCheck = &instancev1alpha1.HealthCheckSpec{Enabled: true}
+	scraped := metav1.NewTime(time.Now().Add(-10 * time.Second))
+	falco.Status.Nodes = []instancev1alpha1.FalcoNodeHealth{{NodeName: "node-1", PodName: "test-abc", Healthy: true,
+		DropRate: "0.00%", RulesFilesLoaded: new(int64(1)), LastScrapeTime: scraped}}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+			return fmt.Errorf("pods listed before the interval elapsed")
+		},
+	}).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false,
+		WithHealthScraper(stubHealthScraper{}))
+
+	require.NoError(t, r.computeHealthConditions(context.Background(), falco))
+	require.Len(t, falco.Status.Nodes, 1)
+	assert.Equal(t, scraped, falco.Status.Nodes[0].LastScrapeTime)
+	testutil.RequireCondition(t, falco.Status.Conditions, commonv1alpha1.ConditionDegraded.String(),
+		metav1.ConditionFalse, instance.ReasonFalcoHealthy)
+}
+
+func TestHealthCheckRequeue(t *testing.T) {
+	now := time.Now()
+	scrapedAt := func(ago time.Duration) []instancev1alpha1.FalcoNodeHealth {
+		return []instancev1alpha1.FalcoNodeHealth{
+			{NodeName: "node-1", LastScrapeTime: metav1.NewTime(now.Add(-time.Hour))},
+			{NodeName: "node-2", LastScrapeTime: metav1.NewTime(now.Add(-ago))},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		healthCheck *instancev1alpha1.HealthCheckSpec
+		nodes       []instancev1alpha1.FalcoNodeHealth
+		wantNext    time.Duration
+		wantRequeue time.Duration
+		wantEnabled bool
+	}{
+		{name: "disabled"},
+		{name: "never scraped", healthCheck: &instancev1alpha1.HealthCheckSpec{Enabled: true},
+			wantRequeue: instance.DefaultHealthCheckInterval, wantEnabled: true},
+		{name: "scraped within the interval", healthCheck: &instancev1alpha1.HealthCheckSpec{Enabled: true},
+			nodes: scrapedAt(10 * time.Second), wantNext: 20 * time.Second, wantRequeue: 20 * time.Second, wantEnabled: true},
+		{name: "interval elapsed", healthCheck: &instancev1alpha1.HealthCheckSpec{Enabled: true},
+			nodes: scrapedAt(time.Minute), wantRequeue: instance.DefaultHealthCheckInterval, wantEnabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := builders.NewFalco().WithName(defaultName).Build()
+			falco.Spec.HealthCheck = tt.healthCheck
+			falco.Status.Nodes = tt.nodes
+
+			next, enabled := nextHealthCheck(falco, now)
+			assert.Equal(t, tt.wantNext, next)
+			assert.Equal(t, tt.wantEnabled, enabled)
+			requeue, _ := healthCheckRequeue(falco, now)
+			assert.Equal(t, tt.wantRequeue, requeue)
+		})
+	}
+}
+
+func TestHealthCheckInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		healthCheck *instancev1alpha1.HealthCheckSpec
+		want        time.Duration
+		wantEnabled bool
+	}{
+		{name: "not configured"},
+		{name: "disabled", healthCheck: &instancev1alpha1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time.Minute}}},
+		{name: "default interval", healthCheck: &instancev1alpha1.HealthCheckSpec{Enabled: true},
+			want: instance.DefaultHealthCheckInterval, wantEnabled: true},
+		{name: "custom interval", healthCheck: &instancev1alpha1.HealthCheckSpec{Enabled: true, Interval: &metav1.Duration{Duration: time.Minute}},
+			want: time.Minute, wantEnabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := builders.NewFalco().WithName(defaultName).Build()
+			falco.Spec.HealthCheck = tt.healthCheck
+			got, enabled := healthCheckInterval(falco)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantEnabled, enabled)
+		})
+	}
+}
+
 // TestEnsureDeploymentWithCustomPodTemplateSpec verifies container merge — structurally
 // different assertions (iterating containers) from the table-driven TestEnsureDeployment.
 func TestEnsureDeploymentWithCustomPodTemplateSpec(t *testing.T) {
@@ -17,23 +17,274 @@
 package falco
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/instance"
 	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
 )
 
+// generateApplyConfiguration generates the default workload of the Falco instance. When node pools are
+// configured, the default DaemonSet only runs on the nodes matched by no pool.
 func generateApplyConfiguration(falco *instancev1alpha1.Falco, resourceType string, nativeSidecar bool) (*unstructured.Unstructured, error) {
+	podTemplateSpec := falco.Spec.PodTemplateSpec
+	if pools := nodePools(falco, resourceType); len(pools) > 0 {
+		var err error
+		if podTemplateSpec, err = nodePoolPodTemplateSpec(falco.Spec.PodTemplateSpec, pools, len(pools)); err != nil {
+			return nil, err
+		}
+	}
+
+	baseResource, err := generateBaseWorkload(falco, resourceType, nativeSidecar)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := configRevision(falco, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeWorkload(falco, resourceType, baseResource, podTemplateSpec, falco.GetLabels(), revision)
+}
+
+// generateNodePoolApplyConfiguration generates the DaemonSet running on the nodes of the node pool at the given index.
+// It is generated as the default DaemonSet, with the resources, tolerations and configuration of the pool, then renamed
+// after the pool and labeled so that its selector only matches its own pods. It keeps the service account of the instance, and its
+// ConfigMap unless the pool overrides the configuration.
+func generateNodePoolApplyConfiguration(falco *instancev1alpha1.Falco, index int, nativeSidecar bool) (*unstructured.Unstructured, error) {
+	pools := falco.Spec.NodePools
+	pool := &pools[index]
+
+	podTemplateSpec, err := nodePoolPodTemplateSpec(falco.Spec.PodTemplateSpec, pools, index)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := configRevision(falco, resources.ResourceTypeDaemonSet)
+	if err != nil {
+		return nil, err
+	}
+	data, err := nodePoolConfigMapData(falco, pool)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		revision = resources.ComputeConfigMapHash(data)
+	}
+
+	baseResource, err := generateBaseWorkload(falco, resources.ResourceTypeDaemonSet, nativeSidecar)
+	if err != nil {
+		return nil, err
+	}
+	ds, ok := baseResource.(*appsv1.DaemonSet)
+	if !ok {
+		return nil, fmt.Errorf("unexpected workload type %T", baseResource)
+	}
+	poolName := resources.NodePoolName(falco.Name, pool.Name)
+	ds.Name = poolName
+	ds.Spec.Template.Labels[resources.NodePoolLabel] = pool.Name
+	if data != nil {
+		for i := range ds.Spec.Template.Spec.Volumes {
+			if cm := ds.Spec.Template.Spec.Volumes[i].ConfigMap; cm != nil && cm.Name == falco.Name {
+				cm.Name = poolName
+			}
+		}
+	}
+
+	poolLabels := maps.Clone(falco.GetLabels())
+	if poolLabels == nil {
+		poolLabels = map[string]string{}
+	}
+	poolLabels[resources.NodePoolLabel] = pool.Name
+
+	result, err := mergeWorkload(falco, resources.ResourceTypeDaemonSet, ds, podTemplateSpec, poolLabels, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// The selector is replaced as a whole by the overlay, so the pool label is added once merged.
+	if err := unstructured.SetNestedField(result.Object, pool.Name,
+		"spec", "selector", "matchLabels", resources.NodePoolLabel); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// generateBaseWorkload generates the workload of the Falco instance from the defaults, before the user overlay is
+// merged onto it. The metrics of the artifact operator sidecar are enabled for the PodMonitor to scrape them, and
+// the certificate is mounted when TLS is enabled.
+func generateBaseWorkload(falco *instancev1alpha1.Falco, resourceType string, nativeSidecar bool) (runtime.Object, error) {
 	baseResource, err := resources.GenerateWorkload(resourceType, &falco.ObjectMeta, resources.FalcoDefaults, nativeSidecar)
 	if err != nil {
 		return nil, err
 	}
+	if monitoring := falco.Spec.Monitoring; monitoring != nil && monitoring.PodMonitor != nil && monitoring.PodMonitor.Enabled {
+		resources.EnableArtifactOperatorMetrics(baseResource)
+	}
+	if tlsEnabled(falco) {
+		resources.EnableTLS(baseResource, falco.Name, resources.FalcoDefaults)
+	}
+	return baseResource, nil
+}
+
+// mergeWorkload merges the user overlay of the Falco instance onto the given base workload. The configuration
+// revision and the serial number of the certificate are stamped on the pod template, to roll the pods when they change.
+func mergeWorkload(falco *instancev1alpha1.Falco, resourceType string, baseResource runtime.Object,
+	podTemplateSpec *corev1.PodTemplateSpec, labels map[string]string, revision string) (*unstructured.Unstructured, error) {
+	overlayOpts := resources.GenerateOverlayOptions(falco)
+	overlayOpts = append(overlayOpts, resources.WithOverlayLabels(labels))
+	if podTemplateSpec != nil {
+		overlayOpts = append(overlayOpts, resources.WithOverlayPodTemplateSpec(podTemplateSpec))
+	}
+	podAnnotations := map[string]string{}
+	if revision != "" {
+		podAnnotations[resources.ConfigHashAnnotation] = revision
+	}
+	if tlsEnabled(falco) && falco.Status.TLS != nil {
+		podAnnotations[resources.CertificateSerialAnnotation] = falco.Status.TLS.SerialNumber
+	}
+	if len(podAnnotations) > 0 {
+		overlayOpts = append(overlayOpts, resources.WithOverlayPodAnnotations(podAnnotations))
+	}
 
-	userOverlay, err := resources.GenerateUserOverlay(resourceType, falco.Name, resources.FalcoDefaults, resources.GenerateOverlayOptions(falco)...)
+	userOverlay, err := resources.GenerateUserOverlay(resourceType, falco.Name, resources.FalcoDefaults, overlayOpts...)
 	if err != nil {
 		return nil, err
 	}
 
 	return instance.MergeApplyConfiguration(resourceType, baseResource, userOverlay)
 }
+
+// nodePools returns the node pools of the Falco instance. Node pools only apply to DaemonSets.
+func nodePools(falco *instancev1alpha1.Falco, resourceType string) []instancev1alpha1.NodePool {
+	if resourceType != resources.ResourceTypeDaemonSet {
+		return nil
+	}
+	return falco.Spec.NodePools
+}
+
+// nodePoolPodTemplateSpec returns a copy of the user pod template spec restricted to the nodes of the pool at the given
+// index, or to the nodes of no pool when index is len(pools), and carrying the resources and tolerations of the pool.
+func nodePoolPodTemplateSpec(podTemplateSpec *corev1.PodTemplateSpec, pools []instancev1alpha1.NodePool,
+	index int) (*corev1.PodTemplateSpec, error) {
+	template := &corev1.PodTemplateSpec{}
+	if podTemplateSpec != nil {
+		template = podTemplateSpec.DeepCopy()
+	}
+
+	if template.Spec.Affinity == nil {
+		template.Spec.Affinity = &corev1.Affinity{}
+	}
+	if template.Spec.Affinity.NodeAffinity == nil {
+		template.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := template.Spec.Affinity.NodeAffinity
+	var userTerms []corev1.NodeSelectorTerm
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		userTerms = nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	}
+	terms, err := instance.NodePoolSelectorTerms(pools, index, userTerms)
+	if err != nil {
+		return nil, err
+	}
+	nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{NodeSelectorTerms: terms}
+
+	if index == len(pools) {
+		return template, nil
+	}
+	pool := &pools[index]
+
+	// Tolerations are replaced as a whole by the overlay, so the pool tolerations are added to the
+	// user tolerations or, when there are none, to the default ones.
+	if len(pool.Tolerations) > 0 {
+		tolerations := template.Spec.Tolerations
+		if tolerations == nil {
+			tolerations = resources.FalcoDefaults.Tolerations
+		}
+		template.Spec.Tolerations = append(slices.Clone(tolerations), pool.Tolerations...)
+	}
+
+	if pool.Resources != nil {
+		i := slices.IndexFunc(template.Spec.Containers, func(c corev1.Container) bool {
+			return c.Name == resources.FalcoDefaults.ContainerName
+		})
+		if i < 0 {
+			template.Spec.Containers = append(template.Spec.Containers, corev1.Container{Name: resources.FalcoDefaults.ContainerName})
+			i = len(template.Spec.Containers) - 1
+		}
+		template.Spec.Containers[i].Resources = *pool.Resources.DeepCopy()
+	}
+
+	return template, nil
+}
+
+// nodePoolConfigMapData returns the data of the ConfigMap of a node pool: the DaemonSet configuration of the Falco
+// instance with the configuration of the pool merged on top. It returns nil when the pool does not override the
+// configuration.
+func nodePoolConfigMapData(falco *instancev1alpha1.Falco, pool *instancev1alpha1.NodePool) (map[string]string, error) {
+	if pool.Config == nil || len(pool.Config.Raw) == 0 {
+		return nil, nil
+	}
+
+	data, err := configMapData(falco, resources.ResourceTypeDaemonSet)
+	if err != nil {
+		return nil, err
+	}
+	key := resources.FalcoDefaults.ConfigMapVolume.SubPath
+	merged, err := instance.MergeNodePoolConfig(data[key], pool.Config)
+	if err != nil {
+		return nil, fmt.Errorf("node pool %q: %w", pool.Name, err)
+	}
+	data[key] = merged
+	return data, nil
+}
+
+// configMapData returns the data of the Falco ConfigMap generated for the given resource type: the default
+// configuration, with the TLS one merged on top when TLS is enabled. It returns nil when no ConfigMap is generated
+// for the resource type.
+func configMapData(falco *instancev1alpha1.Falco, resourceType string) (map[string]string, error) {
+	data, ok := resources.FalcoDefaults.ConfigMapData[resourceType]
+	if !ok {
+		return nil, nil
+	}
+	data = maps.Clone(data)
+	if tlsEnabled(falco) {
+		key := resources.FalcoDefaults.ConfigMapVolume.SubPath
+		merged, err := instance.MergeNodePoolConfig(data[key],
+			&apiextensionsv1.JSON{Raw: []byte(resources.FalcoDefaults.TLS.Config)})
+		if err != nil {
+			return nil, fmt.Errorf("TLS configuration: %w", err)
+		}
+		data[key] = merged
+	}
+	return data, nil
+}
+
+// configRevision returns the hash of the Falco ConfigMap generated for the given resource type.
+// Stamping it on the pod template makes the workload roll its pods whenever the base configuration changes.
+// An empty string is returned when no ConfigMap is generated for the resource type, and an error when the
+// configuration cannot be generated, e.g. when the TLS settings cannot be merged into it.
+func configRevision(falco *instancev1alpha1.Falco, resourceType string) (string, error) {
+	data, err := configMapData(falco, resourceType)
+	if err != nil {
+		return "", fmt.Errorf("computing the configuration revision: %w", err)
+	}
+	if data == nil {
+		return "", nil
+	}
+	return resources.ComputeConfigMapHash(data), nil
+}
+
+// tlsEnabled reports whether TLS is enabled for the Falco instance.
+func tlsEnabled(falco *instancev1alpha1.Falco) bool {
+	return falco.Spec.TLS != nil && falco.Spec.TLS.Enabled
+}
@@ -23,11 +23,18 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/controllers/testutil"
 	"github.com/falcosecurity/falco-operator/internal/pkg/builders"
 	"github.com/falcosecurity/falco-operator/internal/pkg/image"
+	"github.com/falcosecurity/falco-operator/internal/pkg/instance"
 	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
 )
 
@@ -425,6 +432,32 @@ func TestGenerateApplyConfiguration(t *testing.T) {
 	}
 }
 
+// TestGenerateApplyConfigurationSidecarMetrics verifies that the metrics of the sidecar are only served
+// when a PodMonitor scrapes them.
+func TestGenerateApplyConfigurationSidecarMetrics(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		falco := builders.NewFalco().WithName("test-f").WithNamespace(testutil.TestNamespace).Build()
+		falco.Spec.Monitoring = &instancev1alpha1.FalcoMonitoringSpec{
+			MonitoringSpec: commonv1alpha1.MonitoringSpec{PodMonitor: &commonv1alpha1.MonitorSpec{Enabled: enabled}},
+		}
+
+		result, err := generateApplyConfiguration(falco, resources.ResourceTypeDaemonSet, false)
+		require.NoError(t, err)
+
+		sidecar := mustFindContainer(t, mustGetContainers(t, result), falcoDefs.SidecarContainerName)
+		args, _, _ := unstructured.NestedStringSlice(sidecar, "args")
+		ports, _, _ := unstructured.NestedSlice(sidecar, "ports")
+		if !enabled {
+			assert.Empty(t, args)
+			assert.Empty(t, ports)
+			continue
+		}
+		assert.Equal(t, []string{"--metrics-bind-address=:8080", "--metrics-secure=false"}, args)
+		require.Len(t, ports, 1)
+		assert.Equal(t, resources.ArtifactOperatorMetricsPort, ports[0].(map[string]any)["name"])
+	}
+}
+
 // TestGenerateApplyConfigurationSidecarProbes verifies that the sidecar container
 // retains its probes after the merge — structurally different from the table-driven test.
 func TestGenerateApplyConfigurationSidecarProbes(t *testing.T) {
@@ -483,3 +516,204 @@ func TestGenerateApplyConfigurationConfigMapVolume(t *testing.T) {
 	}
 	assert.True(t, configMapMountFound, "configMap volumeMount should be present")
 }
+
+// TestGenerateApplyConfigurationConfigHash verifies the pod template carries the hash of the
+// generated ConfigMap for the resolved resource type, so that config changes roll the pods.
+func TestGenerateApplyConfigurationConfigHash(t *testing.T) {
+	for _, resourceType := range []string{resources.ResourceTypeDaemonSet, resources.ResourceTypeDeployment} {
+		t.Run(resourceType, func(t *testing.T) {
+			falco := builders.NewFalco().WithName("test-f").WithNamespace(testutil.TestNamespace).
+				WithType(resourceType).
+				WithPodTemplateSpec(&corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"team": "secops"}},
+				}).Build()
+
+			result, err := generateApplyConfiguration(falco, resourceType, false)
+			require.NoError(t, err)
+
+			annotations, _, err := unstructured.NestedStringMap(result.Object, "spec", "template", "metadata", "annotations")
+			require.NoError(t, err)
+			want := resources.ComputeConfigMapHash(falcoDefs.ConfigMapData[resourceType])
+			assert.Equal(t, want, annotations[resources.ConfigHashAnnotation])
+			assert.Equal(t, "secops", annotations["team"], "user pod annotations must be preserved")
+		})
+	}
+
+	plain := &instancev1alpha1.Falco{}
+	assert.NotEqual(t, mustConfigRevision(t, plain, resources.ResourceTypeDaemonSet), mustConfigRevision(t, plain, resources.ResourceTypeDeployment),
+		"each resource type has its own configuration")
+	assert.Empty(t, mustConfigRevision(t, plain, "InvalidType"))
+	withTLS := &instancev1alpha1.Falco{Spec: instancev1alpha1.FalcoSpec{TLS: &commonv1alpha1.TLSSpec{Enabled: true}}}
+	assert.NotEqual(t, mustConfigRevision(t, plain, resources.ResourceTypeDaemonSet), mustConfigRevision(t, withTLS, resources.ResourceTypeDaemonSet),
+		"enabling TLS changes the configuration")
+}
+
+func TestConfigRevision_TLSMergeError(t *testing.T) {
+	tlsConfig := resources.FalcoDefaults.TLS.Config
+	resources.FalcoDefaults.TLS.Config = "webserver: ["
+	t.Cleanup(func() { resources.FalcoDefaults.TLS.Config = tlsConfig })
+
+	falco := builders.NewFalco().WithName("test-f").WithNamespace(testutil.TestNamespace).Build()
+	falco.Spec.TLS = &commonv1alpha1.TLSSpec{Enabled: true}
+
+	_, err := configRevision(falco, resources.ResourceTypeDaemonSet)
+	require.Error(t, err)
+	_, err = generateApplyConfiguration(falco, resources.ResourceTypeDaemonSet, false)
+	require.Error(t, err, "the workload must not be generated without its config hash")
+}
+
+// mustConfigRevision returns the configuration revision of the Falco instance for the resource type.
+func mustConfigRevision(t *testing.T, falco *instancev1alpha1.Falco, resourceType string) string {
+	t.Helper()
+	revision, err := configRevision(falco, resourceType)
+	require.NoError(t, err)
+	return revision
+}
+
+func testNodePools() []instancev1alpha1.NodePool {
+	return []instancev1alpha1.NodePool{
+		{
+			Name:         "gpu",
+			NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"nvidia.com/gpu": "true"}},
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			Tolerations: []corev1.Toleration{
+				{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			},
+			Config: &apiextensionsv1.JSON{Raw: []byte(`{"engine":{"kind":"kmod"}}`)},
+		},
+		{
+			Name:         "arm",
+			NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/arch": "arm64"}},
+		},
+	}
+}
+
+// mustGetRequiredNodeSelectorTerms extracts the required node affinity terms of an unstructured workload.
+func mustGetRequiredNodeSelectorTerms(t *testing.T, obj *unstructured.Unstructured) []corev1.NodeSelectorTerm {
+	t.Helper()
+	var ds appsv1.DaemonSet
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &ds))
+	affinity := ds.Spec.Template.Spec.Affinity
+	require.NotNil(t, affinity)
+	require.NotNil(t, affinity.NodeAffinity)
+	require.NotNil(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	return affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+}
+
+func TestGenerateNodePoolApplyConfiguration(t *testing.T) {
+	pools := testNodePools()
+	falco := builders.NewFalco().WithName("test-f").WithNamespace(testutil.TestNamespace).
+		WithLabels(map[string]string{"team": "secops"}).WithVersion("0.40.0").WithNodePools(pools...).Build()
+
+	t.Run("pool with overrides", func(t *testing.T) {
+		result, err := generateNodePoolApplyConfiguration(falco, 0, false)
+		require.NoError(t, err)
+
+		var ds appsv1.DaemonSet
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(result.Object, &ds))
+
+		assert.Equal(t, "test-f-gpu", ds.Name)
+		assert.Equal(t, map[string]string{"team": "secops", resources.NodePoolLabel: "gpu"}, ds.Labels)
+		assert.Equal(t, "gpu", ds.Spec.Selector.MatchLabels[resources.NodePoolLabel])
+		assert.Equal(t, "test-f", ds.Spec.Selector.MatchLabels["app.kubernetes.io/instance"])
+		assert.Equal(t, "gpu", ds.Spec.Template.Labels[resources.NodePoolLabel])
+
+		wantTerms, err := instance.NodePoolSelectorTerms(pools, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, wantTerms, ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+		assert.Len(t, ds.Spec.Template.Spec.Tolerations, len(falcoDefs.Tolerations)+1,
+			"pool tolerations are added to the default ones")
+		assert.Contains(t, ds.Spec.Template.Spec.Tolerations, pools[0].Tolerations[0])
+
+		var mainContainer *corev1.Container
+		for i := range ds.Spec.Template.Spec.Containers {
+			if ds.Spec.Template.Spec.Containers[i].Name == falcoDefs.ContainerName {
+				mainContainer = &ds.Spec.Template.Spec.Containers[i]
+			}
+		}
+		require.NotNil(t, mainContainer)
+		assert.Equal(t, resource.MustParse("2Gi"), mainContainer.Resources.Limits[corev1.ResourceMemory])
+		assert.Equal(t, falcoDefs.ImageRepository+":0.40.0", mainContainer.Image)
+
+		for _, v := range ds.Spec.Template.Spec.Volumes {
+			if v.Name == falcoDefs.ConfigMapVolume.VolumeName {
+				assert.Equal(t, "test-f-gpu", v.ConfigMap.Name, "pool with a config mounts its own ConfigMap")
+			}
+		}
+		data, err := nodePoolConfigMapData(falco, &pools[0])
+		require.NoError(t, err)
+		assert.Contains(t, data[falcoDefs.ConfigMapVolume.SubPath], "kind: kmod")
+		assert.Equal(t, resources.ComputeConfigMapHash(data), ds.Spec.Template.Annotations[resources.ConfigHashAnnotation])
+	})
+
+	t.Run("pool without overrides", func(t *testing.T) {
+		result, err := generateNodePoolApplyConfiguration(falco, 1, false)
+		require.NoError(t, err)
+
+		var ds appsv1.DaemonSet
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(result.Object, &ds))
+
+		assert.Equal(t, "test-f-arm", ds.Name)
+		assert.Len(t, ds.Spec.Template.Spec.Tolerations, len(falcoDefs.Tolerations))
+		for _, v := range ds.Spec.Template.Spec.Volumes {
+			if v.Name == falcoDefs.ConfigMapVolume.VolumeName {
+				assert.Equal(t, "test-f", v.ConfigMap.Name, "pool without a config mounts the base ConfigMap")
+			}
+		}
+		assert.Equal(t, mustConfigRevision(t, falco, resources.ResourceTypeDaemonSet), ds.Spec.Template.Annotations[resources.ConfigHashAnnotation])
+
+		wantTerms, err := instance.NodePoolSelectorTerms(pools, 1, nil)
+		require.NoError(t, err)
+		assert.Equal(t, wantTerms, mustGetRequiredNodeSelectorTerms(t, result))
+	})
+
+	t.Run("default daemonset excludes the nodes of every pool", func(t *testing.T) {
+		result, err := generateApplyConfiguration(falco, resources.ResourceTypeDaemonSet, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-f", result.GetName())
+		assert.NotContains(t, result.GetLabels(), resources.NodePoolLabel)
+		wantTerms, err := instance.NodePoolSelectorTerms(pools, len(pools), nil)
+		require.NoError(t, err)
+		assert.Equal(t, wantTerms, mustGetRequiredNodeSelectorTerms(t, result))
+	})
+
+	t.Run("node pools are ignored for deployments", func(t *testing.T) {
+		result, err := generateApplyConfiguration(falco, resources.ResourceTypeDeployment, false)
+		require.NoError(t, err)
+
+		_, found, err := unstructured.NestedMap(result.Object, "spec", "template", "spec", "affinity")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("user tolerations are kept", func(t *testing.T) {
+		withTolerations := falco.DeepCopy()
+		withTolerations.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		}}
+
+		result, err := generateNodePoolApplyConfiguration(withTolerations, 0, false)
+		require.NoError(t, err)
+
+		var ds appsv1.DaemonSet
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(result.Object, &ds))
+		assert.Equal(t, []corev1.Toleration{
+			{Key: "dedicated", Operator: corev1.TolerationOpExists},
+			pools[0].Tolerations[0],
+		}, ds.Spec.Template.Spec.Tolerations)
+	})
+
+	t.Run("empty node selector", func(t *testing.T) {
+		invalid := builders.NewFalco().WithName("test-f").WithNamespace(testutil.TestNamespace).
+			WithNodePools(instancev1alpha1.NodePool{Name: "empty"}).Build()
+
+		_, err := generateNodePoolApplyConfiguration(invalid, 0, false)
+		require.ErrorContains(t, err, "empty node selector")
+		_, err = generateApplyConfiguration(invalid, resources.ResourceTypeDaemonSet, false)
+		require.ErrorContains(t, err, "empty node selector")
+	})
+}
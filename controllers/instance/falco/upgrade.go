@@ -0,0 +1,399 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package falco
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/instance"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+// reconcileUpgrade moves the Falco instance towards the target version according to its upgrade policy.
+// Without a policy, the target version is deployed right away. Otherwise the version, and while the canary
+// is running the OnDelete update strategy, are pinned on the in-memory copy of the instance the workloads
+// are generated from. It returns how long to wait before checking the upgrade again.
+//
+// Until the rollout, the pod templates only carry the new version while the pods of the canary nodes are being
+// replaced, and the previous one otherwise: a pod deleted on another node, e.g. by a node drain, is recreated
+// on the previous version instead of bypassing the canary.
+func (r *Reconciler) reconcileUpgrade(ctx context.Context, falco *instancev1alpha1.Falco, target string,
+	now metav1.Time) (time.Duration, error) {
+	policy := falco.Spec.UpgradePolicy
+	if policy == nil || resolveResourceType(falco.Spec.Type) != resources.ResourceTypeDaemonSet {
+		falco.Status.Upgrade = nil
+		falco.Status.Version = target
+		return 0, nil
+	}
+
+	current := falco.Status.Version
+	upgrade := falco.Status.Upgrade
+
+	// In plan mode, the upgrade is neither started nor advanced: the workloads are planned with the
+	// version the next reconciliation in apply mode would pin.
+	if instance.PlanFromContext(ctx) != nil {
+		switch {
+		case upgrade != nil && upgrade.ToVersion == target:
+			pods, err := r.upgradePods(ctx, falco, upgrade)
+			if err != nil {
+				return 0, err
+			}
+			pinUpgrade(falco, upgrade, pods)
+		case current != "" && current != target:
+			pinUpgrade(falco, &instancev1alpha1.UpgradeStatus{
+				Phase: instancev1alpha1.UpgradePhaseCanary, FromVersion: current, ToVersion: target,
+			}, nil)
+		}
+		return 0, nil
+	}
+
+	switch {
+	case upgrade != nil && upgrade.ToVersion == target:
+		// Carry on with the upgrade to the target version, or keep the outcome of the finished one.
+	case current == "" || current == target:
+		// Nothing to upgrade: first deployment, or the version was reverted during an upgrade.
+		if instance.UpgradeInProgress(upgrade) {
+			r.endUpgrade(falco, instancev1alpha1.UpgradePhaseRolledBack, fmt.Sprintf(instance.MessageFormatUpgradeCancelled, upgrade.ToVersion), now)
+		}
+		falco.Status.Version = target
+		return 0, nil
+	default:
+		upgrade = &instancev1alpha1.UpgradeStatus{
+			Phase:              instancev1alpha1.UpgradePhaseCanary,
+			FromVersion:        current,
+			ToVersion:          target,
+			StartTime:          now,
+			LastTransitionTime: now,
+		}
+		falco.Status.Upgrade = upgrade
+	}
+
+	pods, err := r.upgradePods(ctx, falco, upgrade)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.advanceUpgrade(ctx, falco, policy, pods, now); err != nil {
+		return 0, err
+	}
+
+	pinUpgrade(falco, upgrade, pods)
+	if instance.UpgradeInProgress(upgrade) {
+		return instance.UpgradeRequeueInterval, nil
+	}
+	return 0, nil
+}
+
+// upgradePods returns the Falco pods while the upgrade is in progress, nil otherwise.
+func (r *Reconciler) upgradePods(ctx context.Context, falco *instancev1alpha1.Falco,
+	upgrade *instancev1alpha1.UpgradeStatus) ([]corev1.Pod, error) {
+	if !instance.UpgradeInProgress(upgrade) {
+		return nil, nil
+	}
+	pods, err := r.listPods(ctx, falco)
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// pinUpgrade pins the version the workloads run in the given phase of the upgrade. The canary nodes
+// and a halted upgrade hold the pods of the daemonsets. The new version is only pinned while the pods of
+// the canary nodes are being replaced, so that the daemonsets recreate any other pod on the previous one.
+func pinUpgrade(falco *instancev1alpha1.Falco, upgrade *instancev1alpha1.UpgradeStatus, pods []corev1.Pod) {
+	switch upgrade.Phase {
+	case instancev1alpha1.UpgradePhaseCanary:
+		version := upgrade.FromVersion
+		if canaryReplacementPending(upgrade, pods) {
+			version = upgrade.ToVersion
+		}
+		instance.PinVersion(falco, version, true, resources.FalcoDefaults)
+	case instancev1alpha1.UpgradePhaseSoaking, instancev1alpha1.UpgradePhaseFailed:
+		instance.PinVersion(falco, upgrade.FromVersion, true, resources.FalcoDefaults)
+	case instancev1alpha1.UpgradePhaseRollingOut, instancev1alpha1.UpgradePhaseCompleted:
+		instance.PinVersion(falco, upgrade.ToVersion, false, resources.FalcoDefaults)
+	case instancev1alpha1.UpgradePhaseRolledBack:
+		instance.PinVersion(falco, upgrade.FromVersion, false, resources.FalcoDefaults)
+	}
+}
+
+// canaryReplacementPending reports whether the canary phase still has to replace the pod of a canary node, when
+// the canary nodes are not selected yet or one of them does not run a pod on the new version.
+func canaryReplacementPending(upgrade *instancev1alpha1.UpgradeStatus, pods []corev1.Pod) bool {
+	if len(upgrade.CanaryNodes) == 0 {
+		return true
+	}
+	for _, node := range upgrade.CanaryNodes {
+		if !slices.ContainsFunc(pods, func(pod corev1.Pod) bool {
+			return pod.Spec.NodeName == node && pod.DeletionTimestamp == nil &&
+				instance.PodVersion(&pod, resources.FalcoDefaults) == upgrade.ToVersion
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// advanceUpgrade moves the upgrade of the Falco instance to its next phase when its conditions are met.
+func (r *Reconciler) advanceUpgrade(ctx context.Context, falco *instancev1alpha1.Falco,
+	policy *instancev1alpha1.UpgradePolicy, pods []corev1.Pod, now metav1.Time) error {
+	upgrade := falco.Status.Upgrade
+	if !instance.UpgradeInProgress(upgrade) {
+		return nil
+	}
+
+	if upgrade.Phase == instancev1alpha1.UpgradePhaseRollingOut {
+		pending := 0
+		for i := range pods {
+			pod := &pods[i]
+			if pod.DeletionTimestamp == nil && instance.PodVersion(pod, resources.FalcoDefaults) != upgrade.ToVersion {
+				pending++
+			}
+		}
+		if pending == 0 && isAvailable(falco) {
+			upgrade.Message = fmt.Sprintf(instance.MessageFormatUpgradeCompleted, upgrade.ToVersion)
+			r.setUpgradePhase(falco, instancev1alpha1.UpgradePhaseCompleted, instance.ReasonUpgradeCompleted, upgrade.Message, now)
+		} else {
+			upgrade.Message = fmt.Sprintf(instance.MessageFormatRolloutPending, pending, upgrade.ToVersion)
+		}
+		return nil
+	}
+
+	if len(upgrade.CanaryNodes) == 0 {
+		nodes, err := r.selectCanaryNodes(ctx, policy.Canary, pods)
+		if err != nil {
+			return err
+		}
+		if len(nodes) == 0 {
+			r.failUpgrade(falco, policy, instance.MessageNoCanaryNodes, now)
+			return nil
+		}
+		upgrade.CanaryNodes = nodes
+		upgrade.Message = fmt.Sprintf(instance.MessageFormatUpgradeStarted, upgrade.FromVersion, upgrade.ToVersion,
+			strings.Join(nodes, ", "))
+		r.recorder.Eventf(falco, nil, corev1.EventTypeNormal, instance.ReasonUpgradeStarted,
+			instance.ReasonUpgradeStarted, upgrade.Message)
+	}
+
+	problem := canaryProblem(falco, pods)
+	switch upgrade.Phase {
+	case instancev1alpha1.UpgradePhaseCanary:
+		deadline := durationOrDefault(policy.ProgressDeadline, instance.DefaultUpgradeProgressDeadline)
+		switch {
+		case problem == "":
+			soak := durationOrDefault(policy.SoakDuration, instance.DefaultUpgradeSoakDuration)
+			upgrade.Message = fmt.Sprintf(instance.MessageFormatUpgradeSoaking, upgrade.ToVersion, soak)
+			r.setUpgradePhase(falco, instancev1alpha1.UpgradePhaseSoaking, instance.ReasonUpgradeSoaking, upgrade.Message, now)
+		case now.Sub(upgrade.LastTransitionTime.Time) > deadline:
+			r.failUpgrade(falco, policy, fmt.Sprintf(instance.MessageFormatCanaryDeadlineExceeded, deadline, problem), now)
+		default:
+			upgrade.Message = problem
+		}
+	case instancev1alpha1.UpgradePhaseSoaking:
+		soak := durationOrDefault(policy.SoakDuration, instance.DefaultUpgradeSoakDuration)
+		switch {
+		case problem != "":
+			r.failUpgrade(falco, policy, fmt.Sprintf(instance.MessageFormatCanaryUnhealthy, problem), now)
+		case now.Sub(upgrade.LastTransitionTime.Time) >= soak:
+			upgrade.Message = fmt.Sprintf(instance.MessageFormatUpgradePromoted, upgrade.ToVersion)
+			r.setUpgradePhase(falco, instancev1alpha1.UpgradePhaseRollingOut, instance.ReasonUpgradePromoted, upgrade.Message, now)
+			falco.Status.Version = upgrade.ToVersion
+		}
+	}
+
+	return nil
+}
+
+// failUpgrade ends the canary of the upgrade, rolling back to the previous version unless disabled by the policy.
+func (r *Reconciler) failUpgrade(falco *instancev1alpha1.Falco, policy *instancev1alpha1.UpgradePolicy,
+	reason string, now metav1.Time) {
+	if policy.AutoRollback == nil || *policy.AutoRollback {
+		r.endUpgrade(falco, instancev1alpha1.UpgradePhaseRolledBack, reason, now)
+		return
+	}
+	r.endUpgrade(falco, instancev1alpha1.UpgradePhaseFailed, reason, now)
+}
+
+// endUpgrade moves the upgrade to the RolledBack or Failed phase.
+func (r *Reconciler) endUpgrade(falco *instancev1alpha1.Falco, phase instancev1alpha1.UpgradePhase,
+	reason string, now metav1.Time) {
+	upgrade := falco.Status.Upgrade
+	if phase == instancev1alpha1.UpgradePhaseRolledBack {
+		upgrade.Message = fmt.Sprintf(instance.MessageFormatUpgradeRolledBack, upgrade.FromVersion, reason)
+		r.setUpgradePhase(falco, phase, instance.ReasonUpgradeRolledBack, upgrade.Message, now)
+		return
+	}
+	upgrade.Message = fmt.Sprintf(instance.MessageFormatUpgradeFailed, upgrade.ToVersion, reason)
+	r.setUpgradePhase(falco, phase, instance.ReasonUpgradeFailed, upgrade.Message, now)
+}
+
+// setUpgradePhase moves the upgrade to the given phase and records an event.
+func (r *Reconciler) setUpgradePhase(falco *instancev1alpha1.Falco, phase instancev1alpha1.UpgradePhase,
+	reason, message string, now metav1.Time) {
+	falco.Status.Upgrade.Phase = phase
+	falco.Status.Upgrade.LastTransitionTime = now
+
+	eventType := corev1.EventTypeNormal
+	if phase == instancev1alpha1.UpgradePhaseRolledBack || phase == instancev1alpha1.UpgradePhaseFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	r.recorder.Eventf(falco, nil, eventType, reason, reason, message)
+}
+
+// selectCanaryNodes returns the sorted names of the nodes running Falco that are part of the canary.
+func (r *Reconciler) selectCanaryNodes(ctx context.Context, canary instancev1alpha1.CanarySpec,
+	pods []corev1.Pod) ([]string, error) {
+	var nodes []string
+	for i := range pods {
+		if pods[i].Spec.NodeName != "" && pods[i].DeletionTimestamp == nil {
+			nodes = append(nodes, pods[i].Spec.NodeName)
+		}
+	}
+	slices.Sort(nodes)
+	nodes = slices.Compact(nodes)
+
+	if canary.Nodes != nil {
+		return nodes[:min(int(*canary.Nodes), len(nodes))], nil
+	}
+	if canary.NodeSelector == nil {
+		return nil, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(canary.NodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("parsing canary node selector: %w", err)
+	}
+	selected := &corev1.NodeList{}
+	if err := r.List(ctx, selected, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("listing canary nodes: %w", err)
+	}
+	var canaryNodes []string
+	for i := range selected.Items {
+		if _, found := slices.BinarySearch(nodes, selected.Items[i].Name); found {
+			canaryNodes = append(canaryNodes, selected.Items[i].Name)
+		}
+	}
+	slices.Sort(canaryNodes)
+	log.FromContext(ctx).V(2).Info("Selected canary nodes", "nodes", canaryNodes)
+	return canaryNodes, nil
+}
+
+// canaryProblem returns why the canary of the upgrade is not healthy yet, or an empty string when every canary
+// node runs a ready Falco pod on the new version, healthy when health checks are enabled, and Falco is available.
+func canaryProblem(falco *instancev1alpha1.Falco, pods []corev1.Pod) string {
+	upgrade := falco.Status.Upgrade
+	_, healthChecks := healthCheckInterval(falco)
+
+	for _, node := range upgrade.CanaryNodes {
+		i := slices.IndexFunc(pods, func(pod corev1.Pod) bool {
+			return pod.Spec.NodeName == node && pod.DeletionTimestamp == nil
+		})
+		if i < 0 {
+			return fmt.Sprintf(instance.MessageFormatCanaryPodMissing, node)
+		}
+		pod := &pods[i]
+		if version := instance.PodVersion(pod, resources.FalcoDefaults); version != upgrade.ToVersion {
+			return fmt.Sprintf(instance.MessageFormatCanaryPodVersion, pod.Name, node, version)
+		}
+		if !instance.IsPodReady(pod) {
+			return fmt.Sprintf(instance.MessageFormatCanaryPodNotReady, pod.Name, node)
+		}
+		if !healthChecks {
+			continue
+		}
+		j := slices.IndexFunc(falco.Status.Nodes, func(h instancev1alpha1.FalcoNodeHealth) bool {
+			return h.NodeName == node && h.PodName == pod.Name
+		})
+		if j < 0 {
+			return fmt.Sprintf(instance.MessageFormatCanaryNotScraped, pod.Name, node)
+		}
+		if health := falco.Status.Nodes[j]; !health.Healthy {
+			return fmt.Sprintf(instance.MessageFormatCanaryUnhealthyNode, node, health.Message)
+		}
+	}
+
+	if available := apimeta.FindStatusCondition(falco.Status.Conditions,
+		commonv1alpha1.ConditionAvailable.String()); available == nil || available.Status != metav1.ConditionTrue {
+		message := ""
+		if available != nil {
+			message = available.Message
+		}
+		return fmt.Sprintf(instance.MessageFormatFalcoNotAvailable, message)
+	}
+	return ""
+}
+
+// replaceCanaryPods deletes the Falco pods of the canary nodes that do not run the version being upgraded to.
+// The daemonsets hold their pods during the canary, and recreate the deleted ones from the new template.
+func (r *Reconciler) replaceCanaryPods(ctx context.Context, falco *instancev1alpha1.Falco) error {
+	logger := log.FromContext(ctx)
+
+	upgrade := falco.Status.Upgrade
+	if upgrade == nil || upgrade.Phase != instancev1alpha1.UpgradePhaseCanary {
+		return nil
+	}
+	plan := instance.PlanFromContext(ctx)
+
+	pods, err := r.listPods(ctx, falco)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || !slices.Contains(upgrade.CanaryNodes, pod.Spec.NodeName) ||
+			instance.PodVersion(pod, resources.FalcoDefaults) == upgrade.ToVersion {
+			continue
+		}
+		if plan != nil {
+			plan.Add(commonv1alpha1.PendingActionDelete, "Pod", pod.Name, "")
+			continue
+		}
+		if err := r.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "unable to replace canary pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+			return err
+		}
+		logger.Info("Replaced canary pod", "pod", pod.Name, "node", pod.Spec.NodeName, "version", upgrade.ToVersion)
+		r.recorder.Eventf(falco, nil, corev1.EventTypeNormal, instance.ReasonCanaryPodReplaced, instance.ReasonCanaryPodReplaced,
+			instance.MessageFormatCanaryPodReplaced, pod.Name, pod.Spec.NodeName, upgrade.ToVersion)
+	}
+	return nil
+}
+
+// isAvailable reports whether the Available condition of the Falco instance is True.
+func isAvailable(falco *instancev1alpha1.Falco) bool {
+	return apimeta.IsStatusConditionTrue(falco.Status.Conditions, commonv1alpha1.ConditionAvailable.String())
+}
+
+// durationOrDefault returns the duration, or the default when unset.
+func durationOrDefault(d *metav1.Duration, def time.Duration) time.Duration {
+	if d != nil && d.Duration > 0 {
+		return d.Duration
+	}
+	return def
+}
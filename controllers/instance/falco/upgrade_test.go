@@ -0,0 +1,541 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package falco
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/controllers/testutil"
+	"github.com/falcosecurity/falco-operator/internal/pkg/builders"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/image"
+	"github.com/falcosecurity/falco-operator/internal/pkg/instance"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+// newUpgradePod returns a Falco pod of the test instance running the given version on the node.
+func newUpgradePod(name, node, version string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: testutil.TestNamespace,
+			Labels: map[string]string{"app.kubernetes.io/instance": defaultName},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   node,
+			Containers: []corev1.Container{{Name: testContainerName, Image: "docker.io/falcosecurity/falco:" + version}},
+		},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+	}
+}
+
+// availableCondition returns an Available condition with the given status.
+func availableCondition(status metav1.ConditionStatus) metav1.Condition {
+	return metav1.Condition{
+		Type:   commonv1alpha1.ConditionAvailable.String(),
+		Status: status,
+		Reason: "Test",
+	}
+}
+
+func TestReconcileUpgrade(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	ago := func(d time.Duration) metav1.Time { return metav1.NewTime(now.Add(-d)) }
+
+	onePod := instancev1alpha1.UpgradePolicy{Canary: instancev1alpha1.CanarySpec{Nodes: new(int32(1))}}
+	noRollback := onePod
+	noRollback.AutoRollback = new(false)
+	bySelector := instancev1alpha1.UpgradePolicy{Canary: instancev1alpha1.CanarySpec{
+		NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+	}}
+	canaryOn := func(phase instancev1alpha1.UpgradePhase, since time.Duration) *instancev1alpha1.UpgradeStatus {
+		return &instancev1alpha1.UpgradeStatus{
+			Phase: phase, FromVersion: "0.40.0", ToVersion: "0.41.0", CanaryNodes: []string{"node-1"},
+			StartTime: ago(time.Hour), LastTransitionTime: ago(since),
+		}
+	}
+	oldPods := []client.Object{
+		newUpgradePod("falco-a", "node-1", "0.40.0", true),
+		newUpgradePod("falco-b", "node-2", "0.40.0", true),
+		newUpgradePod("falco-c", "node-3", "0.40.0", true),
+	}
+	canaryPods := []client.Object{
+		newUpgradePod("falco-a", "node-1", "0.41.0", true),
+		newUpgradePod("falco-b", "node-2", "0.40.0", true),
+	}
+
+	tests := []struct {
+		name          string
+		policy        *instancev1alpha1.UpgradePolicy
+		healthCheck   *instancev1alpha1.HealthCheckSpec
+		version       string
+		upgrade       *instancev1alpha1.UpgradeStatus
+		conditions    []metav1.Condition
+		nodes         []instancev1alpha1.FalcoNodeHealth
+		objs          []client.Object
+		target        string
+		wantPhase     instancev1alpha1.UpgradePhase
+		wantCanary    []string
+		wantVersion   string
+		wantPinned    string
+		wantHold      bool
+		wantRequeue   time.Duration
+		wantEvents    []string
+		wantMessage   string
+		wantNoUpgrade bool
+	}{
+		{
+			name:          "without a policy the target is deployed right away",
+			version:       "0.40.0",
+			target:        "0.41.0",
+			wantVersion:   "0.41.0",
+			wantNoUpgrade: true,
+		},
+		{
+			name:          "first deployment does not upgrade",
+			policy:        &onePod,
+			target:        "0.41.0",
+			wantVersion:   "0.41.0",
+			wantNoUpgrade: true,
+		},
+		{
+			name:        "version change starts the canary on the first nodes",
+			policy:      &onePod,
+			version:     "0.40.0",
+			objs:        oldPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseCanary,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.41.0",
+			wantHold:    true,
+			wantRequeue: instance.UpgradeRequeueInterval,
+			wantEvents:  []string{instance.ReasonUpgradeStarted},
+			wantMessage: `runs version "0.40.0"`,
+		},
+		{
+			name:    "canary nodes are selected by label",
+			policy:  &bySelector,
+			version: "0.40.0",
+			objs: append([]client.Object{
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"canary": "true"}}},
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-4", Labels: map[string]string{"canary": "true"}}},
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+			}, oldPods...),
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseCanary,
+			wantCanary:  []string{"node-2"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.41.0",
+			wantHold:    true,
+			wantRequeue: instance.UpgradeRequeueInterval,
+			wantEvents:  []string{instance.ReasonUpgradeStarted},
+		},
+		{
+			name:        "no canary node rolls back",
+			policy:      &bySelector,
+			version:     "0.40.0",
+			objs:        oldPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseRolledBack,
+			wantVersion: "0.40.0",
+			wantPinned:  "0.40.0",
+			wantEvents:  []string{instance.ReasonUpgradeRolledBack},
+			wantMessage: instance.MessageNoCanaryNodes,
+		},
+		{
+			name:        "healthy canary starts soaking",
+			policy:      &onePod,
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseCanary, time.Minute),
+			conditions:  []metav1.Condition{availableCondition(metav1.ConditionTrue)},
+			objs:        canaryPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseSoaking,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.40.0",
+			wantHold:    true,
+			wantRequeue: instance.UpgradeRequeueInterval,
+			wantEvents:  []string{instance.ReasonUpgradeSoaking},
+		},
+		{
+			name:        "canary pod pending replacement pins the new version",
+			policy:      &onePod,
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseCanary, time.Minute),
+			conditions:  []metav1.Condition{availableCondition(metav1.ConditionTrue)},
+			objs:        oldPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseCanary,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.41.0",
+			wantHold:    true,
+			wantRequeue: instance.UpgradeRequeueInterval,
+			wantMessage: `runs version "0.40.0"`,
+		},
+		{
+			name:        "canary not ready waits within the deadline",
+			policy:      &onePod,
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseCanary, time.Minute),
+			conditions:  []metav1.Condition{availableCondition(metav1.ConditionTrue)},
+			objs:        []client.Object{newUpgradePod("falco-a", "node-1", "0.41.0", false)},
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseCanary,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.40.0",
+			wantHold:    true,
+			wantRequeue: instance.UpgradeRequeueInterval,
+			wantMessage: "is not ready",
+		},
+		{
+			name:        "canary past the deadline halts without rollback",
+			policy:      &noRollback,
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseCanary, time.Hour),
+			conditions:  []metav1.Condition{availableCondition(metav1.ConditionTrue)},
+			objs:        []client.Object{newUpgradePod("falco-a", "node-1", "0.41.0", false)},
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseFailed,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.40.0",
+			wantHold:    true,
+			wantEvents:  []string{instance.ReasonUpgradeFailed},
+			wantMessage: "not ready and healthy within",
+		},
+		{
+			name:        "unavailable Falco keeps the canary waiting",
+			policy:      &onePod,
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseCanary, time.Minute),
+			conditions:  []metav1.Condition{availableCondition(metav1.ConditionFalse)},
+			objs:        canaryPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseCanary,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.40.0",
+			wantHold:    true,
+			wantRequeue: instance.UpgradeRequeueInterval,
+			wantMessage: "not available",
+		},
+		{
+			name:        "soak elapsed promotes the upgrade",
+			policy:      &onePod,
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseSoaking, instance.DefaultUpgradeSoakDuration),
+			conditions:  []metav1.Condition{availableCondition(metav1.ConditionTrue)},
+			objs:        canaryPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseRollingOut,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.41.0",
+			wantPinned:  "0.41.0",
+			wantRequeue: instance.UpgradeRequeueInterval,
+			wantEvents:  []string{instance.ReasonUpgradePromoted},
+		},
+		{
+			name:        "unhealthy canary while soaking rolls back",
+			policy:      &onePod,
+			healthCheck: &instancev1alpha1.HealthCheckSpec{Enabled: true},
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseSoaking, time.Minute),
+			conditions:  []metav1.Condition{availableCondition(metav1.ConditionTrue)},
+			nodes: []instancev1alpha1.FalcoNodeHealth{
+				{NodeName: "node-1", PodName: "falco-a", Healthy: false, Message: "no rules files loaded"},
+			},
+			objs:        canaryPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseRolledBack,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.40.0",
+			wantEvents:  []string{instance.ReasonUpgradeRolledBack},
+			wantMessage: "no rules files loaded",
+		},
+		{
+			name:        "rollout waits for every pod",
+			policy:      &onePod,
+			version:     "0.41.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseRollingOut, time.Minute),
+			conditions:  []metav1.Condition{availableCondition(metav1.ConditionTrue)},
+			objs:        canaryPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseRollingOut,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.41.0",
+			wantPinned:  "0.41.0",
+			wantRequeue: instance.UpgradeRequeueInterval,
+			wantMessage: "1 pods do not run 0.41.0 yet",
+		},
+		{
+			name:    "rollout completes when every pod runs the new version",
+			policy:  &onePod,
+			version: "0.41.0",
+			upgrade: canaryOn(instancev1alpha1.UpgradePhaseRollingOut, time.Minute),
+			conditions: []metav1.Condition{
+				availableCondition(metav1.ConditionTrue),
+			},
+			objs: []client.Object{
+				newUpgradePod("falco-a", "node-1", "0.41.0", true),
+				newUpgradePod("falco-b", "node-2", "0.41.0", true),
+			},
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseCompleted,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.41.0",
+			wantPinned:  "0.41.0",
+			wantEvents:  []string{instance.ReasonUpgradeCompleted},
+		},
+		{
+			name:        "reverting the version cancels the upgrade",
+			policy:      &onePod,
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseCanary, time.Minute),
+			objs:        canaryPods,
+			target:      "0.40.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseRolledBack,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantEvents:  []string{instance.ReasonUpgradeRolledBack},
+			wantMessage: "upgrade to 0.41.0 cancelled",
+		},
+		{
+			name:        "rolled back upgrade keeps the previous version",
+			policy:      &onePod,
+			version:     "0.40.0",
+			upgrade:     canaryOn(instancev1alpha1.UpgradePhaseRolledBack, time.Minute),
+			objs:        canaryPods,
+			target:      "0.41.0",
+			wantPhase:   instancev1alpha1.UpgradePhaseRolledBack,
+			wantCanary:  []string{"node-1"},
+			wantVersion: "0.40.0",
+			wantPinned:  "0.40.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).
+				WithType(resources.ResourceTypeDaemonSet).WithVersion(tt.target)
+			if tt.policy != nil {
+				b = b.WithUpgradePolicy(*tt.policy)
+			}
+			falco := b.Build()
+			falco.Spec.HealthCheck = tt.healthCheck
+			falco.Status.Version = tt.version
+			falco.Status.Upgrade = tt.upgrade
+			falco.Status.Conditions = tt.conditions
+			falco.Status.Nodes = tt.nodes
+
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append([]client.Object{falco}, tt.objs...)...).Build()
+			recorder := events.NewFakeRecorder(10)
+			r := NewReconciler(cl, scheme, recorder, false)
+
+			requeue, err := r.reconcileUpgrade(context.Background(), falco, tt.target, now)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantRequeue, requeue)
+			assert.Equal(t, tt.wantVersion, falco.Status.Version)
+			if tt.wantNoUpgrade {
+				assert.Nil(t, falco.Status.Upgrade)
+				assert.Nil(t, falco.Spec.UpdateStrategy)
+				require.Empty(t, recorder.Events)
+				return
+			}
+
+			upgrade := falco.Status.Upgrade
+			require.NotNil(t, upgrade)
+			assert.Equal(t, tt.wantPhase, upgrade.Phase)
+			assert.Equal(t, tt.wantCanary, upgrade.CanaryNodes)
+			assert.Contains(t, upgrade.Message, tt.wantMessage)
+			if tt.wantPinned != "" {
+				require.NotNil(t, falco.Spec.Version)
+				assert.Equal(t, tt.wantPinned, *falco.Spec.Version)
+			}
+			if tt.wantHold {
+				require.NotNil(t, falco.Spec.UpdateStrategy)
+				assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, falco.Spec.UpdateStrategy.Type)
+			} else {
+				assert.Nil(t, falco.Spec.UpdateStrategy)
+			}
+
+			require.Len(t, recorder.Events, len(tt.wantEvents))
+			for _, reason := range tt.wantEvents {
+				assert.Contains(t, <-recorder.Events, reason)
+			}
+		})
+	}
+}
+
+func TestReconcileUpgradePlanMode(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).
+		WithType(resources.ResourceTypeDaemonSet).WithVersion("0.41.0").
+		WithUpgradePolicy(instancev1alpha1.UpgradePolicy{Canary: instancev1alpha1.CanarySpec{Nodes: new(int32(1))}}).
+		Build()
+	falco.Status.Version = "0.40.0"
+	cl := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(falco, newUpgradePod("falco-a", "node-1", "0.40.0", true)).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := NewReconciler(cl, scheme, recorder, false)
+	ctx := instance.PlanIntoContext(context.Background(), &instance.Plan{})
+
+	requeue, err := r.reconcileUpgrade(ctx, falco, "0.41.0", metav1.Now())
+	require.NoError(t, err)
+
+	// The upgrade is not started, but the workloads are planned as its canary phase would pin them.
+	assert.Zero(t, requeue)
+	assert.Nil(t, falco.Status.Upgrade)
+	assert.Equal(t, "0.40.0", falco.Status.Version)
+	require.NotNil(t, falco.Spec.UpdateStrategy)
+	assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, falco.Spec.UpdateStrategy.Type)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestNonCanaryRestartDuringCanary(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme, artifactv1alpha1.AddToScheme)
+	signaledAt := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).
+		WithType(resources.ResourceTypeDaemonSet).WithVersion("0.41.0").
+		WithUpgradePolicy(instancev1alpha1.UpgradePolicy{Canary: instancev1alpha1.CanarySpec{Nodes: new(int32(1))}}).
+		Build()
+	falco.Status.Version = "0.40.0"
+	falco.Status.Upgrade = &instancev1alpha1.UpgradeStatus{
+		Phase: instancev1alpha1.UpgradePhaseCanary, FromVersion: "0.40.0", ToVersion: "0.41.0", CanaryNodes: []string{"node-1"},
+		StartTime: signaledAt, LastTransitionTime: signaledAt,
+	}
+
+	// The canary pod is replaced, and an artifact asks for a restart of the pod of another node.
+	canary := newUpgradePod("falco-a", "node-1", "0.41.0", true)
+	other := newUpgradePod("falco-b", "node-2", "0.40.0", true)
+	other.CreationTimestamp = metav1.NewTime(signaledAt.Add(-time.Hour))
+	engine := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: testutil.TestNamespace}}
+	nodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, "engine", "node-2"),
+			Namespace: testutil.TestNamespace,
+			Labels:    controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindConfig, "engine", "node-2"),
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: "node-2"},
+		Status: artifactv1alpha1.ArtifactNodeStatus{Conditions: []metav1.Condition{{
+			Type: commonv1alpha1.ConditionRestartRequired.String(), Status: metav1.ConditionTrue,
+			Reason: "RestartRequired", LastTransitionTime: signaledAt,
+		}}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco, canary, other, engine, nodeObject).Build()
+	r := NewReconciler(cl, scheme, events.NewFakeRecorder(10), false)
+
+	_, err := r.reconcileUpgrade(context.Background(), falco, "0.41.0", metav1.Now())
+	require.NoError(t, err)
+	require.Equal(t, instancev1alpha1.UpgradePhaseCanary, falco.Status.Upgrade.Phase)
+
+	// The restart is postponed until the rollout.
+	require.NoError(t, r.restartPods(context.Background(), falco))
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(other), &corev1.Pod{}))
+
+	// A pod of another node deleted meanwhile is recreated on the previous version.
+	applyConfig, err := generateApplyConfiguration(falco, resources.ResourceTypeDaemonSet, false)
+	require.NoError(t, err)
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(applyConfig.Object, ds))
+	assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+	for _, container := range ds.Spec.Template.Spec.Containers {
+		if container.Name == testContainerName {
+			assert.Equal(t, image.BuildFalcoImageStringFromVersion("0.40.0"), container.Image)
+		}
+	}
+}
+
+func TestReplaceCanaryPods(t *testing.T) {
+	scheme := testutil.Scheme(t, instancev1alpha1.AddToScheme)
+
+	tests := []struct {
+		name        string
+		phase       instancev1alpha1.UpgradePhase
+		wantDeleted []string
+		wantKept    []string
+	}{
+		{
+			name:        "replaces the outdated pods of the canary nodes",
+			phase:       instancev1alpha1.UpgradePhaseCanary,
+			wantDeleted: []string{"falco-a"},
+			wantKept:    []string{"falco-b", "falco-c"},
+		},
+		{
+			name:     "keeps every pod outside of the canary phase",
+			phase:    instancev1alpha1.UpgradePhaseSoaking,
+			wantKept: []string{"falco-a", "falco-b", "falco-c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := builders.NewFalco().WithName(defaultName).WithNamespace(testutil.TestNamespace).Build()
+			falco.Status.Upgrade = &instancev1alpha1.UpgradeStatus{
+				Phase: tt.phase, FromVersion: "0.40.0", ToVersion: "0.41.0", CanaryNodes: []string{"node-1", "node-2"},
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falco,
+				newUpgradePod("falco-a", "node-1", "0.40.0", true),
+				newUpgradePod("falco-b", "node-2", "0.41.0", true),
+				newUpgradePod("falco-c", "node-3", "0.40.0", true),
+			).Build()
+			recorder := events.NewFakeRecorder(10)
+			r := NewReconciler(cl, scheme, recorder, false)
+
+			require.NoError(t, r.replaceCanaryPods(context.Background(), falco))
+
+			for _, name := range tt.wantDeleted {
+				err := cl.Get(context.Background(), client.ObjectKey{Namespace: testutil.TestNamespace, Name: name}, &corev1.Pod{})
+				assert.Error(t, err, "pod %s should be deleted", name)
+			}
+			for _, name := range tt.wantKept {
+				err := cl.Get(context.Background(), client.ObjectKey{Namespace: testutil.TestNamespace, Name: name}, &corev1.Pod{})
+				assert.NoError(t, err, "pod %s should be kept", name)
+			}
+			require.Len(t, recorder.Events, len(tt.wantDeleted))
+			for _, name := range tt.wantDeleted {
+				event := <-recorder.Events
+				assert.True(t, strings.Contains(event, instance.ReasonCanaryPodReplaced) && strings.Contains(event, name), event)
+			}
+		})
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 // ControllerName is the name of the ConfigMap in-use finalizer controller.
@@ -103,7 +104,7 @@ func (r *ConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		).
 		Named(ControllerName).
 		WithLogConstructor(controllerhelper.LogConstructorFor(mgr.GetLogger(), mgr.GetScheme(), ControllerName, &corev1.ConfigMap{})).
-		Complete(r)
+		Complete(tracing.Reconciler("ConfigMap", r))
 }
 
 // isReferenced returns true when at least one Rulesfile or Config references the given ConfigMap.
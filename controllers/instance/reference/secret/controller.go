@@ -32,6 +32,7 @@ import (
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/index"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 // ControllerName is the name of the Secret controller. It is also used as the field manager name for finalizer updates.
@@ -99,7 +100,7 @@ func (r *SecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		).
 		Named(ControllerName).
 		WithLogConstructor(controllerhelper.LogConstructorFor(mgr.GetLogger(), mgr.GetScheme(), ControllerName, &corev1.Secret{})).
-		Complete(r)
+		Complete(tracing.Reconciler("Secret", r))
 }
 
 // isReferenced returns true when at least one Rulesfile or Plugin references the given Secret.
@@ -24,10 +24,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -46,20 +52,33 @@ type ConditionExpect struct {
 	Reason string
 }
 
-// Scheme creates a runtime.Scheme with common K8s types (core, apps, rbac) and any
-// additional types registered via the provided adders.
+// Scheme creates a runtime.Scheme with common K8s types (core, apps, rbac, networking, policy, autoscaling)
+// and any additional types registered via the provided adders.
 func Scheme(t *testing.T, adders ...func(*runtime.Scheme) error) *runtime.Scheme {
 	t.Helper()
 	s := runtime.NewScheme()
 	require.NoError(t, corev1.AddToScheme(s))
 	require.NoError(t, appsv1.AddToScheme(s))
 	require.NoError(t, rbacv1.AddToScheme(s))
+	require.NoError(t, networkingv1.AddToScheme(s))
+	require.NoError(t, policyv1.AddToScheme(s))
+	require.NoError(t, autoscalingv2.AddToScheme(s))
 	for _, add := range adders {
 		require.NoError(t, add(s))
 	}
 	return s
 }
 
+// RESTMapper creates a RESTMapper knowing the types of the scheme and the given namespaced kinds,
+// e.g. the ones of CRDs installed in the cluster that are not in the scheme.
+func RESTMapper(s *runtime.Scheme, namespacedKinds ...schema.GroupVersionKind) apimeta.RESTMapper {
+	extra := apimeta.NewDefaultRESTMapper(nil)
+	for _, gvk := range namespacedKinds {
+		extra.Add(gvk, apimeta.RESTScopeNamespace)
+	}
+	return apimeta.MultiRESTMapper{testrestmapper.TestOnlyStaticRESTMapper(s), extra}
+}
+
 // Request creates a ctrl.Request for the given resource name in TestNamespace.
 func Request(name string) ctrl.Request {
 	return ctrl.Request{
@@ -70,6 +70,24 @@ const (
 	ReasonProgrammed = "Programmed"
 	// ReasonProgramFailed indicates the artifact failed to program.
 	ReasonProgramFailed = "ProgramFailed"
+	// ReasonRestartRequired indicates a change was written that Falco cannot hot-reload.
+	ReasonRestartRequired = "RestartRequired"
+	// ReasonSuspended indicates the artifact is suspended and its files are neither written nor removed.
+	ReasonSuspended = "Suspended"
+	// ReasonResumed indicates the artifact is no longer suspended.
+	ReasonResumed = "Resumed"
+	// ReasonRolledOut indicates the current generation was programmed on every node.
+	ReasonRolledOut = "RolledOut"
+	// ReasonRolloutInProgress indicates the current generation is being rolled out in batches.
+	ReasonRolloutInProgress = "RolloutInProgress"
+	// ReasonRolloutHalted indicates a node failed to program the current generation and the rollout stopped.
+	ReasonRolloutHalted = "RolloutHalted"
+	// ReasonRolledBack indicates the sources of a previous revision are installed.
+	ReasonRolledBack = "RolledBack"
+	// ReasonRevisionNotFound indicates the revision selected by rollbackTo does not exist.
+	ReasonRevisionNotFound = "RevisionNotFound"
+	// ReasonRulesConflict indicates rules files replace a definition of an earlier rules file.
+	ReasonRulesConflict = "RulesConflict"
 )
 
 // Condition messages.
@@ -108,6 +126,10 @@ const (
 	MessageConfigMapArtifactRemoved = "ConfigMap artifact removed from filesystem"
 	// MessageProgrammed is the message when the artifact is programmed successfully.
 	MessageProgrammed = "All artifacts sources were programmed successfully"
+	// MessageSuspended is the message when the artifact is suspended.
+	MessageSuspended = "Artifact is suspended, its files are neither written nor removed"
+	// MessageResumed is the message when the artifact is no longer suspended.
+	MessageResumed = "Artifact resumed"
 	// MessageReferencesResolved is the message when all references are resolved successfully.
 	MessageReferencesResolved = "All references were resolved successfully"
 )
@@ -130,6 +152,20 @@ const (
 	MessageFormatReferenceResolutionFailed = "Failed to resolve Reference: %s"
 	// MessageFormatReferenceResolved is the format for Reference resolved message.
 	MessageFormatReferenceResolved = "Reference %q resolved successfully"
+	// MessageFormatRestartRequired is the format for the restart required message.
+	MessageFormatRestartRequired = "Falco on node %s must be restarted to apply: %s"
+	// MessageFormatRolledOut is the format for the rolled out message.
+	MessageFormatRolledOut = "Generation %d programmed on all %d nodes"
+	// MessageFormatRolloutInProgress is the format for the rollout in progress message.
+	MessageFormatRolloutInProgress = "Generation %d programmed on %d of %d nodes"
+	// MessageFormatRolloutHalted is the format for the rollout halted message.
+	MessageFormatRolloutHalted = "Rollout of generation %d halted, programming failed on nodes: %s"
+	// MessageFormatRolledBack is the format for the rolled back message.
+	MessageFormatRolledBack = "Installing revision %d instead of the current sources"
+	// MessageFormatRevisionNotFound is the format for the revision not found message.
+	MessageFormatRevisionNotFound = "Revision %d not found"
+	// MessageFormatRulesConflict is the format for the rules conflict message.
+	MessageFormatRulesConflict = "Rules files replace earlier definitions: %s"
 	// MessageFormatInlinePluginConfigStoreFailed is the format for inline plugin config store failure message.
 	MessageFormatInlinePluginConfigStoreFailed = "Failed to store inline plugin config: %v"
 )
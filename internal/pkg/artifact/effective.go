@@ -0,0 +1,168 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/priority"
+)
+
+const (
+	// PluginsConfigName is the name under which the configuration of the Plugin artifacts is stored.
+	PluginsConfigName = "plugins-config"
+	// BaseConfigSource identifies the base falco.yaml in the provenance of an effective configuration.
+	BaseConfigSource = "falco.yaml"
+)
+
+// Source is a file loaded by Falco from one of the directories written by the artifact operator.
+type Source struct {
+	// Name identifies the source in the provenance of the effective configuration and in the rules index.
+	Name string
+	// Path is the on-disk path of the file. Falco loads the files of a directory in the lexical order of their names.
+	Path string
+	// Data is the content of the file.
+	Data []byte
+}
+
+// EffectiveConfig is the configuration Falco runs with once its configuration files are merged.
+type EffectiveConfig struct {
+	// Data is the merged configuration, as YAML.
+	Data []byte
+	// Hash is the SHA-256 hex digest of Data.
+	Hash string
+	// Provenance maps each top-level key to the sources that set it, in load order. A key has several
+	// sources only when it holds a sequence that later sources appended to.
+	Provenance map[string][]string
+}
+
+// Sources returns the files of the given type stored by the manager, named after their artifact. Only configuration
+// and rules files are loaded by Falco from a directory, other types have no sources.
+func (am *Manager) Sources(artifactType Type) []Source {
+	var dir string
+	switch artifactType {
+	case TypeConfig:
+		dir = am.configDir
+	case TypeRulesfile:
+		dir = am.rulesfileDir
+	default:
+		return nil
+	}
+
+	var sources []Source
+	for name, files := range am.files {
+		for _, file := range files {
+			if filepath.Dir(file.Path) != filepath.Clean(dir) {
+				continue
+			}
+			data, err := am.fs.ReadFile(file.Path)
+			if err != nil {
+				continue
+			}
+			sources = append(sources, Source{Name: name, Path: file.Path, Data: data})
+		}
+	}
+	return sources
+}
+
+// PluginsConfigSource returns the configuration file generated for the Plugin artifacts, which is written by the
+// manager of the plugin controller. It returns nil when the file is absent.
+func (am *Manager) PluginsConfigSource() (*Source, error) {
+	path := am.Path(PluginsConfigName, priority.MaxPriority, MediumInline, TypeConfig)
+	exists, err := am.fs.Exists(path)
+	if err != nil || !exists {
+		return nil, err
+	}
+	data, err := am.fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Source{Name: filepath.Base(path), Path: path, Data: data}, nil
+}
+
+// MergeEffectiveConfig merges sources on top of base the way Falco loads its configuration files with the
+// default append strategy: sources are loaded in the lexical order of their file names, and each of their
+// top-level keys replaces the current value, except sequences that are appended to an existing sequence.
+func MergeEffectiveConfig(base []byte, sources []Source) (*EffectiveConfig, error) {
+	merged, err := parseConfigDocument(base)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", BaseConfigSource, err)
+	}
+	provenance := make(map[string][]string, len(merged))
+	for key := range merged {
+		provenance[key] = []string{BaseConfigSource}
+	}
+
+	sources = slices.Clone(sources)
+	slices.SortStableFunc(sources, func(a, b Source) int {
+		return cmp.Compare(filepath.Base(a.Path), filepath.Base(b.Path))
+	})
+	for _, source := range sources {
+		doc, err := parseConfigDocument(source.Data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", source.Name, err)
+		}
+		for key, value := range doc {
+			current, currentIsList := merged[key].([]any)
+			if values, ok := value.([]any); ok && currentIsList {
+				merged[key] = append(current, values...)
+				provenance[key] = append(provenance[key], source.Name)
+				continue
+			}
+			merged[key] = value
+			provenance[key] = []string{source.Name}
+		}
+	}
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling effective configuration: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return &EffectiveConfig{Data: data, Hash: hex.EncodeToString(sum[:]), Provenance: provenance}, nil
+}
+
+// SourceKeys partitions the top-level keys set by the named source into those in effect and those replaced
+// by a source loaded after it. Both lists are sorted.
+func (e *EffectiveConfig) SourceKeys(name string, sources []Source) (applied, overridden []string) {
+	for _, source := range sources {
+		if source.Name != name {
+			continue
+		}
+		doc, err := parseConfigDocument(source.Data)
+		if err != nil {
+			continue
+		}
+		for key := range doc {
+			if slices.Contains(e.Provenance[key], name) {
+				applied = append(applied, key)
+			} else {
+				overridden = append(overridden, key)
+			}
+		}
+	}
+	slices.Sort(applied)
+	slices.Sort(overridden)
+	return slices.Compact(applied), slices.Compact(overridden)
+}
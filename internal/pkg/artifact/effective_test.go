@@ -0,0 +1,101 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/filesystem"
+	"github.com/falcosecurity/falco-operator/internal/pkg/priority"
+)
+
+func TestMergeEffectiveConfig(t *testing.T) {
+	base := []byte("engine:\n  kind: kmod\n  kmod:\n    buf_size_preset: 4\nrules_files:\n- /etc/falco/rules.d\nwatch_config_files: true\n")
+	sources := []Source{
+		{Name: "Config/late", Path: "/etc/falco/config.d/60-03-late-inline.yaml", Data: []byte("engine:\n  kind: ebpf\n")},
+		{Name: "Config/early", Path: "/etc/falco/config.d/10-03-early-inline.yaml",
+			Data: []byte("engine:\n  kind: modern_ebpf\nrules_files:\n- /custom\nwatch_config_files: false\n")},
+		{Name: "Config/early", Path: "/etc/falco/config.d/10-02-early-configmap.yaml", Data: []byte("http_output:\n  enabled: true\n")},
+	}
+
+	got, err := MergeEffectiveConfig(base, sources)
+	require.NoError(t, err)
+
+	assert.Equal(t, "engine:\n  kind: ebpf\nhttp_output:\n  enabled: true\nrules_files:\n- /etc/falco/rules.d\n- /custom\n"+
+		"watch_config_files: false\n", string(got.Data), "top-level keys are replaced as a whole, sequences appended")
+	assert.Equal(t, map[string][]string{
+		"engine":             {"Config/late"},
+		"http_output":        {"Config/early"},
+		"rules_files":        {BaseConfigSource, "Config/early"},
+		"watch_config_files": {"Config/early"},
+	}, got.Provenance)
+	assert.Len(t, got.Hash, 64)
+
+	applied, overridden := got.SourceKeys("Config/early", sources)
+	assert.Equal(t, []string{"http_output", "rules_files", "watch_config_files"}, applied)
+	assert.Equal(t, []string{"engine"}, overridden)
+
+	again, err := MergeEffectiveConfig(base, []Source{sources[2], sources[0], sources[1]})
+	require.NoError(t, err)
+	assert.Equal(t, got.Hash, again.Hash, "the result must not depend on the order of the sources")
+}
+
+func TestMergeEffectiveConfig_Errors(t *testing.T) {
+	_, err := MergeEffectiveConfig([]byte("- not a map"), nil)
+	require.ErrorContains(t, err, BaseConfigSource)
+
+	_, err = MergeEffectiveConfig(nil, []Source{{Name: "Config/broken", Path: "10-03-broken-inline.yaml", Data: []byte("- not a map")}})
+	require.ErrorContains(t, err, "Config/broken")
+}
+
+func TestSources(t *testing.T) {
+	fs := filesystem.NewMockFileSystem()
+	am := NewManagerWithOptions(nil, "default", WithFS(fs))
+	inline := "engine:\n  kind: kmod\n"
+	_, err := am.StoreFromInLineYaml(context.Background(), "engine", 10, &inline, TypeConfig)
+	require.NoError(t, err)
+	rules := "- rule: test\n"
+	_, err = am.StoreFromInLineYaml(context.Background(), "rules", 10, &rules, TypeRulesfile)
+	require.NoError(t, err)
+
+	sources := am.Sources(TypeConfig)
+	require.Len(t, sources, 1, "only configuration files are sources of the effective configuration")
+	assert.Equal(t, "engine", sources[0].Name)
+	assert.Equal(t, am.Path("engine", 10, MediumInline, TypeConfig), sources[0].Path)
+	assert.Equal(t, inline, string(sources[0].Data))
+
+	rulesSources := am.Sources(TypeRulesfile)
+	require.Len(t, rulesSources, 1)
+	assert.Equal(t, "rules", rulesSources[0].Name)
+	assert.Empty(t, am.Sources(TypePlugin), "plugins are not loaded from a directory")
+
+	plugins, err := am.PluginsConfigSource()
+	require.NoError(t, err)
+	assert.Nil(t, plugins)
+
+	path := am.Path(PluginsConfigName, priority.MaxPriority, MediumInline, TypeConfig)
+	fs.Files[path] = []byte("load_plugins: []\n")
+	plugins, err = am.PluginsConfigSource()
+	require.NoError(t, err)
+	require.NotNil(t, plugins)
+	assert.Equal(t, "99-03-plugins-config-inline.yaml", plugins.Name)
+	assert.Equal(t, "load_plugins: []\n", string(plugins.Data))
+}
@@ -18,8 +18,12 @@ package artifact
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 )
 
 // RecordWarning records a Warning event on obj with the given reason and formatted message.
@@ -66,3 +70,16 @@ func RecordStoreEvent(r events.EventRecorder, obj runtime.Object, action StoreAc
 	}
 	RecordNormal(r, obj, reason, message)
 }
+
+// RecordSuspended sets the Suspended condition of obj while it is suspended and removes it otherwise.
+// A Normal event is recorded when obj is suspended or resumed.
+func RecordSuspended(r events.EventRecorder, obj client.Object, conditions *[]metav1.Condition, suspended bool) {
+	if !common.SetSuspendedCondition(conditions, suspended, ReasonSuspended, MessageSuspended, obj.GetGeneration()) {
+		return
+	}
+	if suspended {
+		RecordNormal(r, obj, ReasonSuspended, MessageSuspended)
+	} else {
+		RecordNormal(r, obj, ReasonResumed, MessageResumed)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"io/fs"
 	"path/filepath"
 
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +35,7 @@ import (
 	"github.com/falcosecurity/falco-operator/internal/pkg/mounts"
 	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
 	"github.com/falcosecurity/falco-operator/internal/pkg/priority"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 // Type represents different types of artifacts.
@@ -169,8 +171,9 @@ func (am *Manager) Path(name string, artifactPriority int32, medium Medium, arti
 }
 
 // StoreFromInLineYaml stores an artifact from an inline YAML to the local filesystem.
-func (am *Manager) StoreFromInLineYaml(ctx context.Context, name string, artifactPriority int32, data *string, artifactType Type) (StoreAction, error) {
+func (am *Manager) StoreFromInLineYaml(ctx context.Context, name string, artifactPriority int32, data *string, artifactType Type) (action StoreAction, err error) {
 	logger := log.FromContext(ctx)
+	defer func() { recordStoreAction(artifactType, MediumInline, action) }()
 
 	// If the data is nil, we remove the artifact from the manager and from filesystem.
 	// It means that the instance has been updated and the artifact has been removed from the spec.
@@ -189,8 +192,10 @@ func (am *Manager) StoreFromInLineYaml(ctx context.Context, name string, artifac
 
 	newFile := File{
 		Path:     am.Path(name, artifactPriority, MediumInline, artifactType),
+		Type:     artifactType,
 		Medium:   MediumInline,
 		Priority: artifactPriority,
+		Digest:   contentDigest([]byte(*data)),
 	}
 
 	// wasUpdate tracks whether we replaced an existing file (vs writing a brand-new one).
@@ -247,7 +252,7 @@ func (am *Manager) StoreFromInLineYaml(ctx context.Context, name string, artifac
 	}
 
 	// Write the raw YAML to the filesystem.
-	if err := am.fs.WriteFile(newFile.Path, []byte(*data), 0o600); err != nil {
+	if err := am.writeFile(ctx, newFile.Path, []byte(*data)); err != nil {
 		logger.Error(err, "unable to write file", "file", newFile.Path)
 		return StoreActionNone, err
 	}
@@ -265,8 +270,9 @@ func (am *Manager) StoreFromInLineYaml(ctx context.Context, name string, artifac
 }
 
 // StoreFromOCI stores an artifact from an OCI registry to the local filesystem.
-func (am *Manager) StoreFromOCI(ctx context.Context, name string, artifactPriority int32, artifactType Type, artifact *commonv1alpha1.OCIArtifact) (StoreAction, error) {
+func (am *Manager) StoreFromOCI(ctx context.Context, name string, artifactPriority int32, artifactType Type, artifact *commonv1alpha1.OCIArtifact) (action StoreAction, err error) {
 	logger := log.FromContext(ctx)
+	defer func() { recordStoreAction(artifactType, MediumOCI, action) }()
 
 	// If the artifact is nil, we remove the artifact from the manager and from filesystem.
 	// It means that the instance has been updated and the artifact has been removed from the spec.
@@ -283,21 +289,24 @@ func (am *Manager) StoreFromOCI(ctx context.Context, name string, artifactPriori
 		return StoreActionNone, nil
 	}
 
-	secretRef := authSecretRef(artifact)
+	secretRef := AuthSecretRef(artifact)
 
 	authSecret, err := am.fetchOCIAuthSecret(ctx, secretRef)
 	if err != nil {
 		logger.Error(err, "unable to fetch auth secret for the OCI artifact", "authSecretRef", secretRef)
+		newPullObserver(artifact).failed(pullFailureAuth)
 		return StoreActionNone, err
 	}
 	creds, err := credentials.FromSecret(ResolveRegistryHost(artifact), authSecret)
 	if err != nil {
 		logger.Error(err, "unable to derive credentials for the OCI artifact", "authSecretRef", secretRef)
+		newPullObserver(artifact).failed(pullFailureAuth)
 		return StoreActionNone, err
 	}
 
 	newFile := File{
 		Path:            am.Path(name, artifactPriority, MediumOCI, artifactType),
+		Type:            artifactType,
 		Medium:          MediumOCI,
 		Priority:        artifactPriority,
 		SourceSignature: computeOCISourceSignature(artifact, authSecret),
@@ -321,6 +330,7 @@ func (am *Manager) StoreFromOCI(ctx context.Context, name string, artifactPriori
 				logger.Error(err, "Failed to rename file", "oldFile", oldFile.Path, "newFile", newFile.Path)
 				return StoreActionNone, err
 			}
+			newFile.Digest = oldFile.Digest
 			am.removeArtifactFile(name, MediumOCI)
 			am.addArtifactFile(name, newFile)
 			return StoreActionPriorityChanged, nil
@@ -332,11 +342,12 @@ func (am *Manager) StoreFromOCI(ctx context.Context, name string, artifactPriori
 	ref := ResolveReference(artifact)
 	logger.Info("Pulling OCI artifact", "reference", ref)
 
-	payload, err := am.pullOCIFile(ctx, ref, artifactType, artifact, creds)
+	payload, digest, err := am.pullOCIFile(ctx, ref, artifactType, artifact, creds)
 	if err != nil {
 		logger.Error(err, "unable to pull artifact", "reference", ref)
 		return StoreActionNone, err
 	}
+	newFile.Digest = digest
 
 	if err := am.installOCIFile(ctx, newFile.Path, payload); err != nil {
 		logger.Error(err, "unable to install OCI artifact file", "file", newFile.Path)
@@ -360,8 +371,9 @@ func (am *Manager) StoreFromOCI(ctx context.Context, name string, artifactPriori
 
 // StoreFromConfigMap stores an artifact from a ConfigMap to the local filesystem.
 // The ConfigMap is fetched from the specified namespace (typically the same namespace as the Rulesfile CR).
-func (am *Manager) StoreFromConfigMap(ctx context.Context, name, namespace string, artifactPriority int32, configMapRef *commonv1alpha1.ConfigMapRef, artifactType Type) (StoreAction, error) {
+func (am *Manager) StoreFromConfigMap(ctx context.Context, name, namespace string, artifactPriority int32, configMapRef *commonv1alpha1.ConfigMapRef, artifactType Type) (action StoreAction, err error) {
 	logger := log.FromContext(ctx)
+	defer func() { recordStoreAction(artifactType, MediumConfigMap, action) }()
 
 	// If the configMapRef is nil, we remove the artifact from the manager and from filesystem.
 	// It means that the instance has been updated and the artifact has been removed from the spec.
@@ -380,6 +392,7 @@ func (am *Manager) StoreFromConfigMap(ctx context.Context, name, namespace strin
 
 	newFile := File{
 		Path:     am.Path(name, artifactPriority, MediumConfigMap, artifactType),
+		Type:     artifactType,
 		Medium:   MediumConfigMap,
 		Priority: artifactPriority,
 	}
@@ -450,6 +463,7 @@ func (am *Manager) StoreFromConfigMap(ctx context.Context, name, namespace strin
 			"configMap", configMapRef.Name, "expectedKey", dataKey)
 		return StoreActionNone, nil
 	}
+	newFile.Digest = contentDigest([]byte(data))
 
 	// wasUpdate tracks whether we replaced an existing file (vs writing a brand-new one).
 	wasUpdate := false
@@ -505,7 +519,7 @@ func (am *Manager) StoreFromConfigMap(ctx context.Context, name, namespace strin
 	}
 
 	// Write the data to the filesystem.
-	if err := am.fs.WriteFile(newFile.Path, []byte(data), 0o600); err != nil {
+	if err := am.writeFile(ctx, newFile.Path, []byte(data)); err != nil {
 		logger.Error(err, "unable to write file", "file", newFile.Path)
 		return StoreActionNone, err
 	}
@@ -522,6 +536,13 @@ func (am *Manager) StoreFromConfigMap(ctx context.Context, name, namespace strin
 	return StoreActionAdded, nil
 }
 
+// writeFile writes the content of an inline or ConfigMap artifact to path.
+func (am *Manager) writeFile(ctx context.Context, path string, data []byte) (err error) {
+	_, span := tracing.Start(ctx, "Install file", attribute.String("file.path", path))
+	defer func() { tracing.End(span, err) }()
+	return am.fs.WriteFile(path, data, 0o600)
+}
+
 func (am *Manager) removeArtifact(ctx context.Context, name string, medium Medium) error {
 	logger := log.FromContext(ctx)
 
@@ -567,11 +588,38 @@ func (am *Manager) RemoveAll(ctx context.Context, name string) error {
 	}
 
 	// Remove the instance from the manager.
+	for _, file := range files {
+		am.deleteArtifactInfo(name, file.Type, file.Medium)
+	}
 	delete(am.files, name)
 
 	return nil
 }
 
+// Content returns the content of the artifact currently stored for the given name and medium.
+// It returns nil when no artifact is tracked or the file is missing from disk.
+func (am *Manager) Content(name string, medium Medium) []byte {
+	file := am.getArtifactFile(name, medium)
+	if file == nil {
+		return nil
+	}
+	content, err := am.fs.ReadFile(file.Path)
+	if err != nil {
+		return nil
+	}
+	return content
+}
+
+// Digest returns the digest of the artifact currently stored for the given name and medium, the manifest
+// digest for MediumOCI. It returns an empty string when no artifact is tracked.
+func (am *Manager) Digest(name string, medium Medium) string {
+	file := am.getArtifactFile(name, medium)
+	if file == nil {
+		return ""
+	}
+	return file.Digest
+}
+
 func (am *Manager) getArtifactFile(name string, medium Medium) *File {
 	// Check if there are artifacts for the given instance name.
 	files, ok := am.files[name]
@@ -592,6 +640,8 @@ func (am *Manager) getArtifactFile(name string, medium Medium) *File {
 
 // addArtifactFile adds an artifact file to the manager.
 func (am *Manager) addArtifactFile(name string, file File) {
+	am.setArtifactInfo(name, file)
+
 	// Check if there are artifacts for the given instance name.
 	files, ok := am.files[name]
 	if !ok {
@@ -614,6 +664,7 @@ func (am *Manager) removeArtifactFile(name string, medium Medium) {
 	// Remove the artifact for the given medium.
 	for i, file := range files {
 		if file.Medium == medium {
+			am.deleteArtifactInfo(name, file.Type, medium)
 			files[i] = files[len(files)-1]
 			files = files[:len(files)-1]
 			if len(files) == 0 {
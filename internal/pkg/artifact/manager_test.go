@@ -19,6 +19,7 @@ package artifact
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"testing"
@@ -1126,6 +1127,61 @@ func TestGetArtifactFile(t *testing.T) {
 	}
 }
 
+func TestContent(t *testing.T) {
+	const path = "/etc/falco/config.d/50-03-test-artifact-inline.yaml"
+
+	tests := []struct {
+		name          string
+		existingFiles []File
+		fsFiles       map[string][]byte
+		readErr       error
+		want          []byte
+	}{
+		{
+			name: "returns nil when the artifact is not tracked",
+			want: nil,
+		},
+		{
+			name:          "returns the stored content",
+			existingFiles: []File{{Path: path, Medium: MediumInline, Priority: 50}},
+			fsFiles:       map[string][]byte{path: []byte("engine:\n  kind: modern_ebpf\n")},
+			want:          []byte("engine:\n  kind: modern_ebpf\n"),
+		},
+		{
+			name:          "returns nil when the file cannot be read",
+			existingFiles: []File{{Path: path, Medium: MediumInline, Priority: 50}},
+			readErr:       fmt.Errorf("read error"),
+			want:          nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := filesystem.NewMockFileSystem()
+			maps.Copy(mockFS.Files, tt.fsFiles)
+			mockFS.ReadErr = tt.readErr
+
+			manager := NewManagerWithOptions(nil, "", WithFS(mockFS))
+			if tt.existingFiles != nil {
+				manager.files["test-artifact"] = tt.existingFiles
+			}
+
+			assert.Equal(t, tt.want, manager.Content("test-artifact", MediumInline))
+		})
+	}
+}
+
+func TestDigest(t *testing.T) {
+	manager := NewManagerWithOptions(nil, "", WithFS(filesystem.NewMockFileSystem()))
+	assert.Empty(t, manager.Digest("test-artifact", MediumOCI))
+
+	manager.files["test-artifact"] = []File{
+		{Path: "/etc/falco/rules.d/50-01-test-artifact-oci.yaml", Medium: MediumOCI, Digest: "sha256:abc"},
+	}
+	assert.Equal(t, "sha256:abc", manager.Digest("test-artifact", MediumOCI))
+	assert.Empty(t, manager.Digest("test-artifact", MediumInline))
+}
+
 func TestAddArtifactFile(t *testing.T) {
 	const testNamespace = "test-namespace"
 
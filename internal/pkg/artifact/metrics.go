@@ -0,0 +1,94 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
+)
+
+// Reasons of the failed OCI artifact pulls, reported by metrics.ArtifactPullFailuresTotal.
+const (
+	pullFailureAuth          = "auth"
+	pullFailureRegistry      = "registry"
+	pullFailureInvalidResult = "invalid_result"
+	pullFailureTypeMismatch  = "type_mismatch"
+	pullFailureExtract       = "extract"
+)
+
+// pullObserver records the metrics of a pull of an OCI artifact.
+type pullObserver struct {
+	registry   string
+	repository string
+	start      time.Time
+}
+
+func newPullObserver(artifact *commonv1alpha1.OCIArtifact) *pullObserver {
+	return &pullObserver{
+		registry:   ResolveRegistryHost(artifact),
+		repository: artifact.Image.Repository,
+		start:      time.Now(),
+	}
+}
+
+// done observes the duration of the pull and the compressed bytes it read.
+func (o *pullObserver) done(pulledBytes int) {
+	metrics.ArtifactPullDurationSeconds.WithLabelValues(o.registry, o.repository).Observe(time.Since(o.start).Seconds())
+	metrics.ArtifactPullBytesTotal.WithLabelValues(o.registry, o.repository).Add(float64(pulledBytes))
+}
+
+// failed counts a failed pull with the given reason.
+func (o *pullObserver) failed(reason string) {
+	metrics.ArtifactPullFailuresTotal.WithLabelValues(o.registry, o.repository, reason).Inc()
+}
+
+// recordStoreAction counts a store operation. StoreActionNone is not counted since nothing was found or changed.
+func recordStoreAction(artifactType Type, medium Medium, action StoreAction) {
+	if action == StoreActionNone {
+		return
+	}
+	metrics.ArtifactStoreActionsTotal.WithLabelValues(string(artifactType), string(medium), string(action)).Inc()
+}
+
+// setArtifactInfo reports the file installed for the named artifact.
+func (am *Manager) setArtifactInfo(name string, file File) {
+	am.deleteArtifactInfo(name, file.Type, file.Medium)
+	metrics.ArtifactInfo.WithLabelValues(string(file.Type), am.namespace, name, string(file.Medium), file.Digest).Set(1)
+}
+
+// deleteArtifactInfo stops reporting the file of the named artifact of the given kind with the given medium.
+// Artifacts of different kinds may share a name, so the kind is part of the key.
+func (am *Manager) deleteArtifactInfo(name string, artifactType Type, medium Medium) {
+	metrics.ArtifactInfo.DeletePartialMatch(prometheus.Labels{
+		"kind":      string(artifactType),
+		"namespace": am.namespace,
+		"name":      name,
+		"medium":    string(medium),
+	})
+}
+
+// contentDigest returns the sha256 digest of the given content, in the OCI digest format.
+func contentDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/filesystem"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
+)
+
+func TestStoreMetrics(t *testing.T) {
+	const (
+		namespace = "metrics-namespace"
+		name      = "metrics-artifact"
+	)
+	ctx := context.Background()
+	manager := NewManagerWithOptions(fake.NewClientBuilder().WithScheme(createTestScheme(t)).Build(), namespace,
+		WithFS(filesystem.NewMockFileSystem()))
+	added := metrics.ArtifactStoreActionsTotal.WithLabelValues(string(TypeRulesfile), string(MediumInline), string(StoreActionAdded))
+	updated := metrics.ArtifactStoreActionsTotal.WithLabelValues(string(TypeRulesfile), string(MediumInline), string(StoreActionUpdated))
+	addedBefore, updatedBefore := testutil.ToFloat64(added), testutil.ToFloat64(updated)
+	infoLabels := prometheus.Labels{"kind": string(TypeRulesfile), "namespace": namespace, "name": name, "medium": string(MediumInline)}
+	info := func(artifactType Type, content string) prometheus.Gauge {
+		return metrics.ArtifactInfo.WithLabelValues(string(artifactType), namespace, name, string(MediumInline), contentDigest([]byte(content)))
+	}
+
+	first, second := "- rule: first", "- rule: second"
+	_, err := manager.StoreFromInLineYaml(ctx, name, 50, &first, TypeRulesfile)
+	require.NoError(t, err)
+	assert.Equal(t, addedBefore+1, testutil.ToFloat64(added))
+	assert.Equal(t, float64(1), testutil.ToFloat64(info(TypeRulesfile, first)))
+
+	_, err = manager.StoreFromInLineYaml(ctx, name, 50, &second, TypeRulesfile)
+	require.NoError(t, err)
+	assert.Equal(t, updatedBefore+1, testutil.ToFloat64(updated))
+	// The digest of the replaced content is no longer reported.
+	assert.False(t, metrics.ArtifactInfo.DeleteLabelValues(string(TypeRulesfile), namespace, name, string(MediumInline),
+		contentDigest([]byte(first))))
+	assert.Equal(t, float64(1), testutil.ToFloat64(info(TypeRulesfile, second)))
+
+	// An artifact of another kind with the same name is reported and removed on its own.
+	configManager := NewManagerWithOptions(fake.NewClientBuilder().WithScheme(createTestScheme(t)).Build(), namespace,
+		WithFS(filesystem.NewMockFileSystem()))
+	engine := "engine:\n  kind: modern_ebpf"
+	_, err = configManager.StoreFromInLineYaml(ctx, name, 50, &engine, TypeConfig)
+	require.NoError(t, err)
+	require.NoError(t, configManager.RemoveAll(ctx, name))
+	assert.Equal(t, float64(1), testutil.ToFloat64(info(TypeRulesfile, second)))
+
+	require.NoError(t, manager.RemoveAll(ctx, name))
+	assert.Zero(t, metrics.ArtifactInfo.DeletePartialMatch(infoLabels))
+	assert.Zero(t, metrics.ArtifactInfo.DeletePartialMatch(prometheus.Labels{"kind": string(TypeConfig), "name": name}))
+}
+
+func TestRecordStoreActionSkipsNone(t *testing.T) {
+	counter := metrics.ArtifactStoreActionsTotal.WithLabelValues(string(TypeConfig), string(MediumConfigMap), string(StoreActionNone))
+	recordStoreAction(TypeConfig, MediumConfigMap, StoreActionNone)
+	assert.Zero(t, testutil.ToFloat64(counter))
+}
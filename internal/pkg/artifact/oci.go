@@ -24,6 +24,7 @@ import (
 	"io/fs"
 	"runtime"
 
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	"oras.land/oras-go/v2/registry/remote/auth"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,6 +33,7 @@ import (
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 func (am *Manager) fetchOCIAuthSecret(ctx context.Context, ref *commonv1alpha1.SecretRef) (*corev1.Secret, error) {
@@ -70,24 +72,36 @@ func (am *Manager) getCurrentOCIFile(ctx context.Context, name string) (*File, e
 	return &current, nil
 }
 
-func (am *Manager) pullOCIFile(ctx context.Context, ref string, artifactType Type, artifact *commonv1alpha1.OCIArtifact, creds auth.CredentialFunc) (common.ExtractedFile, error) {
+// pullOCIFile pulls the artifact and returns its single file along with the digest of its manifest.
+func (am *Manager) pullOCIFile(ctx context.Context, ref string, artifactType Type, artifact *commonv1alpha1.OCIArtifact, creds auth.CredentialFunc) (file common.ExtractedFile, digest string, err error) {
+	ctx, span := tracing.Start(ctx, "Pull OCI artifact", attribute.String("oci.reference", ref))
+	defer func() { tracing.End(span, err) }()
+
+	observer := newPullObserver(artifact)
 	var compressed bytes.Buffer
 	res, err := am.ociPuller.Pull(ctx, ref, runtime.GOOS, runtime.GOARCH, creds, ResolveRegistryOptions(artifact), &compressed)
+	observer.done(compressed.Len())
 	if err != nil {
-		return common.ExtractedFile{}, err
+		observer.failed(pullFailureRegistry)
+		return common.ExtractedFile{}, "", err
 	}
 	if res == nil {
-		return common.ExtractedFile{}, fmt.Errorf("puller returned nil result for reference %q", ref)
+		observer.failed(pullFailureInvalidResult)
+		return common.ExtractedFile{}, "", fmt.Errorf("puller returned nil result for reference %q", ref)
 	}
 	if !isExpectedOCIArtifactType(artifactType, res.Type) {
-		return common.ExtractedFile{}, fmt.Errorf("pulled OCI artifact type %q does not match expected type %q", res.Type, artifactType)
+		observer.failed(pullFailureTypeMismatch)
+		return common.ExtractedFile{}, "", fmt.Errorf("pulled OCI artifact type %q does not match expected type %q", res.Type, artifactType)
 	}
 
-	file, err := common.ExtractSingleFileFromTarGz(ctx, &compressed, 0)
+	extractCtx, extractSpan := tracing.Start(ctx, "Extract archive", attribute.Int("archive.size", compressed.Len()))
+	file, err = common.ExtractSingleFileFromTarGz(extractCtx, &compressed, 0)
+	tracing.End(extractSpan, err)
 	if err != nil {
-		return common.ExtractedFile{}, err
+		observer.failed(pullFailureExtract)
+		return common.ExtractedFile{}, "", err
 	}
-	return file, nil
+	return file, res.Digest, nil
 }
 
 func isExpectedOCIArtifactType(expected Type, actual puller.ArtifactType) bool {
@@ -118,7 +132,10 @@ func (am *Manager) removeReplacedOCIFile(ctx context.Context, oldFile *File, new
 	return nil
 }
 
-func (am *Manager) installOCIFile(ctx context.Context, path string, file common.ExtractedFile) error {
+func (am *Manager) installOCIFile(ctx context.Context, path string, file common.ExtractedFile) (err error) {
+	_, span := tracing.Start(ctx, "Install file", attribute.String("file.path", path))
+	defer func() { tracing.End(span, err) }()
+
 	logger := log.FromContext(ctx)
 	tmpPath := path + ".tmp"
 
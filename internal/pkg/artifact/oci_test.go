@@ -23,8 +23,13 @@ import (
 	"maps"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +38,7 @@ import (
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/common"
 	"github.com/falcosecurity/falco-operator/internal/pkg/filesystem"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
 	"github.com/falcosecurity/falco-operator/internal/pkg/oci/puller"
 )
 
@@ -176,37 +182,51 @@ func TestPullOCIFile(t *testing.T) {
 		result      *puller.RegistryResult
 		layer       []byte
 		nilResult   bool
+		pullErr     error
 		wantErr     string
+		wantReason  string
 		wantContent string
+		wantDigest  string
 	}{
 		{
 			name:        "pulls and extracts single file",
-			result:      &puller.RegistryResult{Type: puller.Rulesfile},
+			result:      &puller.RegistryResult{Type: puller.Rulesfile, Digest: "sha256:abc"},
 			layer:       validLayer,
 			wantContent: "rules-content",
+			wantDigest:  "sha256:abc",
 		},
 		{
-			name:    "rejects mismatched artifact type",
-			result:  &puller.RegistryResult{Type: puller.Plugin},
-			layer:   validLayer,
-			wantErr: "does not match expected type",
+			name:       "reports registry errors",
+			pullErr:    fmt.Errorf("unauthorized"),
+			wantErr:    "unauthorized",
+			wantReason: pullFailureRegistry,
 		},
 		{
-			name:    "rejects empty artifact type",
-			result:  &puller.RegistryResult{},
-			layer:   validLayer,
-			wantErr: "does not match expected type",
+			name:       "rejects mismatched artifact type",
+			result:     &puller.RegistryResult{Type: puller.Plugin},
+			layer:      validLayer,
+			wantErr:    "does not match expected type",
+			wantReason: pullFailureTypeMismatch,
 		},
 		{
-			name:      "rejects nil puller result",
-			nilResult: true,
-			wantErr:   "nil result",
+			name:       "rejects empty artifact type",
+			result:     &puller.RegistryResult{},
+			layer:      validLayer,
+			wantErr:    "does not match expected type",
+			wantReason: pullFailureTypeMismatch,
 		},
 		{
-			name:    "rejects invalid gzip layer",
-			result:  &puller.RegistryResult{Type: puller.Rulesfile},
-			layer:   []byte("not-gzip"),
-			wantErr: "unexpected EOF",
+			name:       "rejects nil puller result",
+			nilResult:  true,
+			wantErr:    "nil result",
+			wantReason: pullFailureInvalidResult,
+		},
+		{
+			name:       "rejects invalid gzip layer",
+			result:     &puller.RegistryResult{Type: puller.Rulesfile},
+			layer:      []byte("not-gzip"),
+			wantErr:    "unexpected EOF",
+			wantReason: pullFailureExtract,
 		},
 	}
 
@@ -217,30 +237,82 @@ func TestPullOCIFile(t *testing.T) {
 				"test-namespace",
 				WithOCIPuller(&puller.MockOCIPuller{
 					Result:         tt.result,
+					PullErr:        tt.pullErr,
 					LayerContent:   tt.layer,
 					AllowNilResult: tt.nilResult,
 				}),
 			)
+			failures := metrics.ArtifactPullFailuresTotal.WithLabelValues(DefaultRegistry, "falco/rules", tt.wantReason)
+			failuresBefore := testutil.ToFloat64(failures)
+			bytesBefore := testutil.ToFloat64(metrics.ArtifactPullBytesTotal.WithLabelValues(DefaultRegistry, "falco/rules"))
 
-			file, err := manager.pullOCIFile(
+			file, digest, err := manager.pullOCIFile(
 				context.Background(),
-				"registry.example.test/falco/rules:latest",
+				"ghcr.io/falco/rules:latest",
 				TypeRulesfile,
-				&commonv1alpha1.OCIArtifact{},
+				&commonv1alpha1.OCIArtifact{Image: commonv1alpha1.ImageSpec{Repository: "falco/rules"}},
 				nil,
 			)
 			if tt.wantErr != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Equal(t, failuresBefore+1, testutil.ToFloat64(failures))
 				return
 			}
 			require.NoError(t, err)
+			assert.Equal(t, tt.wantDigest, digest)
+			assert.Equal(t, bytesBefore+float64(len(tt.layer)),
+				testutil.ToFloat64(metrics.ArtifactPullBytesTotal.WithLabelValues(DefaultRegistry, "falco/rules")))
 			assert.Equal(t, tt.wantContent, string(file.Content))
 			assert.Equal(t, fs.FileMode(0o644), file.Perm)
 		})
 	}
 }
 
+func TestPullOCIFile_Spans(t *testing.T) {
+	validLayer, err := puller.MakeTarGz("rules.yaml", []byte("rules-content"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		layer      []byte
+		wantStatus codes.Code
+	}{
+		{name: "success", layer: validLayer, wantStatus: codes.Unset},
+		{name: "extraction failure", layer: []byte("not-gzip"), wantStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			previous := otel.GetTracerProvider()
+			otel.SetTracerProvider(provider)
+			t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+			manager := NewManagerWithOptions(
+				fake.NewClientBuilder().WithScheme(createTestScheme(t)).Build(),
+				"test-namespace",
+				WithOCIPuller(&puller.MockOCIPuller{
+					Result:       &puller.RegistryResult{Type: puller.Rulesfile},
+					LayerContent: tt.layer,
+				}),
+			)
+			_, _, _ = manager.pullOCIFile(context.Background(), "ghcr.io/falco/rules:latest", TypeRulesfile,
+				&commonv1alpha1.OCIArtifact{Image: commonv1alpha1.ImageSpec{Repository: "falco/rules"}}, nil)
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 2)
+			extract, pull := spans[0], spans[1]
+			assert.Equal(t, "Extract archive", extract.Name)
+			assert.Equal(t, "Pull OCI artifact", pull.Name)
+			assert.Equal(t, pull.SpanContext.SpanID(), extract.Parent.SpanID())
+			assert.Equal(t, tt.wantStatus, extract.Status.Code)
+			assert.Equal(t, tt.wantStatus, pull.Status.Code)
+		})
+	}
+}
+
 func TestRemoveReplacedOCIFile(t *testing.T) {
 	tests := []struct {
 		name          string
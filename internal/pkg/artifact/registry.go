@@ -56,14 +56,15 @@ func ResolveRegistryHost(artifact *commonv1alpha1.OCIArtifact) string {
 }
 
 func authSecretRefName(artifact *commonv1alpha1.OCIArtifact) string {
-	ref := authSecretRef(artifact)
+	ref := AuthSecretRef(artifact)
 	if ref == nil {
 		return ""
 	}
 	return ref.Name
 }
 
-func authSecretRef(artifact *commonv1alpha1.OCIArtifact) *commonv1alpha1.SecretRef {
+// AuthSecretRef returns the reference to the Secret holding the registry credentials of an OCIArtifact, or nil.
+func AuthSecretRef(artifact *commonv1alpha1.OCIArtifact) *commonv1alpha1.SecretRef {
 	if artifact == nil || artifact.Registry == nil || artifact.Registry.Auth == nil {
 		return nil
 	}
@@ -72,7 +73,14 @@ func authSecretRef(artifact *commonv1alpha1.OCIArtifact) *commonv1alpha1.SecretR
 
 // ResolveRegistryOptions builds a RegistryOptions from the registry configuration of an OCIArtifact.
 // Returns nil when no transport configuration is present (use system defaults: HTTPS with system CAs).
+// An artifact installed from an OCI layout only sets the layout path.
 func ResolveRegistryOptions(artifact *commonv1alpha1.OCIArtifact) *puller.RegistryOptions {
+	if artifact != nil && artifact.OCILayout != nil {
+		return &puller.RegistryOptions{
+			LayoutPath: artifact.OCILayout.Path,
+		}
+	}
+
 	if artifact == nil || artifact.Registry == nil {
 		return nil
 	}
@@ -125,6 +125,7 @@ func TestResolveRegistryOptions(t *testing.T) {
 		wantNil       bool
 		wantPlainHTTP bool
 		wantInsecure  bool
+		wantLayout    string
 	}{
 		{
 			name:     "nil artifact returns nil",
@@ -170,6 +171,17 @@ func TestResolveRegistryOptions(t *testing.T) {
 			},
 			wantInsecure: true,
 		},
+		{
+			name: "OCI layout ignores transport options",
+			artifact: &commonv1alpha1.OCIArtifact{
+				Image: commonv1alpha1.ImageSpec{Repository: "test", Tag: "latest"},
+				Registry: &commonv1alpha1.RegistryConfig{
+					PlainHTTP: new(true),
+				},
+				OCILayout: &commonv1alpha1.OCILayoutSource{Path: "/bundles/falco.tar"},
+			},
+			wantLayout: "/bundles/falco.tar",
+		},
 	}
 
 	for _, tt := range tests {
@@ -184,6 +196,7 @@ func TestResolveRegistryOptions(t *testing.T) {
 			require.NotNil(t, opts)
 			assert.Equal(t, tt.wantPlainHTTP, opts.PlainHTTP)
 			assert.Equal(t, tt.wantInsecure, opts.InsecureSkipVerify)
+			assert.Equal(t, tt.wantLayout, opts.LayoutPath)
 		})
 	}
 }
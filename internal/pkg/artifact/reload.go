@@ -0,0 +1,123 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// restartRequiredKeys lists the dotted falco.yaml keys that Falco cannot apply through a hot reload:
+// the driver is opened once at start, so the engine kind and its ring buffer sizing are fixed.
+var restartRequiredKeys = []string{
+	"engine.kind",
+	"engine.kmod.buf_size_preset",
+	"engine.ebpf.buf_size_preset",
+	"engine.modern_ebpf.buf_size_preset",
+	"engine.modern_ebpf.cpus_for_each_buffer",
+}
+
+// PluginLibraryPathKey returns the key reported when the library of the named plugin changes.
+func PluginLibraryPathKey(pluginName string) string {
+	return fmt.Sprintf("plugins.%s.library_path", pluginName)
+}
+
+// RestartRequiredKeys compares two Falco configuration documents and returns the sorted keys that
+// changed and require a Falco restart to take effect. A nil document means the file is absent, so
+// adding or removing a file that sets a restart-requiring key is reported as a change as well.
+// Plugin library paths are compared per plugin name.
+func RestartRequiredKeys(oldData, newData []byte) ([]string, error) {
+	oldDoc, err := parseConfigDocument(oldData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing previous configuration: %w", err)
+	}
+	newDoc, err := parseConfigDocument(newData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing new configuration: %w", err)
+	}
+
+	var changed []string
+	for _, key := range restartRequiredKeys {
+		oldValue, _ := lookupKey(oldDoc, key)
+		newValue, _ := lookupKey(newDoc, key)
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changed = append(changed, key)
+		}
+	}
+
+	oldPaths := pluginLibraryPaths(oldDoc)
+	newPaths := pluginLibraryPaths(newDoc)
+	for name, newPath := range newPaths {
+		if oldPath, ok := oldPaths[name]; ok && oldPath != newPath {
+			changed = append(changed, PluginLibraryPathKey(name))
+		}
+	}
+
+	slices.Sort(changed)
+	return changed, nil
+}
+
+func parseConfigDocument(data []byte) (map[string]any, error) {
+	doc := map[string]any{}
+	if len(data) == 0 {
+		return doc, nil
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// lookupKey resolves a dotted key in a parsed YAML document.
+func lookupKey(doc map[string]any, key string) (any, bool) {
+	var current any = doc
+	for part := range strings.SplitSeq(key, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// pluginLibraryPaths returns the library_path of every entry of the plugins list, keyed by plugin name.
+func pluginLibraryPaths(doc map[string]any) map[string]string {
+	paths := map[string]string{}
+	plugins, ok := doc["plugins"].([]any)
+	if !ok {
+		return paths
+	}
+	for _, p := range plugins {
+		entry, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := entry["name"].(string)
+		libraryPath, _ := entry["library_path"].(string)
+		if name != "" {
+			paths[name] = libraryPath
+		}
+	}
+	return paths
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartRequiredKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldData string
+		newData string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "identical documents",
+			oldData: "engine:\n  kind: modern_ebpf\n",
+			newData: "engine:\n  kind: modern_ebpf\n",
+			want:    nil,
+		},
+		{
+			name:    "hot-reloadable change only",
+			oldData: "engine:\n  kind: modern_ebpf\npriority: debug\n",
+			newData: "engine:\n  kind: modern_ebpf\npriority: warning\n",
+			want:    nil,
+		},
+		{
+			name:    "engine kind change",
+			oldData: "engine:\n  kind: modern_ebpf\n",
+			newData: "engine:\n  kind: kmod\n",
+			want:    []string{"engine.kind"},
+		},
+		{
+			name:    "buffer sizing changes are sorted",
+			oldData: "engine:\n  modern_ebpf:\n    buf_size_preset: 4\n    cpus_for_each_buffer: 2\n",
+			newData: "engine:\n  modern_ebpf:\n    buf_size_preset: 6\n    cpus_for_each_buffer: 1\n",
+			want:    []string{"engine.modern_ebpf.buf_size_preset", "engine.modern_ebpf.cpus_for_each_buffer"},
+		},
+		{
+			name:    "new file setting a restart-requiring key",
+			oldData: "",
+			newData: "engine:\n  kind: ebpf\n",
+			want:    []string{"engine.kind"},
+		},
+		{
+			name:    "removed file setting a restart-requiring key",
+			oldData: "engine:\n  kind: ebpf\n",
+			newData: "",
+			want:    []string{"engine.kind"},
+		},
+		{
+			name:    "plugin library path change",
+			oldData: "plugins:\n- name: k8saudit\n  library_path: /usr/share/falco/plugins/a.so\n",
+			newData: "plugins:\n- name: k8saudit\n  library_path: /usr/share/falco/plugins/b.so\n",
+			want:    []string{"plugins.k8saudit.library_path"},
+		},
+		{
+			name:    "added plugin does not require a restart",
+			oldData: "plugins: []\n",
+			newData: "plugins:\n- name: k8saudit\n  library_path: /usr/share/falco/plugins/a.so\n",
+			want:    nil,
+		},
+		{
+			name:    "plugin init config change does not require a restart",
+			oldData: "plugins:\n- name: k8saudit\n  library_path: a.so\n  init_config:\n    maxEventSize: 1\n",
+			newData: "plugins:\n- name: k8saudit\n  library_path: a.so\n  init_config:\n    maxEventSize: 2\n",
+			want:    nil,
+		},
+		{
+			name:    "invalid previous document",
+			oldData: "engine: [",
+			newData: "engine:\n  kind: kmod\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid new document",
+			oldData: "engine:\n  kind: kmod\n",
+			newData: "engine: [",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RestartRequiredKeys([]byte(tt.oldData), []byte(tt.newData))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
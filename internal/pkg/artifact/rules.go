@@ -0,0 +1,152 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Kinds of the items defined by rules files.
+const (
+	RulesItemRule  = "rule"
+	RulesItemMacro = "macro"
+	RulesItemList  = "list"
+)
+
+// RulesIndex lists the rules files loaded by Falco in load order, with the items each defines and the
+// conflicts between them.
+type RulesIndex struct {
+	// Files are the rules files in the order Falco loads them.
+	Files []RulesIndexFile `json:"files"`
+	// Conflicts are the items defined again by a later file without override or append.
+	Conflicts []RulesConflict `json:"conflicts,omitempty"`
+}
+
+// RulesIndexFile describes a rules file and the names of the items it defines or modifies.
+type RulesIndexFile struct {
+	// Path is the on-disk path of the file.
+	Path string `json:"path"`
+	// Source identifies the artifact that installed the file.
+	Source string `json:"source"`
+	// Rules, Macros and Lists are the names of the items of the file.
+	Rules  []string `json:"rules,omitempty"`
+	Macros []string `json:"macros,omitempty"`
+	Lists  []string `json:"lists,omitempty"`
+	// Error reports why the file could not be indexed.
+	Error string `json:"error,omitempty"`
+}
+
+// RulesConflict is an item that a rules file defines again after an earlier one, which replaces the earlier
+// definition as a whole.
+type RulesConflict struct {
+	// Kind is the kind of the item: rule, macro or list.
+	Kind string `json:"kind"`
+	// Name is the name of the item.
+	Name string `json:"name"`
+	// DefinedBy is the source of the definition that is replaced.
+	DefinedBy string `json:"definedBy"`
+	// RedefinedBy is the source of the definition that replaces it.
+	RedefinedBy string `json:"redefinedBy"`
+}
+
+// String returns a human-readable description of the conflict.
+func (c RulesConflict) String() string {
+	return fmt.Sprintf("%s %q already defined by %s", c.Kind, c.Name, c.DefinedBy)
+}
+
+// IndexRules indexes sources in the order Falco loads them, the lexical order of their file names. An item
+// defined without override or append while an earlier definition exists is reported as a conflict, as the later
+// definition silently replaces the earlier one. An item that only sets enabled modifies the earlier definition
+// instead of replacing it.
+func IndexRules(sources []Source) *RulesIndex {
+	sources = slices.Clone(sources)
+	slices.SortStableFunc(sources, func(a, b Source) int {
+		return cmp.Compare(filepath.Base(a.Path), filepath.Base(b.Path))
+	})
+
+	index := &RulesIndex{Files: make([]RulesIndexFile, 0, len(sources))}
+	definedBy := map[string]string{}
+	for _, source := range sources {
+		file := RulesIndexFile{Path: source.Path, Source: source.Name}
+		var items []map[string]any
+		if err := yaml.Unmarshal(source.Data, &items); err != nil {
+			file.Error = err.Error()
+			index.Files = append(index.Files, file)
+			continue
+		}
+		for _, item := range items {
+			kind, name, ok := rulesItem(item)
+			if !ok {
+				continue
+			}
+			switch kind {
+			case RulesItemRule:
+				file.Rules = append(file.Rules, name)
+			case RulesItemMacro:
+				file.Macros = append(file.Macros, name)
+			case RulesItemList:
+				file.Lists = append(file.Lists, name)
+			}
+			if _, override := item["override"]; override || item["append"] == true || onlyEnabled(item) {
+				continue
+			}
+			key := kind + "/" + name
+			if previous, ok := definedBy[key]; ok {
+				index.Conflicts = append(index.Conflicts, RulesConflict{
+					Kind: kind, Name: name, DefinedBy: previous, RedefinedBy: source.Name,
+				})
+			}
+			definedBy[key] = source.Name
+		}
+		index.Files = append(index.Files, file)
+	}
+	return index
+}
+
+// ConflictsOf returns the conflicts in which the named source replaces an earlier definition.
+func (i *RulesIndex) ConflictsOf(name string) []RulesConflict {
+	var conflicts []RulesConflict
+	for _, conflict := range i.Conflicts {
+		if conflict.RedefinedBy == name {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts
+}
+
+// onlyEnabled reports whether item only sets the enabled flag of the item it names, which Falco applies to the
+// earlier definition, as in `- rule: X` followed by `enabled: false`.
+func onlyEnabled(item map[string]any) bool {
+	_, ok := item["enabled"]
+	return ok && len(item) == 2
+}
+
+// rulesItem returns the kind and name of a rules file item, and false for the items defining neither a rule,
+// a macro nor a list, such as required_engine_version.
+func rulesItem(item map[string]any) (kind, name string, ok bool) {
+	for _, kind := range []string{RulesItemRule, RulesItemMacro, RulesItemList} {
+		if name, ok := item[kind].(string); ok {
+			return kind, name, true
+		}
+	}
+	return "", "", false
+}
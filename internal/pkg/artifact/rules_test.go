@@ -0,0 +1,91 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexRules(t *testing.T) {
+	sources := []Source{
+		{Name: "Rulesfile/custom", Path: "/etc/falco/rules.d/60-03-custom-inline.yaml", Data: []byte(`
+- rule: Terminal shell in container
+  condition: spawned_process and shell_procs
+- macro: shell_procs
+  condition: proc.name in (bash)
+- rule: Read sensitive file untrusted
+  condition: evt.type = open
+  override:
+    condition: replace
+- list: shell_binaries
+  items: [zsh]
+  append: true
+- rule: Write below etc
+  enabled: false
+`)},
+		{Name: "Rulesfile/falco-rules", Path: "/etc/falco/rules.d/50-01-falco-rules-oci.yaml", Data: []byte(`
+- required_engine_version: 0.50.0
+- list: shell_binaries
+  items: [bash, sh]
+- macro: shell_procs
+  condition: proc.name in (shell_binaries)
+- rule: Terminal shell in container
+  condition: spawned_process and shell_procs
+- rule: Read sensitive file untrusted
+  condition: open_read
+- rule: Write below etc
+  condition: open_write
+`)},
+		{Name: "Rulesfile/broken", Path: "/etc/falco/rules.d/70-03-broken-inline.yaml", Data: []byte("rule: not a list\n")},
+	}
+
+	index := IndexRules(sources)
+
+	assert.Equal(t, []RulesIndexFile{
+		{
+			Path:   "/etc/falco/rules.d/50-01-falco-rules-oci.yaml",
+			Source: "Rulesfile/falco-rules",
+			Rules:  []string{"Terminal shell in container", "Read sensitive file untrusted", "Write below etc"},
+			Macros: []string{"shell_procs"},
+			Lists:  []string{"shell_binaries"},
+		},
+		{
+			Path:   "/etc/falco/rules.d/60-03-custom-inline.yaml",
+			Source: "Rulesfile/custom",
+			Rules:  []string{"Terminal shell in container", "Read sensitive file untrusted", "Write below etc"},
+			Macros: []string{"shell_procs"},
+			Lists:  []string{"shell_binaries"},
+		},
+		{
+			Path:   "/etc/falco/rules.d/70-03-broken-inline.yaml",
+			Source: "Rulesfile/broken",
+			Error:  index.Files[2].Error,
+		},
+	}, index.Files, "files are indexed in load order")
+	assert.NotEmpty(t, index.Files[2].Error)
+
+	want := []RulesConflict{
+		{Kind: RulesItemRule, Name: "Terminal shell in container", DefinedBy: "Rulesfile/falco-rules", RedefinedBy: "Rulesfile/custom"},
+		{Kind: RulesItemMacro, Name: "shell_procs", DefinedBy: "Rulesfile/falco-rules", RedefinedBy: "Rulesfile/custom"},
+	}
+	assert.Equal(t, want, index.Conflicts, "overrides, appends and enabled toggles are not conflicts")
+	assert.Equal(t, want, index.ConflictsOf("Rulesfile/custom"))
+	assert.Empty(t, index.ConflictsOf("Rulesfile/falco-rules"), "conflicts are reported on the later definition")
+	assert.Equal(t, `rule "Terminal shell in container" already defined by Rulesfile/falco-rules`, want[0].String())
+}
@@ -44,6 +44,10 @@ func computeOCISourceSignature(artifact *commonv1alpha1.OCIArtifact, authSecret
 	} else {
 		writeHashBool(h, opts.PlainHTTP)
 		writeHashBool(h, opts.InsecureSkipVerify)
+		// Only hashed when set, so that the signature of artifacts pulled from a registry is unchanged.
+		if opts.LayoutPath != "" {
+			writeHashString(h, opts.LayoutPath)
+		}
 	}
 
 	writeHashString(h, authSecretRefName(artifact))
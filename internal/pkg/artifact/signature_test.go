@@ -74,6 +74,19 @@ func TestComputeOCISourceSignatureUsesSecretDataOnly(t *testing.T) {
 	)
 }
 
+func TestComputeOCISourceSignatureUsesLayoutPath(t *testing.T) {
+	registry := &commonv1alpha1.OCIArtifact{
+		Image: commonv1alpha1.ImageSpec{Repository: "repo/rules", Tag: "v1"},
+	}
+	layout := registry.DeepCopy()
+	layout.OCILayout = &commonv1alpha1.OCILayoutSource{Path: "/bundles/a"}
+	otherLayout := registry.DeepCopy()
+	otherLayout.OCILayout = &commonv1alpha1.OCILayoutSource{Path: "/bundles/b"}
+
+	assert.NotEqual(t, computeOCISourceSignature(registry, nil), computeOCISourceSignature(layout, nil))
+	assert.NotEqual(t, computeOCISourceSignature(layout, nil), computeOCISourceSignature(otherLayout, nil))
+}
+
 func pullSecret(name, username, password string) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{Name: name},
@@ -50,7 +50,9 @@ const (
 // File represents a tracked file for any artifact type.
 type File struct {
 	Path            string // Full Path on filesystem
+	Type            Type   // Kind of artifact the file belongs to
 	Medium          Medium // How the artifact is stored/distributed
 	Priority        int32  // Priority when created
 	SourceSignature string // Resolved source identity (set for MediumOCI)
+	Digest          string // Digest of the installed content (manifest digest for MediumOCI)
 }
@@ -21,6 +21,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 )
 
@@ -112,6 +113,30 @@ func (b *FalcoBuilder) WithUpdateStrategy(s appsv1.DaemonSetUpdateStrategy) *Fal
 	return b
 }
 
+// WithNodePools sets the node pools.
+func (b *FalcoBuilder) WithNodePools(pools ...instancev1alpha1.NodePool) *FalcoBuilder {
+	b.falco.Spec.NodePools = pools
+	return b
+}
+
+// WithUpgradePolicy sets the upgrade policy.
+func (b *FalcoBuilder) WithUpgradePolicy(p instancev1alpha1.UpgradePolicy) *FalcoBuilder {
+	b.falco.Spec.UpgradePolicy = &p
+	return b
+}
+
+// WithDriftPolicy sets the drift policy.
+func (b *FalcoBuilder) WithDriftPolicy(p commonv1alpha1.DriftPolicy) *FalcoBuilder {
+	b.falco.Spec.DriftPolicy = &p
+	return b
+}
+
+// WithOverlays sets the overlays applied to the generated resources.
+func (b *FalcoBuilder) WithOverlays(overlays ...commonv1alpha1.Overlay) *FalcoBuilder {
+	b.falco.Spec.Overlays = overlays
+	return b
+}
+
 // Build returns the constructed Falco object.
 func (b *FalcoBuilder) Build() *instancev1alpha1.Falco {
 	return b.falco
@@ -24,6 +24,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 )
 
 func TestNewFalco_Empty(t *testing.T) {
@@ -114,6 +117,33 @@ func TestFalcoBuilder_WithUpdateStrategy(t *testing.T) {
 	assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, f.Spec.UpdateStrategy.Type)
 }
 
+func TestFalcoBuilder_WithNodePools(t *testing.T) {
+	f := NewFalco().WithNodePools(instancev1alpha1.NodePool{Name: "gpu"}, instancev1alpha1.NodePool{Name: "arm"}).Build()
+	require.Len(t, f.Spec.NodePools, 2)
+	assert.Equal(t, "gpu", f.Spec.NodePools[0].Name)
+	assert.Equal(t, "arm", f.Spec.NodePools[1].Name)
+}
+
+func TestFalcoBuilder_WithUpgradePolicy(t *testing.T) {
+	f := NewFalco().WithUpgradePolicy(instancev1alpha1.UpgradePolicy{
+		Canary: instancev1alpha1.CanarySpec{Nodes: new(int32(2))},
+	}).Build()
+	require.NotNil(t, f.Spec.UpgradePolicy)
+	assert.Equal(t, int32(2), *f.Spec.UpgradePolicy.Canary.Nodes)
+}
+
+func TestFalcoBuilder_WithDriftPolicy(t *testing.T) {
+	f := NewFalco().WithDriftPolicy(commonv1alpha1.DriftPolicyReport).Build()
+	require.NotNil(t, f.Spec.DriftPolicy)
+	assert.Equal(t, commonv1alpha1.DriftPolicyReport, *f.Spec.DriftPolicy)
+}
+
+func TestFalcoBuilder_WithOverlays(t *testing.T) {
+	overlay := commonv1alpha1.Overlay{Target: commonv1alpha1.OverlayTarget{Kind: "Service"}}
+	f := NewFalco().WithOverlays(overlay).Build()
+	assert.Equal(t, []commonv1alpha1.Overlay{overlay}, f.Spec.Overlays)
+}
+
 func TestFalcoBuilder_StrategyIndependence(t *testing.T) {
 	f := NewFalco().
 		WithStrategy(appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}).
@@ -17,6 +17,7 @@
 package common
 
 import (
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
@@ -57,3 +58,65 @@ func NewAvailableCondition(status metav1.ConditionStatus, reason, message string
 func NewProgrammedCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
 	return NewCondition(commonv1alpha1.ConditionProgrammed, status, reason, message, generation)
 }
+
+// NewRestartRequiredCondition creates a ConditionRestartRequired condition.
+func NewRestartRequiredCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionRestartRequired, status, reason, message, generation)
+}
+
+// NewRulesLoadedCondition creates a ConditionRulesLoaded condition.
+func NewRulesLoadedCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionRulesLoaded, status, reason, message, generation)
+}
+
+// NewEventDropsCondition creates a ConditionEventDrops condition.
+func NewEventDropsCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionEventDrops, status, reason, message, generation)
+}
+
+// NewDegradedCondition creates a ConditionDegraded condition.
+func NewDegradedCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionDegraded, status, reason, message, generation)
+}
+
+// NewDriftedCondition creates a ConditionDrifted condition.
+func NewDriftedCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionDrifted, status, reason, message, generation)
+}
+
+// NewSuspendedCondition creates a ConditionSuspended condition.
+func NewSuspendedCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionSuspended, status, reason, message, generation)
+}
+
+// NewCollectorAvailableCondition creates a ConditionCollectorAvailable condition.
+func NewCollectorAvailableCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionCollectorAvailable, status, reason, message, generation)
+}
+
+// NewRolledOutCondition creates a ConditionRolledOut condition.
+func NewRolledOutCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionRolledOut, status, reason, message, generation)
+}
+
+// NewRolledBackCondition creates a ConditionRolledBack condition.
+func NewRolledBackCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionRolledBack, status, reason, message, generation)
+}
+
+// NewRulesConflictCondition creates a ConditionRulesConflict condition.
+func NewRulesConflictCondition(status metav1.ConditionStatus, reason, message string, generation int64) metav1.Condition {
+	return NewCondition(commonv1alpha1.ConditionRulesConflict, status, reason, message, generation)
+}
+
+// SetSuspendedCondition sets the Suspended condition when suspended is true and removes it otherwise.
+// It returns true when the conditions switch between suspended and not suspended.
+func SetSuspendedCondition(conditions *[]metav1.Condition, suspended bool, reason, message string, generation int64) bool {
+	wasSuspended := apimeta.IsStatusConditionTrue(*conditions, commonv1alpha1.ConditionSuspended.String())
+	if suspended {
+		apimeta.SetStatusCondition(conditions, NewSuspendedCondition(metav1.ConditionTrue, reason, message, generation))
+	} else {
+		apimeta.RemoveStatusCondition(conditions, commonv1alpha1.ConditionSuspended.String())
+	}
+	return wasSuspended != suspended
+}
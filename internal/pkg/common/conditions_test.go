@@ -19,6 +19,7 @@ package common
 import (
 	"testing"
 
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
@@ -113,3 +114,67 @@ func TestNewProgrammedCondition(t *testing.T) {
 			condition.Reason, "ProgramFailed")
 	}
 }
+
+func TestNewHealthConditions(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(metav1.ConditionStatus, string, string, int64) metav1.Condition
+		wantType commonv1alpha1.ConditionType
+	}{
+		{name: "RulesLoaded", build: NewRulesLoadedCondition, wantType: commonv1alpha1.ConditionRulesLoaded},
+		{name: "EventDrops", build: NewEventDropsCondition, wantType: commonv1alpha1.ConditionEventDrops},
+		{name: "Degraded", build: NewDegradedCondition, wantType: commonv1alpha1.ConditionDegraded},
+		{name: "Drifted", build: NewDriftedCondition, wantType: commonv1alpha1.ConditionDrifted},
+		{name: "Suspended", build: NewSuspendedCondition, wantType: commonv1alpha1.ConditionSuspended},
+		{name: "CollectorAvailable", build: NewCollectorAvailableCondition, wantType: commonv1alpha1.ConditionCollectorAvailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := tt.build(metav1.ConditionTrue, "Reason", "message", 2)
+			if condition.Type != string(tt.wantType) {
+				t.Errorf("Type = %v, want %v", condition.Type, tt.wantType)
+			}
+			if condition.Status != metav1.ConditionTrue {
+				t.Errorf("Status = %v, want %v", condition.Status, metav1.ConditionTrue)
+			}
+			if condition.ObservedGeneration != 2 {
+				t.Errorf("ObservedGeneration = %v, want %v", condition.ObservedGeneration, 2)
+			}
+		})
+	}
+}
+
+func TestSetSuspendedCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		suspended   bool
+		previous    bool
+		wantChanged bool
+	}{
+		{name: "suspends", suspended: true, wantChanged: true},
+		{name: "stays suspended", suspended: true, previous: true},
+		{name: "resumes", previous: true, wantChanged: true},
+		{name: "stays resumed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conditions []metav1.Condition
+			if tt.previous {
+				conditions = append(conditions, NewSuspendedCondition(metav1.ConditionTrue, "Suspended", "message", 1))
+			}
+
+			changed := SetSuspendedCondition(&conditions, tt.suspended, "Suspended", "message", 2)
+			if changed != tt.wantChanged {
+				t.Errorf("SetSuspendedCondition() = %v, want %v", changed, tt.wantChanged)
+			}
+			if got := apimeta.IsStatusConditionTrue(conditions, commonv1alpha1.ConditionSuspended.String()); got != tt.suspended {
+				t.Errorf("Suspended condition true = %v, want %v", got, tt.suspended)
+			}
+			if !tt.suspended && len(conditions) != 0 {
+				t.Errorf("conditions = %v, want none", conditions)
+			}
+		})
+	}
+}
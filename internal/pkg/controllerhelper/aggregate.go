@@ -195,13 +195,16 @@ func ApplyAggregateConditions(current *[]metav1.Condition, newConds []metav1.Con
 }
 
 // ComputeAggregateConditions computes aggregate conditions from nodeList, logs transitions, and
-// applies the result to conditions in place. It does NOT call PatchStatusSSA: the caller is
-// responsible for deciding whether the status changed and issuing the patch.
+// applies the result to conditions in place. extra conditions computed by the caller for the parent
+// itself (e.g. RolledOut) are applied alongside, so they are not dropped as stale aggregate types.
+// It does NOT call PatchStatusSSA: the caller is responsible for deciding whether the status changed
+// and issuing the patch.
 func ComputeAggregateConditions(
 	ctx context.Context,
 	obj client.Object,
 	conditions *[]metav1.Condition,
 	nodeList *artifactv1alpha1.ArtifactNodeList,
+	extra ...metav1.Condition,
 ) {
 	sets := make([]NodeConditionSet, len(nodeList.Items))
 	for i := range nodeList.Items {
@@ -210,7 +213,7 @@ func ComputeAggregateConditions(
 			Conditions: nodeList.Items[i].Status.Conditions,
 		}
 	}
-	aggregated := AggregateConditions(sets, obj.GetGeneration())
+	aggregated := append(AggregateConditions(sets, obj.GetGeneration()), extra...)
 	LogConditionTransitions(ctx, *conditions, aggregated)
 	ApplyAggregateConditions(conditions, aggregated)
 }
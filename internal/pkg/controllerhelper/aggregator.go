@@ -0,0 +1,317 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
+)
+
+// AggregatorControllerName identifies the aggregator controllers in logs and as the SSA field manager,
+// suffixed with the artifact kind.
+const AggregatorControllerName = "instance-artifact"
+
+// AggregatorReconciler is the node-object aggregator controller of one artifact kind: T is the artifact
+// (Config, Plugin or Rulesfile) and L its list type. It runs in the instance operator and is responsible for:
+//   - Creating one ArtifactNode per cluster node that matches the artifact selector and runs a targeted Falco instance.
+//   - Deleting ArtifactNode objects when a node no longer matches.
+//   - Admitting the nodes to the current generation as the rollout strategy of the artifact allows.
+//   - Aggregating per-node conditions into the parent artifact status (sole writer).
+//   - Managing the NodeObjectsInUseFinalizer on the parent artifact.
+//
+// The ArtifactNode objects are where the per-node artifact operators report the Programmed condition, the
+// Falco restarts a new plugin library needs and the RulesConflict condition of the rules files they load.
+type AggregatorReconciler[T client.Object, L client.ObjectList] struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// object and list are empty prototypes of the artifact and its list, copied for each use.
+	object T
+	list   L
+	// kind is the API Kind of the artifacts, artifactKind the matching label value.
+	kind         string
+	artifactKind string
+}
+
+// NewAggregatorReconciler returns a new AggregatorReconciler for the artifacts of the type of object, whose
+// list type is the one of list, e.g. &artifactv1alpha1.Config{} and &artifactv1alpha1.ConfigList{}.
+func NewAggregatorReconciler[T client.Object, L client.ObjectList](
+	cl client.Client, scheme *runtime.Scheme, object T, list L,
+) (*AggregatorReconciler[T, L], error) {
+	r := &AggregatorReconciler[T, L]{Client: cl, Scheme: scheme, object: object, list: list}
+	switch any(object).(type) {
+	case *artifactv1alpha1.Config:
+		r.kind, r.artifactKind = KindConfig, ArtifactKindConfig
+	case *artifactv1alpha1.Plugin:
+		r.kind, r.artifactKind = KindPlugin, ArtifactKindPlugin
+	case *artifactv1alpha1.Rulesfile:
+		r.kind, r.artifactKind = KindRulesfile, ArtifactKindRulesfile
+	default:
+		return nil, fmt.Errorf("unsupported artifact type %T", object)
+	}
+	return r, nil
+}
+
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=configs;plugins;rulesfiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=configs/status;plugins/status;rulesfiles/status,verbs=patch;update
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=configs/finalizers;plugins/finalizers;rulesfiles/finalizers,verbs=patch;update
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=artifactnodes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=artifact.falcosecurity.dev,resources=artifactnodes/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=instance.falcosecurity.dev,resources=falcos,verbs=get;list;watch
+
+// Name returns the name of the controller, which is also its SSA field manager.
+func (r *AggregatorReconciler[T, L]) Name() string {
+	return AggregatorControllerName + "-" + r.artifactKind
+}
+
+// Reconcile reconciles an artifact: ensures ArtifactNode objects exist for matching nodes,
+// removes stale ones, admits the nodes to the current generation as the rollout strategy allows,
+// and writes the aggregate conditions and the node summary back to the artifact.
+func (r *AggregatorReconciler[T, L]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(1).Info("Reconciling " + r.kind)
+
+	obj := r.newObject()
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if k8serrors.IsNotFound(err) {
+			ForgetProgrammedNodes(r.kind, req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	fields, err := aggregatedFields(obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		logger.V(1).Info(r.kind + " marked for deletion, running cleanup")
+		return ctrl.Result{}, r.handleDeletion(ctx, obj)
+	}
+
+	matchingNodes, err := ListMatchingFalcoNodes(ctx, r.Client, fields.target, obj.GetNamespace())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.V(1).Info("Listed matching nodes", "count", len(matchingNodes))
+
+	existingNodes, err := ListOwnedNodes(ctx, r.Client, obj.GetNamespace(), obj.GetName(), r.kind)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.V(1).Info("Listed existing ArtifactNode objects", "count", len(existingNodes.Items))
+
+	desired := make(map[string]struct{}, len(matchingNodes))
+	for i := range matchingNodes {
+		desired[matchingNodes[i].Name] = struct{}{}
+	}
+
+	if err := DeleteStaleNodeObjects(ctx, r.Client, existingNodes.Items, desired); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// No network-bound work happens between here and node creation, so the DeletionTimestamp
+	// check at the top of Reconcile is still valid.
+
+	// Ensure an ArtifactNode exists for each matching node.
+	gvk := artifactv1alpha1.GroupVersion.WithKind(r.kind)
+	for i := range matchingNodes {
+		if err := EnsureNodeObject(ctx, r.Client, obj, gvk, r.artifactKind, matchingNodes[i].Name); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Re-fetch node objects (some may have just been created) to compute the aggregate.
+	existingNodes, err = ListOwnedNodes(ctx, r.Client, obj.GetNamespace(), obj.GetName(), r.kind)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.V(1).Info("Re-listed ArtifactNode objects after sync", "count", len(existingNodes.Items))
+
+	// Keep the parent alive when children are desired or until every existing child is
+	// physically gone. The desired-node check also covers a just-created child that the
+	// informer cache may not expose in the immediate re-list yet.
+	if err := ReconcileInUseFinalizer(
+		ctx, r.Client, obj,
+		NodeObjectsInUseFinalizer,
+		len(matchingNodes) > 0 || len(existingNodes.Items) > 0,
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// A stale or terminating child no longer represents the desired assignment and must not
+	// keep its last condition in the aggregate while deletion is pending.
+	activeNodes := &artifactv1alpha1.ArtifactNodeList{}
+	for i := range existingNodes.Items {
+		nodeObject := &existingNodes.Items[i]
+		if !nodeObject.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if _, ok := desired[nodeObject.Spec.NodeName]; !ok {
+			continue
+		}
+		activeNodes.Items = append(activeNodes.Items, *nodeObject)
+	}
+
+	rolledOut, requeueAfter, err := ReconcileRollout(
+		ctx, r.Client, fields.rollout, obj.GetGeneration(), activeNodes.Items, time.Now(),
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var extra []metav1.Condition
+	if rolledOut != nil {
+		extra = append(extra, *rolledOut)
+	}
+
+	// Only the status changes from here on, so comparing the whole object detects a status change.
+	old := obj.DeepCopyObject()
+	if fields.observedGeneration != nil {
+		*fields.observedGeneration = obj.GetGeneration()
+	}
+	ComputeAggregateConditions(ctx, obj, fields.conditions, activeNodes, extra...)
+	*fields.nodeSummary = ComputeNodeSummary(activeNodes)
+	RecordProgrammedNodes(r.kind, req.NamespacedName, activeNodes)
+	if !apiequality.Semantic.DeepEqual(old, obj) {
+		return ctrl.Result{RequeueAfter: requeueAfter}, PatchStatusSSA(ctx, r.Client, r.Scheme, obj, r.Name())
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// handleDeletion deletes all ArtifactNode objects so each per-node artifact operator can clean up and
+// remove its own finalizer (skipping this would deadlock: GC cascade only fires after the owner is
+// deleted, but the in-use finalizer keeps the owner alive).
+func (r *AggregatorReconciler[T, L]) handleDeletion(ctx context.Context, obj T) error {
+	existing, err := ListOwnedNodes(ctx, r.Client, obj.GetNamespace(), obj.GetName(), r.kind)
+	if err != nil {
+		return err
+	}
+
+	nodesRemaining, err := DeleteNodeObjectsForParentDeletion(ctx, r.Client, existing.Items)
+	if err != nil {
+		return err
+	}
+	if nodesRemaining {
+		return nil
+	}
+
+	return ReconcileInUseFinalizer(ctx, r.Client, obj, NodeObjectsInUseFinalizer, false)
+}
+
+func (r *AggregatorReconciler[T, L]) newObject() T {
+	return r.object.DeepCopyObject().(T)
+}
+
+func (r *AggregatorReconciler[T, L]) newList() L {
+	return r.list.DeepCopyObject().(L)
+}
+
+// SetupWithManager registers this controller with the manager.
+func (r *AggregatorReconciler[T, L]) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(r.newObject(), builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return !obj.GetDeletionTimestamp().IsZero()
+			}),
+		))).
+		Watches(&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+				return EnqueueAllOfType(ctx, r.Client, r.newList())
+			}),
+		).
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, pod client.Object) []reconcile.Request {
+				return EnqueueAllOfType(ctx, r.Client, r.newList(), client.InNamespace(pod.GetNamespace()))
+			}),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				_, ok := obj.GetLabels()["app.kubernetes.io/instance"]
+				return ok
+			})),
+		).
+		Watches(&instancev1alpha1.Falco{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, falco client.Object) []reconcile.Request {
+				return EnqueueAllOfType(ctx, r.Client, r.newList(), client.InNamespace(falco.GetNamespace()))
+			}),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Watches(&artifactv1alpha1.ArtifactNode{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), r.newObject()),
+		).
+		Named(r.Name()).
+		WithLogConstructor(LogConstructorFor(mgr.GetLogger(), mgr.GetScheme(), r.Name(), r.newObject())).
+		Complete(tracing.Reconciler(r.kind, r))
+}
+
+// aggregatedArtifact holds the targeting and rollout of an artifact, and pointers to the status fields
+// the aggregator writes. observedGeneration is nil for the kinds whose status has none.
+type aggregatedArtifact struct {
+	target             Target
+	rollout            *commonv1alpha1.Rollout
+	conditions         *[]metav1.Condition
+	nodeSummary        **artifactv1alpha1.NodeSummary
+	observedGeneration *int64
+}
+
+// aggregatedFields returns the fields of the artifact obj the aggregator reads and writes.
+func aggregatedFields(obj client.Object) (aggregatedArtifact, error) {
+	switch o := obj.(type) {
+	case *artifactv1alpha1.Config:
+		return aggregatedArtifact{
+			target:             Target{NodeSelector: o.Spec.Selector, FalcoRef: o.Spec.FalcoRef, InstanceSelector: o.Spec.InstanceSelector},
+			rollout:            o.Spec.Rollout,
+			conditions:         &o.Status.Conditions,
+			nodeSummary:        &o.Status.NodeSummary,
+			observedGeneration: &o.Status.ObservedGeneration,
+		}, nil
+	case *artifactv1alpha1.Plugin:
+		return aggregatedArtifact{
+			target:      Target{NodeSelector: o.Spec.Selector, FalcoRef: o.Spec.FalcoRef, InstanceSelector: o.Spec.InstanceSelector},
+			rollout:     o.Spec.Rollout,
+			conditions:  &o.Status.Conditions,
+			nodeSummary: &o.Status.NodeSummary,
+		}, nil
+	case *artifactv1alpha1.Rulesfile:
+		return aggregatedArtifact{
+			target:      Target{NodeSelector: o.Spec.Selector, FalcoRef: o.Spec.FalcoRef, InstanceSelector: o.Spec.InstanceSelector},
+			rollout:     o.Spec.Rollout,
+			conditions:  &o.Status.Conditions,
+			nodeSummary: &o.Status.NodeSummary,
+		}, nil
+	default:
+		return aggregatedArtifact{}, fmt.Errorf("unsupported artifact type %T", obj)
+	}
+}
@@ -0,0 +1,414 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/controllers/testutil"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/index"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
+)
+
+const (
+	testAggregatedName  = "test-artifact"
+	testAggregatorFalco = "test-falco"
+)
+
+// aggregatorKind describes one artifact kind for the table of TestAggregatorReconciler.
+type aggregatorKind struct {
+	kind         string
+	artifactKind string
+	// newReconciler returns the aggregator of the kind.
+	newReconciler func(cl client.Client, s *runtime.Scheme) (reconcile.Reconciler, error)
+	// newArtifact returns an artifact of the kind with the given node selector and rollout.
+	newArtifact func(selector *metav1.LabelSelector, rollout *commonv1alpha1.Rollout) client.Object
+	// status returns the conditions and the node summary of an artifact of the kind.
+	status func(obj client.Object) ([]metav1.Condition, *artifactv1alpha1.NodeSummary)
+}
+
+var aggregatorKinds = []aggregatorKind{
+	{
+		kind:         controllerhelper.KindConfig,
+		artifactKind: controllerhelper.ArtifactKindConfig,
+		newReconciler: func(cl client.Client, s *runtime.Scheme) (reconcile.Reconciler, error) {
+			return controllerhelper.NewAggregatorReconciler(cl, s, &artifactv1alpha1.Config{}, &artifactv1alpha1.ConfigList{})
+		},
+		newArtifact: func(selector *metav1.LabelSelector, rollout *commonv1alpha1.Rollout) client.Object {
+			return &artifactv1alpha1.Config{Spec: artifactv1alpha1.ConfigSpec{Selector: selector, Rollout: rollout}}
+		},
+		status: func(obj client.Object) ([]metav1.Condition, *artifactv1alpha1.NodeSummary) {
+			o := obj.(*artifactv1alpha1.Config)
+			return o.Status.Conditions, o.Status.NodeSummary
+		},
+	},
+	{
+		kind:         controllerhelper.KindPlugin,
+		artifactKind: controllerhelper.ArtifactKindPlugin,
+		newReconciler: func(cl client.Client, s *runtime.Scheme) (reconcile.Reconciler, error) {
+			return controllerhelper.NewAggregatorReconciler(cl, s, &artifactv1alpha1.Plugin{}, &artifactv1alpha1.PluginList{})
+		},
+		newArtifact: func(selector *metav1.LabelSelector, rollout *commonv1alpha1.Rollout) client.Object {
+			return &artifactv1alpha1.Plugin{Spec: artifactv1alpha1.PluginSpec{Selector: selector, Rollout: rollout}}
+		},
+		status: func(obj client.Object) ([]metav1.Condition, *artifactv1alpha1.NodeSummary) {
+			o := obj.(*artifactv1alpha1.Plugin)
+			return o.Status.Conditions, o.Status.NodeSummary
+		},
+	},
+	{
+		kind:         controllerhelper.KindRulesfile,
+		artifactKind: controllerhelper.ArtifactKindRulesfile,
+		newReconciler: func(cl client.Client, s *runtime.Scheme) (reconcile.Reconciler, error) {
+			return controllerhelper.NewAggregatorReconciler(cl, s, &artifactv1alpha1.Rulesfile{}, &artifactv1alpha1.RulesfileList{})
+		},
+		newArtifact: func(selector *metav1.LabelSelector, rollout *commonv1alpha1.Rollout) client.Object {
+			return &artifactv1alpha1.Rulesfile{Spec: artifactv1alpha1.RulesfileSpec{Selector: selector, Rollout: rollout}}
+		},
+		status: func(obj client.Object) ([]metav1.Condition, *artifactv1alpha1.NodeSummary) {
+			o := obj.(*artifactv1alpha1.Rulesfile)
+			return o.Status.Conditions, o.Status.NodeSummary
+		},
+	},
+}
+
+// artifact returns the artifact of the test with the given options applied.
+func (k aggregatorKind) artifact(opts ...func(client.Object)) client.Object {
+	return k.artifactWithSpec(nil, nil, opts...)
+}
+
+// artifactWithSpec returns the artifact of the test with the given node selector, rollout and options.
+func (k aggregatorKind) artifactWithSpec(
+	selector *metav1.LabelSelector, rollout *commonv1alpha1.Rollout, opts ...func(client.Object),
+) client.Object {
+	obj := k.newArtifact(selector, rollout)
+	obj.SetName(testAggregatedName)
+	obj.SetNamespace(testutil.TestNamespace)
+	for _, o := range opts {
+		o(obj)
+	}
+	return obj
+}
+
+// nodeObject returns the ArtifactNode of the artifact of the test on the test node.
+func (k aggregatorKind) nodeObject(opts ...func(*artifactv1alpha1.ArtifactNode)) *artifactv1alpha1.ArtifactNode {
+	isController := true
+	n := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(k.artifactKind, testAggregatedName, testutil.TestNodeName),
+			Namespace: testutil.TestNamespace,
+			Labels:    controllerhelper.NodeObjectLabels(k.artifactKind, testAggregatedName, testutil.TestNodeName),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: artifactv1alpha1.GroupVersion.String(),
+				Kind:       k.kind,
+				Name:       testAggregatedName,
+				Controller: &isController,
+			}},
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: testutil.TestNodeName},
+	}
+	for _, o := range opts {
+		o(n)
+	}
+	return n
+}
+
+// falcoOnNode returns a Falco and its running pod on the test node, which makes the node match.
+func falcoOnNode() []client.Object {
+	return []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testutil.TestNodeName}},
+		&instancev1alpha1.Falco{ObjectMeta: metav1.ObjectMeta{Name: testAggregatorFalco, Namespace: testutil.TestNamespace}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "falco-pod",
+				Namespace: testutil.TestNamespace,
+				Labels:    map[string]string{"app.kubernetes.io/instance": testAggregatorFalco},
+			},
+			Spec:   corev1.PodSpec{NodeName: testutil.TestNodeName},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	}
+}
+
+func withFinalizer(obj client.Object) {
+	obj.SetFinalizers([]string{controllerhelper.NodeObjectsInUseFinalizer})
+}
+
+func withDeletion(obj client.Object) {
+	withFinalizer(obj)
+	now := metav1.Now()
+	obj.SetDeletionTimestamp(&now)
+}
+
+func withProgrammed(status metav1.ConditionStatus, reason string, generation int64) func(*artifactv1alpha1.ArtifactNode) {
+	return func(n *artifactv1alpha1.ArtifactNode) {
+		n.Status.Conditions = []metav1.Condition{{
+			Type:               commonv1alpha1.ConditionProgrammed.String(),
+			Status:             status,
+			Reason:             reason,
+			ObservedGeneration: generation,
+			LastTransitionTime: metav1.Now(),
+		}}
+	}
+}
+
+func TestAggregatorReconciler(t *testing.T) {
+	programmed := commonv1alpha1.ConditionProgrammed.String()
+	// prodSelector matches no node of the tests.
+	prodSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+
+	tests := []struct {
+		name string
+		// objects returns the objects the fake client starts with.
+		objects func(k aggregatorKind) []client.Object
+		// funcs intercepts the calls of the reconciler to the fake client.
+		funcs interceptor.Funcs
+		// reconciles is the number of passes to run, one when zero.
+		reconciles int
+		wantErr    bool
+		check      func(t *testing.T, k aggregatorKind, cl client.Client)
+	}{
+		{
+			name:    "artifact not found",
+			objects: func(aggregatorKind) []client.Object { return nil },
+			check: func(t *testing.T, _ aggregatorKind, cl client.Client) {
+				nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+				require.NoError(t, cl.List(context.Background(), nodeObjects))
+				assert.Empty(t, nodeObjects.Items)
+			},
+		},
+		{
+			name:    "get error",
+			objects: func(k aggregatorKind) []client.Object { return []client.Object{k.artifact()} },
+			funcs: interceptor.Funcs{
+				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+					return fmt.Errorf("api server error")
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "creates the ArtifactNode of a matching node",
+			objects: func(k aggregatorKind) []client.Object {
+				return append(falcoOnNode(), k.artifact(func(obj client.Object) { obj.SetGeneration(2) }))
+			},
+			check: func(t *testing.T, k aggregatorKind, cl client.Client) {
+				nodeObject := &artifactv1alpha1.ArtifactNode{}
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(k.nodeObject()), nodeObject))
+				assert.Equal(t, testutil.TestNodeName, nodeObject.Spec.NodeName)
+				assert.Equal(t, k.artifactKind, nodeObject.Labels[controllerhelper.LabelArtifactKind])
+				assert.Equal(t, int64(2), nodeObject.Spec.AllowedGeneration, "without a rollout every node is admitted")
+
+				got := k.artifact()
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(got), got))
+				assert.Contains(t, got.GetFinalizers(), controllerhelper.NodeObjectsInUseFinalizer)
+				conditions, _ := k.status(got)
+				assert.Nil(t, apimeta.FindStatusCondition(conditions, commonv1alpha1.ConditionRolledOut.String()))
+				if config, ok := got.(*artifactv1alpha1.Config); ok {
+					assert.Equal(t, int64(2), config.Status.ObservedGeneration)
+				}
+			},
+		},
+		{
+			name: "no Falco pod on the node",
+			objects: func(k aggregatorKind) []client.Object {
+				return []client.Object{k.artifact(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testutil.TestNodeName}}}
+			},
+			check: func(t *testing.T, k aggregatorKind, cl client.Client) {
+				nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+				require.NoError(t, cl.List(context.Background(), nodeObjects))
+				assert.Empty(t, nodeObjects.Items)
+
+				got := k.artifact()
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(got), got))
+				assert.NotContains(t, got.GetFinalizers(), controllerhelper.NodeObjectsInUseFinalizer)
+				conditions, _ := k.status(got)
+				testutil.RequireCondition(t, conditions, programmed, metav1.ConditionUnknown, "NoNodesAssigned")
+			},
+		},
+		{
+			name: "aggregates the conditions of the nodes",
+			objects: func(k aggregatorKind) []client.Object {
+				return append(falcoOnNode(), k.artifact(),
+					k.nodeObject(withProgrammed(metav1.ConditionFalse, "OCIArtifactPullError", 0)))
+			},
+			check: func(t *testing.T, k aggregatorKind, cl client.Client) {
+				got := k.artifact()
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(got), got))
+				conditions, summary := k.status(got)
+				testutil.RequireCondition(t, conditions, programmed, metav1.ConditionFalse, "OCIArtifactPullError")
+				require.NotNil(t, summary)
+				assert.Equal(t, int32(1), summary.Total)
+				assert.Equal(t, int32(1), summary.Failed)
+				assert.Equal(t, float64(1), promtestutil.ToFloat64(metrics.ArtifactNodes.WithLabelValues(
+					k.kind, testutil.TestNamespace, testAggregatedName, "False")))
+			},
+		},
+		{
+			name: "rollout admits the first batch",
+			objects: func(k aggregatorKind) []client.Object {
+				artifact := k.artifactWithSpec(nil, &commonv1alpha1.Rollout{PauseBetweenBatches: &metav1.Duration{Duration: time.Hour}},
+					func(obj client.Object) { obj.SetGeneration(2) })
+				return append(falcoOnNode(), artifact,
+					k.nodeObject(func(n *artifactv1alpha1.ArtifactNode) { n.Spec.AllowedGeneration = 1 }))
+			},
+			check: func(t *testing.T, k aggregatorKind, cl client.Client) {
+				nodeObject := k.nodeObject()
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), nodeObject))
+				assert.Equal(t, int64(2), nodeObject.Spec.AllowedGeneration)
+				assert.NotEmpty(t, nodeObject.Annotations[controllerhelper.AnnotationAdmittedAt])
+
+				got := k.artifact()
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(got), got))
+				conditions, _ := k.status(got)
+				testutil.RequireCondition(t, conditions, commonv1alpha1.ConditionRolledOut.String(),
+					metav1.ConditionFalse, "RolloutInProgress")
+			},
+		},
+		{
+			name: "deletes the ArtifactNode of a node no longer matching",
+			objects: func(k aggregatorKind) []client.Object {
+				artifact := k.artifactWithSpec(prodSelector, nil)
+				return append(falcoOnNode(), artifact, k.nodeObject())
+			},
+			check: func(t *testing.T, _ aggregatorKind, cl client.Client) {
+				nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+				require.NoError(t, cl.List(context.Background(), nodeObjects))
+				assert.Empty(t, nodeObjects.Items)
+			},
+		},
+		{
+			name: "terminating stale ArtifactNode keeps the finalizer but leaves the aggregate",
+			objects: func(k aggregatorKind) []client.Object {
+				artifact := k.artifactWithSpec(prodSelector, nil, withFinalizer)
+				return []client.Object{artifact, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testutil.TestNodeName}}, k.nodeObject(
+					withProgrammed(metav1.ConditionTrue, "Programmed", 0),
+					func(n *artifactv1alpha1.ArtifactNode) { n.Finalizers = []string{"artifact.example.com/node-cleanup"} },
+				)}
+			},
+			check: func(t *testing.T, k aggregatorKind, cl client.Client) {
+				nodeObject := k.nodeObject()
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), nodeObject))
+				assert.False(t, nodeObject.DeletionTimestamp.IsZero())
+
+				got := k.artifact()
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(got), got))
+				assert.Contains(t, got.GetFinalizers(), controllerhelper.NodeObjectsInUseFinalizer)
+				conditions, _ := k.status(got)
+				testutil.RequireCondition(t, conditions, programmed, metav1.ConditionUnknown, "NoNodesAssigned")
+			},
+		},
+		{
+			name: "deletion removes the ArtifactNode objects, then the finalizer",
+			objects: func(k aggregatorKind) []client.Object {
+				return []client.Object{k.artifact(withDeletion), k.nodeObject()}
+			},
+			reconciles: 2,
+			check: func(t *testing.T, k aggregatorKind, cl client.Client) {
+				nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+				require.NoError(t, cl.List(context.Background(), nodeObjects))
+				assert.Empty(t, nodeObjects.Items)
+
+				got := k.artifact()
+				err := cl.Get(context.Background(), client.ObjectKeyFromObject(got), got)
+				assert.True(t, k8serrors.IsNotFound(err), "the artifact must be gone, got %v", err)
+			},
+		},
+		{
+			name: "ArtifactNode list error",
+			objects: func(k aggregatorKind) []client.Object {
+				return append(falcoOnNode(), k.artifact())
+			},
+			funcs: interceptor.Funcs{
+				List: func(ctx context.Context, cl client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					if _, ok := list.(*artifactv1alpha1.ArtifactNodeList); ok {
+						return fmt.Errorf("api server error")
+					}
+					return cl.List(ctx, list, opts...)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ArtifactNode create error",
+			objects: func(k aggregatorKind) []client.Object {
+				return append(falcoOnNode(), k.artifact())
+			},
+			funcs: interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if _, ok := obj.(*artifactv1alpha1.ArtifactNode); ok {
+						return fmt.Errorf("api server error")
+					}
+					return cl.Create(ctx, obj, opts...)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, k := range aggregatorKinds {
+		for _, tt := range tests {
+			t.Run(k.kind+"/"+tt.name, func(t *testing.T) {
+				s := testutil.Scheme(t, artifactv1alpha1.AddToScheme, instancev1alpha1.AddToScheme)
+				cl := fake.NewClientBuilder().
+					WithScheme(s).
+					WithObjects(tt.objects(k)...).
+					WithStatusSubresource(k.newArtifact(nil, nil), &artifactv1alpha1.ArtifactNode{}).
+					WithIndex(&artifactv1alpha1.ArtifactNode{}, index.ArtifactNodeOwnerKind, index.ArtifactNodeOwnerKindIndexer).
+					WithInterceptorFuncs(tt.funcs).
+					Build()
+				r, err := k.newReconciler(cl, s)
+				require.NoError(t, err)
+
+				for range max(tt.reconciles, 1) {
+					_, err = r.Reconcile(context.Background(), testutil.Request(testAggregatedName))
+					if tt.wantErr {
+						require.Error(t, err)
+						return
+					}
+					require.NoError(t, err)
+				}
+				tt.check(t, k, cl)
+			})
+		}
+	}
+}
+
+func TestNewAggregatorReconcilerUnsupportedKind(t *testing.T) {
+	_, err := controllerhelper.NewAggregatorReconciler(nil, nil, &artifactv1alpha1.ArtifactNode{}, &artifactv1alpha1.ArtifactNodeList{})
+	require.Error(t, err)
+}
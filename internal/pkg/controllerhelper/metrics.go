@@ -0,0 +1,54 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
+)
+
+// RecordProgrammedNodes reports the nodes of the artifact of the given kind by status of their Programmed
+// condition. A node not reporting the condition yet counts as Unknown.
+func RecordProgrammedNodes(kind string, key client.ObjectKey, nodeList *artifactv1alpha1.ArtifactNodeList) {
+	counts := map[metav1.ConditionStatus]int{
+		metav1.ConditionTrue:    0,
+		metav1.ConditionFalse:   0,
+		metav1.ConditionUnknown: 0,
+	}
+	for i := range nodeList.Items {
+		status := metav1.ConditionUnknown
+		if c := apimeta.FindStatusCondition(nodeList.Items[i].Status.Conditions,
+			commonv1alpha1.ConditionProgrammed.String()); c != nil {
+			status = c.Status
+		}
+		counts[status]++
+	}
+	for status, n := range counts {
+		metrics.ArtifactNodes.WithLabelValues(kind, key.Namespace, key.Name, string(status)).Set(float64(n))
+	}
+}
+
+// ForgetProgrammedNodes stops reporting the nodes of the deleted artifact of the given kind.
+func ForgetProgrammedNodes(kind string, key client.ObjectKey) {
+	metrics.ArtifactNodes.DeletePartialMatch(prometheus.Labels{"kind": kind, "namespace": key.Namespace, "name": key.Name})
+}
@@ -79,18 +79,18 @@ func ListMatchingNodes(ctx context.Context, cl client.Client, sel *metav1.LabelS
 	return nodeList.Items, nil
 }
 
-// ListMatchingFalcoNodes returns the subset of nodes that (a) match sel and (b) have at least
-// one Running Falco pod scheduled on them across all Falco CRs in namespace. In DaemonSet mode
+// ListMatchingFalcoNodes returns the subset of nodes that (a) match the node selector of the target and (b) have at
+// least one Running Falco pod scheduled on them across the Falco CRs in namespace targeted by it. In DaemonSet mode
 // every matching node has a Falco pod, so the result equals ListMatchingNodes. In Deployment mode
 // only the node(s) hosting a scheduled Falco pod are returned. Nodes excluded by taints or
 // tolerations that prevent Falco from running there are correctly omitted in both modes.
-func ListMatchingFalcoNodes(ctx context.Context, cl client.Client, sel *metav1.LabelSelector, namespace string) ([]corev1.Node, error) {
-	nodes, err := ListMatchingNodes(ctx, cl, sel)
+func ListMatchingFalcoNodes(ctx context.Context, cl client.Client, target Target, namespace string) ([]corev1.Node, error) {
+	nodes, err := ListMatchingNodes(ctx, cl, target.NodeSelector)
 	if err != nil {
 		return nil, err
 	}
 
-	falcoNodes, err := listFalcoRunningNodeNames(ctx, cl, namespace)
+	falcoNodes, err := listFalcoRunningNodeNames(ctx, cl, target, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -104,9 +104,9 @@ func ListMatchingFalcoNodes(ctx context.Context, cl client.Client, sel *metav1.L
 	return filtered, nil
 }
 
-// listFalcoRunningNodeNames lists all Falco CRs in namespace and returns the set of node names
+// listFalcoRunningNodeNames lists the Falco CRs in namespace targeted by target and returns the set of node names
 // that currently have a Running pod for any of them.
-func listFalcoRunningNodeNames(ctx context.Context, cl client.Client, namespace string) (map[string]struct{}, error) {
+func listFalcoRunningNodeNames(ctx context.Context, cl client.Client, target Target, namespace string) (map[string]struct{}, error) {
 	falcoList := &instancev1alpha1.FalcoList{}
 	if err := cl.List(ctx, falcoList, client.InNamespace(namespace)); err != nil {
 		return nil, fmt.Errorf("list Falco CRs for node filtering: %w", err)
@@ -115,6 +115,9 @@ func listFalcoRunningNodeNames(ctx context.Context, cl client.Client, namespace
 	running := make(map[string]struct{})
 	for i := range falcoList.Items {
 		name := falcoList.Items[i].Name
+		if !target.InstanceTargeted(name, falcoList.Items[i].Labels) {
+			continue
+		}
 		podList := &corev1.PodList{}
 		if err := cl.List(ctx, podList,
 			client.InNamespace(namespace),
@@ -0,0 +1,141 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+)
+
+const (
+	// EffectiveConfigPrefix prefixes the name of the ConfigMaps publishing the effective configuration of a node.
+	EffectiveConfigPrefix = "effective-config"
+	// EffectiveConfigProvenanceKey is the key of the effective configuration ConfigMaps holding the provenance of the
+	// top-level keys.
+	EffectiveConfigProvenanceKey = "provenance.yaml"
+	// RulesIndexPrefix prefixes the name of the ConfigMaps publishing the rules index of a node.
+	RulesIndexPrefix = "rules-index"
+	// RulesIndexKey is the key of the rules index ConfigMaps holding the index.
+	RulesIndexKey = "index.yaml"
+)
+
+// EffectiveConfigMapName returns the name of the ConfigMap publishing the effective configuration of the Falco
+// instance on the node.
+func EffectiveConfigMapName(instance, nodeName string) string {
+	return NodeObjectName(EffectiveConfigPrefix, instance, nodeName)
+}
+
+// RulesIndexConfigMapName returns the name of the ConfigMap publishing the rules index of the Falco instance on the
+// node.
+func RulesIndexConfigMapName(instance, nodeName string) string {
+	return NodeObjectName(RulesIndexPrefix, instance, nodeName)
+}
+
+// GetInstance returns the Falco instance whose pods run the calling artifact operator, or nil when the instance is
+// unknown or gone.
+func GetInstance(ctx context.Context, cl client.Client, instance client.ObjectKey) (*instancev1alpha1.Falco, error) {
+	if instance.Name == "" {
+		log.FromContext(ctx).V(2).Info("Falco instance unknown")
+		return nil, nil
+	}
+	falco := &instancev1alpha1.Falco{}
+	if err := cl.Get(ctx, instance, falco); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to fetch Falco instance %q: %w", instance.Name, err)
+	}
+	return falco, nil
+}
+
+// ListInstanceNodeObjects returns the ArtifactNodes of artifactKind on nodeName whose parent artifact targets falco,
+// the ones the artifact operator of falco on nodeName reports the state of the node on.
+func ListInstanceNodeObjects(
+	ctx context.Context,
+	cl client.Client,
+	falco *instancev1alpha1.Falco,
+	artifactKind, nodeName string,
+) ([]artifactv1alpha1.ArtifactNode, error) {
+	nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+	if err := cl.List(ctx, nodeObjects, client.InNamespace(falco.Namespace), client.MatchingLabels{
+		LabelArtifactKind: artifactKind,
+		LabelArtifactNode: nodeName,
+	}); err != nil {
+		return nil, fmt.Errorf("listing ArtifactNodes: %w", err)
+	}
+
+	var targeting []artifactv1alpha1.ArtifactNode
+	for i := range nodeObjects.Items {
+		target, found, err := NodeObjectTarget(ctx, cl, &nodeObjects.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		if found && target.InstanceTargeted(falco.Name, falco.Labels) {
+			targeting = append(targeting, nodeObjects.Items[i])
+		}
+	}
+	return targeting, nil
+}
+
+// PublishNodeConfigMap applies the ConfigMap name holding data reported by the artifact operator of falco on
+// nodeName. The ConfigMap is owned by the Falco instance, so that it is deleted along with it, and labeled with the
+// artifact kind and the node. Nothing is written when the ConfigMap already holds data.
+func PublishNodeConfigMap(
+	ctx context.Context,
+	cl client.Client,
+	falco *instancev1alpha1.Falco,
+	artifactKind, name, nodeName string,
+	data map[string]string,
+	fieldManager string,
+) error {
+	current := &corev1.ConfigMap{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: falco.Namespace, Name: name}, current); err == nil {
+		if maps.Equal(current.Data, data) {
+			return nil
+		}
+	} else if !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("fetching ConfigMap %s: %w", name, err)
+	}
+
+	log.FromContext(ctx).Info("Publishing node ConfigMap", "configMap", name)
+	configMap := corev1ac.ConfigMap(name, falco.Namespace).
+		WithLabels(map[string]string{
+			LabelArtifactKind: artifactKind,
+			LabelArtifactNode: nodeName,
+		}).
+		WithOwnerReferences(metav1ac.OwnerReference().
+			WithAPIVersion(instancev1alpha1.GroupVersion.String()).
+			WithKind("Falco").
+			WithName(falco.Name).
+			WithUID(falco.UID)).
+		WithData(data)
+	if err := cl.Apply(ctx, configMap, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("applying ConfigMap %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+func TestGetInstance(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, instancev1alpha1.AddToScheme(s))
+	falco := &instancev1alpha1.Falco{ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: "default"}}
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(falco).Build()
+
+	got, err := controllerhelper.GetInstance(context.Background(), cl, client.ObjectKeyFromObject(falco))
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "falco", got.Name)
+
+	got, err = controllerhelper.GetInstance(context.Background(), cl, client.ObjectKey{Namespace: "default", Name: "gone"})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = controllerhelper.GetInstance(context.Background(), cl, client.ObjectKey{})
+	require.NoError(t, err)
+	assert.Nil(t, got, "an unknown instance is not an error")
+}
+
+func TestPublishNodeConfigMap(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, instancev1alpha1.AddToScheme(s))
+	falco := &instancev1alpha1.Falco{ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: "default", UID: "falco-uid"}}
+	cl := fake.NewClientBuilder().WithScheme(s).Build()
+	ctx := context.Background()
+	key := client.ObjectKey{Namespace: "default", Name: "effective-config--falco--node-1"}
+
+	require.NoError(t, controllerhelper.PublishNodeConfigMap(ctx, cl, falco, controllerhelper.ArtifactKindConfig,
+		key.Name, "node-1", map[string]string{"falco.yaml": "a: 1\n"}, "test-manager"))
+
+	got := &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(ctx, key, got))
+	assert.Equal(t, map[string]string{"falco.yaml": "a: 1\n"}, got.Data)
+	assert.Equal(t, map[string]string{
+		controllerhelper.LabelArtifactKind: controllerhelper.ArtifactKindConfig,
+		controllerhelper.LabelArtifactNode: "node-1",
+	}, got.Labels)
+	require.Len(t, got.OwnerReferences, 1)
+	assert.Equal(t, "Falco", got.OwnerReferences[0].Kind)
+	assert.Equal(t, falco.UID, got.OwnerReferences[0].UID)
+
+	resourceVersion := got.ResourceVersion
+	require.NoError(t, controllerhelper.PublishNodeConfigMap(ctx, cl, falco, controllerhelper.ArtifactKindConfig,
+		key.Name, "node-1", map[string]string{"falco.yaml": "a: 1\n"}, "test-manager"))
+	require.NoError(t, cl.Get(ctx, key, got))
+	assert.Equal(t, resourceVersion, got.ResourceVersion, "unchanged data must not be written again")
+
+	require.NoError(t, controllerhelper.PublishNodeConfigMap(ctx, cl, falco, controllerhelper.ArtifactKindConfig,
+		key.Name, "node-1", map[string]string{"falco.yaml": "a: 2\n"}, "test-manager"))
+	require.NoError(t, cl.Get(ctx, key, got))
+	assert.Equal(t, "a: 2\n", got.Data["falco.yaml"])
+}
+
+func TestListInstanceNodeObjects(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, artifactv1alpha1.AddToScheme(s))
+	require.NoError(t, instancev1alpha1.AddToScheme(s))
+	falco := &instancev1alpha1.Falco{ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: "default"}}
+	newConfig := func(name string, falcoRef *commonv1alpha1.FalcoRef) *artifactv1alpha1.Config {
+		return &artifactv1alpha1.Config{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       artifactv1alpha1.ConfigSpec{FalcoRef: falcoRef},
+		}
+	}
+	newNodeObject := func(kind, parent, node string) *artifactv1alpha1.ArtifactNode {
+		return &artifactv1alpha1.ArtifactNode{ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(kind, parent, node),
+			Namespace: "default",
+			Labels:    controllerhelper.NodeObjectLabels(kind, parent, node),
+		}}
+	}
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		newConfig("shared", nil),
+		newConfig("restricted", &commonv1alpha1.FalcoRef{Name: "falco"}),
+		newConfig("other", &commonv1alpha1.FalcoRef{Name: "other"}),
+		newNodeObject(controllerhelper.ArtifactKindConfig, "shared", "node-1"),
+		newNodeObject(controllerhelper.ArtifactKindConfig, "restricted", "node-1"),
+		newNodeObject(controllerhelper.ArtifactKindConfig, "other", "node-1"),
+		newNodeObject(controllerhelper.ArtifactKindConfig, "gone", "node-1"),
+		newNodeObject(controllerhelper.ArtifactKindConfig, "shared", "node-2"),
+		newNodeObject(controllerhelper.ArtifactKindRulesfile, "shared", "node-1"),
+	).Build()
+
+	got, err := controllerhelper.ListInstanceNodeObjects(context.Background(), cl, falco, controllerhelper.ArtifactKindConfig, "node-1")
+	require.NoError(t, err)
+	var names []string
+	for i := range got {
+		names = append(names, got[i].Labels[controllerhelper.LabelArtifactParent])
+	}
+	assert.ElementsMatch(t, []string{"shared", "restricted"}, names)
+}
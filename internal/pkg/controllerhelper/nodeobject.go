@@ -171,7 +171,8 @@ func EnforceNodeObjectMeta(
 }
 
 // EnsureNodeObject creates an ArtifactNode for nodeName if it does not already exist, or enforces
-// its labels/owner reference if it does. Shared by the three aggregator controllers (Plugin,
+// its labels/owner reference if it does. When ctx carries a span, its trace context is stamped on
+// the node object so that the sidecar programming the node can continue the trace. Shared by the three aggregator controllers (Plugin,
 // Rulesfile, Config), which otherwise each reimplement this identical create-or-enforce logic.
 // artifactKind is the lowercase artifact.falcosecurity.dev/kind label value (e.g. "plugin"),
 // ownerGVK the owner's GroupVersionKind (e.g. artifactv1alpha1.GroupVersion.WithKind("Plugin")).
@@ -187,6 +188,7 @@ func EnsureNodeObject(
 
 	desiredLabels := NodeObjectLabels(artifactKind, owner.GetName(), nodeName)
 	desiredOwnerRef := *metav1.NewControllerRef(owner, ownerGVK)
+	traceAnnotations := nodeObjectTraceAnnotations(ctx, owner)
 
 	existing := &artifactv1alpha1.ArtifactNode{}
 	err := cl.Get(ctx, client.ObjectKey{Namespace: owner.GetNamespace(), Name: name}, existing)
@@ -195,6 +197,10 @@ func EnsureNodeObject(
 			logger.Error(err, "unable to enforce ArtifactNode metadata", "node", nodeName, "artifactNode", name)
 			return err
 		}
+		if err := propagateTraceContext(ctx, cl, existing, traceAnnotations); err != nil {
+			logger.Error(err, "unable to propagate trace context to ArtifactNode", "node", nodeName, "artifactNode", name)
+			return err
+		}
 		return nil
 	}
 	if !k8serrors.IsNotFound(err) {
@@ -207,6 +213,7 @@ func EnsureNodeObject(
 			Name:            name,
 			Namespace:       owner.GetNamespace(),
 			Labels:          desiredLabels,
+			Annotations:     traceAnnotations,
 			OwnerReferences: []metav1.OwnerReference{desiredOwnerRef},
 		},
 		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: nodeName},
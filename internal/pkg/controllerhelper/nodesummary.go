@@ -0,0 +1,102 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"sort"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+)
+
+// maxNodeSummaryDigests caps how many digests ComputeNodeSummary lists, so that the status of an
+// artifact stays bounded while a rollout is in progress on a large cluster.
+const maxNodeSummaryDigests = 10
+
+// ComputeNodeSummary summarizes the per-node state of an artifact from its ArtifactNodes: the nodes by
+// status of their Programmed condition, the distribution of the installed content and the node pending
+// for the longest time. The result only depends on nodeList, so an unchanged rollout yields an equal
+// summary and does not trigger a status patch.
+func ComputeNodeSummary(nodeList *artifactv1alpha1.ArtifactNodeList) *artifactv1alpha1.NodeSummary {
+	summary := &artifactv1alpha1.NodeSummary{}
+
+	type digestKey struct{ medium, digest string }
+	digests := map[digestKey]int32{}
+
+	for i := range nodeList.Items {
+		nodeObject := &nodeList.Items[i]
+		summary.Total++
+
+		programmed := apimeta.FindStatusCondition(nodeObject.Status.Conditions, commonv1alpha1.ConditionProgrammed.String())
+		switch {
+		case programmed != nil && programmed.Status == metav1.ConditionTrue:
+			summary.Programmed++
+		case programmed != nil && programmed.Status == metav1.ConditionFalse:
+			summary.Failed++
+		default:
+			summary.Pending++
+			since := nodeObject.CreationTimestamp
+			if programmed != nil {
+				since = programmed.LastTransitionTime
+			}
+			if olderPending(nodeObject.Spec.NodeName, since, summary.OldestPending) {
+				summary.OldestPending = &artifactv1alpha1.PendingNode{NodeName: nodeObject.Spec.NodeName, Since: since}
+			}
+		}
+
+		for _, installed := range nodeObject.Status.InstalledArtifacts {
+			if installed.ContentHash == "" {
+				continue
+			}
+			digests[digestKey{medium: installed.Medium, digest: "sha256:" + installed.ContentHash}]++
+		}
+	}
+
+	for key, nodes := range digests {
+		summary.Digests = append(summary.Digests, artifactv1alpha1.NodeDigestCount{Medium: key.medium, Digest: key.digest, Nodes: nodes})
+	}
+	// Most widespread first; ties are broken by medium and digest for deterministic SSA patches.
+	sort.Slice(summary.Digests, func(i, j int) bool {
+		a, b := summary.Digests[i], summary.Digests[j]
+		if a.Nodes != b.Nodes {
+			return a.Nodes > b.Nodes
+		}
+		if a.Medium != b.Medium {
+			return a.Medium < b.Medium
+		}
+		return a.Digest < b.Digest
+	})
+	if len(summary.Digests) > maxNodeSummaryDigests {
+		summary.Digests = summary.Digests[:maxNodeSummaryDigests]
+	}
+	return summary
+}
+
+// olderPending reports whether the node pending since since has been pending longer than current.
+// Ties are broken by node name so that the result does not depend on the listing order.
+func olderPending(nodeName string, since metav1.Time, current *artifactv1alpha1.PendingNode) bool {
+	if current == nil {
+		return true
+	}
+	if !since.Equal(&current.Since) {
+		return since.Before(&current.Since)
+	}
+	return nodeName < current.NodeName
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+func summaryNode(name string, created time.Time, programmed *metav1.Condition, installed ...artifactv1alpha1.InstalledArtifact) artifactv1alpha1.ArtifactNode {
+	nodeObject := artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "config--base--" + name, CreationTimestamp: metav1.NewTime(created)},
+		Spec:       artifactv1alpha1.ArtifactNodeSpec{NodeName: name},
+		Status:     artifactv1alpha1.ArtifactNodeStatus{InstalledArtifacts: installed},
+	}
+	if programmed != nil {
+		nodeObject.Status.Conditions = []metav1.Condition{*programmed}
+	}
+	return nodeObject
+}
+
+func programmedCondition(status metav1.ConditionStatus, since time.Time) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               commonv1alpha1.ConditionProgrammed.String(),
+		Status:             status,
+		LastTransitionTime: metav1.NewTime(since),
+	}
+}
+
+func TestComputeNodeSummary(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inline := func(hash string) artifactv1alpha1.InstalledArtifact {
+		return artifactv1alpha1.InstalledArtifact{Medium: "inline", ContentHash: hash, Path: "/etc/falco/config.d/50-01-base.yaml"}
+	}
+
+	tests := []struct {
+		name  string
+		nodes []artifactv1alpha1.ArtifactNode
+		want  *artifactv1alpha1.NodeSummary
+	}{
+		{
+			name: "no nodes",
+			want: &artifactv1alpha1.NodeSummary{},
+		},
+		{
+			name: "counts nodes by Programmed status",
+			nodes: []artifactv1alpha1.ArtifactNode{
+				summaryNode("node-a", base, programmedCondition(metav1.ConditionTrue, base), inline("new")),
+				summaryNode("node-b", base, programmedCondition(metav1.ConditionTrue, base), inline("new")),
+				summaryNode("node-c", base, programmedCondition(metav1.ConditionFalse, base), inline("old")),
+				summaryNode("node-d", base.Add(time.Minute), programmedCondition(metav1.ConditionUnknown, base.Add(2*time.Minute))),
+				// Not reporting yet: pending since its creation, which is older than node-d's transition.
+				summaryNode("node-e", base.Add(time.Minute), nil),
+			},
+			want: &artifactv1alpha1.NodeSummary{
+				Total:      5,
+				Programmed: 2,
+				Failed:     1,
+				Pending:    2,
+				Digests: []artifactv1alpha1.NodeDigestCount{
+					{Medium: "inline", Digest: "sha256:new", Nodes: 2},
+					{Medium: "inline", Digest: "sha256:old", Nodes: 1},
+				},
+				OldestPending: &artifactv1alpha1.PendingNode{NodeName: "node-e", Since: metav1.NewTime(base.Add(time.Minute))},
+			},
+		},
+		{
+			name: "ties on the pending time are broken by node name",
+			nodes: []artifactv1alpha1.ArtifactNode{
+				summaryNode("node-b", base, nil),
+				summaryNode("node-a", base, nil),
+			},
+			want: &artifactv1alpha1.NodeSummary{
+				Total:         2,
+				Pending:       2,
+				OldestPending: &artifactv1alpha1.PendingNode{NodeName: "node-a", Since: metav1.NewTime(base)},
+			},
+		},
+		{
+			name: "ignores installed artifacts without content hash",
+			nodes: []artifactv1alpha1.ArtifactNode{
+				summaryNode("node-a", base, programmedCondition(metav1.ConditionTrue, base), inline("")),
+			},
+			want: &artifactv1alpha1.NodeSummary{Total: 1, Programmed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := controllerhelper.ComputeNodeSummary(&artifactv1alpha1.ArtifactNodeList{Items: tt.nodes})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestComputeNodeSummary_CapsDigests(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var nodes []artifactv1alpha1.ArtifactNode
+	for i := range 12 {
+		nodes = append(nodes, summaryNode(fmt.Sprintf("node-%02d", i), base, programmedCondition(metav1.ConditionTrue, base),
+			artifactv1alpha1.InstalledArtifact{Medium: "oci", ContentHash: fmt.Sprintf("%02d", i)}))
+	}
+	// One more node with the first digest makes it the most widespread.
+	nodes = append(nodes, summaryNode("node-12", base, programmedCondition(metav1.ConditionTrue, base),
+		artifactv1alpha1.InstalledArtifact{Medium: "oci", ContentHash: "11"}))
+
+	got := controllerhelper.ComputeNodeSummary(&artifactv1alpha1.ArtifactNodeList{Items: nodes})
+	require.Len(t, got.Digests, 10)
+	assert.Equal(t, artifactv1alpha1.NodeDigestCount{Medium: "oci", Digest: "sha256:11", Nodes: 2}, got.Digests[0])
+	assert.Equal(t, "sha256:00", got.Digests[1].Digest)
+	assert.Equal(t, int32(13), got.Total)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+)
+
+// SyncRestartRequired reconciles the RestartRequired condition on the ArtifactNode tracking parent on nodeName.
+//
+// When keys is non-empty the condition is set to True, listing the keys in its message. When keys is empty, a
+// True condition whose last transition predates since (the start time of the calling artifact operator) is
+// removed: the Falco pod running next to it was started after the change and has already loaded it. A True
+// condition set after since is kept until the instance operator restarts the pod.
+//
+// A missing ArtifactNode is not an error: the instance operator has not created it yet and the next reconcile
+// of the parent catches up.
+func SyncRestartRequired(
+	ctx context.Context,
+	cl client.Client,
+	scheme *runtime.Scheme,
+	artifactKind string,
+	parent client.Object,
+	nodeName string,
+	keys []string,
+	since time.Time,
+	fieldManager string,
+) error {
+	logger := log.FromContext(ctx)
+
+	nodeObject := &artifactv1alpha1.ArtifactNode{}
+	key := client.ObjectKey{Namespace: parent.GetNamespace(), Name: NodeObjectName(artifactKind, parent.GetName(), nodeName)}
+	if err := cl.Get(ctx, key, nodeObject); err != nil {
+		if k8serrors.IsNotFound(err) {
+			if len(keys) > 0 {
+				logger.Info("ArtifactNode not found, unable to signal Falco restart", "artifactNode", key.Name, "keys", keys)
+			} else {
+				logger.V(3).Info("ArtifactNode not found, skipping restart signal", "artifactNode", key.Name)
+			}
+			return nil
+		}
+		return fmt.Errorf("fetching ArtifactNode %s: %w", key.Name, err)
+	}
+
+	conditionType := commonv1alpha1.ConditionRestartRequired.String()
+	current := apimeta.FindStatusCondition(nodeObject.Status.Conditions, conditionType)
+
+	switch {
+	case len(keys) > 0:
+		message := fmt.Sprintf(artifact.MessageFormatRestartRequired, nodeName, strings.Join(keys, ", "))
+		if current != nil && current.Status == metav1.ConditionTrue && current.Message == message {
+			return nil
+		}
+		logger.Info("Signaling Falco restart", "artifactNode", key.Name, "keys", keys)
+		if current != nil && current.LastTransitionTime.Time.Before(since) {
+			// The pod has restarted since the previous signal: start a new transition so the instance
+			// operator recognizes it as newer than the running pod.
+			apimeta.RemoveStatusCondition(&nodeObject.Status.Conditions, conditionType)
+		}
+		apimeta.SetStatusCondition(&nodeObject.Status.Conditions, common.NewRestartRequiredCondition(
+			metav1.ConditionTrue, artifact.ReasonRestartRequired, message, parent.GetGeneration()))
+	case current != nil && current.LastTransitionTime.Time.Before(since):
+		logger.Info("Falco restarted since the change was signaled, clearing restart signal", "artifactNode", key.Name)
+		apimeta.RemoveStatusCondition(&nodeObject.Status.Conditions, conditionType)
+	default:
+		return nil
+	}
+
+	return PatchStatusSSA(ctx, cl, scheme, nodeObject, fieldManager)
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+func TestSyncRestartRequired(t *testing.T) {
+	const nodeName = "node-1"
+	startedAt := time.Now()
+	before := metav1.NewTime(startedAt.Add(-time.Hour))
+	after := metav1.NewTime(startedAt.Add(time.Minute))
+
+	parent := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: "default", Generation: 2}}
+	restartCondition := func(ltt metav1.Time, message string) metav1.Condition {
+		return metav1.Condition{
+			Type:               commonv1alpha1.ConditionRestartRequired.String(),
+			Status:             metav1.ConditionTrue,
+			Reason:             "RestartRequired",
+			Message:            message,
+			LastTransitionTime: ltt,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		noNode      bool
+		existing    []metav1.Condition
+		keys        []string
+		wantPresent bool
+		wantMessage string
+		wantNewLTT  bool
+	}{
+		{
+			name:   "missing ArtifactNode is not an error",
+			noNode: true,
+			keys:   []string{"engine.kind"},
+		},
+		{
+			name:        "keys set the condition",
+			keys:        []string{"engine.kind", "engine.kmod.buf_size_preset"},
+			wantPresent: true,
+			wantMessage: "Falco on node node-1 must be restarted to apply: engine.kind, engine.kmod.buf_size_preset",
+			wantNewLTT:  true,
+		},
+		{
+			name: "no keys and no condition is a no-op",
+		},
+		{
+			name:        "no keys keeps a condition set after the start",
+			existing:    []metav1.Condition{restartCondition(after, "pending")},
+			wantPresent: true,
+			wantMessage: "pending",
+		},
+		{
+			name:        "keys after a restart start a new transition",
+			existing:    []metav1.Condition{restartCondition(before, "old")},
+			keys:        []string{"engine.kind"},
+			wantPresent: true,
+			wantMessage: "Falco on node node-1 must be restarted to apply: engine.kind",
+			wantNewLTT:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			require.NoError(t, artifactv1alpha1.AddToScheme(s))
+
+			nodeObject := &artifactv1alpha1.ArtifactNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, parent.Name, nodeName),
+					Namespace: parent.Namespace,
+				},
+				Spec:   artifactv1alpha1.ArtifactNodeSpec{NodeName: nodeName},
+				Status: artifactv1alpha1.ArtifactNodeStatus{Conditions: tt.existing},
+			}
+			builder := fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(nodeObject)
+			if !tt.noNode {
+				builder = builder.WithObjects(nodeObject)
+			}
+			cl := builder.Build()
+
+			err := controllerhelper.SyncRestartRequired(context.Background(), cl, s, controllerhelper.ArtifactKindConfig,
+				parent, nodeName, tt.keys, startedAt, "test-manager")
+			require.NoError(t, err)
+			if tt.noNode {
+				return
+			}
+
+			got := &artifactv1alpha1.ArtifactNode{}
+			require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(nodeObject), got))
+			cond := apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionRestartRequired.String())
+			if !tt.wantPresent {
+				assert.Nil(t, cond)
+				return
+			}
+			require.NotNil(t, cond)
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, tt.wantMessage, cond.Message)
+			if tt.wantNewLTT {
+				assert.False(t, cond.LastTransitionTime.Time.Before(startedAt.Truncate(time.Second)))
+				assert.Equal(t, parent.Generation, cond.ObservedGeneration)
+			}
+		})
+	}
+}
+
+func TestSyncRestartRequired_ClearedAfterRestart(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, artifactv1alpha1.AddToScheme(s))
+
+	parent := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: "default"}}
+	nodeObject := &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, parent.Name, "node-1"),
+			Namespace: parent.Namespace,
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: "node-1"},
+	}
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(nodeObject).WithStatusSubresource(nodeObject).Build()
+	ctx := context.Background()
+	conditionType := commonv1alpha1.ConditionRestartRequired.String()
+
+	// The artifact operator of the running pod signals the change.
+	require.NoError(t, controllerhelper.SyncRestartRequired(ctx, cl, s, controllerhelper.ArtifactKindConfig,
+		parent, "node-1", []string{"engine.kind"}, time.Now().Add(-time.Hour), "test-manager"))
+	got := &artifactv1alpha1.ArtifactNode{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(nodeObject), got))
+	require.NotNil(t, apimeta.FindStatusCondition(got.Status.Conditions, conditionType))
+
+	// The artifact operator of the replacement pod finds nothing left to restart for.
+	require.NoError(t, controllerhelper.SyncRestartRequired(ctx, cl, s, controllerhelper.ArtifactKindConfig,
+		parent, "node-1", nil, time.Now().Add(time.Hour), "test-manager"))
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(nodeObject), got))
+	assert.Nil(t, apimeta.FindStatusCondition(got.Status.Conditions, conditionType))
+}
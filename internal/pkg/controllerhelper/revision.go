@@ -0,0 +1,428 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+)
+
+const (
+	// AnnotationRevisionDigest is the annotation key storing, on the ControllerRevision of an artifact
+	// sourced from an OCI artifact, the manifest digest an artifact operator pulled for it, recorded by the
+	// instance operator. A rollback to the revision pulls this digest rather than the tag, which may have
+	// moved since.
+	AnnotationRevisionDigest = "artifact.falcosecurity.dev/oci-digest"
+
+	// DefaultRevisionHistoryLimit is the number of previous revisions kept when an artifact does not set
+	// revisionHistoryLimit.
+	DefaultRevisionHistoryLimit = 10
+)
+
+// RevisionName returns the deterministic name of the ControllerRevision recording the sources of an
+// artifact with the given hash. Format: "<kind>--<artifactName>--<hash>", truncated like NodeObjectName.
+func RevisionName(kind, artifactName, hash string) string {
+	return NodeObjectName(kind, artifactName, hash)
+}
+
+// RevisionLabels returns the standard labels to set on the ControllerRevisions of an artifact.
+func RevisionLabels(kind, artifactName string) map[string]string {
+	return map[string]string{
+		LabelArtifactParent: artifactName,
+		LabelArtifactKind:   kind,
+	}
+}
+
+// revisionSources returns the part of the spec of an artifact recorded in its revisions: the fields naming
+// the content to install. Targeting, priority and the other settings of the artifact are not part of it.
+func revisionSources(obj client.Object) (any, error) {
+	switch o := obj.(type) {
+	case *artifactv1alpha1.Config:
+		return artifactv1alpha1.ConfigSpec{Config: o.Spec.Config, ConfigMapRef: o.Spec.ConfigMapRef}, nil
+	case *artifactv1alpha1.Plugin:
+		return artifactv1alpha1.PluginSpec{OCIArtifact: o.Spec.OCIArtifact, Config: o.Spec.Config}, nil
+	case *artifactv1alpha1.Rulesfile:
+		return artifactv1alpha1.RulesfileSpec{
+			OCIArtifact: o.Spec.OCIArtifact, InlineRules: o.Spec.InlineRules, ConfigMapRef: o.Spec.ConfigMapRef,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported artifact type %T", obj)
+	}
+}
+
+// applyRevisionSources replaces the sources in the spec of obj, in memory only, with the ones recorded in
+// data. When digest is set, the OCI artifact of the revision is pulled by digest.
+func applyRevisionSources(obj client.Object, data []byte, digest string) error {
+	pin := func(oci *commonv1alpha1.OCIArtifact) *commonv1alpha1.OCIArtifact {
+		if oci != nil && digest != "" {
+			oci.Image.Tag = digest
+		}
+		return oci
+	}
+
+	switch o := obj.(type) {
+	case *artifactv1alpha1.Config:
+		sources := artifactv1alpha1.ConfigSpec{}
+		if err := json.Unmarshal(data, &sources); err != nil {
+			return err
+		}
+		o.Spec.Config, o.Spec.ConfigMapRef = sources.Config, sources.ConfigMapRef
+	case *artifactv1alpha1.Plugin:
+		sources := artifactv1alpha1.PluginSpec{}
+		if err := json.Unmarshal(data, &sources); err != nil {
+			return err
+		}
+		o.Spec.OCIArtifact, o.Spec.Config = pin(sources.OCIArtifact), sources.Config
+	case *artifactv1alpha1.Rulesfile:
+		sources := artifactv1alpha1.RulesfileSpec{}
+		if err := json.Unmarshal(data, &sources); err != nil {
+			return err
+		}
+		o.Spec.OCIArtifact, o.Spec.InlineRules, o.Spec.ConfigMapRef = pin(sources.OCIArtifact), sources.InlineRules, sources.ConfigMapRef
+	default:
+		return fmt.Errorf("unsupported artifact type %T", obj)
+	}
+	return nil
+}
+
+// HasRollback reports whether the artifact obj has rollbackTo set.
+func HasRollback(obj client.Object) bool {
+	return artifactRollback(obj) != nil
+}
+
+// artifactRollback returns the rollbackTo setting of an artifact.
+func artifactRollback(obj client.Object) *commonv1alpha1.RollbackConfig {
+	switch o := obj.(type) {
+	case *artifactv1alpha1.Config:
+		return o.Spec.RollbackTo
+	case *artifactv1alpha1.Plugin:
+		return o.Spec.RollbackTo
+	case *artifactv1alpha1.Rulesfile:
+		return o.Spec.RollbackTo
+	default:
+		return nil
+	}
+}
+
+// artifactRevisionHistoryLimit returns the number of previous revisions to keep for an artifact.
+func artifactRevisionHistoryLimit(obj client.Object) int {
+	var limit *int32
+	switch o := obj.(type) {
+	case *artifactv1alpha1.Config:
+		limit = o.Spec.RevisionHistoryLimit
+	case *artifactv1alpha1.Plugin:
+		limit = o.Spec.RevisionHistoryLimit
+	case *artifactv1alpha1.Rulesfile:
+		limit = o.Spec.RevisionHistoryLimit
+	}
+	if limit == nil {
+		return DefaultRevisionHistoryLimit
+	}
+	return int(*limit)
+}
+
+// revisionData returns the serialized sources of an artifact and their hash.
+func revisionData(obj client.Object) (data []byte, hash string, err error) {
+	sources, err := revisionSources(obj)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err = json.Marshal(sources)
+	if err != nil {
+		return nil, "", fmt.Errorf("serializing sources of %s: %w", obj.GetName(), err)
+	}
+	return data, fmt.Sprintf("%x", sha256.Sum256(data))[:10], nil
+}
+
+// ListRevisions returns the ControllerRevisions controlled by the artifact parent, oldest revision first.
+func ListRevisions(ctx context.Context, cl client.Client, artifactKind string, parent client.Object) ([]appsv1.ControllerRevision, error) {
+	list := &appsv1.ControllerRevisionList{}
+	if err := cl.List(ctx, list,
+		client.InNamespace(parent.GetNamespace()),
+		client.MatchingLabels(RevisionLabels(artifactKind, parent.GetName())),
+	); err != nil {
+		return nil, fmt.Errorf("listing revisions of %s: %w", parent.GetName(), err)
+	}
+
+	revisions := make([]appsv1.ControllerRevision, 0, len(list.Items))
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], parent) {
+			revisions = append(revisions, list.Items[i])
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, nil
+}
+
+// EnsureRevision records the current sources of the artifact parent as its latest revision. A new
+// ControllerRevision is created for sources not recorded yet; a revision recording the same sources as
+// the current ones is renumbered as the latest one, as Deployments do for their ReplicaSets. It returns
+// the revisions of parent, oldest first, and whether the latest revision changed.
+func EnsureRevision(
+	ctx context.Context,
+	cl client.Client,
+	scheme *runtime.Scheme,
+	artifactKind string,
+	parent client.Object,
+) ([]appsv1.ControllerRevision, bool, error) {
+	logger := log.FromContext(ctx)
+
+	data, hash, err := revisionData(parent)
+	if err != nil {
+		return nil, false, err
+	}
+	revisions, err := ListRevisions(ctx, cl, artifactKind, parent)
+	if err != nil {
+		return nil, false, err
+	}
+
+	name := RevisionName(artifactKind, parent.GetName(), hash)
+	var latest int64
+	if len(revisions) > 0 {
+		latest = revisions[len(revisions)-1].Revision
+	}
+	for i := range revisions {
+		revision := &revisions[i]
+		if revision.Name != name {
+			continue
+		}
+		if revision.Revision == latest {
+			return revisions, false, nil
+		}
+
+		patch := client.MergeFrom(revision.DeepCopy())
+		revision.Revision = latest + 1
+		logger.Info("Renumbering revision as the latest one", "revision", revision.Name, "number", revision.Revision)
+		if err := cl.Patch(ctx, revision, patch); err != nil {
+			return nil, false, fmt.Errorf("renumbering revision %s: %w", revision.Name, err)
+		}
+		sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+		return revisions, true, nil
+	}
+
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: parent.GetNamespace(),
+			Labels:    RevisionLabels(artifactKind, parent.GetName()),
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: latest + 1,
+	}
+	if err := controllerutil.SetControllerReference(parent, revision, scheme); err != nil {
+		return nil, false, fmt.Errorf("setting owner of revision %s: %w", name, err)
+	}
+	logger.Info("Recording revision", "revision", name, "number", revision.Revision)
+	if err := cl.Create(ctx, revision); err != nil {
+		return nil, false, fmt.Errorf("creating revision %s: %w", name, err)
+	}
+	return append(revisions, *revision), true, nil
+}
+
+// PruneRevisions deletes the oldest revisions of the artifact parent beyond its revisionHistoryLimit,
+// keeping the latest revision and the one selected by rollbackTo. revisions must be sorted oldest first.
+func PruneRevisions(ctx context.Context, cl client.Client, parent client.Object, revisions []appsv1.ControllerRevision) error {
+	excess := len(revisions) - 1 - artifactRevisionHistoryLimit(parent)
+	if excess <= 0 {
+		return nil
+	}
+
+	var keep int64
+	if rollback := artifactRollback(parent); rollback != nil {
+		keep = rollback.Revision
+	}
+	for i := 0; i < len(revisions)-1 && excess > 0; i++ {
+		if revisions[i].Revision == keep {
+			continue
+		}
+		log.FromContext(ctx).Info("Pruning revision", "revision", revisions[i].Name, "number", revisions[i].Revision)
+		if err := cl.Delete(ctx, &revisions[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("deleting revision %s: %w", revisions[i].Name, err)
+		}
+		excess--
+	}
+	return nil
+}
+
+// ClearRollback removes rollbackTo from the spec of the artifact parent.
+func ClearRollback(ctx context.Context, cl client.Client, parent client.Object) error {
+	patch := client.MergeFrom(parent.DeepCopyObject().(client.Object))
+	switch o := parent.(type) {
+	case *artifactv1alpha1.Config:
+		o.Spec.RollbackTo = nil
+	case *artifactv1alpha1.Plugin:
+		o.Spec.RollbackTo = nil
+	case *artifactv1alpha1.Rulesfile:
+		o.Spec.RollbackTo = nil
+	default:
+		return fmt.Errorf("unsupported artifact type %T", parent)
+	}
+	log.FromContext(ctx).Info("Sources changed, clearing rollbackTo")
+	return cl.Patch(ctx, parent, patch)
+}
+
+// ApplyRollback replaces, in memory only, the sources of the artifact parent with the ones of the revision
+// selected by its rollbackTo, and reports it with the RolledBack condition. It returns whether a rollback
+// is in effect. Without rollbackTo, the condition is removed and parent is left untouched. A missing
+// revision sets RolledBack and Programmed to False and returns an error.
+func ApplyRollback(
+	ctx context.Context,
+	cl client.Client,
+	artifactKind string,
+	parent client.Object,
+	conditions *[]metav1.Condition,
+) (bool, error) {
+	rollback := artifactRollback(parent)
+	if rollback == nil {
+		apimeta.RemoveStatusCondition(conditions, commonv1alpha1.ConditionRolledBack.String())
+		return false, nil
+	}
+
+	revisions, err := ListRevisions(ctx, cl, artifactKind, parent)
+	if err != nil {
+		return false, err
+	}
+	for i := range revisions {
+		revision := &revisions[i]
+		if revision.Revision != rollback.Revision {
+			continue
+		}
+		if err := applyRevisionSources(parent, revision.Data.Raw, revision.Annotations[AnnotationRevisionDigest]); err != nil {
+			return false, fmt.Errorf("reading revision %s: %w", revision.Name, err)
+		}
+		log.FromContext(ctx).Info("Installing a previous revision", "revision", revision.Name, "number", revision.Revision)
+		apimeta.SetStatusCondition(conditions, common.NewRolledBackCondition(metav1.ConditionTrue, artifact.ReasonRolledBack,
+			fmt.Sprintf(artifact.MessageFormatRolledBack, rollback.Revision), parent.GetGeneration()))
+		return true, nil
+	}
+
+	message := fmt.Sprintf(artifact.MessageFormatRevisionNotFound, rollback.Revision)
+	apimeta.SetStatusCondition(conditions, common.NewRolledBackCondition(
+		metav1.ConditionFalse, artifact.ReasonRevisionNotFound, message, parent.GetGeneration()))
+	apimeta.SetStatusCondition(conditions, common.NewProgrammedCondition(
+		metav1.ConditionFalse, artifact.ReasonRevisionNotFound, message, parent.GetGeneration()))
+	return false, fmt.Errorf("revision %d of %s not found", rollback.Revision, parent.GetName())
+}
+
+// ReportRevisionDigest reports digest, the manifest digest the artifact operator pulled on nodeName for the
+// OCI artifact of parent, on the ArtifactNode tracking it, along with the revision recording the current
+// sources of parent. Like SyncNodeProgrammed, a missing ArtifactNode is not an error.
+func ReportRevisionDigest(
+	ctx context.Context,
+	cl client.Client,
+	scheme *runtime.Scheme,
+	artifactKind string,
+	parent client.Object,
+	nodeName string,
+	digest string,
+	fieldManager string,
+) error {
+	if digest == "" {
+		return nil
+	}
+	_, hash, err := revisionData(parent)
+	if err != nil {
+		return err
+	}
+	reported := &artifactv1alpha1.RevisionDigest{Revision: RevisionName(artifactKind, parent.GetName(), hash), Digest: digest}
+
+	nodeObject := &artifactv1alpha1.ArtifactNode{}
+	key := client.ObjectKey{Namespace: parent.GetNamespace(), Name: NodeObjectName(artifactKind, parent.GetName(), nodeName)}
+	if err := cl.Get(ctx, key, nodeObject); err != nil {
+		if k8serrors.IsNotFound(err) {
+			log.FromContext(ctx).V(3).Info("ArtifactNode not found, skipping digest", "artifactNode", key.Name)
+			return nil
+		}
+		return fmt.Errorf("fetching ArtifactNode %s: %w", key.Name, err)
+	}
+	if current := nodeObject.Status.RevisionDigest; current != nil && *current == *reported {
+		return nil
+	}
+	nodeObject.Status.RevisionDigest = reported
+	return PatchStatusSSA(ctx, cl, scheme, nodeObject, fieldManager)
+}
+
+// RecordRevisionDigests stores on the revisions of parent the manifest digests the artifact operators
+// reported on the ArtifactNodes of parent. The first node, by name, reporting a revision records its
+// digest; a revision keeps the digest recorded first. revisions must be the revisions of parent.
+func RecordRevisionDigests(
+	ctx context.Context,
+	cl client.Client,
+	artifactKind string,
+	parent client.Object,
+	revisions []appsv1.ControllerRevision,
+) error {
+	nodeObjects := &artifactv1alpha1.ArtifactNodeList{}
+	if err := cl.List(ctx, nodeObjects, client.InNamespace(parent.GetNamespace()), client.MatchingLabels{
+		LabelArtifactParent: parent.GetName(),
+		LabelArtifactKind:   artifactKind,
+	}); err != nil {
+		return fmt.Errorf("listing ArtifactNodes of %s: %w", parent.GetName(), err)
+	}
+	sort.Slice(nodeObjects.Items, func(i, j int) bool { return nodeObjects.Items[i].Name < nodeObjects.Items[j].Name })
+
+	digests := map[string]string{}
+	for i := range nodeObjects.Items {
+		nodeObject := &nodeObjects.Items[i]
+		reported := nodeObject.Status.RevisionDigest
+		if reported == nil || !metav1.IsControlledBy(nodeObject, parent) {
+			continue
+		}
+		if _, ok := digests[reported.Revision]; !ok {
+			digests[reported.Revision] = reported.Digest
+		}
+	}
+
+	for i := range revisions {
+		revision := &revisions[i]
+		digest, ok := digests[revision.Name]
+		if !ok {
+			continue
+		}
+		if _, ok := revision.Annotations[AnnotationRevisionDigest]; ok {
+			continue
+		}
+
+		patch := client.MergeFrom(revision.DeepCopy())
+		if revision.Annotations == nil {
+			revision.Annotations = map[string]string{}
+		}
+		revision.Annotations[AnnotationRevisionDigest] = digest
+		log.FromContext(ctx).Info("Recording digest on revision", "revision", revision.Name, "digest", digest)
+		if err := cl.Patch(ctx, revision, patch); err != nil {
+			return fmt.Errorf("recording digest on revision %s: %w", revision.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+func revisionScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, artifactv1alpha1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+	return s
+}
+
+func newRevisionRulesfile(tag, inline string) *artifactv1alpha1.Rulesfile {
+	rulesfile := &artifactv1alpha1.Rulesfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: "default", UID: "rulesfile-uid", Generation: 1},
+		Spec:       artifactv1alpha1.RulesfileSpec{Priority: 50},
+	}
+	if tag != "" {
+		rulesfile.Spec.OCIArtifact = &commonv1alpha1.OCIArtifact{
+			Image: commonv1alpha1.ImageSpec{Repository: "falcosecurity/rules/falco-rules", Tag: tag},
+		}
+	}
+	if inline != "" {
+		rulesfile.Spec.InlineRules = &apiextensionsv1.JSON{Raw: []byte(inline)}
+	}
+	return rulesfile
+}
+
+func TestEnsureRevision(t *testing.T) {
+	s := revisionScheme(t)
+	ctx := context.Background()
+	rulesfile := newRevisionRulesfile("4", "")
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(rulesfile).Build()
+	ensure := func() ([]appsv1.ControllerRevision, bool) {
+		t.Helper()
+		revisions, changed, err := controllerhelper.EnsureRevision(ctx, cl, s, controllerhelper.ArtifactKindRulesfile, rulesfile)
+		require.NoError(t, err)
+		return revisions, changed
+	}
+
+	// The first sources are recorded as revision 1.
+	revisions, changed := ensure()
+	assert.True(t, changed)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, int64(1), revisions[0].Revision)
+	assert.True(t, metav1.IsControlledBy(&revisions[0], rulesfile))
+	assert.JSONEq(t, `{"ociArtifact":{"image":{"repository":"falcosecurity/rules/falco-rules","tag":"4"}}}`, string(revisions[0].Data.Raw))
+
+	// Settings other than the sources do not record a revision.
+	rulesfile.Spec.Priority = 60
+	rulesfile.Spec.Suspend = true
+	revisions, changed = ensure()
+	assert.False(t, changed)
+	assert.Len(t, revisions, 1)
+
+	// New sources are recorded as revision 2.
+	rulesfile.Spec.OCIArtifact.Image.Tag = "5"
+	revisions, changed = ensure()
+	assert.True(t, changed)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, int64(2), revisions[1].Revision)
+
+	// Going back to the first sources renumbers their revision.
+	rulesfile.Spec.OCIArtifact.Image.Tag = "4"
+	revisions, changed = ensure()
+	assert.True(t, changed)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, int64(3), revisions[1].Revision)
+	assert.JSONEq(t, `{"ociArtifact":{"image":{"repository":"falcosecurity/rules/falco-rules","tag":"4"}}}`, string(revisions[1].Data.Raw))
+}
+
+func TestPruneRevisions(t *testing.T) {
+	s := revisionScheme(t)
+	ctx := context.Background()
+	rulesfile := newRevisionRulesfile("", "")
+	rulesfile.Spec.RevisionHistoryLimit = ptr.To[int32](1)
+	rulesfile.Spec.RollbackTo = &commonv1alpha1.RollbackConfig{Revision: 1}
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(rulesfile).Build()
+
+	var revisions []appsv1.ControllerRevision
+	for _, tag := range []string{"1", "2", "3", "4"} {
+		rulesfile.Spec.OCIArtifact = &commonv1alpha1.OCIArtifact{Image: commonv1alpha1.ImageSpec{Repository: "rules", Tag: tag}}
+		var err error
+		revisions, _, err = controllerhelper.EnsureRevision(ctx, cl, s, controllerhelper.ArtifactKindRulesfile, rulesfile)
+		require.NoError(t, err)
+	}
+	require.NoError(t, controllerhelper.PruneRevisions(ctx, cl, rulesfile, revisions))
+
+	got, err := controllerhelper.ListRevisions(ctx, cl, controllerhelper.ArtifactKindRulesfile, rulesfile)
+	require.NoError(t, err)
+	numbers := make([]int64, len(got))
+	for i := range got {
+		numbers[i] = got[i].Revision
+	}
+	// The previous revision kept is the one selected by rollbackTo, even though it is the oldest.
+	assert.Equal(t, []int64{1, 4}, numbers)
+}
+
+func TestClearRollback(t *testing.T) {
+	s := revisionScheme(t)
+	ctx := context.Background()
+	rulesfile := newRevisionRulesfile("4", "")
+	rulesfile.Spec.RollbackTo = &commonv1alpha1.RollbackConfig{Revision: 1}
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(rulesfile).Build()
+
+	assert.True(t, controllerhelper.HasRollback(rulesfile))
+	require.NoError(t, controllerhelper.ClearRollback(ctx, cl, rulesfile))
+
+	got := &artifactv1alpha1.Rulesfile{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(rulesfile), got))
+	assert.Nil(t, got.Spec.RollbackTo)
+	assert.False(t, controllerhelper.HasRollback(got))
+}
+
+func TestApplyRollback(t *testing.T) {
+	s := revisionScheme(t)
+	ctx := context.Background()
+	rolledBack := commonv1alpha1.ConditionRolledBack.String()
+
+	// Record the original sources, pulled at a known digest, then new sources.
+	rulesfile := newRevisionRulesfile("4", `[{"macro":"m","condition":"evt.type=open"}]`)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(rulesfile).Build()
+	revisions, _, err := controllerhelper.EnsureRevision(ctx, cl, s, controllerhelper.ArtifactKindRulesfile, rulesfile)
+	require.NoError(t, err)
+	require.NoError(t, cl.Create(ctx, revisionDigestNode(rulesfile, "node-1", revisions[0].Name, "sha256:aaa")))
+	require.NoError(t, controllerhelper.RecordRevisionDigests(ctx, cl, controllerhelper.ArtifactKindRulesfile, rulesfile, revisions))
+	rulesfile.Spec.OCIArtifact.Image.Tag = "5"
+	rulesfile.Spec.InlineRules = nil
+	_, _, err = controllerhelper.EnsureRevision(ctx, cl, s, controllerhelper.ArtifactKindRulesfile, rulesfile)
+	require.NoError(t, err)
+
+	t.Run("without rollbackTo the sources are kept", func(t *testing.T) {
+		current := rulesfile.DeepCopy()
+		conditions := []metav1.Condition{{Type: rolledBack, Status: metav1.ConditionTrue, Reason: "RolledBack"}}
+		ok, err := controllerhelper.ApplyRollback(ctx, cl, controllerhelper.ArtifactKindRulesfile, current, &conditions)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, rulesfile.Spec, current.Spec)
+		assert.Nil(t, apimeta.FindStatusCondition(conditions, rolledBack))
+	})
+
+	t.Run("rollbackTo installs the sources of the revision at its digest", func(t *testing.T) {
+		current := rulesfile.DeepCopy()
+		current.Spec.RollbackTo = &commonv1alpha1.RollbackConfig{Revision: 1}
+		var conditions []metav1.Condition
+		ok, err := controllerhelper.ApplyRollback(ctx, cl, controllerhelper.ArtifactKindRulesfile, current, &conditions)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "sha256:aaa", current.Spec.OCIArtifact.Image.Tag)
+		require.NotNil(t, current.Spec.InlineRules)
+		assert.JSONEq(t, `[{"macro":"m","condition":"evt.type=open"}]`, string(current.Spec.InlineRules.Raw))
+		assert.Equal(t, int32(50), current.Spec.Priority, "settings other than the sources are kept")
+		cond := apimeta.FindStatusCondition(conditions, rolledBack)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, "Installing revision 1 instead of the current sources", cond.Message)
+	})
+
+	t.Run("a missing revision fails programming", func(t *testing.T) {
+		current := rulesfile.DeepCopy()
+		current.Spec.RollbackTo = &commonv1alpha1.RollbackConfig{Revision: 7}
+		var conditions []metav1.Condition
+		ok, err := controllerhelper.ApplyRollback(ctx, cl, controllerhelper.ArtifactKindRulesfile, current, &conditions)
+		require.Error(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "5", current.Spec.OCIArtifact.Image.Tag)
+		for _, conditionType := range []string{rolledBack, commonv1alpha1.ConditionProgrammed.String()} {
+			cond := apimeta.FindStatusCondition(conditions, conditionType)
+			require.NotNil(t, cond, conditionType)
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, "RevisionNotFound", cond.Reason)
+		}
+	})
+}
+
+// revisionDigestNode returns the ArtifactNode of parent on nodeName reporting digest for revision.
+func revisionDigestNode(parent client.Object, nodeName, revision, digest string) *artifactv1alpha1.ArtifactNode {
+	return &artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindRulesfile, parent.GetName(), nodeName),
+			Namespace: parent.GetNamespace(),
+			Labels:    controllerhelper.NodeObjectLabels(controllerhelper.ArtifactKindRulesfile, parent.GetName(), nodeName),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: artifactv1alpha1.GroupVersion.String(), Kind: "Rulesfile", Name: parent.GetName(),
+				UID: parent.GetUID(), Controller: ptr.To(true),
+			}},
+		},
+		Spec:   artifactv1alpha1.ArtifactNodeSpec{NodeName: nodeName},
+		Status: artifactv1alpha1.ArtifactNodeStatus{RevisionDigest: &artifactv1alpha1.RevisionDigest{Revision: revision, Digest: digest}},
+	}
+}
+
+func TestReportRevisionDigest(t *testing.T) {
+	s := revisionScheme(t)
+	ctx := context.Background()
+	rulesfile := newRevisionRulesfile("4", "")
+
+	t.Run("missing ArtifactNode is not an error", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(s).Build()
+		require.NoError(t, controllerhelper.ReportRevisionDigest(ctx, cl, s, controllerhelper.ArtifactKindRulesfile,
+			rulesfile, "node-1", "sha256:aaa", "test-manager"))
+	})
+
+	t.Run("reports the digest of the current revision", func(t *testing.T) {
+		nodeObject := revisionDigestNode(rulesfile, "node-1", "", "")
+		nodeObject.Status.RevisionDigest = nil
+		cl := fake.NewClientBuilder().WithScheme(s).WithObjects(rulesfile, nodeObject).WithStatusSubresource(nodeObject).Build()
+		require.NoError(t, controllerhelper.ReportRevisionDigest(ctx, cl, s, controllerhelper.ArtifactKindRulesfile,
+			rulesfile, "node-1", "sha256:aaa", "test-manager"))
+
+		revisions, _, err := controllerhelper.EnsureRevision(ctx, cl, s, controllerhelper.ArtifactKindRulesfile, rulesfile)
+		require.NoError(t, err)
+		got := &artifactv1alpha1.ArtifactNode{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(nodeObject), got))
+		require.NotNil(t, got.Status.RevisionDigest)
+		assert.Equal(t, artifactv1alpha1.RevisionDigest{Revision: revisions[0].Name, Digest: "sha256:aaa"}, *got.Status.RevisionDigest)
+	})
+}
+
+func TestRecordRevisionDigests(t *testing.T) {
+	s := revisionScheme(t)
+	ctx := context.Background()
+	rulesfile := newRevisionRulesfile("4", "")
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(rulesfile).Build()
+	revisions, _, err := controllerhelper.EnsureRevision(ctx, cl, s, controllerhelper.ArtifactKindRulesfile, rulesfile)
+	require.NoError(t, err)
+	digest := func() string {
+		t.Helper()
+		revisions, err := controllerhelper.ListRevisions(ctx, cl, controllerhelper.ArtifactKindRulesfile, rulesfile)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		return revisions[0].Annotations[controllerhelper.AnnotationRevisionDigest]
+	}
+	record := func() {
+		t.Helper()
+		revisions, err := controllerhelper.ListRevisions(ctx, cl, controllerhelper.ArtifactKindRulesfile, rulesfile)
+		require.NoError(t, err)
+		require.NoError(t, controllerhelper.RecordRevisionDigests(ctx, cl, controllerhelper.ArtifactKindRulesfile, rulesfile, revisions))
+	}
+
+	// Digests reported for unknown revisions or by ArtifactNodes of another parent are ignored.
+	foreign := revisionDigestNode(rulesfile, "node-0", revisions[0].Name, "sha256:ccc")
+	foreign.OwnerReferences[0].UID = "other-uid"
+	require.NoError(t, cl.Create(ctx, foreign))
+	require.NoError(t, cl.Create(ctx, revisionDigestNode(rulesfile, "node-1", "rulesfile--custom--unknown", "sha256:ddd")))
+	record()
+	assert.Empty(t, digest())
+
+	// The first node by name reporting the revision records its digest.
+	require.NoError(t, cl.Create(ctx, revisionDigestNode(rulesfile, "node-3", revisions[0].Name, "sha256:bbb")))
+	require.NoError(t, cl.Create(ctx, revisionDigestNode(rulesfile, "node-2", revisions[0].Name, "sha256:aaa")))
+	record()
+	assert.Equal(t, "sha256:aaa", digest())
+
+	// The first recorded digest is kept.
+	nodeObject := &artifactv1alpha1.ArtifactNode{}
+	key := client.ObjectKey{Namespace: "default", Name: controllerhelper.NodeObjectName(controllerhelper.ArtifactKindRulesfile, "custom", "node-2")}
+	require.NoError(t, cl.Get(ctx, key, nodeObject))
+	nodeObject.Status.RevisionDigest.Digest = "sha256:eee"
+	require.NoError(t, cl.Update(ctx, nodeObject))
+	record()
+	assert.Equal(t, "sha256:aaa", digest())
+}
@@ -0,0 +1,285 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/artifact"
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+)
+
+// AnnotationAdmittedAt is the annotation key storing when the instance operator last raised the
+// AllowedGeneration of an ArtifactNode, in RFC 3339 format. The most recent value among the nodes of a
+// generation marks the admission of the last batch, from which PauseBetweenBatches is measured.
+const AnnotationAdmittedAt = "artifact.falcosecurity.dev/admitted-at"
+
+// RolloutPlan is the next step of the rollout of a generation, as computed by PlanRollout.
+type RolloutPlan struct {
+	// Admit lists the names of the nodes to admit to the generation, sorted.
+	Admit []string
+	// RequeueAfter is the time left before the next batch may be admitted, zero when not waiting on a pause.
+	RequeueAfter time.Duration
+	// Condition is the RolledOut condition to report on the parent artifact.
+	Condition metav1.Condition
+}
+
+// PlanRollout computes the next step of the rollout of generation over nodes, the ArtifactNodes of the
+// parent artifact. A node is admitted once its AllowedGeneration reaches generation, and is done once its
+// Programmed condition is True for generation.
+//
+// Nodes are admitted in batches of rollout.BatchSize, while the admitted nodes not done yet stay within
+// rollout.MaxUnavailable and at least rollout.PauseBetweenBatches after the previous batch. A node whose
+// Programmed condition is False for generation halts the rollout: no more nodes are admitted until a new
+// generation is rolled out.
+func PlanRollout(rollout *commonv1alpha1.Rollout, generation int64, nodes []artifactv1alpha1.ArtifactNode, now time.Time) RolloutPlan {
+	var waiting, failed []string
+	var done, inFlight int
+	var lastAdmission time.Time
+
+	for i := range nodes {
+		nodeObject := &nodes[i]
+		if nodeObject.Spec.AllowedGeneration < generation {
+			waiting = append(waiting, nodeObject.Spec.NodeName)
+			continue
+		}
+		if admittedAt, err := time.Parse(time.RFC3339, nodeObject.Annotations[AnnotationAdmittedAt]); err == nil && admittedAt.After(lastAdmission) {
+			lastAdmission = admittedAt
+		}
+
+		programmed := apimeta.FindStatusCondition(nodeObject.Status.Conditions, commonv1alpha1.ConditionProgrammed.String())
+		switch {
+		case programmed == nil || programmed.ObservedGeneration < generation:
+			inFlight++
+		case programmed.Status == metav1.ConditionTrue:
+			done++
+		case programmed.Status == metav1.ConditionFalse:
+			failed = append(failed, nodeObject.Spec.NodeName)
+		default:
+			inFlight++
+		}
+	}
+
+	total := len(nodes)
+	plan := RolloutPlan{}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		plan.Condition = common.NewRolledOutCondition(metav1.ConditionFalse, artifact.ReasonRolloutHalted,
+			fmt.Sprintf(artifact.MessageFormatRolloutHalted, generation, strings.Join(failed, ", ")), generation)
+		return plan
+	}
+	if len(waiting) == 0 && inFlight == 0 {
+		plan.Condition = common.NewRolledOutCondition(metav1.ConditionTrue, artifact.ReasonRolledOut,
+			fmt.Sprintf(artifact.MessageFormatRolledOut, generation, total), generation)
+		return plan
+	}
+	plan.Condition = common.NewRolledOutCondition(metav1.ConditionFalse, artifact.ReasonRolloutInProgress,
+		fmt.Sprintf(artifact.MessageFormatRolloutInProgress, generation, done, total), generation)
+
+	if len(waiting) == 0 {
+		return plan
+	}
+
+	if pause := rolloutPause(rollout); pause > 0 && !lastAdmission.IsZero() {
+		if next := lastAdmission.Add(pause); now.Before(next) {
+			plan.RequeueAfter = next.Sub(now)
+			return plan
+		}
+	}
+
+	budget := rolloutMaxUnavailable(rollout, total)
+	batch := budget
+	if rollout.BatchSize != nil {
+		batch = int(*rollout.BatchSize)
+	}
+	n := min(batch, budget-inFlight, len(waiting))
+	if n <= 0 {
+		return plan
+	}
+	sort.Strings(waiting)
+	plan.Admit = waiting[:n]
+	return plan
+}
+
+// rolloutMaxUnavailable resolves rollout.MaxUnavailable against the number of nodes, rounding percentages
+// down with a minimum of 1 so that a rollout always progresses.
+func rolloutMaxUnavailable(rollout *commonv1alpha1.Rollout, total int) int {
+	if rollout.MaxUnavailable == nil {
+		return 1
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(rollout.MaxUnavailable, total, false)
+	if err != nil || value < 1 {
+		return 1
+	}
+	return value
+}
+
+func rolloutPause(rollout *commonv1alpha1.Rollout) time.Duration {
+	if rollout.PauseBetweenBatches == nil {
+		return 0
+	}
+	return rollout.PauseBetweenBatches.Duration
+}
+
+// ReconcileRollout drives the rollout of generation over nodes, the active ArtifactNodes of a parent
+// artifact, and returns the RolledOut condition to report on the parent along with the time after which
+// the parent must be reconciled again (zero when the next step is triggered by the nodes themselves).
+// Without a rollout strategy every node is admitted to generation right away and no condition is
+// returned, so that enabling a strategy later starts from the generation the nodes already run.
+func ReconcileRollout(
+	ctx context.Context,
+	cl client.Client,
+	rollout *commonv1alpha1.Rollout,
+	generation int64,
+	nodes []artifactv1alpha1.ArtifactNode,
+	now time.Time,
+) (*metav1.Condition, time.Duration, error) {
+	if rollout == nil {
+		names := make([]string, len(nodes))
+		for i := range nodes {
+			names[i] = nodes[i].Spec.NodeName
+		}
+		return nil, 0, AdmitNodes(ctx, cl, nodes, names, generation, now)
+	}
+
+	plan := PlanRollout(rollout, generation, nodes, now)
+	if err := AdmitNodes(ctx, cl, nodes, plan.Admit, generation, now); err != nil {
+		return nil, 0, err
+	}
+	return &plan.Condition, plan.RequeueAfter, nil
+}
+
+// AdmitNodes raises the AllowedGeneration of the named ArtifactNodes to generation and stamps their
+// admission time. Nodes already admitted to generation are left alone.
+func AdmitNodes(
+	ctx context.Context,
+	cl client.Client,
+	nodes []artifactv1alpha1.ArtifactNode,
+	names []string,
+	generation int64,
+	now time.Time,
+) error {
+	logger := log.FromContext(ctx)
+	admit := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		admit[name] = struct{}{}
+	}
+
+	for i := range nodes {
+		nodeObject := &nodes[i]
+		if _, ok := admit[nodeObject.Spec.NodeName]; !ok || nodeObject.Spec.AllowedGeneration >= generation {
+			continue
+		}
+
+		patch := client.MergeFrom(nodeObject.DeepCopy())
+		nodeObject.Spec.AllowedGeneration = generation
+		if nodeObject.Annotations == nil {
+			nodeObject.Annotations = map[string]string{}
+		}
+		nodeObject.Annotations[AnnotationAdmittedAt] = now.UTC().Format(time.RFC3339)
+		logger.Info("Admitting node to generation", "node", nodeObject.Spec.NodeName, "generation", generation)
+		if err := cl.Patch(ctx, nodeObject, patch); err != nil {
+			logger.Error(err, "unable to admit node to generation", "node", nodeObject.Spec.NodeName, "artifactNode", nodeObject.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// RolloutAdmitted reports whether the artifact operator on nodeName may apply the current generation of
+// parent. Without a rollout strategy every generation is applied as soon as it is observed. With one, the
+// node waits for the instance operator to create its ArtifactNode and admit it to the generation.
+func RolloutAdmitted(
+	ctx context.Context,
+	cl client.Client,
+	artifactKind string,
+	parent client.Object,
+	rollout *commonv1alpha1.Rollout,
+	nodeName string,
+) (bool, error) {
+	if rollout == nil {
+		return true, nil
+	}
+
+	nodeObject := &artifactv1alpha1.ArtifactNode{}
+	key := client.ObjectKey{Namespace: parent.GetNamespace(), Name: NodeObjectName(artifactKind, parent.GetName(), nodeName)}
+	if err := cl.Get(ctx, key, nodeObject); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("fetching ArtifactNode %s: %w", key.Name, err)
+	}
+	return nodeObject.Spec.AllowedGeneration >= parent.GetGeneration(), nil
+}
+
+// SyncNodeProgrammed copies the Programmed condition the artifact operator computed for parent on nodeName
+// onto the ArtifactNode tracking it, so that the instance operator can follow the rollout node by node.
+// Like SyncRestartRequired, a missing ArtifactNode is not an error.
+func SyncNodeProgrammed(
+	ctx context.Context,
+	cl client.Client,
+	scheme *runtime.Scheme,
+	artifactKind string,
+	parent client.Object,
+	conditions []metav1.Condition,
+	nodeName string,
+	fieldManager string,
+) error {
+	programmed := apimeta.FindStatusCondition(conditions, commonv1alpha1.ConditionProgrammed.String())
+	if programmed == nil {
+		return nil
+	}
+
+	nodeObject := &artifactv1alpha1.ArtifactNode{}
+	key := client.ObjectKey{Namespace: parent.GetNamespace(), Name: NodeObjectName(artifactKind, parent.GetName(), nodeName)}
+	if err := cl.Get(ctx, key, nodeObject); err != nil {
+		if k8serrors.IsNotFound(err) {
+			log.FromContext(ctx).V(3).Info("ArtifactNode not found, skipping Programmed condition", "artifactNode", key.Name)
+			return nil
+		}
+		return fmt.Errorf("fetching ArtifactNode %s: %w", key.Name, err)
+	}
+
+	current := apimeta.FindStatusCondition(nodeObject.Status.Conditions, programmed.Type)
+	if current != nil && current.Status == programmed.Status && current.Reason == programmed.Reason &&
+		current.Message == programmed.Message && current.ObservedGeneration == programmed.ObservedGeneration {
+		return nil
+	}
+	apimeta.SetStatusCondition(&nodeObject.Status.Conditions, metav1.Condition{
+		Type:               programmed.Type,
+		Status:             programmed.Status,
+		Reason:             programmed.Reason,
+		Message:            programmed.Message,
+		ObservedGeneration: programmed.ObservedGeneration,
+	})
+	return PatchStatusSSA(ctx, cl, scheme, nodeObject, fieldManager)
+}
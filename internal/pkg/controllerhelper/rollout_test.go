@@ -0,0 +1,301 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+// rolloutNode returns the ArtifactNode of nodeName allowed to apply allowed, with a Programmed
+// condition of the given status for programmedGen when status is not empty.
+func rolloutNode(nodeName string, allowed int64, status metav1.ConditionStatus, programmedGen int64) artifactv1alpha1.ArtifactNode {
+	nodeObject := artifactv1alpha1.ArtifactNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, "engine", nodeName),
+			Namespace: "default",
+		},
+		Spec: artifactv1alpha1.ArtifactNodeSpec{NodeName: nodeName, AllowedGeneration: allowed},
+	}
+	if status != "" {
+		nodeObject.Status.Conditions = []metav1.Condition{{
+			Type:               commonv1alpha1.ConditionProgrammed.String(),
+			Status:             status,
+			Reason:             "Programmed",
+			ObservedGeneration: programmedGen,
+		}}
+	}
+	return nodeObject
+}
+
+func TestPlanRollout(t *testing.T) {
+	const gen = int64(3)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	admittedAt := func(nodeObject artifactv1alpha1.ArtifactNode, at time.Time) artifactv1alpha1.ArtifactNode {
+		nodeObject.Annotations = map[string]string{controllerhelper.AnnotationAdmittedAt: at.Format(time.RFC3339)}
+		return nodeObject
+	}
+
+	tests := []struct {
+		name          string
+		rollout       commonv1alpha1.Rollout
+		nodes         []artifactv1alpha1.ArtifactNode
+		wantAdmit     []string
+		wantRequeue   time.Duration
+		wantStatus    metav1.ConditionStatus
+		wantReason    string
+		wantMessageIn string
+	}{
+		{
+			name:    "defaults admit one node at a time",
+			rollout: commonv1alpha1.Rollout{},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				rolloutNode("node-c", 2, metav1.ConditionTrue, 2),
+				rolloutNode("node-a", 2, metav1.ConditionTrue, 2),
+				rolloutNode("node-b", 2, metav1.ConditionTrue, 2),
+			},
+			wantAdmit:     []string{"node-a"},
+			wantStatus:    metav1.ConditionFalse,
+			wantReason:    "RolloutInProgress",
+			wantMessageIn: "Generation 3 programmed on 0 of 3 nodes",
+		},
+		{
+			name:    "batch size admits several nodes within the budget",
+			rollout: commonv1alpha1.Rollout{MaxUnavailable: ptr.To(intstr.FromInt32(2)), BatchSize: ptr.To[int32](5)},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				rolloutNode("node-a", 2, "", 0),
+				rolloutNode("node-b", 2, "", 0),
+				rolloutNode("node-c", 2, "", 0),
+			},
+			wantAdmit:  []string{"node-a", "node-b"},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "RolloutInProgress",
+		},
+		{
+			name:    "nodes in flight use up the budget",
+			rollout: commonv1alpha1.Rollout{MaxUnavailable: ptr.To(intstr.FromInt32(2))},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				rolloutNode("node-a", gen, metav1.ConditionTrue, gen),
+				rolloutNode("node-b", gen, "", 0),
+				rolloutNode("node-c", 2, metav1.ConditionTrue, 2),
+				rolloutNode("node-d", 2, metav1.ConditionTrue, 2),
+			},
+			wantAdmit:  []string{"node-c"},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "RolloutInProgress",
+		},
+		{
+			name:    "percentages round down to at least one node",
+			rollout: commonv1alpha1.Rollout{MaxUnavailable: ptr.To(intstr.FromString("10%"))},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				rolloutNode("node-a", 2, "", 0),
+				rolloutNode("node-b", 2, "", 0),
+				rolloutNode("node-c", 2, "", 0),
+			},
+			wantAdmit:  []string{"node-a"},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "RolloutInProgress",
+		},
+		{
+			name:    "pause between batches delays the next batch",
+			rollout: commonv1alpha1.Rollout{PauseBetweenBatches: &metav1.Duration{Duration: 10 * time.Minute}},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				admittedAt(rolloutNode("node-a", gen, metav1.ConditionTrue, gen), now.Add(-4*time.Minute)),
+				rolloutNode("node-b", 2, metav1.ConditionTrue, 2),
+			},
+			wantRequeue:   6 * time.Minute,
+			wantStatus:    metav1.ConditionFalse,
+			wantReason:    "RolloutInProgress",
+			wantMessageIn: "Generation 3 programmed on 1 of 2 nodes",
+		},
+		{
+			name:    "elapsed pause admits the next batch",
+			rollout: commonv1alpha1.Rollout{PauseBetweenBatches: &metav1.Duration{Duration: 10 * time.Minute}},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				admittedAt(rolloutNode("node-a", gen, metav1.ConditionTrue, gen), now.Add(-11*time.Minute)),
+				rolloutNode("node-b", 2, metav1.ConditionTrue, 2),
+			},
+			wantAdmit:  []string{"node-b"},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "RolloutInProgress",
+		},
+		{
+			name:    "a node failing to program halts the rollout",
+			rollout: commonv1alpha1.Rollout{MaxUnavailable: ptr.To(intstr.FromInt32(3))},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				rolloutNode("node-a", gen, metav1.ConditionFalse, gen),
+				rolloutNode("node-b", 2, metav1.ConditionTrue, 2),
+			},
+			wantStatus:    metav1.ConditionFalse,
+			wantReason:    "RolloutHalted",
+			wantMessageIn: "node-a",
+		},
+		{
+			name:    "a failure on a previous generation does not halt",
+			rollout: commonv1alpha1.Rollout{},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				rolloutNode("node-a", 2, metav1.ConditionFalse, 2),
+			},
+			wantAdmit:  []string{"node-a"},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "RolloutInProgress",
+		},
+		{
+			name:    "all nodes programmed",
+			rollout: commonv1alpha1.Rollout{},
+			nodes: []artifactv1alpha1.ArtifactNode{
+				rolloutNode("node-a", gen, metav1.ConditionTrue, gen),
+				rolloutNode("node-b", gen, metav1.ConditionTrue, gen),
+			},
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    "RolledOut",
+			wantMessageIn: "Generation 3 programmed on all 2 nodes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := controllerhelper.PlanRollout(&tt.rollout, gen, tt.nodes, now)
+			assert.Equal(t, tt.wantAdmit, plan.Admit)
+			assert.Equal(t, tt.wantRequeue, plan.RequeueAfter)
+			assert.Equal(t, commonv1alpha1.ConditionRolledOut.String(), plan.Condition.Type)
+			assert.Equal(t, tt.wantStatus, plan.Condition.Status)
+			assert.Equal(t, tt.wantReason, plan.Condition.Reason)
+			assert.Contains(t, plan.Condition.Message, tt.wantMessageIn)
+			assert.Equal(t, gen, plan.Condition.ObservedGeneration)
+		})
+	}
+}
+
+func TestReconcileRollout(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, artifactv1alpha1.AddToScheme(s))
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("without a rollout every node is admitted", func(t *testing.T) {
+		nodes := []artifactv1alpha1.ArtifactNode{rolloutNode("node-a", 0, "", 0), rolloutNode("node-b", 2, "", 0)}
+		cl := fake.NewClientBuilder().WithScheme(s).WithObjects(&nodes[0], &nodes[1]).Build()
+
+		cond, requeue, err := controllerhelper.ReconcileRollout(ctx, cl, nil, 2, nodes, now)
+		require.NoError(t, err)
+		assert.Nil(t, cond)
+		assert.Zero(t, requeue)
+		for i := range nodes {
+			got := &artifactv1alpha1.ArtifactNode{}
+			require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(&nodes[i]), got))
+			assert.Equal(t, int64(2), got.Spec.AllowedGeneration)
+		}
+	})
+
+	t.Run("with a rollout only the batch is admitted", func(t *testing.T) {
+		nodes := []artifactv1alpha1.ArtifactNode{rolloutNode("node-a", 1, "", 0), rolloutNode("node-b", 1, "", 0)}
+		cl := fake.NewClientBuilder().WithScheme(s).WithObjects(&nodes[0], &nodes[1]).Build()
+
+		cond, _, err := controllerhelper.ReconcileRollout(ctx, cl, &commonv1alpha1.Rollout{}, 2, nodes, now)
+		require.NoError(t, err)
+		require.NotNil(t, cond)
+		assert.Equal(t, "RolloutInProgress", cond.Reason)
+
+		got := &artifactv1alpha1.ArtifactNode{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(&nodes[0]), got))
+		assert.Equal(t, int64(2), got.Spec.AllowedGeneration)
+		assert.Equal(t, now.Format(time.RFC3339), got.Annotations[controllerhelper.AnnotationAdmittedAt])
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(&nodes[1]), got))
+		assert.Equal(t, int64(1), got.Spec.AllowedGeneration)
+	})
+}
+
+func TestRolloutAdmitted(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, artifactv1alpha1.AddToScheme(s))
+	ctx := context.Background()
+	parent := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: "default", Generation: 2}}
+	rollout := &commonv1alpha1.Rollout{}
+
+	tests := []struct {
+		name    string
+		rollout *commonv1alpha1.Rollout
+		nodes   []client.Object
+		want    bool
+	}{
+		{name: "no rollout", want: true},
+		{name: "missing ArtifactNode", rollout: rollout},
+		{name: "not admitted yet", rollout: rollout, nodes: []client.Object{ptr.To(rolloutNode("node-1", 1, "", 0))}},
+		{name: "admitted", rollout: rollout, nodes: []client.Object{ptr.To(rolloutNode("node-1", 2, "", 0))}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(s).WithObjects(tt.nodes...).Build()
+			got, err := controllerhelper.RolloutAdmitted(ctx, cl, controllerhelper.ArtifactKindConfig, parent, tt.rollout, "node-1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSyncNodeProgrammed(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, artifactv1alpha1.AddToScheme(s))
+	ctx := context.Background()
+	parent := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: "default", Generation: 2}}
+	conditions := []metav1.Condition{{
+		Type:               commonv1alpha1.ConditionProgrammed.String(),
+		Status:             metav1.ConditionFalse,
+		Reason:             "ProgramFailed",
+		Message:            "boom",
+		ObservedGeneration: 2,
+	}}
+
+	t.Run("missing ArtifactNode is not an error", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(s).Build()
+		require.NoError(t, controllerhelper.SyncNodeProgrammed(ctx, cl, s, controllerhelper.ArtifactKindConfig,
+			parent, conditions, "node-1", "test-manager"))
+	})
+
+	t.Run("copies the Programmed condition", func(t *testing.T) {
+		nodeObject := ptr.To(rolloutNode("node-1", 2, metav1.ConditionTrue, 1))
+		cl := fake.NewClientBuilder().WithScheme(s).WithObjects(nodeObject).WithStatusSubresource(nodeObject).Build()
+		require.NoError(t, controllerhelper.SyncNodeProgrammed(ctx, cl, s, controllerhelper.ArtifactKindConfig,
+			parent, conditions, "node-1", "test-manager"))
+
+		got := &artifactv1alpha1.ArtifactNode{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(nodeObject), got))
+		cond := apimeta.FindStatusCondition(got.Status.Conditions, commonv1alpha1.ConditionProgrammed.String())
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, "ProgramFailed", cond.Reason)
+		assert.Equal(t, "boom", cond.Message)
+		assert.Equal(t, int64(2), cond.ObservedGeneration)
+	})
+}
@@ -20,20 +20,30 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 // PatchStatusSSA patches the status subresource of the given object using server-side apply.
 // It converts the object to extract its current status, builds a minimal unstructured
 // apply-configuration containing only identity fields and the status.
-func PatchStatusSSA(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj client.Object, fieldManager string) error {
+func PatchStatusSSA(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj client.Object, fieldManager string) (err error) {
 	logger := log.FromContext(ctx)
 
+	ctx, span := tracing.Start(ctx, "Patch status",
+		attribute.String("k8s.namespace.name", obj.GetNamespace()),
+		attribute.String("k8s.object.name", obj.GetName()),
+		attribute.String("field_manager", fieldManager),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	gvk, err := apiutil.GVKForObject(obj, scheme)
 	if err != nil {
 		logger.Error(err, "unable to resolve GVK for object")
@@ -0,0 +1,134 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+)
+
+// Target is where an artifact applies: the nodes matched by NodeSelector, for the Falco instances referenced by
+// FalcoRef or matched by InstanceSelector. A nil field matches everything.
+type Target struct {
+	NodeSelector     *metav1.LabelSelector
+	FalcoRef         *commonv1alpha1.FalcoRef
+	InstanceSelector *metav1.LabelSelector
+}
+
+// TargetsInstances reports whether the target is restricted to some Falco instances.
+func (t Target) TargetsInstances() bool {
+	return t.FalcoRef != nil || t.InstanceSelector != nil
+}
+
+// InstanceTargeted reports whether the Falco instance with the given name and labels is targeted. An invalid
+// instance selector matches no instance.
+func (t Target) InstanceTargeted(name string, instanceLabels map[string]string) bool {
+	if t.FalcoRef != nil && t.FalcoRef.Name != name {
+		return false
+	}
+	if t.InstanceSelector == nil {
+		return true
+	}
+	selector, err := metav1.LabelSelectorAsSelector(t.InstanceSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(instanceLabels))
+}
+
+// TargetMatches checks if an artifact with the given target applies to the node and to the Falco instance the
+// caller runs for. An artifact restricted to some Falco instances matches no unknown or missing instance.
+func TargetMatches(ctx context.Context, cl client.Client, nodeName string, instance client.ObjectKey, target Target) (bool, error) {
+	if ok, err := NodeMatchesSelector(ctx, cl, nodeName, target.NodeSelector); err != nil || !ok {
+		return ok, err
+	}
+	if !target.TargetsInstances() {
+		return true, nil
+	}
+	if instance.Name == "" {
+		log.FromContext(ctx).V(2).Info("Falco instance unknown, skipping artifact restricted to some instances")
+		return false, nil
+	}
+
+	falco := &instancev1alpha1.Falco{}
+	if err := cl.Get(ctx, instance, falco); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to fetch Falco instance %q: %w", instance.Name, err)
+	}
+	return target.InstanceTargeted(falco.Name, falco.Labels), nil
+}
+
+// NodeObjectTarget returns the Target of the parent artifact of nodeObject, found through its kind and parent
+// labels. found is false when the labels do not name a known artifact or the parent no longer exists.
+func NodeObjectTarget(ctx context.Context, cl client.Client, nodeObject *artifactv1alpha1.ArtifactNode) (target Target, found bool, err error) {
+	key := client.ObjectKey{Namespace: nodeObject.Namespace, Name: nodeObject.Labels[LabelArtifactParent]}
+	if key.Name == "" {
+		return Target{}, false, nil
+	}
+
+	switch nodeObject.Labels[LabelArtifactKind] {
+	case ArtifactKindConfig:
+		parent := &artifactv1alpha1.Config{}
+		if err = cl.Get(ctx, key, parent); err == nil {
+			target = Target{NodeSelector: parent.Spec.Selector, FalcoRef: parent.Spec.FalcoRef, InstanceSelector: parent.Spec.InstanceSelector}
+		}
+	case ArtifactKindPlugin:
+		parent := &artifactv1alpha1.Plugin{}
+		if err = cl.Get(ctx, key, parent); err == nil {
+			target = Target{NodeSelector: parent.Spec.Selector, FalcoRef: parent.Spec.FalcoRef, InstanceSelector: parent.Spec.InstanceSelector}
+		}
+	case ArtifactKindRulesfile:
+		parent := &artifactv1alpha1.Rulesfile{}
+		if err = cl.Get(ctx, key, parent); err == nil {
+			target = Target{NodeSelector: parent.Spec.Selector, FalcoRef: parent.Spec.FalcoRef, InstanceSelector: parent.Spec.InstanceSelector}
+		}
+	default:
+		return Target{}, false, nil
+	}
+
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return Target{}, false, nil
+		}
+		return Target{}, false, fmt.Errorf("unable to fetch parent %q of ArtifactNode %q: %w", key.Name, nodeObject.Name, err)
+	}
+	return target, true, nil
+}
+
+// InstanceLabelsChangedPredicate filters the events of the given Falco instance to its creation, deletion and
+// label changes, which change the artifacts matched by an instance selector.
+func InstanceLabelsChangedPredicate(instance client.ObjectKey) predicate.Predicate {
+	return predicate.And(
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == instance.Name && obj.GetNamespace() == instance.Namespace
+		}),
+		predicate.LabelChangedPredicate{},
+	)
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+func TestTargetInstanceTargeted(t *testing.T) {
+	instanceLabels := map[string]string{"source": "syscall"}
+
+	tests := []struct {
+		name   string
+		target controllerhelper.Target
+		want   bool
+	}{
+		{name: "untargeted", want: true},
+		{
+			name:   "falcoRef matches",
+			target: controllerhelper.Target{FalcoRef: &commonv1alpha1.FalcoRef{Name: "falco"}},
+			want:   true,
+		},
+		{
+			name:   "falcoRef differs",
+			target: controllerhelper.Target{FalcoRef: &commonv1alpha1.FalcoRef{Name: "other"}},
+		},
+		{
+			name: "instanceSelector matches",
+			target: controllerhelper.Target{
+				InstanceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"source": "syscall"}},
+			},
+			want: true,
+		},
+		{
+			name: "instanceSelector differs",
+			target: controllerhelper.Target{
+				InstanceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"source": "k8saudit"}},
+			},
+		},
+		{
+			name: "invalid instanceSelector",
+			target: controllerhelper.Target{InstanceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "source", Operator: "Bogus"}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.target.InstanceTargeted("falco", instanceLabels))
+		})
+	}
+}
+
+func TestTargetMatches(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, instancev1alpha1.AddToScheme(s))
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{"role": "worker"}}}
+	falco := &instancev1alpha1.Falco{ObjectMeta: metav1.ObjectMeta{
+		Name: "falco", Namespace: "default", Labels: map[string]string{"source": "syscall"},
+	}}
+	instance := client.ObjectKeyFromObject(falco)
+	ref := &commonv1alpha1.FalcoRef{Name: "falco"}
+
+	tests := []struct {
+		name     string
+		objects  []client.Object
+		instance client.ObjectKey
+		target   controllerhelper.Target
+		want     bool
+	}{
+		{
+			name:     "untargeted without known instance",
+			objects:  []client.Object{node},
+			target:   controllerhelper.Target{},
+			instance: client.ObjectKey{},
+			want:     true,
+		},
+		{
+			name:     "node selector mismatch",
+			objects:  []client.Object{node, falco},
+			instance: instance,
+			target: controllerhelper.Target{
+				NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "control-plane"}},
+				FalcoRef:     ref,
+			},
+		},
+		{
+			name:     "falcoRef matches instance",
+			objects:  []client.Object{node, falco},
+			instance: instance,
+			target:   controllerhelper.Target{FalcoRef: ref},
+			want:     true,
+		},
+		{
+			name:     "unknown instance",
+			objects:  []client.Object{node, falco},
+			instance: client.ObjectKey{Namespace: "default"},
+			target:   controllerhelper.Target{FalcoRef: ref},
+		},
+		{
+			name:     "missing instance",
+			objects:  []client.Object{node},
+			instance: instance,
+			target:   controllerhelper.Target{InstanceSelector: &metav1.LabelSelector{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(s).WithObjects(tt.objects...).Build()
+			got, err := controllerhelper.TargetMatches(context.Background(), cl, "worker-1", tt.instance, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNodeObjectTarget(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, artifactv1alpha1.AddToScheme(s))
+
+	ref := &commonv1alpha1.FalcoRef{Name: "falco"}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"source": "syscall"}}
+	objects := []client.Object{
+		&artifactv1alpha1.Config{
+			ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: "default"},
+			Spec:       artifactv1alpha1.ConfigSpec{FalcoRef: ref},
+		},
+		&artifactv1alpha1.Plugin{
+			ObjectMeta: metav1.ObjectMeta{Name: "container", Namespace: "default"},
+			Spec:       artifactv1alpha1.PluginSpec{InstanceSelector: selector},
+		},
+		&artifactv1alpha1.Rulesfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: "default"},
+			Spec:       artifactv1alpha1.RulesfileSpec{Selector: selector},
+		},
+	}
+	nodeObject := func(kind, parent string) *artifactv1alpha1.ArtifactNode {
+		return &artifactv1alpha1.ArtifactNode{ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerhelper.NodeObjectName(kind, parent, "worker-1"),
+			Namespace: "default",
+			Labels:    controllerhelper.NodeObjectLabels(kind, parent, "worker-1"),
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		nodeObject *artifactv1alpha1.ArtifactNode
+		want       controllerhelper.Target
+		wantFound  bool
+	}{
+		{
+			name:       "config",
+			nodeObject: nodeObject(controllerhelper.ArtifactKindConfig, "engine"),
+			want:       controllerhelper.Target{FalcoRef: ref},
+			wantFound:  true,
+		},
+		{
+			name:       "plugin",
+			nodeObject: nodeObject(controllerhelper.ArtifactKindPlugin, "container"),
+			want:       controllerhelper.Target{InstanceSelector: selector},
+			wantFound:  true,
+		},
+		{
+			name:       "rulesfile",
+			nodeObject: nodeObject(controllerhelper.ArtifactKindRulesfile, "rules"),
+			want:       controllerhelper.Target{NodeSelector: selector},
+			wantFound:  true,
+		},
+		{
+			name:       "deleted parent",
+			nodeObject: nodeObject(controllerhelper.ArtifactKindConfig, "gone"),
+		},
+		{
+			name:       "unknown kind",
+			nodeObject: nodeObject("unknown", "engine"),
+		},
+		{
+			name:       "unlabeled",
+			nodeObject: &artifactv1alpha1.ArtifactNode{ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "default"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build()
+			got, found, err := controllerhelper.NodeObjectTarget(context.Background(), cl, tt.nodeObject)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInstanceLabelsChangedPredicate(t *testing.T) {
+	p := controllerhelper.InstanceLabelsChangedPredicate(client.ObjectKey{Namespace: "default", Name: "falco"})
+	falco := func(name string, l map[string]string) *instancev1alpha1.Falco {
+		return &instancev1alpha1.Falco{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: l}}
+	}
+
+	assert.True(t, p.Create(event.CreateEvent{Object: falco("falco", nil)}))
+	assert.False(t, p.Create(event.CreateEvent{Object: falco("other", nil)}))
+	assert.True(t, p.Update(event.UpdateEvent{
+		ObjectOld: falco("falco", nil),
+		ObjectNew: falco("falco", map[string]string{"source": "syscall"}),
+	}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: falco("falco", nil), ObjectNew: falco("falco", nil)}))
+	assert.False(t, p.Update(event.UpdateEvent{
+		ObjectOld: falco("other", nil),
+		ObjectNew: falco("other", map[string]string{"source": "syscall"}),
+	}))
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper
+
+import (
+	"context"
+	"maps"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
+)
+
+// AnnotationTraceGeneration is the annotation key storing the parent artifact generation whose
+// aggregation produced the trace context annotations of an ArtifactNode. Sidecars only continue
+// the trace while their artifact is still at that generation, so that periodic resyncs do not
+// keep attaching spans to a stale trace.
+const AnnotationTraceGeneration = "artifact.falcosecurity.dev/trace-generation"
+
+// nodeObjectTraceAnnotations returns the trace context annotations to stamp on the ArtifactNodes
+// of owner, or nil when ctx carries no span (tracing disabled or not sampled).
+func nodeObjectTraceAnnotations(ctx context.Context, owner client.Object) map[string]string {
+	annotations := tracing.InjectAnnotations(ctx)
+	if annotations == nil {
+		return nil
+	}
+	annotations[AnnotationTraceGeneration] = strconv.FormatInt(owner.GetGeneration(), 10)
+	return annotations
+}
+
+// propagateTraceContext stamps the trace context annotations on an existing ArtifactNode the
+// first time the aggregator handles a new generation of its parent. Later reconciliations of
+// the same generation leave the annotations alone, so the node is not patched on every pass.
+func propagateTraceContext(ctx context.Context, c client.Client, node client.Object, annotations map[string]string) error {
+	if annotations == nil || node.GetAnnotations()[AnnotationTraceGeneration] == annotations[AnnotationTraceGeneration] {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopyObject().(client.Object))
+	merged := make(map[string]string, len(node.GetAnnotations())+len(annotations))
+	maps.Copy(merged, node.GetAnnotations())
+	maps.Copy(merged, annotations)
+	if _, ok := annotations[tracing.AnnotationTraceState]; !ok {
+		delete(merged, tracing.AnnotationTraceState)
+	}
+	node.SetAnnotations(merged)
+	return c.Patch(ctx, node, patch)
+}
+
+// NodeObjectTraceContext returns ctx continued from the trace context the aggregator stamped on
+// the ArtifactNode of owner for nodeName. The second return value is false, and ctx is returned
+// unchanged, when the node object cannot be read, carries no trace context, or was stamped for
+// another generation of owner. Lookup failures are only logged: tracing never fails a reconcile.
+func NodeObjectTraceContext(
+	ctx context.Context,
+	cl client.Client,
+	owner client.Object,
+	artifactKind, nodeName string,
+) (context.Context, bool) {
+	nodeObj := &artifactv1alpha1.ArtifactNode{}
+	key := client.ObjectKey{Namespace: owner.GetNamespace(), Name: NodeObjectName(artifactKind, owner.GetName(), nodeName)}
+	if err := cl.Get(ctx, key, nodeObj); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.FromContext(ctx).V(3).Info("Unable to read ArtifactNode trace context", "artifactNode", key.Name, "error", err.Error())
+		}
+		return ctx, false
+	}
+
+	if nodeObj.Annotations[AnnotationTraceGeneration] != strconv.FormatInt(owner.GetGeneration(), 10) {
+		return ctx, false
+	}
+	return tracing.ExtractAnnotations(ctx, nodeObj.Annotations)
+}
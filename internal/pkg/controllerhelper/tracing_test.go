@@ -0,0 +1,165 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerhelper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
+)
+
+// installInMemoryExporter routes the global tracer provider to an in-memory exporter for the test.
+func installInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func getNodeObject(t *testing.T, cl client.Client, name string) *artifactv1alpha1.ArtifactNode {
+	t.Helper()
+	nodeObj := &artifactv1alpha1.ArtifactNode{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, nodeObj))
+	return nodeObj
+}
+
+func TestEnsureNodeObject_PropagatesTraceContext(t *testing.T) {
+	exporter := installInMemoryExporter(t)
+
+	s := newArtifactScheme(t)
+	config := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{
+		Name: "base", Namespace: "default", UID: "config-uid", Generation: 1,
+	}}
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(config).Build()
+	gvk := artifactv1alpha1.GroupVersion.WithKind(controllerhelper.KindConfig)
+	name := controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, "base", "node-1")
+
+	// The aggregator creates the node object within its reconcile span.
+	aggCtx, aggSpan := tracing.Start(context.Background(), "aggregate")
+	require.NoError(t, controllerhelper.EnsureNodeObject(aggCtx, cl, config, gvk, controllerhelper.ArtifactKindConfig, "node-1"))
+	aggSpan.End()
+
+	created := getNodeObject(t, cl, name)
+	assert.Equal(t, "1", created.Annotations[controllerhelper.AnnotationTraceGeneration])
+	firstTraceParent := created.Annotations[tracing.AnnotationTraceParent]
+	require.NotEmpty(t, firstTraceParent)
+
+	// The sidecar continues the aggregator's trace.
+	sidecarCtx, ok := controllerhelper.NodeObjectTraceContext(context.Background(), cl, config, controllerhelper.ArtifactKindConfig, "node-1")
+	require.True(t, ok)
+	_, sidecarSpan := tracing.Start(sidecarCtx, "program")
+	sidecarSpan.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanContext.TraceID(), spans[1].SpanContext.TraceID())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), spans[1].Parent.SpanID())
+
+	// Another pass over the same generation leaves the annotations alone.
+	againCtx, againSpan := tracing.Start(context.Background(), "aggregate")
+	require.NoError(t, controllerhelper.EnsureNodeObject(againCtx, cl, config, gvk, controllerhelper.ArtifactKindConfig, "node-1"))
+	againSpan.End()
+	assert.Equal(t, firstTraceParent, getNodeObject(t, cl, name).Annotations[tracing.AnnotationTraceParent])
+
+	// A new generation is stamped with the new trace context.
+	config.Generation = 2
+	nextCtx, nextSpan := tracing.Start(context.Background(), "aggregate")
+	require.NoError(t, controllerhelper.EnsureNodeObject(nextCtx, cl, config, gvk, controllerhelper.ArtifactKindConfig, "node-1"))
+	nextSpan.End()
+	updated := getNodeObject(t, cl, name)
+	assert.Equal(t, "2", updated.Annotations[controllerhelper.AnnotationTraceGeneration])
+	assert.NotEqual(t, firstTraceParent, updated.Annotations[tracing.AnnotationTraceParent])
+}
+
+func TestEnsureNodeObject_NoSpanNoAnnotations(t *testing.T) {
+	s := newArtifactScheme(t)
+	config := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "default", UID: "config-uid"}}
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(config).Build()
+
+	gvk := artifactv1alpha1.GroupVersion.WithKind(controllerhelper.KindConfig)
+	require.NoError(t, controllerhelper.EnsureNodeObject(context.Background(), cl, config, gvk, controllerhelper.ArtifactKindConfig, "node-1"))
+
+	created := getNodeObject(t, cl, controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, "base", "node-1"))
+	assert.Empty(t, created.Annotations)
+}
+
+func TestNodeObjectTraceContext_NotContinued(t *testing.T) {
+	const traceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		noNode      bool
+	}{
+		{name: "missing node object", noNode: true},
+		{name: "no annotations"},
+		{
+			name: "stale generation",
+			annotations: map[string]string{
+				tracing.AnnotationTraceParent:              traceParent,
+				controllerhelper.AnnotationTraceGeneration: "1",
+			},
+		},
+		{
+			name: "malformed traceparent",
+			annotations: map[string]string{
+				tracing.AnnotationTraceParent:              "garbage",
+				controllerhelper.AnnotationTraceGeneration: "2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newArtifactScheme(t)
+			config := &artifactv1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "default", Generation: 2}}
+			builder := fake.NewClientBuilder().WithScheme(s).WithObjects(config)
+			if !tt.noNode {
+				builder = builder.WithObjects(&artifactv1alpha1.ArtifactNode{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        controllerhelper.NodeObjectName(controllerhelper.ArtifactKindConfig, "base", "node-1"),
+						Namespace:   "default",
+						Annotations: tt.annotations,
+					},
+				})
+			}
+
+			ctx := context.Background()
+			got, ok := controllerhelper.NodeObjectTraceContext(ctx, builder.Build(), config, controllerhelper.ArtifactKindConfig, "node-1")
+			assert.False(t, ok)
+			assert.Equal(t, ctx, got)
+		})
+	}
+}
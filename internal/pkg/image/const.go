@@ -42,6 +42,13 @@ const (
 	// FalcosidekickUITag the default tag used for Falcosidekick UI.
 	FalcosidekickUITag = "2.2.0"
 
+	// PluginRegistry the default registry used for plugins.
+	PluginRegistry = "ghcr.io"
+	// K8smetaPluginRepository the default repository used for the k8smeta plugin.
+	K8smetaPluginRepository = "falcosecurity/plugins/plugin/k8smeta"
+	// K8smetaPluginTag the default tag used for the k8smeta plugin.
+	K8smetaPluginTag = "0.3.1"
+
 	// RedisRegistry the default registry used for Redis.
 	RedisRegistry = "docker.io"
 	// RedisRepository the default repository used for Redis.
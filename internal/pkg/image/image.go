@@ -58,3 +58,13 @@ func VersionFromImage(image string) string {
 	}
 	return ""
 }
+
+// ReplaceVersion returns the image string with its tag replaced by the given version.
+// Images without a tag VersionFromImage can read are returned unchanged.
+func ReplaceVersion(image, version string) string {
+	parts := strings.Split(image, ":")
+	if len(parts) == 2 {
+		return parts[0] + ":" + version
+	}
+	return image
+}
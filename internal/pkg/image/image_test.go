@@ -194,3 +194,36 @@ func TestVersionFromImage(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{
+			name:  "image with tag",
+			image: "mirror.example.com/falcosecurity/falco:0.40.0",
+			want:  "mirror.example.com/falcosecurity/falco:0.41.0",
+		},
+		{
+			name:  "image without tag",
+			image: "docker.io/falcosecurity/falco",
+			want:  "docker.io/falcosecurity/falco",
+		},
+		{
+			name:  "empty string",
+			image: "",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReplaceVersion(tt.image, "0.41.0")
+			if got != tt.want {
+				t.Errorf("ReplaceVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
 )
 
 // Availability holds the computed availability state of a workload.
@@ -35,6 +37,8 @@ type Availability struct {
 	DesiredReplicas     int32
 	AvailableReplicas   int32
 	UnavailableReplicas int32
+	// ConfigRevision is the configuration hash stamped on the pod template of the DaemonSet.
+	ConfigRevision string
 }
 
 // ComputeDeploymentAvailability fetches the Deployment and computes availability.
@@ -64,6 +68,12 @@ func ComputeDeploymentAvailability(ctx context.Context, reader client.Reader,
 		return result, fmt.Errorf("unable to fetch deployment: %w", err)
 	}
 
+	// Without replicas in the spec, e.g. when an autoscaler manages them, the Deployment ones are desired.
+	if specReplicas == nil && deployment.Spec.Replicas != nil {
+		desiredReplicas = *deployment.Spec.Replicas
+		result.DesiredReplicas = desiredReplicas
+	}
+
 	result.AvailableReplicas = deployment.Status.AvailableReplicas
 	result.UnavailableReplicas = deployment.Status.UnavailableReplicas
 
@@ -104,6 +114,7 @@ func ComputeDaemonSetAvailability(ctx context.Context, reader client.Reader,
 	result.DesiredReplicas = daemonset.Status.DesiredNumberScheduled
 	result.AvailableReplicas = daemonset.Status.NumberAvailable
 	result.UnavailableReplicas = daemonset.Status.NumberUnavailable
+	result.ConfigRevision = daemonset.Spec.Template.Annotations[resources.ConfigHashAnnotation]
 
 	if daemonset.Status.DesiredNumberScheduled == daemonset.Status.NumberAvailable {
 		result.ConditionStatus = metav1.ConditionTrue
@@ -117,3 +128,41 @@ func ComputeDaemonSetAvailability(ctx context.Context, reader client.Reader,
 
 	return result, nil
 }
+
+// MergeAvailability combines the availability of the workloads of an instance. The replica counts are
+// summed; the instance is available when every workload is, otherwise the first workload that is not
+// decides the condition.
+func MergeAvailability(results ...Availability) Availability {
+	var merged Availability
+	var unavailable, unknown *Availability
+	for i := range results {
+		result := &results[i]
+		merged.DesiredReplicas += result.DesiredReplicas
+		merged.AvailableReplicas += result.AvailableReplicas
+		merged.UnavailableReplicas += result.UnavailableReplicas
+
+		switch {
+		case result.ConditionStatus == metav1.ConditionUnknown && unknown == nil:
+			unknown = result
+		case result.ConditionStatus == metav1.ConditionFalse && unavailable == nil:
+			unavailable = result
+		}
+	}
+
+	decisive := unknown
+	if decisive == nil {
+		decisive = unavailable
+	}
+	if decisive == nil && len(results) > 0 {
+		decisive = &results[0]
+	}
+	if decisive == nil {
+		merged.ConditionStatus = metav1.ConditionUnknown
+		return merged
+	}
+
+	merged.ConditionStatus = decisive.ConditionStatus
+	merged.Reason = decisive.Reason
+	merged.Message = decisive.Message
+	return merged
+}
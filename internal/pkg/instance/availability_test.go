@@ -24,11 +24,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
 )
 
 func availabilityScheme(t *testing.T) *runtime.Scheme {
@@ -104,6 +107,20 @@ func TestComputeDeploymentAvailability(t *testing.T) {
 			wantAvailable:   1,
 			wantUnavailable: 0,
 		},
+		{
+			name: "uses the deployment replicas when nil",
+			deployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+				Status:     appsv1.DeploymentStatus{ReadyReplicas: 3, AvailableReplicas: 3, UnavailableReplicas: 1},
+			},
+			specReplicas:    nil,
+			wantStatus:      metav1.ConditionFalse,
+			wantReason:      ReasonDeploymentUnavailable,
+			wantDesired:     4,
+			wantAvailable:   3,
+			wantUnavailable: 1,
+		},
 		{
 			name:            "fetch error",
 			specReplicas:    int32Ptr(1),
@@ -168,18 +185,23 @@ func TestComputeDaemonSetAvailability(t *testing.T) {
 		wantDesired     int32
 		wantAvailable   int32
 		wantUnavailable int32
+		wantRevision    string
 	}{
 		{
 			name: "available",
 			daemonset: &appsv1.DaemonSet{
 				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
-				Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberAvailable: 3, NumberUnavailable: 0},
+				Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{resources.ConfigHashAnnotation: "abc123"},
+				}}},
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberAvailable: 3, NumberUnavailable: 0},
 			},
 			wantStatus:      metav1.ConditionTrue,
 			wantReason:      ReasonDaemonSetAvailable,
 			wantDesired:     3,
 			wantAvailable:   3,
 			wantUnavailable: 0,
+			wantRevision:    "abc123",
 		},
 		{
 			name: "unavailable",
@@ -244,9 +266,77 @@ func TestComputeDaemonSetAvailability(t *testing.T) {
 
 			assert.Equal(t, tt.wantStatus, result.ConditionStatus)
 			assert.Equal(t, tt.wantReason, result.Reason)
+			assert.Equal(t, tt.wantRevision, result.ConfigRevision)
 			assert.Equal(t, tt.wantDesired, result.DesiredReplicas)
 			assert.Equal(t, tt.wantAvailable, result.AvailableReplicas)
 			assert.Equal(t, tt.wantUnavailable, result.UnavailableReplicas)
 		})
 	}
 }
+
+func TestMergeAvailability(t *testing.T) {
+	available := Availability{
+		ConditionStatus: metav1.ConditionTrue, Reason: ReasonDaemonSetAvailable, Message: MessageDaemonSetAvailable,
+		DesiredReplicas: 3, AvailableReplicas: 3,
+	}
+	unavailable := Availability{
+		ConditionStatus: metav1.ConditionFalse, Reason: ReasonDaemonSetUnavailable, Message: MessageDaemonSetUnavailable,
+		DesiredReplicas: 2, AvailableReplicas: 1, UnavailableReplicas: 1,
+	}
+	notFound := Availability{
+		ConditionStatus: metav1.ConditionFalse, Reason: ReasonDaemonSetNotFound, Message: MessageDaemonSetNotFound,
+	}
+	unknown := Availability{
+		ConditionStatus: metav1.ConditionUnknown, Reason: ReasonDaemonSetFetchError, Message: "boom",
+	}
+
+	tests := []struct {
+		name       string
+		results    []Availability
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantCounts [3]int32
+	}{
+		{
+			name:       "no results",
+			wantStatus: metav1.ConditionUnknown,
+		},
+		{
+			name:       "single result is kept",
+			results:    []Availability{unavailable},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonDaemonSetUnavailable,
+			wantCounts: [3]int32{2, 1, 1},
+		},
+		{
+			name:       "all available",
+			results:    []Availability{available, available},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonDaemonSetAvailable,
+			wantCounts: [3]int32{6, 6, 0},
+		},
+		{
+			name:       "first unavailable workload decides",
+			results:    []Availability{available, notFound, unavailable},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonDaemonSetNotFound,
+			wantCounts: [3]int32{5, 4, 1},
+		},
+		{
+			name:       "unknown takes precedence",
+			results:    []Availability{unavailable, unknown},
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: ReasonDaemonSetFetchError,
+			wantCounts: [3]int32{2, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeAvailability(tt.results...)
+			assert.Equal(t, tt.wantStatus, got.ConditionStatus)
+			assert.Equal(t, tt.wantReason, got.Reason)
+			assert.Equal(t, tt.wantCounts, [3]int32{got.DesiredReplicas, got.AvailableReplicas, got.UnavailableReplicas})
+		})
+	}
+}
@@ -60,10 +60,122 @@ const (
 	ReasonInstanceDeleted = "InstanceDeleted"
 )
 
+// Plan mode reasons.
+const (
+	// ReasonChangesPending indicates changes were computed but not applied because the instance is reconciled in plan mode.
+	ReasonChangesPending = "ChangesPending"
+)
+
+// Suspension reasons.
+const (
+	// ReasonSuspended indicates reconciliation is suspended by spec.suspend.
+	ReasonSuspended = "Suspended"
+	// ReasonResumed indicates reconciliation resumed after spec.suspend was cleared.
+	ReasonResumed = "Resumed"
+)
+
+// Drift reasons.
+const (
+	// ReasonDriftDetected indicates fields of the generated resources were changed by other field managers.
+	ReasonDriftDetected = "DriftDetected"
+	// ReasonNoDrift indicates no field of the generated resources was changed by other field managers.
+	ReasonNoDrift = "NoDrift"
+	// ReasonInvalidOverlay indicates an overlay cannot be decoded or applied to the resource it targets.
+	ReasonInvalidOverlay = "InvalidOverlay"
+)
+
+// Monitoring reasons.
+const (
+	// ReasonMonitoringNotInstalled indicates a monitoring object is enabled but its prometheus-operator CRD is not installed.
+	ReasonMonitoringNotInstalled = "MonitoringNotInstalled"
+	// ReasonMonitoringCleanup indicates a monitoring object no longer enabled was cleaned up.
+	ReasonMonitoringCleanup = "MonitoringCleanup"
+)
+
+// NetworkPolicy reasons.
+const (
+	// ReasonNetworkPolicyCleanup indicates the NetworkPolicy no longer enabled was cleaned up.
+	ReasonNetworkPolicyCleanup = "NetworkPolicyCleanup"
+)
+
+// TLS reasons.
+const (
+	// ReasonCAIssued indicates the CA signing the certificates of the namespace was issued or renewed.
+	ReasonCAIssued = "CAIssued"
+	// ReasonCertificateCleanup indicates the certificate Secret no longer enabled was cleaned up.
+	ReasonCertificateCleanup = "CertificateCleanup"
+)
+
+// Metacollector reasons.
+const (
+	// ReasonCollectorAvailable indicates the referenced metacollector is available.
+	ReasonCollectorAvailable = "CollectorAvailable"
+	// ReasonCollectorNotFound indicates the referenced metacollector Component does not exist.
+	ReasonCollectorNotFound = "CollectorNotFound"
+	// ReasonCollectorInvalidType indicates the referenced Component is not a metacollector.
+	ReasonCollectorInvalidType = "CollectorInvalidType"
+	// ReasonCollectorUnavailable indicates the referenced metacollector is not available.
+	ReasonCollectorUnavailable = "CollectorUnavailable"
+	// ReasonCollectorPluginCleanup indicates the k8smeta Plugin no longer needed was cleaned up.
+	ReasonCollectorPluginCleanup = "CollectorPluginCleanup"
+)
+
+// Scaling reasons.
+const (
+	// ReasonPodDisruptionBudgetCleanup indicates the PodDisruptionBudget no longer needed was cleaned up.
+	ReasonPodDisruptionBudgetCleanup = "PodDisruptionBudgetCleanup"
+	// ReasonAutoscalerCleanup indicates the HorizontalPodAutoscaler no longer enabled was cleaned up.
+	ReasonAutoscalerCleanup = "AutoscalerCleanup"
+)
+
 // Dual deployment cleanup reasons.
 const (
 	// ReasonDualDeploymentCleanup indicates a dual deployment was cleaned up during resource type switch.
 	ReasonDualDeploymentCleanup = "DualDeploymentCleanup"
+	// ReasonNodePoolCleanup indicates a resource of a node pool no longer in the spec was cleaned up.
+	ReasonNodePoolCleanup = "NodePoolCleanup"
+	// ReasonUpgradeStarted indicates a version upgrade started with its canary phase.
+	ReasonUpgradeStarted = "UpgradeStarted"
+	// ReasonUpgradeSoaking indicates the canary pods are healthy and soaking.
+	ReasonUpgradeSoaking = "UpgradeSoaking"
+	// ReasonUpgradePromoted indicates the new version is rolled out to every node.
+	ReasonUpgradePromoted = "UpgradePromoted"
+	// ReasonUpgradeCompleted indicates every node runs the new version.
+	ReasonUpgradeCompleted = "UpgradeCompleted"
+	// ReasonUpgradeRolledBack indicates the previous version was restored.
+	ReasonUpgradeRolledBack = "UpgradeRolledBack"
+	// ReasonUpgradeFailed indicates the canary failed and the upgrade is halted.
+	ReasonUpgradeFailed = "UpgradeFailed"
+	// ReasonCanaryPodReplaced indicates a pod of a canary node was deleted to run the new version.
+	ReasonCanaryPodReplaced = "CanaryPodReplaced"
+)
+
+// Restart reasons.
+const (
+	// ReasonFalcoPodRestarted indicates a Falco pod was deleted to apply a change it cannot hot-reload.
+	ReasonFalcoPodRestarted = "FalcoPodRestarted"
+	// ReasonFalcoPodRestartError indicates an error deleting a Falco pod that requires a restart.
+	ReasonFalcoPodRestartError = "FalcoPodRestartError"
+)
+
+// Health check condition reasons.
+const (
+	// ReasonHealthNotScraped indicates no Falco pod could be scraped yet.
+	ReasonHealthNotScraped = "NotScraped"
+	// ReasonRulesLoaded indicates every scraped Falco pod has rules files loaded.
+	ReasonRulesLoaded = "RulesLoaded"
+	// ReasonNoRulesLoaded indicates one or more Falco pods have no rules files loaded.
+	ReasonNoRulesLoaded = "NoRulesLoaded"
+	// ReasonRulesNotReported indicates Falco does not report its loaded rules files.
+	ReasonRulesNotReported = "RulesNotReported"
+	// ReasonEventDropsWithinThreshold indicates every scraped Falco pod is within the allowed drop rate.
+	ReasonEventDropsWithinThreshold = "DropsWithinThreshold"
+	// ReasonEventDropsAboveThreshold indicates one or more Falco pods exceed the allowed drop rate.
+	ReasonEventDropsAboveThreshold = "DropsAboveThreshold"
+	// ReasonFalcoHealthy indicates every scraped Falco pod is healthy.
+	ReasonFalcoHealthy = "FalcoHealthy"
+	// ReasonFalcoDegraded indicates one or more Falco pods are unhealthy.
+	ReasonFalcoDegraded = "FalcoDegraded"
 )
 
 // Available condition reasons (Deployment).
@@ -112,6 +224,14 @@ const (
 	MessageFormatResourceUpdated = "Resource updated successfully (changed: %s)"
 	// MessageResourceUpToDate is the message when resource is up to date.
 	MessageResourceUpToDate = "Resource is up to date"
+	// MessageHealthNotScraped is the message when no Falco pod could be scraped yet.
+	MessageHealthNotScraped = "No Falco pod could be scraped yet"
+	// MessageRulesLoaded is the message when every scraped Falco pod has rules files loaded.
+	MessageRulesLoaded = "Every scraped Falco pod has rules files loaded"
+	// MessageRulesNotReported is the message when Falco does not report its loaded rules files.
+	MessageRulesNotReported = "Falco does not report its loaded rules files"
+	// MessageFalcoHealthy is the message when every scraped Falco pod is healthy.
+	MessageFalcoHealthy = "Every scraped Falco pod is healthy"
 	// MessageFalcoInstanceDeleted is the message when a Falco instance is deleted.
 	MessageFalcoInstanceDeleted = "Falco instance deleted successfully"
 	// MessageFormatComponentInstanceDeleted is the format for the message when a Component instance is deleted.
@@ -150,4 +270,88 @@ const (
 	MessageFormatDeletionError = "Unable to delete %s during cleanup: %s"
 	// MessageFormatDualDeploymentCleanup is the format for dual deployment cleanup message.
 	MessageFormatDualDeploymentCleanup = "Deleted %s due to resource type switch"
+	// MessageFormatNodePoolCleanup is the format for node pool cleanup message.
+	MessageFormatNodePoolCleanup = "Deleted %s %s of removed node pool %s"
+	// MessageFormatMonitoringNotInstalled is the format for a monitoring object whose CRD is not installed.
+	MessageFormatMonitoringNotInstalled = "%s not generated, the prometheus-operator CRD is not installed"
+	// MessageFormatDisabledCleanup is the format for the cleanup message of a resource no longer enabled.
+	MessageFormatDisabledCleanup = "Deleted %s %s, no longer enabled"
+	// MessageFormatCAIssued is the format for the CA issued message.
+	MessageFormatCAIssued = "Issued the CA of the namespace in Secret %s"
+	// MessageFormatCollectorAvailable is the format for the available metacollector message.
+	MessageFormatCollectorAvailable = "Metacollector %s is available"
+	// MessageFormatCollectorNotFound is the format for the missing metacollector message.
+	MessageFormatCollectorNotFound = "Component %s not found"
+	// MessageFormatCollectorInvalidType is the format for the referenced Component not being a metacollector.
+	MessageFormatCollectorInvalidType = "Component %s is of type %s, not metacollector"
+	// MessageFormatCollectorUnavailable is the format for the unavailable metacollector message.
+	MessageFormatCollectorUnavailable = "Metacollector %s is not available"
+	// MessageFormatChangesPending is the format for the changes pending in plan mode.
+	MessageFormatChangesPending = "Changes pending in plan mode (%d): %s"
+	// MessageSuspended is the message when reconciliation is suspended.
+	MessageSuspended = "Reconciliation is suspended, the generated resources are not applied"
+	// MessageResumed is the message when reconciliation resumes.
+	MessageResumed = "Reconciliation resumed"
+	// MessageFormatDriftReverted is the format for the drift reverted to the desired state.
+	MessageFormatDriftReverted = "Drift reverted: %s"
+	// MessageFormatDriftReported is the format for the drift left in place by the report drift policy.
+	MessageFormatDriftReported = "Drift reported, not reverted: %s"
+	// MessageNoDrift is the message when no drift is detected.
+	MessageNoDrift = "No field of the generated resources was changed by other field managers"
+	// MessageFormatInvalidOverlay is the format for an overlay that cannot be decoded or applied.
+	MessageFormatInvalidOverlay = "Invalid overlay, fix spec.overlays: %s"
+	// MessageFormatUpgradeStarted is the format for upgrade started message.
+	MessageFormatUpgradeStarted = "Upgrading Falco from %s to %s on canary nodes %s"
+	// MessageFormatUpgradeSoaking is the format for upgrade soaking message.
+	MessageFormatUpgradeSoaking = "Canary nodes healthy on %s, soaking for %s"
+	// MessageFormatUpgradePromoted is the format for upgrade promoted message.
+	MessageFormatUpgradePromoted = "Canary soaked, rolling out %s to every node"
+	// MessageFormatUpgradeCompleted is the format for upgrade completed message.
+	MessageFormatUpgradeCompleted = "Every node runs Falco %s"
+	// MessageFormatUpgradeRolledBack is the format for upgrade rolled back message.
+	MessageFormatUpgradeRolledBack = "Rolled Falco back to %s: %s"
+	// MessageFormatUpgradeFailed is the format for upgrade failed message.
+	MessageFormatUpgradeFailed = "Halted the upgrade of Falco to %s: %s"
+	// MessageFormatCanaryPodReplaced is the format for canary pod replaced message.
+	MessageFormatCanaryPodReplaced = "Deleted Falco pod %s on canary node %s to run %s"
+	// MessageNoCanaryNodes is the message when no node running Falco matches the canary.
+	MessageNoCanaryNodes = "no node running Falco matches the canary"
+	// MessageFormatUpgradeCancelled is the message when the version is reverted during an upgrade.
+	MessageFormatUpgradeCancelled = "upgrade to %s cancelled"
+	// MessageFormatCanaryDeadlineExceeded is the message when the canary does not become healthy in time.
+	MessageFormatCanaryDeadlineExceeded = "canary not ready and healthy within %s: %s"
+	// MessageFormatCanaryUnhealthy is the message when the canary becomes unhealthy while soaking.
+	MessageFormatCanaryUnhealthy = "canary unhealthy while soaking: %s"
+	// MessageFormatCanaryPodMissing is the format when no Falco pod runs on a canary node.
+	MessageFormatCanaryPodMissing = "no Falco pod on node %s"
+	// MessageFormatCanaryPodVersion is the format when a canary pod does not run the new version yet.
+	MessageFormatCanaryPodVersion = "pod %s on node %s runs version %q"
+	// MessageFormatCanaryPodNotReady is the format when a canary pod is not ready.
+	MessageFormatCanaryPodNotReady = "pod %s on node %s is not ready"
+	// MessageFormatCanaryNotScraped is the format when the health of a canary pod is not known yet.
+	MessageFormatCanaryNotScraped = "health of pod %s on node %s not scraped yet"
+	// MessageFormatCanaryUnhealthyNode is the format when Falco is unhealthy on a canary node.
+	MessageFormatCanaryUnhealthyNode = "Falco is unhealthy on node %s: %s"
+	// MessageFormatFalcoNotAvailable is the format when the Falco instance is not available.
+	MessageFormatFalcoNotAvailable = "Falco is not available: %s"
+	// MessageFormatRolloutPending is the format when pods still run another version during the rollout.
+	MessageFormatRolloutPending = "%d pods do not run %s yet"
+	// MessageFormatNoRulesLoaded is the format for the message when Falco pods have no rules files loaded.
+	MessageFormatNoRulesLoaded = "No rules files loaded on nodes: %s"
+	// MessageFormatEventDropsWithinThreshold is the format for the message when drops are within the threshold.
+	MessageFormatEventDropsWithinThreshold = "Every scraped Falco pod drops at most %d%% of events"
+	// MessageFormatEventDropsAboveThreshold is the format for the message when drops exceed the threshold.
+	MessageFormatEventDropsAboveThreshold = "Falco drops more than %d%% of events on nodes: %s"
+	// MessageFormatFalcoDegraded is the format for the message when Falco pods are unhealthy.
+	MessageFormatFalcoDegraded = "Falco is unhealthy on nodes: %s"
+	// MessageFormatHealthCheckFailed is the format for the node message when scraping a Falco pod fails.
+	MessageFormatHealthCheckFailed = "health check failed: %s"
+	// MessageNoRulesFilesLoaded is the node message when a Falco pod has no rules files loaded.
+	MessageNoRulesFilesLoaded = "no rules files loaded"
+	// MessageFormatDroppingEvents is the node message when a Falco pod exceeds the allowed drop rate.
+	MessageFormatDroppingEvents = "dropping %s of events"
+	// MessageFormatFalcoPodRestarted is the format for the message when a Falco pod is restarted.
+	MessageFormatFalcoPodRestarted = "Restarted pod %s on node %s: %s"
+	// MessageFormatFalcoPodRestartError is the format for Falco pod restart error message.
+	MessageFormatFalcoPodRestartError = "Unable to restart pod %s on node %s: %s"
 )
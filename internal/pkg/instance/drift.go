@@ -0,0 +1,178 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+	"github.com/falcosecurity/falco-operator/internal/pkg/managedfields"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
+)
+
+// DriftedResource is a generated resource whose fields were changed by another field manager.
+type DriftedResource struct {
+	// Kind is the kind of the resource.
+	Kind string
+	// Name is the name of the resource.
+	Name string
+	// Manager is the field manager that changed the fields.
+	Manager string
+	// Fields are the changed fields.
+	Fields []string
+}
+
+// String returns a human-readable description of the drift.
+func (d DriftedResource) String() string {
+	return fmt.Sprintf("%s %s: %s (%s)", d.Kind, d.Name, strings.Join(d.Fields, ", "), d.Manager)
+}
+
+// Drift collects the fields of the generated resources changed by other field managers
+// while an instance is reconciled.
+type Drift struct {
+	report    bool
+	resources []DriftedResource
+}
+
+// NewDrift returns a drift collector for the given drift policy. A nil policy reverts the drift.
+func NewDrift(policy *commonv1alpha1.DriftPolicy) *Drift {
+	return &Drift{report: policy != nil && *policy == commonv1alpha1.DriftPolicyReport}
+}
+
+// Resources returns the drifted resources in the order they were detected.
+func (d *Drift) Resources() []DriftedResource {
+	return d.resources
+}
+
+// Check records the fields of the desired resource that other field managers changed on the existing one
+// and returns the resource to apply. With the report policy, the changed fields are removed from the
+// returned resource so that the apply leaves them to the managers that changed them.
+func (d *Drift) Check(existing runtime.Object, desired *unstructured.Unstructured,
+	fieldManager string) (*unstructured.Unstructured, error) {
+	foreign, err := managedfields.ForeignFields(existing, desired, fieldManager)
+	if err != nil {
+		return nil, fmt.Errorf("unable to detect drift of %s: %w", desired.GetKind(), err)
+	}
+	if len(foreign) == 0 {
+		return desired, nil
+	}
+
+	drifted := &fieldpath.Set{}
+	for _, manager := range slices.Sorted(maps.Keys(foreign)) {
+		var fields []string
+		foreign[manager].Iterate(func(path fieldpath.Path) {
+			fields = append(fields, path.String())
+		})
+		d.resources = append(d.resources, DriftedResource{
+			Kind:    desired.GetKind(),
+			Name:    desired.GetName(),
+			Manager: manager,
+			Fields:  fields,
+		})
+		drifted = drifted.Union(foreign[manager])
+	}
+
+	if !d.report {
+		return desired, nil
+	}
+	return managedfields.RemoveFields(desired, drifted)
+}
+
+// driftContextKey is the context key of the drift collector.
+type driftContextKey struct{}
+
+// DriftIntoContext returns a copy of ctx carrying the drift collector. The drift of the resources
+// ensured with the returned context is recorded in the collector.
+func DriftIntoContext(ctx context.Context, drift *Drift) context.Context {
+	return context.WithValue(ctx, driftContextKey{}, drift)
+}
+
+// DriftFromContext returns the drift collector carried by ctx, or nil when drift is not detected.
+func DriftFromContext(ctx context.Context) *Drift {
+	drift, _ := ctx.Value(driftContextKey{}).(*Drift)
+	return drift
+}
+
+// RecordDrift sets the Drifted condition of the owner from the drift collected while it was reconciled.
+// A warning event is recorded and the drift counter is incremented for each drifted resource that
+// the previous condition did not report yet.
+func RecordDrift(recorder events.EventRecorder, owner client.Object, conditions *[]metav1.Condition, drift *Drift) {
+	if drift == nil {
+		return
+	}
+	if len(drift.Resources()) == 0 {
+		apimeta.SetStatusCondition(conditions, common.NewDriftedCondition(
+			metav1.ConditionFalse, ReasonNoDrift, MessageNoDrift, owner.GetGeneration()))
+		return
+	}
+
+	var previous string
+	if cond := apimeta.FindStatusCondition(*conditions, commonv1alpha1.ConditionDrifted.String()); cond != nil &&
+		cond.Status == metav1.ConditionTrue {
+		previous = cond.Message
+	}
+
+	summary := make([]string, 0, len(drift.Resources()))
+	for _, resource := range drift.Resources() {
+		summary = append(summary, resource.String())
+	}
+	format := MessageFormatDriftReverted
+	if drift.report {
+		format = MessageFormatDriftReported
+	}
+	message := fmt.Sprintf(format, strings.Join(summary, "; "))
+
+	for i, resource := range drift.Resources() {
+		if strings.Contains(previous, summary[i]) {
+			continue
+		}
+		metrics.DriftDetectedTotal.WithLabelValues(resource.Kind).Inc()
+		recorder.Eventf(owner, nil, corev1.EventTypeWarning, ReasonDriftDetected, ReasonDriftDetected,
+			format, summary[i])
+	}
+
+	apimeta.SetStatusCondition(conditions, common.NewDriftedCondition(
+		metav1.ConditionTrue, ReasonDriftDetected, message, owner.GetGeneration()))
+}
+
+// CheckDrift records the drift of the existing resource in the drift collector carried by ctx and returns
+// the resource to apply. The desired resource is returned as is when ctx carries no collector or when
+// the existing resource was never applied by fieldManager.
+func CheckDrift(ctx context.Context, existing client.Object, desired *unstructured.Unstructured,
+	fieldManager string) (*unstructured.Unstructured, error) {
+	drift := DriftFromContext(ctx)
+	if drift == nil || !slices.ContainsFunc(existing.GetManagedFields(), func(mf metav1.ManagedFieldsEntry) bool {
+		return mf.Manager == fieldManager
+	}) {
+		return desired, nil
+	}
+	return drift.Check(existing, desired, fieldManager)
+}
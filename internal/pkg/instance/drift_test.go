@@ -0,0 +1,207 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/builders"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
+)
+
+const (
+	ourLabelFields  = `{"f:metadata":{"f:labels":{"f:app":{}}}}`
+	teamLabelFields = `{"f:metadata":{"f:labels":{"f:team":{}}}}`
+)
+
+// driftedServiceAccount returns a ServiceAccount applied by test-manager whose app label was changed by kubectl-edit.
+func driftedServiceAccount(t *testing.T) *unstructured.Unstructured {
+	t.Helper()
+	sa := builders.NewServiceAccount().WithName("test").WithNamespace("default").
+		WithLabels(map[string]string{"app": "edited", "team": "falco"}).Build()
+	sa.ManagedFields = []metav1.ManagedFieldsEntry{
+		{
+			Manager: "test-manager", Operation: metav1.ManagedFieldsOperationApply, FieldsType: "FieldsV1",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(teamLabelFields)},
+		},
+		{
+			Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(ourLabelFields)},
+		},
+	}
+	u, err := controllerhelper.ToUnstructured(sa)
+	require.NoError(t, err)
+	return u
+}
+
+// desiredServiceAccount returns the ServiceAccount test-manager applies.
+func desiredServiceAccount(t *testing.T) *unstructured.Unstructured {
+	t.Helper()
+	u, err := controllerhelper.ToUnstructured(builders.NewServiceAccount().WithName("test").WithNamespace("default").
+		WithLabels(map[string]string{"app": "falco", "team": "falco"}).Build())
+	require.NoError(t, err)
+	return u
+}
+
+func TestDriftCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    *commonv1alpha1.DriftPolicy
+		wantLabel bool
+	}{
+		{name: "revert by default keeps the drifted fields", wantLabel: true},
+		{name: "revert keeps the drifted fields", policy: new(commonv1alpha1.DriftPolicyRevert), wantLabel: true},
+		{name: "report removes the drifted fields", policy: new(commonv1alpha1.DriftPolicyReport)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := NewDrift(tt.policy)
+
+			applied, err := drift.Check(driftedServiceAccount(t), desiredServiceAccount(t), "test-manager")
+			require.NoError(t, err)
+
+			require.Len(t, drift.Resources(), 1)
+			assert.Equal(t, DriftedResource{
+				Kind: "ServiceAccount", Name: "test", Manager: "kubectl-edit", Fields: []string{".metadata.labels.app"},
+			}, drift.Resources()[0])
+
+			_, found := applied.GetLabels()["app"]
+			assert.Equal(t, tt.wantLabel, found)
+			assert.Equal(t, "falco", applied.GetLabels()["team"])
+		})
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	t.Run("without collector returns the desired resource", func(t *testing.T) {
+		desired := desiredServiceAccount(t)
+
+		applied, err := CheckDrift(context.Background(), driftedServiceAccount(t), desired, "test-manager")
+		require.NoError(t, err)
+		assert.Same(t, desired, applied)
+	})
+
+	t.Run("ignores resources never applied by the field manager", func(t *testing.T) {
+		drift := NewDrift(new(commonv1alpha1.DriftPolicyReport))
+		desired := desiredServiceAccount(t)
+
+		applied, err := CheckDrift(DriftIntoContext(context.Background(), drift), driftedServiceAccount(t),
+			desired, "other-manager")
+		require.NoError(t, err)
+		assert.Same(t, desired, applied)
+		assert.Empty(t, drift.Resources())
+	})
+
+	t.Run("records the drift in the collector", func(t *testing.T) {
+		drift := NewDrift(nil)
+		ctx := DriftIntoContext(context.Background(), drift)
+		assert.Same(t, drift, DriftFromContext(ctx))
+
+		_, err := CheckDrift(ctx, driftedServiceAccount(t), desiredServiceAccount(t), "test-manager")
+		require.NoError(t, err)
+		assert.Len(t, drift.Resources(), 1)
+	})
+}
+
+func TestRecordDrift(t *testing.T) {
+	drifted := DriftedResource{Kind: "ServiceAccount", Name: "test", Manager: "kubectl-edit", Fields: []string{".metadata.labels.app"}}
+
+	tests := []struct {
+		name        string
+		policy      *commonv1alpha1.DriftPolicy
+		resources   []DriftedResource
+		previous    *metav1.Condition
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantMessage string
+		wantEvents  int
+	}{
+		{
+			name:        "no drift",
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  ReasonNoDrift,
+			wantMessage: MessageNoDrift,
+		},
+		{
+			name:        "new drift reverted",
+			resources:   []DriftedResource{drifted},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonDriftDetected,
+			wantMessage: "Drift reverted: ServiceAccount test: .metadata.labels.app (kubectl-edit)",
+			wantEvents:  1,
+		},
+		{
+			name:      "drift already reported",
+			policy:    new(commonv1alpha1.DriftPolicyReport),
+			resources: []DriftedResource{drifted},
+			previous: &metav1.Condition{
+				Type: commonv1alpha1.ConditionDrifted.String(), Status: metav1.ConditionTrue, Reason: ReasonDriftDetected,
+				Message: "Drift reported, not reverted: ServiceAccount test: .metadata.labels.app (kubectl-edit)",
+			},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonDriftDetected,
+			wantMessage: "Drift reported, not reverted: ServiceAccount test: .metadata.labels.app (kubectl-edit)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := &instancev1alpha1.Falco{}
+			if tt.previous != nil {
+				apimeta.SetStatusCondition(&falco.Status.Conditions, *tt.previous)
+			}
+			drift := NewDrift(tt.policy)
+			drift.resources = tt.resources
+			recorder := events.NewFakeRecorder(10)
+			before := testutil.ToFloat64(metrics.DriftDetectedTotal.WithLabelValues("ServiceAccount"))
+
+			RecordDrift(recorder, falco, &falco.Status.Conditions, drift)
+
+			cond := apimeta.FindStatusCondition(falco.Status.Conditions, commonv1alpha1.ConditionDrifted.String())
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.wantStatus, cond.Status)
+			assert.Equal(t, tt.wantReason, cond.Reason)
+			assert.Equal(t, tt.wantMessage, cond.Message)
+			assert.Len(t, recorder.Events, tt.wantEvents)
+			if tt.wantEvents > 0 {
+				assert.Contains(t, <-recorder.Events, corev1.EventTypeWarning+" "+ReasonDriftDetected)
+			}
+			assert.Equal(t, before+float64(tt.wantEvents),
+				testutil.ToFloat64(metrics.DriftDetectedTotal.WithLabelValues("ServiceAccount")))
+		})
+	}
+
+	t.Run("nil drift leaves the conditions untouched", func(t *testing.T) {
+		falco := &instancev1alpha1.Falco{}
+		RecordDrift(events.NewFakeRecorder(1), falco, &falco.Status.Conditions, nil)
+		assert.Empty(t, falco.Status.Conditions)
+	})
+}
@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
 	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
 )
@@ -107,10 +108,15 @@ type GenerateOptions struct {
 	SetControllerRef bool
 	// IsClusterScoped indicates whether the resource is cluster-scoped.
 	IsClusterScoped bool
+	// KeepName keeps the name set on the resource instead of deriving it from the owner.
+	KeepName bool
+	// Overlays are the overlays of the owner, applied to the resource once named.
+	Overlays []commonv1alpha1.Overlay
 }
 
 // PrepareResource converts a runtime.Object into an unstructured resource ready for server-side apply.
-// It optionally sets the controller reference and sets the name based on the resource scope.
+// It optionally sets the controller reference, sets the name based on the resource scope and
+// applies the overlays targeting the resource.
 func PrepareResource(
 	cl client.Client,
 	owner client.Object,
@@ -143,18 +149,20 @@ func PrepareResource(
 	}
 
 	// Set the name based on the resource scope.
-	if options.IsClusterScoped {
+	switch {
+	case options.KeepName:
+	case options.IsClusterScoped:
 		resourceName := resources.GenerateUniqueName(owner.GetName(), owner.GetNamespace())
 		if err := unstructured.SetNestedField(unstructuredObj.Object, resourceName, "metadata", "name"); err != nil {
 			return nil, fmt.Errorf("failed to set name field for cluster-scoped resource: %w", err)
 		}
-	} else {
+	default:
 		if err := unstructured.SetNestedField(unstructuredObj.Object, owner.GetName(), "metadata", "name"); err != nil {
 			return nil, fmt.Errorf("failed to set name field for namespaced resource: %w", err)
 		}
 	}
 
-	return unstructuredObj, nil
+	return ApplyOverlays(cl.Scheme(), unstructuredObj, options.Overlays)
 }
 
 // EnsureResource prepares a runtime.Object for server-side apply, diffs it against
@@ -183,6 +191,7 @@ func EnsureResource(ctx context.Context, cl client.Client, recorder events.Event
 		if k8serrors.IsNotFound(err) {
 			resourceExists = false
 		} else {
+			recordEnsureOutcome(resourceType, ensureOutcomeFailed)
 			return fmt.Errorf("unable to fetch existing %s: %w", resourceType, err)
 		}
 	}
@@ -192,25 +201,44 @@ func EnsureResource(ctx context.Context, cl client.Client, recorder events.Event
 	// See: https://github.com/kubernetes/kubernetes/issues/124605
 	var changedFields string
 	if resourceExists {
+		if desiredResource, err = CheckDrift(ctx, existingResource, desiredResource, fieldManager); err != nil {
+			recordEnsureOutcome(resourceType, ensureOutcomeFailed)
+			return err
+		}
 		comparison, err := controllerhelper.Diff(existingResource, desiredResource, fieldManager)
 		if err != nil {
 			if !errors.Is(err, controllerhelper.ErrNoManagedFields) {
+				recordEnsureOutcome(resourceType, ensureOutcomeFailed)
 				return fmt.Errorf("unable to compare existing %s with desired state: %w", resourceType, err)
 			}
 			logger.V(3).Info("No managed fields found, proceeding with apply to take ownership", "type", resourceType, "name", desiredResource.GetName())
 		} else {
 			if comparison.IsSame() {
 				logger.V(3).Info(resourceType+" is up to date, skipping apply", "name", desiredResource.GetName())
+				recordEnsureOutcome(resourceType, ensureOutcomeSkipped)
 				return nil
 			}
 			changedFields = controllerhelper.FormatChangedFields(comparison)
 		}
 	}
 
+	// In plan mode, record the change instead of applying it.
+	if plan := PlanFromContext(ctx); plan != nil {
+		action := commonv1alpha1.PendingActionUpdate
+		if !resourceExists {
+			action = commonv1alpha1.PendingActionCreate
+		}
+		logger.V(3).Info(resourceType+" change planned", "name", desiredResource.GetName(), "action", action)
+		plan.Add(action, resourceType, desiredResource.GetName(), changedFields)
+		recordEnsureOutcome(resourceType, ensureOutcomePlanned)
+		return nil
+	}
+
 	applyOpts := []client.ApplyOption{client.ForceOwnership, client.FieldOwner(fieldManager)}
 	if err := cl.Apply(ctx, client.ApplyConfigurationFromUnstructured(desiredResource), applyOpts...); err != nil {
 		recorder.Eventf(owner, nil, corev1.EventTypeWarning, ReasonResourceApplyError,
 			ReasonResourceApplyError, MessageFormatResourceApplyError, resourceType, err.Error())
+		recordEnsureOutcome(resourceType, ensureOutcomeFailed)
 		// Validation errors are terminal — the user must fix the CR spec.
 		// Return nil so controller-runtime does not requeue with stack trace spam.
 		if k8serrors.IsInvalid(err) {
@@ -221,10 +249,12 @@ func EnsureResource(ctx context.Context, cl client.Client, recorder events.Event
 	}
 
 	if !resourceExists {
+		recordEnsureOutcome(resourceType, ensureOutcomeCreated)
 		logger.V(3).Info(resourceType+" created", "name", desiredResource.GetName())
 		recorder.Eventf(owner, nil, corev1.EventTypeNormal, ReasonSubResourceCreated,
 			ReasonSubResourceCreated, MessageFormatSubResourceCreated, resourceType, desiredResource.GetName())
 	} else {
+		recordEnsureOutcome(resourceType, ensureOutcomeUpdated)
 		logger.V(3).Info(resourceType+" updated", "name", desiredResource.GetName(), "changedFields", changedFields)
 		recorder.Eventf(owner, nil, corev1.EventTypeNormal, ReasonSubResourceUpdated,
 			ReasonSubResourceUpdated, MessageFormatSubResourceUpdated, resourceType, desiredResource.GetName(), changedFields)
@@ -232,3 +262,32 @@ func EnsureResource(ctx context.Context, cl client.Client, recorder events.Event
 
 	return nil
 }
+
+// deleteDisabledResource deletes the resource of the given kind and name, when controlled by the owner, because
+// it is no longer enabled in the spec of the owner. The deletion is planned instead in plan mode.
+func deleteDisabledResource(ctx context.Context, cl client.Client, recorder events.EventRecorder,
+	owner client.Object, gvk schema.GroupVersionKind, name, reason string) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gvk)
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: owner.GetNamespace(), Name: name}, existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(existing, owner) {
+		return nil
+	}
+
+	if plan := PlanFromContext(ctx); plan != nil {
+		plan.Add(commonv1alpha1.PendingActionDelete, gvk.Kind, existing.GetName(), "")
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Deleting resource no longer enabled", "kind", gvk.Kind, "name", existing.GetName())
+	if err := cl.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+		recorder.Eventf(owner, nil, corev1.EventTypeWarning, ReasonDeletionError,
+			ReasonDeletionError, MessageFormatDeletionError, gvk.Kind, err.Error())
+		return fmt.Errorf("unable to delete %s: %w", gvk.Kind, err)
+	}
+	recorder.Eventf(owner, nil, corev1.EventTypeNormal, reason,
+		reason, MessageFormatDisabledCleanup, gvk.Kind, existing.GetName())
+	return nil
+}
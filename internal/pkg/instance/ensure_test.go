@@ -18,13 +18,16 @@ package instance
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,7 +37,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/builders"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
 	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
 )
 
@@ -329,6 +334,15 @@ func TestPrepareResource(t *testing.T) {
 			wantName:       "test-owner",
 			wantLabels:     map[string]string{"app": "test"},
 		},
+		{
+			name:           "namespaced resource keeps its own name when requested",
+			owner:          newOwner(map[string]string{"app": "test"}, true),
+			resource:       builders.NewConfigMap().WithName("test-owner-gpu").WithNamespace("default").Build(),
+			options:        GenerateOptions{SetControllerRef: true, KeepName: true},
+			wantKind:       "ConfigMap",
+			wantAPIVersion: "v1",
+			wantName:       "test-owner-gpu",
+		},
 		{
 			name:           "cluster-scoped resource gets a unique name",
 			owner:          newOwner(map[string]string{"app": "test"}, false),
@@ -339,6 +353,29 @@ func TestPrepareResource(t *testing.T) {
 			wantName:       "test-owner--default",
 			wantLabels:     map[string]string{"app": "test"},
 		},
+		{
+			name:     "overlays are applied to the named resource",
+			owner:    newOwner(map[string]string{"app": "test"}, true),
+			resource: builders.NewService().WithNamespace("default").WithLabels(map[string]string{"app": "test"}).Build(),
+			options: GenerateOptions{SetControllerRef: true, Overlays: []commonv1alpha1.Overlay{{
+				Target: commonv1alpha1.OverlayTarget{Kind: "Service", Name: "test-owner"},
+				Patch:  apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"labels":{"team":"security"}}}`)},
+			}}},
+			wantKind:       "Service",
+			wantAPIVersion: "v1",
+			wantName:       "test-owner",
+			wantLabels:     map[string]string{"app": "test", "team": "security"},
+		},
+		{
+			name:     "overlay renaming the resource",
+			owner:    newOwner(nil, true),
+			resource: builders.NewService().WithNamespace("default").Build(),
+			options: GenerateOptions{Overlays: []commonv1alpha1.Overlay{{
+				Target: commonv1alpha1.OverlayTarget{Kind: "Service"},
+				Patch:  apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"name":"other"}}`)},
+			}}},
+			wantErr: "cannot change the apiVersion, kind, name or namespace",
+		},
 		{
 			name:     "controller reference failure on owner without UID",
 			owner:    newOwner(nil, false),
@@ -372,24 +409,28 @@ func TestEnsureResource(t *testing.T) {
 	opts := GenerateOptions{}
 
 	tests := []struct {
-		name     string
-		existing []client.Object
-		getErr   error
-		applyErr error
-		wantErr  string
+		name        string
+		existing    []client.Object
+		getErr      error
+		applyErr    error
+		wantErr     string
+		wantOutcome string
 	}{
 		{
-			name: "creates new resource",
+			name:        "creates new resource",
+			wantOutcome: ensureOutcomeCreated,
 		},
 		{
-			name:    "returns error when get fails",
-			getErr:  fmt.Errorf("injected get error"),
-			wantErr: "unable to fetch existing",
+			name:        "returns error when get fails",
+			getErr:      fmt.Errorf("injected get error"),
+			wantErr:     "unable to fetch existing",
+			wantOutcome: ensureOutcomeFailed,
 		},
 		{
-			name:     "returns error when apply fails",
-			applyErr: fmt.Errorf("injected apply error"),
-			wantErr:  "unable to apply",
+			name:        "returns error when apply fails",
+			applyErr:    fmt.Errorf("injected apply error"),
+			wantErr:     "unable to apply",
+			wantOutcome: ensureOutcomeFailed,
 		},
 		{
 			name: "proceeds when no managed fields found",
@@ -397,6 +438,7 @@ func TestEnsureResource(t *testing.T) {
 				builders.NewServiceAccount().
 					WithName("test").WithNamespace("default").Build(),
 			},
+			wantOutcome: ensureOutcomeUpdated,
 		},
 	}
 
@@ -423,7 +465,10 @@ func TestEnsureResource(t *testing.T) {
 			cl := builder.WithInterceptorFuncs(funcs).Build()
 
 			recorder := events.NewFakeRecorder(10)
+			outcome := metrics.ResourceEnsureTotal.WithLabelValues("ServiceAccount", tt.wantOutcome)
+			before := testutil.ToFloat64(outcome)
 			err := EnsureResource(context.Background(), cl, recorder, obj, "test-manager", testSAGenerator(obj), opts)
+			assert.Equal(t, before+1, testutil.ToFloat64(outcome))
 
 			if tt.wantErr != "" {
 				require.Error(t, err)
@@ -440,3 +485,88 @@ func TestEnsureResource(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureResourcePlanMode(t *testing.T) {
+	scheme := testScheme(t)
+
+	tests := []struct {
+		name       string
+		existing   []client.Object
+		wantAction commonv1alpha1.PendingAction
+	}{
+		{
+			name:       "records the creation of a missing resource",
+			wantAction: commonv1alpha1.PendingActionCreate,
+		},
+		{
+			name: "records the update of an existing resource",
+			existing: []client.Object{
+				builders.NewServiceAccount().WithName("test").WithNamespace("default").Build(),
+			},
+			wantAction: commonv1alpha1.PendingActionUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newConfigMap()
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append([]client.Object{obj}, tt.existing...)...).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Apply: func(context.Context, client.WithWatch, runtime.ApplyConfiguration, ...client.ApplyOption) error {
+						return fmt.Errorf("apply must not be called in plan mode")
+					},
+				}).Build()
+			recorder := events.NewFakeRecorder(10)
+			plan := &Plan{}
+
+			err := EnsureResource(PlanIntoContext(context.Background(), plan), cl, recorder, obj, "test-manager",
+				testSAGenerator(obj), GenerateOptions{})
+			require.NoError(t, err)
+
+			require.Len(t, plan.Changes(), 1)
+			assert.Equal(t, tt.wantAction, plan.Changes()[0].Action)
+			assert.Equal(t, "ServiceAccount", plan.Changes()[0].Kind)
+			assert.Equal(t, "test", plan.Changes()[0].Name)
+			assert.Empty(t, recorder.Events)
+		})
+	}
+}
+
+func TestEnsureResourceDriftReport(t *testing.T) {
+	obj := newConfigMap()
+	existing := driftedServiceAccount(t)
+	var applied map[string]any
+	cl := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(obj).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, o client.Object, opts ...client.GetOption) error {
+				if u, ok := o.(*unstructured.Unstructured); ok {
+					existing.DeepCopyInto(u)
+					return nil
+				}
+				return cl.Get(ctx, key, o, opts...)
+			},
+			Apply: func(_ context.Context, _ client.WithWatch, o runtime.ApplyConfiguration, _ ...client.ApplyOption) error {
+				raw, err := json.Marshal(o)
+				if err != nil {
+					return err
+				}
+				return json.Unmarshal(raw, &applied)
+			},
+		}).Build()
+	drift := NewDrift(new(commonv1alpha1.DriftPolicyReport))
+	generator := func(*corev1.ConfigMap) runtime.Object {
+		return builders.NewServiceAccount().WithName("test").WithNamespace("default").
+			WithLabels(map[string]string{"app": "falco", "team": "platform"}).Build()
+	}
+
+	err := EnsureResource(DriftIntoContext(context.Background(), drift), cl, events.NewFakeRecorder(10), obj,
+		"test-manager", generator(obj), GenerateOptions{KeepName: true})
+	require.NoError(t, err)
+
+	require.Len(t, drift.Resources(), 1)
+	assert.Equal(t, "kubectl-edit", drift.Resources()[0].Manager)
+	require.NotNil(t, applied, "the fields still owned by the field manager must be applied")
+	labels, _, err := unstructured.NestedStringMap(applied, "metadata", "labels")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "platform"}, labels)
+}
@@ -0,0 +1,401 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+// Falco webserver endpoints and metrics used by the health checks.
+const (
+	// FalcoWebserverPort is the port of the Falco webserver.
+	FalcoWebserverPort = 8765
+	// FalcoHealthzPath is the path of the Falco health endpoint.
+	FalcoHealthzPath = "/healthz"
+	// FalcoMetricsPath is the path of the Falco Prometheus metrics endpoint.
+	FalcoMetricsPath = "/metrics"
+	// MetricEventsTotal counts the kernel events received by Falco.
+	MetricEventsTotal = "falcosecurity_scap_n_evts_total"
+	// MetricDropsTotal counts the kernel events dropped by Falco.
+	MetricDropsTotal = "falcosecurity_scap_n_drops_total"
+	// MetricRulesFilesInfo has one series for each rules file loaded by Falco.
+	MetricRulesFilesInfo = "falcosecurity_falco_sha256_rules_files_info"
+)
+
+// Health check defaults.
+const (
+	// DefaultHealthCheckInterval is the default period between two scrapes.
+	DefaultHealthCheckInterval = 30 * time.Second
+	// DefaultMaxDropRatePercent is the default share of dropped events above which a node is reported.
+	DefaultMaxDropRatePercent int32 = 1
+	// healthCheckTimeout bounds a single request to the Falco webserver.
+	healthCheckTimeout = 5 * time.Second
+	// healthCheckWorkers bounds the number of pods scraped concurrently.
+	healthCheckWorkers = 16
+)
+
+// HealthSample is the health of a Falco pod as read from its webserver.
+type HealthSample struct {
+	// EventsTotal and DropsTotal are the cumulative kernel event counters since Falco started.
+	EventsTotal float64
+	DropsTotal  float64
+	// RulesFilesLoaded is the number of loaded rules files, nil when Falco does not report it.
+	RulesFilesLoaded *int64
+}
+
+// HealthScraper reads the health of a Falco pod.
+type HealthScraper interface {
+	Scrape(ctx context.Context, pod *corev1.Pod) (HealthSample, error)
+}
+
+// HTTPHealthScraper scrapes the health and metrics endpoints of the Falco webserver on the pod IP, over HTTPS
+// for the pods of a Falco with TLS enabled.
+type HTTPHealthScraper struct {
+	// Client is the HTTP client used for the requests.
+	Client *http.Client
+	// Port is the port of the Falco webserver.
+	Port int
+}
+
+// NewHTTPHealthScraper returns an HTTPHealthScraper for the default Falco webserver port.
+func NewHTTPHealthScraper() *HTTPHealthScraper {
+	// Like the kubelet probes, the webserver is reached on the pod IP, which its certificate does not cover:
+	// the certificate is not verified.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // pod IP not in the certificate
+	return &HTTPHealthScraper{
+		Client: &http.Client{Timeout: healthCheckTimeout, Transport: transport},
+		Port:   FalcoWebserverPort,
+	}
+}
+
+// Scrape checks the health endpoint of the pod and parses its metrics.
+func (s *HTTPHealthScraper) Scrape(ctx context.Context, pod *corev1.Pod) (HealthSample, error) {
+	if pod.Status.PodIP == "" {
+		return HealthSample{}, fmt.Errorf("pod %s has no IP", pod.Name)
+	}
+	scheme := "http"
+	if pod.Annotations[resources.CertificateSerialAnnotation] != "" {
+		scheme = "https"
+	}
+	base := scheme + "://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(s.Port))
+
+	if _, err := s.get(ctx, base+FalcoHealthzPath); err != nil {
+		return HealthSample{}, err
+	}
+	body, err := s.get(ctx, base+FalcoMetricsPath)
+	if err != nil {
+		return HealthSample{}, err
+	}
+	return ParseHealthMetrics(strings.NewReader(body))
+}
+
+func (s *HTTPHealthScraper) get(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return string(body), nil
+}
+
+// ParseHealthMetrics extracts a HealthSample from metrics in the Prometheus text format.
+// Counters exposed with several label sets are summed.
+func ParseHealthMetrics(r io.Reader) (HealthSample, error) {
+	var sample HealthSample
+	var rulesFiles int64
+	rulesReported := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, err := parseMetricLine(line)
+		if err != nil {
+			return HealthSample{}, err
+		}
+		switch name {
+		case MetricEventsTotal:
+			sample.EventsTotal += value
+		case MetricDropsTotal:
+			sample.DropsTotal += value
+		case MetricRulesFilesInfo:
+			rulesReported = true
+			rulesFiles++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return HealthSample{}, fmt.Errorf("reading metrics: %w", err)
+	}
+	if rulesReported {
+		sample.RulesFilesLoaded = &rulesFiles
+	}
+	return sample, nil
+}
+
+// parseMetricLine splits a sample line of the Prometheus text format into its metric name and value.
+func parseMetricLine(line string) (string, float64, error) {
+	name, rest := line, ""
+	if i := strings.IndexAny(line, "{ "); i >= 0 {
+		name, rest = line[:i], line[i:]
+	}
+	if strings.HasPrefix(rest, "{") {
+		end := strings.LastIndex(rest, "}")
+		if end < 0 {
+			return "", 0, fmt.Errorf("malformed metric line %q", line)
+		}
+		rest = rest[end+1:]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", 0, fmt.Errorf("malformed metric line %q", line)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed value in metric line %q: %w", line, err)
+	}
+	return name, value, nil
+}
+
+// HealthChecker scrapes Falco pods and keeps their previous samples to compute drop rates between scrapes.
+type HealthChecker struct {
+	scraper HealthScraper
+	workers int
+
+	mu       sync.Mutex
+	previous map[types.UID]HealthSample
+}
+
+// NewHealthChecker creates a HealthChecker using the given scraper.
+func NewHealthChecker(scraper HealthScraper) *HealthChecker {
+	return &HealthChecker{
+		scraper:  scraper,
+		workers:  healthCheckWorkers,
+		previous: make(map[types.UID]HealthSample),
+	}
+}
+
+// Check scrapes the running pods and returns the health of each of them, sorted by node and pod name.
+// Pods not yet scheduled or not running are skipped. The pods are scraped concurrently by a bounded
+// number of workers, so that a large cluster does not wait for each request in turn.
+func (h *HealthChecker) Check(ctx context.Context, pods []corev1.Pod, maxDropRatePercent int32,
+	now metav1.Time) []instancev1alpha1.FalcoNodeHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var running []*corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		running = append(running, pod)
+	}
+	samples, errs := h.scrape(ctx, running)
+
+	seen := make(map[types.UID]bool, len(running))
+	var nodes []instancev1alpha1.FalcoNodeHealth
+	for i, pod := range running {
+		seen[pod.UID] = true
+
+		node := instancev1alpha1.FalcoNodeHealth{
+			NodeName:       pod.Spec.NodeName,
+			PodName:        pod.Name,
+			LastScrapeTime: now,
+		}
+		if errs[i] != nil {
+			node.Message = fmt.Sprintf(MessageFormatHealthCheckFailed, errs[i].Error())
+			nodes = append(nodes, node)
+			continue
+		}
+		sample := samples[i]
+
+		var problems []string
+		node.RulesFilesLoaded = sample.RulesFilesLoaded
+		if sample.RulesFilesLoaded != nil && *sample.RulesFilesLoaded == 0 {
+			problems = append(problems, MessageNoRulesFilesLoaded)
+		}
+		rate := dropRate(h.previous[pod.UID], sample)
+		node.DropRate = strconv.FormatFloat(rate, 'f', 2, 64) + "%"
+		if rate > float64(maxDropRatePercent) {
+			problems = append(problems, fmt.Sprintf(MessageFormatDroppingEvents, node.DropRate))
+		}
+		h.previous[pod.UID] = sample
+
+		node.Healthy = len(problems) == 0
+		node.Message = strings.Join(problems, "; ")
+		nodes = append(nodes, node)
+	}
+
+	// Forget the pods that are gone.
+	for uid := range h.previous {
+		if !seen[uid] {
+			delete(h.previous, uid)
+		}
+	}
+
+	slices.SortFunc(nodes, func(a, b instancev1alpha1.FalcoNodeHealth) int {
+		return strings.Compare(a.NodeName+"/"+a.PodName, b.NodeName+"/"+b.PodName)
+	})
+	return nodes
+}
+
+// scrape scrapes the pods with at most h.workers requests in flight and returns the sample or the error
+// of each pod, in the order of the pods.
+func (h *HealthChecker) scrape(ctx context.Context, pods []*corev1.Pod) ([]HealthSample, []error) {
+	samples := make([]HealthSample, len(pods))
+	errs := make([]error, len(pods))
+	sem := make(chan struct{}, max(h.workers, 1))
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			samples[i], errs[i] = h.scraper.Scrape(ctx, pod)
+		})
+	}
+	wg.Wait()
+	return samples, errs
+}
+
+// dropRate returns the percentage of events dropped between two samples. Without a usable previous
+// sample (first scrape, or counters reset by a Falco restart) the counters since start are used.
+func dropRate(previous, current HealthSample) float64 {
+	events, drops := current.EventsTotal, current.DropsTotal
+	if previous.EventsTotal > 0 && current.EventsTotal >= previous.EventsTotal && current.DropsTotal >= previous.DropsTotal {
+		events -= previous.EventsTotal
+		drops -= previous.DropsTotal
+	}
+	if events <= 0 {
+		return 0
+	}
+	return drops / events * 100
+}
+
+// SetHealthConditions sets the RulesLoaded, EventDrops and Degraded conditions from the node health.
+func SetHealthConditions(conditions *[]metav1.Condition, nodes []instancev1alpha1.FalcoNodeHealth,
+	maxDropRatePercent int32, generation int64) {
+	var scraped, noRules, dropping, unhealthy []string
+	rulesReported := true
+	for _, node := range nodes {
+		if !node.Healthy {
+			unhealthy = append(unhealthy, node.NodeName)
+		}
+		if node.DropRate == "" {
+			// Scrape failed: only reflected in Degraded.
+			continue
+		}
+		scraped = append(scraped, node.NodeName)
+		switch {
+		case node.RulesFilesLoaded == nil:
+			rulesReported = false
+		case *node.RulesFilesLoaded == 0:
+			noRules = append(noRules, node.NodeName)
+		}
+		if rate, err := strconv.ParseFloat(strings.TrimSuffix(node.DropRate, "%"), 64); err == nil &&
+			rate > float64(maxDropRatePercent) {
+			dropping = append(dropping, node.NodeName)
+		}
+	}
+
+	// Several pods of a Deployment can run on the same node: the entries are sorted by node, report it once.
+	noRules, dropping, unhealthy = slices.Compact(noRules), slices.Compact(dropping), slices.Compact(unhealthy)
+
+	switch {
+	case len(noRules) > 0:
+		apimeta.SetStatusCondition(conditions, common.NewRulesLoadedCondition(metav1.ConditionFalse,
+			ReasonNoRulesLoaded, fmt.Sprintf(MessageFormatNoRulesLoaded, strings.Join(noRules, ", ")), generation))
+	case len(scraped) == 0:
+		apimeta.SetStatusCondition(conditions, common.NewRulesLoadedCondition(metav1.ConditionUnknown,
+			ReasonHealthNotScraped, MessageHealthNotScraped, generation))
+	case !rulesReported:
+		apimeta.SetStatusCondition(conditions, common.NewRulesLoadedCondition(metav1.ConditionUnknown,
+			ReasonRulesNotReported, MessageRulesNotReported, generation))
+	default:
+		apimeta.SetStatusCondition(conditions, common.NewRulesLoadedCondition(metav1.ConditionTrue,
+			ReasonRulesLoaded, MessageRulesLoaded, generation))
+	}
+
+	switch {
+	case len(dropping) > 0:
+		apimeta.SetStatusCondition(conditions, common.NewEventDropsCondition(metav1.ConditionTrue,
+			ReasonEventDropsAboveThreshold,
+			fmt.Sprintf(MessageFormatEventDropsAboveThreshold, maxDropRatePercent, strings.Join(dropping, ", ")), generation))
+	case len(scraped) == 0:
+		apimeta.SetStatusCondition(conditions, common.NewEventDropsCondition(metav1.ConditionUnknown,
+			ReasonHealthNotScraped, MessageHealthNotScraped, generation))
+	default:
+		apimeta.SetStatusCondition(conditions, common.NewEventDropsCondition(metav1.ConditionFalse,
+			ReasonEventDropsWithinThreshold, fmt.Sprintf(MessageFormatEventDropsWithinThreshold, maxDropRatePercent), generation))
+	}
+
+	switch {
+	case len(unhealthy) > 0:
+		apimeta.SetStatusCondition(conditions, common.NewDegradedCondition(metav1.ConditionTrue,
+			ReasonFalcoDegraded, fmt.Sprintf(MessageFormatFalcoDegraded, strings.Join(unhealthy, ", ")), generation))
+	case len(nodes) == 0:
+		apimeta.SetStatusCondition(conditions, common.NewDegradedCondition(metav1.ConditionUnknown,
+			ReasonHealthNotScraped, MessageHealthNotScraped, generation))
+	default:
+		apimeta.SetStatusCondition(conditions, common.NewDegradedCondition(metav1.ConditionFalse,
+			ReasonFalcoHealthy, MessageFalcoHealthy, generation))
+	}
+}
+
+// RemoveHealthConditions removes the conditions set by SetHealthConditions.
+func RemoveHealthConditions(conditions *[]metav1.Condition) {
+	for _, t := range []commonv1alpha1.ConditionType{
+		commonv1alpha1.ConditionRulesLoaded, commonv1alpha1.ConditionEventDrops, commonv1alpha1.ConditionDegraded,
+	} {
+		apimeta.RemoveStatusCondition(conditions, t.String())
+	}
+}
@@ -0,0 +1,462 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+const testFalcoMetrics = `# HELP falcosecurity_scap_n_evts_total https://falco.org/docs/metrics/
+# TYPE falcosecurity_scap_n_evts_total counter
+falcosecurity_scap_n_evts_total{raw_name="n_evts"} 1000
+# TYPE falcosecurity_scap_n_drops_total counter
+falcosecurity_scap_n_drops_total{raw_name="n_drops"} 5
+# TYPE falcosecurity_falco_sha256_rules_files_info gauge
+falcosecurity_falco_sha256_rules_files_info{file_name="/etc/falco/rules.d/50-01-falco-rules-oci.yaml",sha256="abc"} 1
+falcosecurity_falco_sha256_rules_files_info{file_name="/etc/falco/rules.d/50-03-custom-inline.yaml",sha256="def"} 1
+`
+
+func TestParseHealthMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantEvents float64
+		wantDrops  float64
+		wantRules  *int64
+		wantErr    bool
+	}{
+		{
+			name:       "counters and rules files",
+			input:      testFalcoMetrics,
+			wantEvents: 1000,
+			wantDrops:  5,
+			wantRules:  new(int64(2)),
+		},
+		{
+			name:       "counters with several label sets are summed",
+			input:      "falcosecurity_scap_n_evts_total{cpu=\"0\"} 10\nfalcosecurity_scap_n_evts_total{cpu=\"1\"} 20 1700000000\n",
+			wantEvents: 30,
+		},
+		{
+			name:  "missing rules metric is not reported",
+			input: "falcosecurity_scap_n_evts_total 10\n",
+			// wantRules nil
+			wantEvents: 10,
+		},
+		{
+			name:    "malformed value",
+			input:   "falcosecurity_scap_n_evts_total{} abc\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			input:   "falcosecurity_scap_n_evts_total\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHealthMetrics(strings.NewReader(tt.input))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEvents, got.EventsTotal)
+			assert.Equal(t, tt.wantDrops, got.DropsTotal)
+			assert.Equal(t, tt.wantRules, got.RulesFilesLoaded)
+		})
+	}
+}
+
+// newFalcoWebserver starts a fake Falco webserver and returns the pod pointing at it and a scraper for its port.
+func newFalcoWebserver(t *testing.T, healthzStatus int, metrics string) (*corev1.Pod, *HTTPHealthScraper) {
+	t.Helper()
+	return newFalcoWebserverWithTLS(t, healthzStatus, metrics, false)
+}
+
+// newFalcoWebserverWithTLS is newFalcoWebserver serving TLS when tls is set, the pod then carrying the
+// certificate annotation of the Falco pods with TLS enabled.
+func newFalcoWebserverWithTLS(t *testing.T, healthzStatus int, metrics string, tls bool) (*corev1.Pod, *HTTPHealthScraper) {
+	t.Helper()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case FalcoHealthzPath:
+			w.WriteHeader(healthzStatus)
+			_, _ = w.Write([]byte(`{"status": "ok"}`))
+		case FalcoMetricsPath:
+			_, _ = w.Write([]byte(metrics))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(handler)
+	if tls {
+		srv = httptest.NewTLSServer(handler)
+	}
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	scraper := NewHTTPHealthScraper()
+	scraper.Port = port
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "falco-abc"},
+		Status:     corev1.PodStatus{PodIP: u.Hostname()},
+	}
+	if tls {
+		pod.Annotations = map[string]string{resources.CertificateSerialAnnotation: "1"}
+	}
+	return pod, scraper
+}
+
+func TestHTTPHealthScraper_Scrape(t *testing.T) {
+	t.Run("healthy pod", func(t *testing.T) {
+		pod, scraper := newFalcoWebserver(t, http.StatusOK, testFalcoMetrics)
+		got, err := scraper.Scrape(context.Background(), pod)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1000), got.EventsTotal)
+		assert.Equal(t, float64(5), got.DropsTotal)
+		require.NotNil(t, got.RulesFilesLoaded)
+		assert.Equal(t, int64(2), *got.RulesFilesLoaded)
+	})
+
+	t.Run("pod with TLS", func(t *testing.T) {
+		pod, scraper := newFalcoWebserverWithTLS(t, http.StatusOK, testFalcoMetrics, true)
+		got, err := scraper.Scrape(context.Background(), pod)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1000), got.EventsTotal)
+	})
+
+	t.Run("failing health endpoint", func(t *testing.T) {
+		pod, scraper := newFalcoWebserver(t, http.StatusServiceUnavailable, testFalcoMetrics)
+		_, err := scraper.Scrape(context.Background(), pod)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+	})
+
+	t.Run("pod without IP", func(t *testing.T) {
+		_, err := NewHTTPHealthScraper().Scrape(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has no IP")
+	})
+}
+
+// fakeScraper returns the queued samples of each pod in order.
+type fakeScraper struct {
+	mu      sync.Mutex
+	samples map[string][]HealthSample
+	errs    map[string]error
+}
+
+func (f *fakeScraper) Scrape(_ context.Context, pod *corev1.Pod) (HealthSample, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs[pod.Name]; err != nil {
+		return HealthSample{}, err
+	}
+	queue := f.samples[pod.Name]
+	if len(queue) == 0 {
+		return HealthSample{}, fmt.Errorf("no sample for %s", pod.Name)
+	}
+	f.samples[pod.Name] = queue[1:]
+	return queue[0], nil
+}
+
+func runningPod(name, node string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestHealthChecker_Check(t *testing.T) {
+	now := metav1.Now()
+	scraper := &fakeScraper{
+		samples: map[string][]HealthSample{
+			"falco-a": {
+				{EventsTotal: 1000, DropsTotal: 100, RulesFilesLoaded: new(int64(3))},
+				// 10 drops out of 1000 new events: 1%.
+				{EventsTotal: 2000, DropsTotal: 110, RulesFilesLoaded: new(int64(3))},
+			},
+			"falco-b": {
+				{EventsTotal: 1000, DropsTotal: 0, RulesFilesLoaded: new(int64(0))},
+			},
+		},
+		errs: map[string]error{"falco-c": fmt.Errorf("connection refused")},
+	}
+	pending := runningPod("falco-d", "node-4")
+	pending.Status.Phase = corev1.PodPending
+	checker := NewHealthChecker(scraper)
+
+	// First scrape: counters since start.
+	nodes := checker.Check(context.Background(), []corev1.Pod{
+		runningPod("falco-c", "node-3"), runningPod("falco-a", "node-1"), runningPod("falco-b", "node-2"), pending,
+	}, 5, now)
+	require.Len(t, nodes, 3)
+
+	assert.Equal(t, "node-1", nodes[0].NodeName)
+	assert.Equal(t, "10.00%", nodes[0].DropRate)
+	assert.False(t, nodes[0].Healthy)
+	assert.Equal(t, "dropping 10.00% of events", nodes[0].Message)
+
+	assert.Equal(t, "node-2", nodes[1].NodeName)
+	assert.False(t, nodes[1].Healthy)
+	assert.Equal(t, MessageNoRulesFilesLoaded, nodes[1].Message)
+
+	assert.Equal(t, "node-3", nodes[2].NodeName)
+	assert.False(t, nodes[2].Healthy)
+	assert.Empty(t, nodes[2].DropRate)
+	assert.Contains(t, nodes[2].Message, "connection refused")
+	assert.Equal(t, now, nodes[2].LastScrapeTime)
+
+	// Second scrape: the rate only covers the events since the previous scrape.
+	nodes = checker.Check(context.Background(), []corev1.Pod{runningPod("falco-a", "node-1")}, 5, now)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "1.00%", nodes[0].DropRate)
+	assert.True(t, nodes[0].Healthy)
+	assert.Empty(t, nodes[0].Message)
+
+	// Samples of pods that are gone are forgotten.
+	assert.Len(t, checker.previous, 1)
+}
+
+// blockingScraper records the highest number of scrapes in flight, each lasting a few milliseconds.
+type blockingScraper struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (b *blockingScraper) Scrape(context.Context, *corev1.Pod) (HealthSample, error) {
+	n := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	for {
+		highest := b.maxInFlight.Load()
+		if n <= highest || b.maxInFlight.CompareAndSwap(highest, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return HealthSample{EventsTotal: 1, RulesFilesLoaded: new(int64(1))}, nil
+}
+
+func TestHealthChecker_CheckConcurrency(t *testing.T) {
+	scraper := &blockingScraper{}
+	checker := NewHealthChecker(scraper)
+	checker.workers = 4
+
+	pods := make([]corev1.Pod, 20)
+	for i := range pods {
+		pods[i] = runningPod(fmt.Sprintf("falco-%02d", i), fmt.Sprintf("node-%02d", i))
+	}
+	nodes := checker.Check(context.Background(), pods, 5, metav1.Now())
+
+	require.Len(t, nodes, 20)
+	for i, node := range nodes {
+		assert.Equal(t, fmt.Sprintf("node-%02d", i), node.NodeName)
+		assert.True(t, node.Healthy)
+	}
+	assert.Greater(t, scraper.maxInFlight.Load(), int32(1), "pods are scraped concurrently")
+	assert.LessOrEqual(t, scraper.maxInFlight.Load(), int32(4), "at most workers pods are scraped at once")
+}
+
+func TestHealthChecker_CheckPodsOnSameNode(t *testing.T) {
+	// In Deployment mode, several Falco pods can run on the same node: each of them is reported.
+	scraper := &fakeScraper{samples: map[string][]HealthSample{
+		"falco-b": {{EventsTotal: 1000, RulesFilesLoaded: new(int64(1))}},
+		"falco-a": {{EventsTotal: 1000, RulesFilesLoaded: new(int64(0))}},
+	}}
+	nodes := NewHealthChecker(scraper).Check(context.Background(), []corev1.Pod{
+		runningPod("falco-b", "node-1"), runningPod("falco-a", "node-1"),
+	}, 5, metav1.Now())
+
+	require.Len(t, nodes, 2)
+	assert.Equal(t, "node-1", nodes[0].NodeName)
+	assert.Equal(t, "falco-a", nodes[0].PodName)
+	assert.False(t, nodes[0].Healthy)
+	assert.Equal(t, "node-1", nodes[1].NodeName)
+	assert.Equal(t, "falco-b", nodes[1].PodName)
+	assert.True(t, nodes[1].Healthy)
+}
+
+func TestDropRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous HealthSample
+		current  HealthSample
+		want     float64
+	}{
+		{name: "no events", want: 0},
+		{name: "first sample uses counters since start", current: HealthSample{EventsTotal: 200, DropsTotal: 2}, want: 1},
+		{
+			name:     "delta between samples",
+			previous: HealthSample{EventsTotal: 100, DropsTotal: 50},
+			current:  HealthSample{EventsTotal: 300, DropsTotal: 54},
+			want:     2,
+		},
+		{
+			name:     "counter reset uses counters since start",
+			previous: HealthSample{EventsTotal: 1000, DropsTotal: 500},
+			current:  HealthSample{EventsTotal: 100, DropsTotal: 0},
+			want:     0,
+		},
+		{
+			name:     "no new events",
+			previous: HealthSample{EventsTotal: 100, DropsTotal: 5},
+			current:  HealthSample{EventsTotal: 100, DropsTotal: 5},
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, dropRate(tt.previous, tt.current), 0.0001)
+		})
+	}
+}
+
+func TestSetHealthConditions(t *testing.T) {
+	healthy := func(node string) instancev1alpha1.FalcoNodeHealth {
+		return instancev1alpha1.FalcoNodeHealth{NodeName: node, Healthy: true, DropRate: "0.00%", RulesFilesLoaded: new(int64(2))}
+	}
+
+	tests := []struct {
+		name         string
+		nodes        []instancev1alpha1.FalcoNodeHealth
+		wantRules    metav1.ConditionStatus
+		wantDrops    metav1.ConditionStatus
+		wantDegraded metav1.ConditionStatus
+		wantReason   string // Degraded reason
+		wantMessage  string // Degraded message
+	}{
+		{
+			name:         "no nodes",
+			wantRules:    metav1.ConditionUnknown,
+			wantDrops:    metav1.ConditionUnknown,
+			wantDegraded: metav1.ConditionUnknown,
+			wantReason:   ReasonHealthNotScraped,
+		},
+		{
+			name:         "all healthy",
+			nodes:        []instancev1alpha1.FalcoNodeHealth{healthy("node-1"), healthy("node-2")},
+			wantRules:    metav1.ConditionTrue,
+			wantDrops:    metav1.ConditionFalse,
+			wantDegraded: metav1.ConditionFalse,
+			wantReason:   ReasonFalcoHealthy,
+		},
+		{
+			name: "no rules files on a node",
+			nodes: []instancev1alpha1.FalcoNodeHealth{healthy("node-1"),
+				{NodeName: "node-2", DropRate: "0.00%", RulesFilesLoaded: new(int64(0)), Message: MessageNoRulesFilesLoaded}},
+			wantRules:    metav1.ConditionFalse,
+			wantDrops:    metav1.ConditionFalse,
+			wantDegraded: metav1.ConditionTrue,
+			wantReason:   ReasonFalcoDegraded,
+			wantMessage:  "Falco is unhealthy on nodes: node-2",
+		},
+		{
+			name:         "drops above threshold",
+			nodes:        []instancev1alpha1.FalcoNodeHealth{{NodeName: "node-1", DropRate: "7.50%", RulesFilesLoaded: new(int64(1))}},
+			wantRules:    metav1.ConditionTrue,
+			wantDrops:    metav1.ConditionTrue,
+			wantDegraded: metav1.ConditionTrue,
+			wantReason:   ReasonFalcoDegraded,
+			wantMessage:  "Falco is unhealthy on nodes: node-1",
+		},
+		{
+			name: "two unhealthy pods on a node",
+			nodes: []instancev1alpha1.FalcoNodeHealth{
+				{NodeName: "node-1", PodName: "falco-a", Message: "health check failed: timeout"},
+				{NodeName: "node-1", PodName: "falco-b", Message: "health check failed: timeout"},
+				healthy("node-2"),
+			},
+			wantRules:    metav1.ConditionTrue,
+			wantDrops:    metav1.ConditionFalse,
+			wantDegraded: metav1.ConditionTrue,
+			wantReason:   ReasonFalcoDegraded,
+			wantMessage:  "Falco is unhealthy on nodes: node-1",
+		},
+		{
+			name:         "rules not reported",
+			nodes:        []instancev1alpha1.FalcoNodeHealth{{NodeName: "node-1", Healthy: true, DropRate: "0.00%"}},
+			wantRules:    metav1.ConditionUnknown,
+			wantDrops:    metav1.ConditionFalse,
+			wantDegraded: metav1.ConditionFalse,
+			wantReason:   ReasonFalcoHealthy,
+		},
+		{
+			name:         "scrape failure only degrades",
+			nodes:        []instancev1alpha1.FalcoNodeHealth{{NodeName: "node-1", Message: "health check failed: timeout"}},
+			wantRules:    metav1.ConditionUnknown,
+			wantDrops:    metav1.ConditionUnknown,
+			wantDegraded: metav1.ConditionTrue,
+			wantReason:   ReasonFalcoDegraded,
+			wantMessage:  "Falco is unhealthy on nodes: node-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conditions []metav1.Condition
+			SetHealthConditions(&conditions, tt.nodes, 5, 3)
+
+			rules := apimeta.FindStatusCondition(conditions, commonv1alpha1.ConditionRulesLoaded.String())
+			drops := apimeta.FindStatusCondition(conditions, commonv1alpha1.ConditionEventDrops.String())
+			degraded := apimeta.FindStatusCondition(conditions, commonv1alpha1.ConditionDegraded.String())
+			require.NotNil(t, rules)
+			require.NotNil(t, drops)
+			require.NotNil(t, degraded)
+			assert.Equal(t, tt.wantRules, rules.Status)
+			assert.Equal(t, tt.wantDrops, drops.Status)
+			assert.Equal(t, tt.wantDegraded, degraded.Status)
+			assert.Equal(t, tt.wantReason, degraded.Reason)
+			assert.Equal(t, int64(3), degraded.ObservedGeneration)
+			if tt.wantMessage != "" {
+				assert.Equal(t, tt.wantMessage, degraded.Message)
+			}
+
+			RemoveHealthConditions(&conditions)
+			assert.Empty(t, conditions)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
+)
+
+// Outcomes of EnsureResource, reported by metrics.ResourceEnsureTotal.
+const (
+	ensureOutcomeCreated = "created"
+	ensureOutcomeUpdated = "updated"
+	ensureOutcomeSkipped = "skipped"
+	ensureOutcomePlanned = "planned"
+	ensureOutcomeFailed  = "failed"
+)
+
+// RecordReplicas reports the replicas of the instance of the given kind, as set in its status.
+func RecordReplicas(kind string, key client.ObjectKey, desired, available, unavailable int32) {
+	metrics.InstanceReplicas.WithLabelValues(kind, key.Namespace, key.Name, "desired").Set(float64(desired))
+	metrics.InstanceReplicas.WithLabelValues(kind, key.Namespace, key.Name, "available").Set(float64(available))
+	metrics.InstanceReplicas.WithLabelValues(kind, key.Namespace, key.Name, "unavailable").Set(float64(unavailable))
+}
+
+// ForgetReplicas stops reporting the replicas of the deleted instance of the given kind.
+func ForgetReplicas(kind string, key client.ObjectKey) {
+	metrics.InstanceReplicas.DeletePartialMatch(prometheus.Labels{"kind": kind, "namespace": key.Namespace, "name": key.Name})
+}
+
+func recordEnsureOutcome(kind, outcome string) {
+	metrics.ResourceEnsureTotal.WithLabelValues(kind, outcome).Inc()
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
+)
+
+func TestRecordReplicas(t *testing.T) {
+	key := client.ObjectKey{Namespace: "metrics", Name: "falco"}
+	replicas := func(state string) float64 {
+		return testutil.ToFloat64(metrics.InstanceReplicas.WithLabelValues("Falco", key.Namespace, key.Name, state))
+	}
+
+	RecordReplicas("Falco", key, 3, 2, 1)
+	assert.Equal(t, float64(3), replicas("desired"))
+	assert.Equal(t, float64(2), replicas("available"))
+	assert.Equal(t, float64(1), replicas("unavailable"))
+
+	ForgetReplicas("Falco", key)
+	assert.Zero(t, metrics.InstanceReplicas.DeletePartialMatch(prometheus.Labels{"namespace": key.Namespace, "name": key.Name}))
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// EnsureMonitoringResource ensures the prometheus-operator object of the given kind generated for the owner
+// matches the desired one: it is applied like EnsureResource, or deleted when desired is nil. Nothing is done
+// when the CRD of the kind is not installed in the cluster, so that the operator doesn't depend on it.
+func EnsureMonitoringResource(ctx context.Context, cl client.Client, recorder events.EventRecorder,
+	owner client.Object, fieldManager string, gvk schema.GroupVersionKind,
+	desired *unstructured.Unstructured, options GenerateOptions) error {
+	logger := log.FromContext(ctx)
+
+	if _, err := cl.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if !apimeta.IsNoMatchError(err) {
+			return fmt.Errorf("unable to look up the %s CRD: %w", gvk.Kind, err)
+		}
+		if desired != nil {
+			logger.Info(gvk.Kind+" CRD not installed, skipping", "name", owner.GetName())
+			recorder.Eventf(owner, nil, corev1.EventTypeWarning, ReasonMonitoringNotInstalled,
+				ReasonMonitoringNotInstalled, MessageFormatMonitoringNotInstalled, gvk.Kind)
+		}
+		return nil
+	}
+
+	if desired != nil {
+		return EnsureResource(ctx, cl, recorder, owner, fieldManager, desired, options)
+	}
+
+	return deleteDisabledResource(ctx, cl, recorder, owner, gvk, owner.GetName(), ReasonMonitoringCleanup)
+}
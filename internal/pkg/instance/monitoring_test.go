@@ -0,0 +1,156 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+// monitoringRESTMapper returns a RESTMapper knowing the ConfigMaps and, when installed is true, the ServiceMonitors.
+func monitoringRESTMapper(installed bool) apimeta.RESTMapper {
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apimeta.RESTScopeNamespace)
+	if installed {
+		mapper.Add(resources.ServiceMonitorGVK, apimeta.RESTScopeNamespace)
+	}
+	return mapper
+}
+
+func TestEnsureMonitoringResource(t *testing.T) {
+	scheme := testScheme(t)
+	owner := newConfigMap()
+	owner.UID = "owner-uid"
+	monitor := &commonv1alpha1.MonitorSpec{Enabled: true}
+
+	existingMonitor := func(controlled bool) *unstructured.Unstructured {
+		u := resources.GenerateServiceMonitor(owner, resources.FalcoDefaults, monitor)
+		if controlled {
+			u.SetOwnerReferences([]metav1.OwnerReference{{
+				APIVersion: "v1", Kind: "ConfigMap", Name: owner.Name, UID: owner.UID, Controller: new(true),
+			}})
+		}
+		return u
+	}
+
+	tests := []struct {
+		name       string
+		installed  bool
+		existing   *unstructured.Unstructured
+		desired    *unstructured.Unstructured
+		plan       bool
+		wantExists bool
+		wantEvent  string
+		wantChange commonv1alpha1.PendingAction
+	}{
+		{
+			name:      "CRD not installed",
+			desired:   resources.GenerateServiceMonitor(owner, resources.FalcoDefaults, monitor),
+			wantEvent: ReasonMonitoringNotInstalled,
+		},
+		{
+			name: "CRD not installed and not enabled",
+		},
+		{
+			name:       "enabled monitor is created",
+			installed:  true,
+			desired:    resources.GenerateServiceMonitor(owner, resources.FalcoDefaults, monitor),
+			wantExists: true,
+			wantEvent:  ReasonSubResourceCreated,
+		},
+		{
+			name:      "disabled monitor is deleted",
+			installed: true,
+			existing:  existingMonitor(true),
+			wantEvent: ReasonMonitoringCleanup,
+		},
+		{
+			name:       "monitor not controlled by the owner is kept",
+			installed:  true,
+			existing:   existingMonitor(false),
+			wantExists: true,
+		},
+		{
+			name:       "deletion is planned in plan mode",
+			installed:  true,
+			existing:   existingMonitor(true),
+			plan:       true,
+			wantExists: true,
+			wantChange: commonv1alpha1.PendingActionDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(monitoringRESTMapper(tt.installed))
+			if tt.existing != nil {
+				builder = builder.WithObjects(tt.existing)
+			}
+			cl := builder.Build()
+			recorder := events.NewFakeRecorder(10)
+			ctx := context.Background()
+			var plan *Plan
+			if tt.plan {
+				plan = &Plan{}
+				ctx = PlanIntoContext(ctx, plan)
+			}
+
+			err := EnsureMonitoringResource(ctx, cl, recorder, owner, "test-manager", resources.ServiceMonitorGVK, tt.desired,
+				GenerateOptions{SetControllerRef: true})
+			require.NoError(t, err)
+
+			if tt.installed {
+				got := &unstructured.Unstructured{}
+				got.SetGroupVersionKind(resources.ServiceMonitorGVK)
+				err = cl.Get(ctx, client.ObjectKeyFromObject(owner), got)
+				if tt.wantExists {
+					require.NoError(t, err)
+				} else {
+					assert.True(t, k8serrors.IsNotFound(err), "monitor must not exist, got %v", err)
+				}
+			}
+
+			if tt.wantEvent == "" {
+				assert.Empty(t, recorder.Events)
+			} else {
+				require.Len(t, recorder.Events, 1)
+				assert.True(t, strings.Contains(<-recorder.Events, tt.wantEvent))
+			}
+
+			if tt.plan {
+				require.Len(t, plan.Changes(), 1)
+				assert.Equal(t, tt.wantChange, plan.Changes()[0].Action)
+				assert.Equal(t, "ServiceMonitor", plan.Changes()[0].Kind)
+			}
+		})
+	}
+}
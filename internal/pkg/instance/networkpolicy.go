@@ -0,0 +1,36 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EnsureNetworkPolicy ensures the NetworkPolicy generated for the owner matches the desired one: it is applied
+// like EnsureResource, or deleted when desired is nil because the policy is no longer enabled.
+func EnsureNetworkPolicy(ctx context.Context, cl client.Client, recorder events.EventRecorder,
+	owner client.Object, fieldManager string, desired *networkingv1.NetworkPolicy, options GenerateOptions) error {
+	if desired != nil {
+		return EnsureResource(ctx, cl, recorder, owner, fieldManager, desired, options)
+	}
+	return deleteDisabledResource(ctx, cl, recorder, owner, networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"),
+		owner.GetName(), ReasonNetworkPolicyCleanup)
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+func TestEnsureNetworkPolicy(t *testing.T) {
+	scheme := testScheme(t)
+	require.NoError(t, networkingv1.AddToScheme(scheme))
+	owner := newConfigMap()
+	owner.UID = "owner-uid"
+	spec := &commonv1alpha1.NetworkPolicySpec{Enabled: true}
+
+	existingPolicy := func(controlled bool) *networkingv1.NetworkPolicy {
+		policy := resources.GenerateNetworkPolicy(owner, resources.FalcosidekickDefaults, spec)
+		if controlled {
+			policy.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "v1", Kind: "ConfigMap", Name: owner.Name, UID: owner.UID, Controller: new(true),
+			}}
+		}
+		return policy
+	}
+
+	tests := []struct {
+		name       string
+		existing   *networkingv1.NetworkPolicy
+		desired    *networkingv1.NetworkPolicy
+		wantExists bool
+		wantEvent  string
+	}{
+		{
+			name:       "enabled policy is created",
+			desired:    resources.GenerateNetworkPolicy(owner, resources.FalcosidekickDefaults, spec),
+			wantExists: true,
+			wantEvent:  ReasonSubResourceCreated,
+		},
+		{
+			name: "nothing to do when not enabled",
+		},
+		{
+			name:      "disabled policy is deleted",
+			existing:  existingPolicy(true),
+			wantEvent: ReasonNetworkPolicyCleanup,
+		},
+		{
+			name:       "policy not controlled by the owner is kept",
+			existing:   existingPolicy(false),
+			wantExists: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The fake client still handles NetworkPolicy as having a status, which its typed converter
+			// cannot parse: use the deduced one only.
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithTypeConverters(managedfields.NewDeducedTypeConverter())
+			if tt.existing != nil {
+				builder = builder.WithObjects(tt.existing)
+			}
+			cl := builder.Build()
+			recorder := events.NewFakeRecorder(10)
+
+			err := EnsureNetworkPolicy(context.Background(), cl, recorder, owner, "test-manager", tt.desired,
+				GenerateOptions{SetControllerRef: true})
+			require.NoError(t, err)
+
+			err = cl.Get(context.Background(), client.ObjectKeyFromObject(owner), &networkingv1.NetworkPolicy{})
+			if tt.wantExists {
+				require.NoError(t, err)
+			} else {
+				assert.True(t, k8serrors.IsNotFound(err), "policy must not exist, got %v", err)
+			}
+
+			if tt.wantEvent == "" {
+				assert.Empty(t, recorder.Events)
+			} else {
+				require.Len(t, recorder.Events, 1)
+				assert.True(t, strings.Contains(<-recorder.Events, tt.wantEvent))
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+)
+
+// MaxNodePoolSelectorTerms bounds the number of node selector terms computed for a workload.
+// Excluding the nodes of a pool multiplies the terms by the number of requirements of its selector.
+const MaxNodePoolSelectorTerms = 64
+
+// NodePoolSelectorTerms returns the required node affinity terms of the workload running on the nodes
+// of pools[index], or of the default workload when index is len(pools). A node belongs to the first pool
+// whose selector matches it, so the terms select the nodes of the pool and exclude the nodes of the pools
+// listed before it; the default workload excludes the nodes of every pool. The terms are combined with
+// the required terms set by the user, which must all be satisfied as well.
+func NodePoolSelectorTerms(pools []instancev1alpha1.NodePool, index int,
+	userTerms []corev1.NodeSelectorTerm) ([]corev1.NodeSelectorTerm, error) {
+	if index < 0 || index > len(pools) {
+		return nil, fmt.Errorf("node pool index %d out of range", index)
+	}
+
+	terms := []corev1.NodeSelectorTerm{{}}
+	if index < len(pools) {
+		requirements, err := nodeSelectorRequirements(&pools[index])
+		if err != nil {
+			return nil, err
+		}
+		terms[0].MatchExpressions = requirements
+	}
+
+	// Not matching a previous pool means failing at least one of its requirements: each negated
+	// requirement is an alternative, and alternatives are expressed as separate terms.
+	for i := range index {
+		requirements, err := nodeSelectorRequirements(&pools[i])
+		if err != nil {
+			return nil, err
+		}
+		alternatives := make([]corev1.NodeSelectorTerm, 0, len(requirements))
+		for _, req := range requirements {
+			alternatives = append(alternatives, corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{negateRequirement(req)},
+			})
+		}
+		if terms, err = crossTerms(terms, alternatives); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(userTerms) > 0 {
+		var err error
+		if terms, err = crossTerms(terms, userTerms); err != nil {
+			return nil, err
+		}
+	}
+
+	return terms, nil
+}
+
+// nodeSelectorRequirements converts the label selector of a pool into node selector requirements.
+func nodeSelectorRequirements(pool *instancev1alpha1.NodePool) ([]corev1.NodeSelectorRequirement, error) {
+	selector := pool.NodeSelector
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return nil, fmt.Errorf("node pool %q has an empty node selector", pool.Name)
+	}
+
+	requirements := make([]corev1.NodeSelectorRequirement, 0, len(selector.MatchLabels)+len(selector.MatchExpressions))
+	keys := make([]string, 0, len(selector.MatchLabels))
+	for key := range selector.MatchLabels {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{selector.MatchLabels[key]},
+		})
+	}
+
+	for _, expr := range selector.MatchExpressions {
+		var op corev1.NodeSelectorOperator
+		switch expr.Operator {
+		case metav1.LabelSelectorOpIn:
+			op = corev1.NodeSelectorOpIn
+		case metav1.LabelSelectorOpNotIn:
+			op = corev1.NodeSelectorOpNotIn
+		case metav1.LabelSelectorOpExists:
+			op = corev1.NodeSelectorOpExists
+		case metav1.LabelSelectorOpDoesNotExist:
+			op = corev1.NodeSelectorOpDoesNotExist
+		default:
+			return nil, fmt.Errorf("node pool %q: unsupported selector operator %q", pool.Name, expr.Operator)
+		}
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      expr.Key,
+			Operator: op,
+			Values:   slices.Clone(expr.Values),
+		})
+	}
+
+	return requirements, nil
+}
+
+// negateRequirement returns the requirement matching exactly the nodes the given one does not match.
+func negateRequirement(req corev1.NodeSelectorRequirement) corev1.NodeSelectorRequirement {
+	negated := req
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		negated.Operator = corev1.NodeSelectorOpNotIn
+	case corev1.NodeSelectorOpNotIn:
+		negated.Operator = corev1.NodeSelectorOpIn
+	case corev1.NodeSelectorOpExists:
+		negated.Operator = corev1.NodeSelectorOpDoesNotExist
+	case corev1.NodeSelectorOpDoesNotExist:
+		negated.Operator = corev1.NodeSelectorOpExists
+	}
+	return negated
+}
+
+// crossTerms returns the terms matching the nodes matched by one of the terms and by one of the others.
+func crossTerms(terms, others []corev1.NodeSelectorTerm) ([]corev1.NodeSelectorTerm, error) {
+	if len(terms)*len(others) > MaxNodePoolSelectorTerms {
+		return nil, fmt.Errorf("node pools require more than %d node selector terms, simplify their node selectors",
+			MaxNodePoolSelectorTerms)
+	}
+
+	crossed := make([]corev1.NodeSelectorTerm, 0, len(terms)*len(others))
+	for _, term := range terms {
+		for _, other := range others {
+			crossed = append(crossed, corev1.NodeSelectorTerm{
+				MatchExpressions: append(slices.Clone(term.MatchExpressions), other.MatchExpressions...),
+				MatchFields:      append(slices.Clone(term.MatchFields), other.MatchFields...),
+			})
+		}
+	}
+	return crossed, nil
+}
+
+// MergeNodePoolConfig merges the configuration of a node pool on top of the base Falco configuration
+// and returns the resulting YAML document. Nested objects are merged, any other value is replaced.
+func MergeNodePoolConfig(base string, config *apiextensionsv1.JSON) (string, error) {
+	doc := map[string]any{}
+	if err := yaml.Unmarshal([]byte(base), &doc); err != nil {
+		return "", fmt.Errorf("parsing base configuration: %w", err)
+	}
+
+	if config != nil && len(config.Raw) > 0 {
+		overlay := map[string]any{}
+		if err := yaml.Unmarshal(config.Raw, &overlay); err != nil {
+			return "", fmt.Errorf("parsing node pool configuration: %w", err)
+		}
+		mergeConfig(doc, overlay)
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("marshaling node pool configuration: %w", err)
+	}
+	return string(out), nil
+}
+
+// mergeConfig merges overlay into dst recursively.
+func mergeConfig(dst, overlay map[string]any) {
+	for key, value := range overlay {
+		if overlayMap, ok := value.(map[string]any); ok {
+			if dstMap, ok := dst[key].(map[string]any); ok {
+				mergeConfig(dstMap, overlayMap)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
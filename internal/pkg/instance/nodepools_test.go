@@ -0,0 +1,176 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+)
+
+func nodeRequirement(key string, op corev1.NodeSelectorOperator, values ...string) corev1.NodeSelectorRequirement {
+	return corev1.NodeSelectorRequirement{Key: key, Operator: op, Values: values}
+}
+
+func TestNodePoolSelectorTerms(t *testing.T) {
+	pools := []instancev1alpha1.NodePool{
+		{
+			Name:         "gpu",
+			NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
+		},
+		{
+			Name: "arm",
+			NodeSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/arch": "arm64"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "system", Operator: metav1.LabelSelectorOpDoesNotExist},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		index     int
+		userTerms []corev1.NodeSelectorTerm
+		want      []corev1.NodeSelectorTerm
+	}{
+		{
+			name:  "first pool only selects its nodes",
+			index: 0,
+			want: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{nodeRequirement("gpu", corev1.NodeSelectorOpIn, "true")}},
+			},
+		},
+		{
+			name:  "second pool excludes the nodes of the first",
+			index: 1,
+			want: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					nodeRequirement("kubernetes.io/arch", corev1.NodeSelectorOpIn, "arm64"),
+					nodeRequirement("system", corev1.NodeSelectorOpDoesNotExist),
+					nodeRequirement("gpu", corev1.NodeSelectorOpNotIn, "true"),
+				}},
+			},
+		},
+		{
+			name:  "default workload excludes the nodes of every pool",
+			index: 2,
+			want: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					nodeRequirement("gpu", corev1.NodeSelectorOpNotIn, "true"),
+					nodeRequirement("kubernetes.io/arch", corev1.NodeSelectorOpNotIn, "arm64"),
+				}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					nodeRequirement("gpu", corev1.NodeSelectorOpNotIn, "true"),
+					nodeRequirement("system", corev1.NodeSelectorOpExists),
+				}},
+			},
+		},
+		{
+			name:  "user terms are combined",
+			index: 0,
+			userTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{nodeRequirement("zone", corev1.NodeSelectorOpIn, "a")}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{nodeRequirement("zone", corev1.NodeSelectorOpIn, "b")}},
+			},
+			want: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					nodeRequirement("gpu", corev1.NodeSelectorOpIn, "true"),
+					nodeRequirement("zone", corev1.NodeSelectorOpIn, "a"),
+				}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					nodeRequirement("gpu", corev1.NodeSelectorOpIn, "true"),
+					nodeRequirement("zone", corev1.NodeSelectorOpIn, "b"),
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NodePoolSelectorTerms(pools, tt.index, tt.userTerms)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNodePoolSelectorTermsErrors(t *testing.T) {
+	t.Run("empty selector", func(t *testing.T) {
+		_, err := NodePoolSelectorTerms([]instancev1alpha1.NodePool{{Name: "empty"}}, 0, nil)
+		require.ErrorContains(t, err, `node pool "empty" has an empty node selector`)
+	})
+
+	t.Run("index out of range", func(t *testing.T) {
+		_, err := NodePoolSelectorTerms(nil, 1, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("too many terms", func(t *testing.T) {
+		labels := map[string]string{}
+		for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			labels[key] = "x"
+		}
+		pools := []instancev1alpha1.NodePool{
+			{Name: "one", NodeSelector: metav1.LabelSelector{MatchLabels: labels}},
+			{Name: "two", NodeSelector: metav1.LabelSelector{MatchLabels: labels}},
+			{Name: "three", NodeSelector: metav1.LabelSelector{MatchLabels: labels}},
+		}
+		_, err := NodePoolSelectorTerms(pools, 3, nil)
+		require.ErrorContains(t, err, "node selector terms")
+	})
+}
+
+func TestMergeNodePoolConfig(t *testing.T) {
+	base := "engine:\n  kind: modern_ebpf\n  modern_ebpf:\n    buf_size_preset: 4\nrules_files:\n- /etc/falco/rules.d\n"
+
+	t.Run("nil config keeps the base configuration", func(t *testing.T) {
+		got, err := MergeNodePoolConfig(base, nil)
+		require.NoError(t, err)
+		assert.YAMLEq(t, base, got)
+	})
+
+	t.Run("nested objects are merged and other values replaced", func(t *testing.T) {
+		got, err := MergeNodePoolConfig(base, &apiextensionsv1.JSON{
+			Raw: []byte(`{"engine":{"modern_ebpf":{"buf_size_preset":6}},"rules_files":["/etc/falco/gpu"]}`),
+		})
+		require.NoError(t, err)
+
+		doc := map[string]any{}
+		require.NoError(t, yaml.Unmarshal([]byte(got), &doc))
+		assert.Equal(t, map[string]any{
+			"engine": map[string]any{
+				"kind":        "modern_ebpf",
+				"modern_ebpf": map[string]any{"buf_size_preset": float64(6)},
+			},
+			"rules_files": []any{"/etc/falco/gpu"},
+		}, doc)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := MergeNodePoolConfig(base, &apiextensionsv1.JSON{Raw: []byte(`[1]`)})
+		require.Error(t, err)
+	})
+}
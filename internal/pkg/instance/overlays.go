@@ -0,0 +1,165 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+)
+
+// ErrInvalidOverlay is returned when an overlay cannot be decoded or applied to the resource it targets.
+var ErrInvalidOverlay = errors.New("invalid overlay")
+
+// ValidateOverlays checks that the patches of the overlays can be decoded in their format.
+func ValidateOverlays(overlays []commonv1alpha1.Overlay) error {
+	for i := range overlays {
+		if _, err := decodeOverlay(&overlays[i]); err != nil {
+			return overlayError(i, &overlays[i], err)
+		}
+	}
+	return nil
+}
+
+// ApplyOverlays applies, in order, the overlays targeting the resource and returns the patched resource.
+// The scheme is used to look up the patch strategies of the strategic merge patches; a resource of a
+// type it doesn't know is merged as a JSON merge patch. The resource is returned as is when no
+// overlay targets it.
+func ApplyOverlays(scheme *runtime.Scheme, resource *unstructured.Unstructured,
+	overlays []commonv1alpha1.Overlay) (*unstructured.Unstructured, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	var doc []byte
+	for i := range overlays {
+		overlay := &overlays[i]
+		if overlay.Target.Kind != resource.GetKind() ||
+			(overlay.Target.Name != "" && overlay.Target.Name != resource.GetName()) {
+			continue
+		}
+
+		if doc == nil {
+			var err error
+			if doc, err = json.Marshal(resource.Object); err != nil {
+				return nil, fmt.Errorf("unable to marshal %s %s: %w", resource.GetKind(), resource.GetName(), err)
+			}
+		}
+
+		patched, err := applyOverlay(scheme, resource, doc, overlay)
+		if err != nil {
+			return nil, overlayError(i, overlay, err)
+		}
+		doc = patched
+	}
+
+	if doc == nil {
+		return resource, nil
+	}
+
+	patched := &unstructured.Unstructured{}
+	if err := json.Unmarshal(doc, &patched.Object); err != nil {
+		return nil, fmt.Errorf("%w: unable to decode the patched %s %s: %w",
+			ErrInvalidOverlay, resource.GetKind(), resource.GetName(), err)
+	}
+
+	// The overlays can't move the resource: the operator would lose track of it.
+	if patched.GetAPIVersion() != resource.GetAPIVersion() || patched.GetKind() != resource.GetKind() ||
+		patched.GetName() != resource.GetName() || patched.GetNamespace() != resource.GetNamespace() {
+		return nil, fmt.Errorf("%w: the overlays cannot change the apiVersion, kind, name or namespace of %s %s",
+			ErrInvalidOverlay, resource.GetKind(), resource.GetName())
+	}
+
+	return patched, nil
+}
+
+// RecordInvalidOverlay sets the Reconciled condition to False with the overlay error, and emits a
+// warning event.
+func RecordInvalidOverlay(recorder events.EventRecorder, owner client.Object,
+	conditions *[]metav1.Condition, err error) {
+	message := fmt.Sprintf(MessageFormatInvalidOverlay, err.Error())
+	apimeta.SetStatusCondition(conditions, common.NewReconciledCondition(
+		metav1.ConditionFalse, ReasonInvalidOverlay, message, owner.GetGeneration()))
+	recorder.Eventf(owner, nil, corev1.EventTypeWarning, ReasonInvalidOverlay, ReasonInvalidOverlay, message)
+}
+
+// decodeOverlay checks that the patch of the overlay is well-formed for its format, and returns the
+// operations of a JSON6902 patch.
+func decodeOverlay(overlay *commonv1alpha1.Overlay) (jsonpatch.Patch, error) {
+	if len(overlay.Patch.Raw) == 0 {
+		return nil, errors.New("the patch is empty")
+	}
+
+	switch overlay.Type {
+	case commonv1alpha1.OverlayPatchTypeJSON6902:
+		operations, err := jsonpatch.DecodePatch(overlay.Patch.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode the JSON6902 patch: %w", err)
+		}
+		return operations, nil
+	case commonv1alpha1.OverlayPatchTypeStrategicMerge, "":
+		var patch map[string]any
+		if err := json.Unmarshal(overlay.Patch.Raw, &patch); err != nil || patch == nil {
+			return nil, errors.New("the strategic merge patch must be an object")
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown patch type %q", overlay.Type)
+	}
+}
+
+// applyOverlay applies the overlay to the JSON document of the resource.
+func applyOverlay(scheme *runtime.Scheme, resource *unstructured.Unstructured,
+	doc []byte, overlay *commonv1alpha1.Overlay) ([]byte, error) {
+	operations, err := decodeOverlay(overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	if operations != nil {
+		return operations.Apply(doc)
+	}
+
+	if scheme != nil {
+		if dataStruct, err := scheme.New(resource.GroupVersionKind()); err == nil {
+			return strategicpatch.StrategicMergePatch(doc, overlay.Patch.Raw, dataStruct)
+		}
+	}
+	return jsonpatch.MergePatch(doc, overlay.Patch.Raw)
+}
+
+// overlayError wraps the error of the i-th overlay in ErrInvalidOverlay.
+func overlayError(i int, overlay *commonv1alpha1.Overlay, err error) error {
+	target := overlay.Target.Kind
+	if overlay.Target.Name != "" {
+		target += " " + overlay.Target.Name
+	}
+	return fmt.Errorf("%w: overlay %d (%s): %w", ErrInvalidOverlay, i, target, err)
+}
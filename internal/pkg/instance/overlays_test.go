@@ -0,0 +1,218 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/builders"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+)
+
+// newOverlay builds an overlay of the given type targeting the kind and name.
+func newOverlay(kind, name string, patchType commonv1alpha1.OverlayPatchType, patch string) commonv1alpha1.Overlay {
+	return commonv1alpha1.Overlay{
+		Target: commonv1alpha1.OverlayTarget{Kind: kind, Name: name},
+		Type:   patchType,
+		Patch:  apiextensionsv1.JSON{Raw: []byte(patch)},
+	}
+}
+
+func TestValidateOverlays(t *testing.T) {
+	tests := []struct {
+		name     string
+		overlays []commonv1alpha1.Overlay
+		wantErr  string
+	}{
+		{
+			name: "no overlays",
+		},
+		{
+			name: "valid overlays",
+			overlays: []commonv1alpha1.Overlay{
+				newOverlay("Service", "", commonv1alpha1.OverlayPatchTypeStrategicMerge, `{"metadata":{"annotations":{"a":"b"}}}`),
+				newOverlay("Service", "", "", `{"spec":{"type":"LoadBalancer"}}`),
+				newOverlay("ClusterRole", "", commonv1alpha1.OverlayPatchTypeJSON6902, `[{"op":"add","path":"/rules/-","value":{}}]`),
+			},
+		},
+		{
+			name:     "strategic merge patch is not an object",
+			overlays: []commonv1alpha1.Overlay{newOverlay("Service", "", commonv1alpha1.OverlayPatchTypeStrategicMerge, `[]`)},
+			wantErr:  "overlay 0 (Service): the strategic merge patch must be an object",
+		},
+		{
+			name:     "JSON6902 patch is not a list of operations",
+			overlays: []commonv1alpha1.Overlay{newOverlay("Service", "falco", commonv1alpha1.OverlayPatchTypeJSON6902, `{"op":"add"}`)},
+			wantErr:  "overlay 0 (Service falco): unable to decode the JSON6902 patch",
+		},
+		{
+			name: "empty patch",
+			overlays: []commonv1alpha1.Overlay{
+				newOverlay("Service", "", "", `{}`),
+				newOverlay("ConfigMap", "", "", ``),
+			},
+			wantErr: "overlay 1 (ConfigMap): the patch is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOverlays(tt.overlays)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidOverlay))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestApplyOverlays(t *testing.T) {
+	scheme := testScheme(t)
+
+	service, err := controllerhelper.ToUnstructured(builders.NewService().WithName("falco").WithNamespace("default").
+		WithLabels(map[string]string{"app": "falco"}).Build())
+	require.NoError(t, err)
+	role, err := controllerhelper.ToUnstructured(builders.NewClusterRole().WithName("falco--default").Build())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		resource *unstructured.Unstructured
+		overlays []commonv1alpha1.Overlay
+		check    func(t *testing.T, result *unstructured.Unstructured)
+		wantErr  string
+	}{
+		{
+			name:     "overlays targeting other resources are skipped",
+			resource: service,
+			overlays: []commonv1alpha1.Overlay{
+				newOverlay("ServiceAccount", "", "", `{"metadata":{"annotations":{"a":"b"}}}`),
+				newOverlay("Service", "metacollector", "", `{"metadata":{"annotations":{"a":"b"}}}`),
+			},
+			check: func(t *testing.T, result *unstructured.Unstructured) {
+				assert.Same(t, service, result)
+			},
+		},
+		{
+			name:     "strategic merge patch",
+			resource: service,
+			overlays: []commonv1alpha1.Overlay{newOverlay("Service", "falco", "",
+				`{"metadata":{"annotations":{"service.beta.kubernetes.io/aws-load-balancer-internal":"true"}},"spec":{"type":"LoadBalancer"}}`)},
+			check: func(t *testing.T, result *unstructured.Unstructured) {
+				assert.Equal(t, map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}, result.GetAnnotations())
+				assert.Equal(t, map[string]string{"app": "falco"}, result.GetLabels())
+				serviceType, _, _ := unstructured.NestedString(result.Object, "spec", "type")
+				assert.Equal(t, "LoadBalancer", serviceType)
+			},
+		},
+		{
+			name:     "overlays are applied in order",
+			resource: service,
+			overlays: []commonv1alpha1.Overlay{
+				newOverlay("Service", "", "", `{"metadata":{"labels":{"team":"security"}}}`),
+				newOverlay("Service", "", commonv1alpha1.OverlayPatchTypeJSON6902, `[{"op":"remove","path":"/metadata/labels/app"}]`),
+			},
+			check: func(t *testing.T, result *unstructured.Unstructured) {
+				assert.Equal(t, map[string]string{"team": "security"}, result.GetLabels())
+				assert.Equal(t, map[string]string{"app": "falco"}, service.GetLabels())
+			},
+		},
+		{
+			name:     "JSON6902 patch adding a rule",
+			resource: role,
+			overlays: []commonv1alpha1.Overlay{newOverlay("ClusterRole", "", commonv1alpha1.OverlayPatchTypeJSON6902,
+				`[{"op":"add","path":"/rules","value":[{"apiGroups":[""],"resources":["secrets"],"verbs":["get"]}]}]`)},
+			check: func(t *testing.T, result *unstructured.Unstructured) {
+				rules, _, _ := unstructured.NestedSlice(result.Object, "rules")
+				require.Len(t, rules, 1)
+				assert.Equal(t, []any{"secrets"}, rules[0].(map[string]any)["resources"])
+			},
+		},
+		{
+			name: "type unknown to the scheme is merged as a JSON merge patch",
+			resource: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "monitoring.coreos.com/v1",
+				"kind":       "ServiceMonitor",
+				"metadata":   map[string]any{"name": "falco"},
+				"spec":       map[string]any{"endpoints": []any{map[string]any{"port": "metrics"}}},
+			}},
+			overlays: []commonv1alpha1.Overlay{newOverlay("ServiceMonitor", "", "", `{"spec":{"endpoints":[{"port":"http"}]}}`)},
+			check: func(t *testing.T, result *unstructured.Unstructured) {
+				endpoints, _, _ := unstructured.NestedSlice(result.Object, "spec", "endpoints")
+				assert.Equal(t, []any{map[string]any{"port": "http"}}, endpoints)
+			},
+		},
+		{
+			name:     "JSON6902 operation on a missing path",
+			resource: service,
+			overlays: []commonv1alpha1.Overlay{newOverlay("Service", "", commonv1alpha1.OverlayPatchTypeJSON6902,
+				`[{"op":"replace","path":"/spec/externalName","value":"x"}]`)},
+			wantErr: "overlay 0 (Service)",
+		},
+		{
+			name:     "overlay moving the resource to another namespace",
+			resource: service,
+			overlays: []commonv1alpha1.Overlay{newOverlay("Service", "", "", `{"metadata":{"namespace":"other"}}`)},
+			wantErr:  "cannot change the apiVersion, kind, name or namespace of Service falco",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyOverlays(scheme, tt.resource, tt.overlays)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrInvalidOverlay))
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, result)
+		})
+	}
+}
+
+func TestRecordInvalidOverlay(t *testing.T) {
+	recorder := events.NewFakeRecorder(1)
+	owner := newConfigMap()
+	owner.Generation = 3
+	var conditions []metav1.Condition
+
+	RecordInvalidOverlay(recorder, owner, &conditions, ValidateOverlays([]commonv1alpha1.Overlay{
+		newOverlay("Service", "", "", `[]`),
+	}))
+
+	require.Len(t, conditions, 1)
+	assert.Equal(t, string(commonv1alpha1.ConditionReconciled), conditions[0].Type)
+	assert.Equal(t, metav1.ConditionFalse, conditions[0].Status)
+	assert.Equal(t, ReasonInvalidOverlay, conditions[0].Reason)
+	assert.Contains(t, conditions[0].Message, "the strategic merge patch must be an object")
+	assert.Equal(t, int64(3), conditions[0].ObservedGeneration)
+	assert.Contains(t, <-recorder.Events, ReasonInvalidOverlay)
+}
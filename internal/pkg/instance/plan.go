@@ -0,0 +1,118 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+)
+
+const (
+	// ReconcileModeAnnotation selects how an instance is reconciled, overriding the operator-wide mode.
+	ReconcileModeAnnotation = "falcosecurity.dev/reconcile-mode"
+	// ReconcileModeApply applies the changes to the generated resources. It is the default mode.
+	ReconcileModeApply = "apply"
+	// ReconcileModePlan computes the changes to the generated resources without applying them.
+	ReconcileModePlan = "plan"
+)
+
+// ValidReconcileMode reports whether the given mode is a known reconcile mode.
+func ValidReconcileMode(mode string) bool {
+	return mode == ReconcileModeApply || mode == ReconcileModePlan
+}
+
+// ResolveReconcileMode returns the reconcile mode of the object: the mode of its annotation when known,
+// otherwise the operator-wide default, otherwise apply.
+func ResolveReconcileMode(obj client.Object, def string) string {
+	if mode := obj.GetAnnotations()[ReconcileModeAnnotation]; ValidReconcileMode(mode) {
+		return mode
+	}
+	if ValidReconcileMode(def) {
+		return def
+	}
+	return ReconcileModeApply
+}
+
+// Plan collects the changes that are computed but not applied while an instance is reconciled in plan mode.
+type Plan struct {
+	changes []commonv1alpha1.PendingChange
+}
+
+// Add records a change to a generated resource.
+func (p *Plan) Add(action commonv1alpha1.PendingAction, kind, name, changedFields string) {
+	p.changes = append(p.changes, commonv1alpha1.PendingChange{
+		Action:        action,
+		Kind:          kind,
+		Name:          name,
+		ChangedFields: changedFields,
+	})
+}
+
+// Changes returns the recorded changes in the order they were recorded.
+func (p *Plan) Changes() []commonv1alpha1.PendingChange {
+	return p.changes
+}
+
+// planContextKey is the context key of the plan.
+type planContextKey struct{}
+
+// PlanIntoContext returns a copy of ctx carrying the plan. The resources ensured with the returned
+// context are recorded in the plan instead of being applied.
+func PlanIntoContext(ctx context.Context, plan *Plan) context.Context {
+	return context.WithValue(ctx, planContextKey{}, plan)
+}
+
+// PlanFromContext returns the plan carried by ctx, or nil when the changes must be applied.
+func PlanFromContext(ctx context.Context) *Plan {
+	plan, _ := ctx.Value(planContextKey{}).(*Plan)
+	return plan
+}
+
+// RecordPlan publishes the changes of the plan and returns them to be stored in the status of the owner.
+// When changes are pending, the Reconciled condition is set to False and an event is recorded if they
+// differ from the previously published ones. It returns nil when the plan is nil or empty.
+func RecordPlan(recorder events.EventRecorder, owner client.Object, conditions *[]metav1.Condition,
+	previous []commonv1alpha1.PendingChange, plan *Plan) []commonv1alpha1.PendingChange {
+	if plan == nil || len(plan.Changes()) == 0 {
+		return nil
+	}
+	changes := plan.Changes()
+
+	summary := make([]string, 0, len(changes))
+	for _, change := range changes {
+		summary = append(summary, fmt.Sprintf("%s %s %s", change.Action, change.Kind, change.Name))
+	}
+	message := fmt.Sprintf(MessageFormatChangesPending, len(changes), strings.Join(summary, ", "))
+
+	apimeta.SetStatusCondition(conditions, common.NewReconciledCondition(
+		metav1.ConditionFalse, ReasonChangesPending, message, owner.GetGeneration()))
+	if !apiequality.Semantic.DeepEqual(previous, changes) {
+		recorder.Eventf(owner, nil, corev1.EventTypeNormal, ReasonChangesPending, ReasonChangesPending, message)
+	}
+	return changes
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+)
+
+func TestResolveReconcileMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		def        string
+		want       string
+	}{
+		{name: "defaults to apply", want: ReconcileModeApply},
+		{name: "operator-wide plan", def: ReconcileModePlan, want: ReconcileModePlan},
+		{name: "annotation plan", annotation: ReconcileModePlan, want: ReconcileModePlan},
+		{name: "annotation apply overrides operator-wide plan", annotation: ReconcileModeApply, def: ReconcileModePlan, want: ReconcileModeApply},
+		{name: "unknown annotation falls back to the default", annotation: "dry-run", def: ReconcileModePlan, want: ReconcileModePlan},
+		{name: "unknown default falls back to apply", def: "dry-run", want: ReconcileModeApply},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := &instancev1alpha1.Falco{}
+			if tt.annotation != "" {
+				falco.Annotations = map[string]string{ReconcileModeAnnotation: tt.annotation}
+			}
+			assert.Equal(t, tt.want, ResolveReconcileMode(falco, tt.def))
+		})
+	}
+}
+
+func TestPlanContext(t *testing.T) {
+	assert.Nil(t, PlanFromContext(context.Background()))
+
+	plan := &Plan{}
+	ctx := PlanIntoContext(context.Background(), plan)
+	require.Same(t, plan, PlanFromContext(ctx))
+
+	PlanFromContext(ctx).Add(commonv1alpha1.PendingActionDelete, "Pod", "falco-a", "")
+	assert.Equal(t, []commonv1alpha1.PendingChange{
+		{Action: commonv1alpha1.PendingActionDelete, Kind: "Pod", Name: "falco-a"},
+	}, plan.Changes())
+}
+
+func TestRecordPlan(t *testing.T) {
+	change := commonv1alpha1.PendingChange{
+		Action: commonv1alpha1.PendingActionUpdate, Kind: "DaemonSet", Name: "falco", ChangedFields: "spec.template",
+	}
+
+	tests := []struct {
+		name          string
+		plan          *Plan
+		previous      []commonv1alpha1.PendingChange
+		wantChanges   []commonv1alpha1.PendingChange
+		wantCondition bool
+		wantEvent     bool
+	}{
+		{name: "apply mode clears the pending changes", previous: []commonv1alpha1.PendingChange{change}},
+		{name: "empty plan has no pending changes", plan: &Plan{}},
+		{
+			name:          "new changes are published",
+			plan:          &Plan{changes: []commonv1alpha1.PendingChange{change}},
+			wantChanges:   []commonv1alpha1.PendingChange{change},
+			wantCondition: true,
+			wantEvent:     true,
+		},
+		{
+			name:          "unchanged changes are not recorded again",
+			plan:          &Plan{changes: []commonv1alpha1.PendingChange{change}},
+			previous:      []commonv1alpha1.PendingChange{change},
+			wantChanges:   []commonv1alpha1.PendingChange{change},
+			wantCondition: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falco := &instancev1alpha1.Falco{ObjectMeta: metav1.ObjectMeta{Name: "falco", Generation: 3}}
+			recorder := events.NewFakeRecorder(10)
+
+			changes := RecordPlan(recorder, falco, &falco.Status.Conditions, tt.previous, tt.plan)
+
+			assert.Equal(t, tt.wantChanges, changes)
+			cond := apimeta.FindStatusCondition(falco.Status.Conditions, commonv1alpha1.ConditionReconciled.String())
+			if tt.wantCondition {
+				require.NotNil(t, cond)
+				assert.Equal(t, metav1.ConditionFalse, cond.Status)
+				assert.Equal(t, ReasonChangesPending, cond.Reason)
+				assert.Equal(t, "Changes pending in plan mode (1): Update DaemonSet falco", cond.Message)
+				assert.Equal(t, int64(3), cond.ObservedGeneration)
+			} else {
+				assert.Nil(t, cond)
+			}
+			if tt.wantEvent {
+				require.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, ReasonChangesPending)
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
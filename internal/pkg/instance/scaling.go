@@ -0,0 +1,91 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/managedfields"
+)
+
+// EnsurePodDisruptionBudget ensures the PodDisruptionBudget generated for the owner matches the desired one:
+// it is applied like EnsureResource, or deleted when desired is nil because the owner no longer needs it.
+func EnsurePodDisruptionBudget(ctx context.Context, cl client.Client, recorder events.EventRecorder,
+	owner client.Object, fieldManager string, desired *policyv1.PodDisruptionBudget, options GenerateOptions) error {
+	if desired != nil {
+		return EnsureResource(ctx, cl, recorder, owner, fieldManager, desired, options)
+	}
+	return deleteDisabledResource(ctx, cl, recorder, owner, policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"),
+		owner.GetName(), ReasonPodDisruptionBudgetCleanup)
+}
+
+// EnsureHorizontalPodAutoscaler ensures the HorizontalPodAutoscaler generated for the owner matches the desired
+// one: it is applied like EnsureResource, or deleted when desired is nil because autoscaling is no longer enabled.
+func EnsureHorizontalPodAutoscaler(ctx context.Context, cl client.Client, recorder events.EventRecorder,
+	owner client.Object, fieldManager string, desired *autoscalingv2.HorizontalPodAutoscaler, options GenerateOptions) error {
+	if desired != nil {
+		return EnsureResource(ctx, cl, recorder, owner, fieldManager, desired, options)
+	}
+	return deleteDisabledResource(ctx, cl, recorder, owner,
+		autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"), owner.GetName(), ReasonAutoscalerCleanup)
+}
+
+// HandOverReplicas hands the replicas of the existing workload over to handoverManager when fieldManager
+// owns them, before fieldManager stops applying them for an autoscaler to manage them. Without it, the
+// API server would remove the replicas, and reset them to their default, at the first apply leaving them
+// out. The handover manager only applies their current value once: the autoscaler takes them over the
+// next time it scales the workload.
+func HandOverReplicas(ctx context.Context, cl client.Client, existing *unstructured.Unstructured,
+	fieldManager, handoverManager string) error {
+	owned, err := managedfields.ExtractAsUnstructured(existing, fieldManager)
+	if err != nil {
+		return fmt.Errorf("unable to extract the fields of %s: %w", existing.GetKind(), err)
+	}
+	if owned == nil {
+		return nil
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(owned.Object, "spec", "replicas"); !found {
+		return nil
+	}
+	replicas, found, err := unstructured.NestedInt64(existing.Object, "spec", "replicas")
+	if err != nil || !found {
+		return err
+	}
+
+	handover := &unstructured.Unstructured{}
+	handover.SetGroupVersionKind(existing.GroupVersionKind())
+	handover.SetName(existing.GetName())
+	handover.SetNamespace(existing.GetNamespace())
+	if err := unstructured.SetNestedField(handover.Object, replicas, "spec", "replicas"); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Handing the replicas over to the autoscaler", "kind", existing.GetKind(),
+		"name", existing.GetName(), "replicas", replicas)
+	if err := cl.Apply(ctx, client.ApplyConfigurationFromUnstructured(handover), client.FieldOwner(handoverManager)); err != nil {
+		return fmt.Errorf("unable to hand the replicas of %s over: %w", existing.GetKind(), err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestHandOverReplicas(t *testing.T) {
+	const manager, handoverManager = "test-manager", "test-manager-handover"
+
+	existingDeployment := func(managedFields string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		u.SetName("test")
+		u.SetNamespace("default")
+		require.NoError(t, unstructured.SetNestedField(u.Object, int64(3), "spec", "replicas"))
+		if managedFields != "" {
+			u.SetManagedFields([]metav1.ManagedFieldsEntry{{
+				Manager:    manager,
+				Operation:  metav1.ManagedFieldsOperationApply,
+				APIVersion: "apps/v1",
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(managedFields)},
+			}})
+		}
+		return u
+	}
+
+	tests := []struct {
+		name         string
+		existing     *unstructured.Unstructured
+		wantHandover bool
+	}{
+		{
+			name:     "never applied by the manager",
+			existing: existingDeployment(""),
+		},
+		{
+			name:     "replicas not owned by the manager",
+			existing: existingDeployment(`{"f:spec":{"f:strategy":{}}}`),
+		},
+		{
+			name:         "replicas owned by the manager",
+			existing:     existingDeployment(`{"f:spec":{"f:replicas":{},"f:strategy":{}}}`),
+			wantHandover: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied map[string]any
+			var owner string
+			cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Apply: func(_ context.Context, _ client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+					data, err := json.Marshal(obj)
+					require.NoError(t, err)
+					require.NoError(t, json.Unmarshal(data, &applied))
+					applyOpts := &client.ApplyOptions{}
+					applyOpts.ApplyOptions(opts)
+					owner = applyOpts.FieldManager
+					return nil
+				},
+			}).Build()
+
+			require.NoError(t, HandOverReplicas(context.Background(), cl, tt.existing, manager, handoverManager))
+
+			if !tt.wantHandover {
+				assert.Nil(t, applied)
+				return
+			}
+			assert.Equal(t, handoverManager, owner)
+			assert.Equal(t, map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "test", "namespace": "default"},
+				"spec":       map[string]any{"replicas": float64(3)},
+			}, applied)
+		})
+	}
+}
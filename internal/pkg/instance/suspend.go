@@ -0,0 +1,39 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/common"
+)
+
+// RecordSuspended sets the Suspended condition of the owner while it is suspended and removes it otherwise.
+// An event is recorded when the owner is suspended or resumed.
+func RecordSuspended(recorder events.EventRecorder, owner client.Object, conditions *[]metav1.Condition, suspended bool) {
+	if !common.SetSuspendedCondition(conditions, suspended, ReasonSuspended, MessageSuspended, owner.GetGeneration()) {
+		return
+	}
+	if suspended {
+		recorder.Eventf(owner, nil, corev1.EventTypeNormal, ReasonSuspended, ReasonSuspended, MessageSuspended)
+	} else {
+		recorder.Eventf(owner, nil, corev1.EventTypeNormal, ReasonResumed, ReasonResumed, MessageResumed)
+	}
+}
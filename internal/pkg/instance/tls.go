@@ -0,0 +1,168 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/pki"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+// EnsureCertificate ensures the certificate Secret of the owner holds a certificate signed by the CA of its
+// namespace when enabled, and deletes the Secret otherwise. The CA and the certificate are issued when missing or
+// invalid, and again once their renew time is reached. The Secret is applied like EnsureResource. It returns the
+// status of the certificate, nil when not enabled.
+func EnsureCertificate(ctx context.Context, cl client.Client, recorder events.EventRecorder, owner client.Object,
+	fieldManager string, enabled bool, options GenerateOptions, now time.Time) (*commonv1alpha1.TLSStatus, error) {
+	secretName := resources.CertificateSecretName(owner.GetName())
+	if !enabled {
+		return nil, deleteDisabledResource(ctx, cl, recorder, owner, corev1.SchemeGroupVersion.WithKind("Secret"),
+			secretName, ReasonCertificateCleanup)
+	}
+
+	ca, caBundle, err := ensureCA(ctx, cl, recorder, owner, now)
+	if err != nil {
+		return nil, err
+	}
+	caCert, err := pki.ParseCertificate(ca.CertPEM)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the CA certificate: %w", err)
+	}
+
+	var pair pki.KeyPair
+	existing := &corev1.Secret{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: owner.GetNamespace(), Name: secretName}, existing); err == nil {
+		pair = pki.KeyPair{CertPEM: existing.Data[corev1.TLSCertKey], KeyPEM: existing.Data[corev1.TLSPrivateKeyKey]}
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("unable to fetch the certificate Secret: %w", err)
+	}
+	dnsNames := resources.CertificateDNSNames(owner)
+	if pki.NeedsRenewal(pair, caCert, dnsNames, now) {
+		log.FromContext(ctx).Info("Issuing certificate", "secret", secretName)
+		if pair, err = pki.Issue(ca, owner.GetName(), dnsNames, now); err != nil {
+			return nil, fmt.Errorf("unable to issue the certificate: %w", err)
+		}
+	}
+
+	options.KeepName = true
+	if err := EnsureResource(ctx, cl, recorder, owner, fieldManager,
+		resources.GenerateCertificateSecret(owner, caBundle, pair), options); err != nil {
+		return nil, err
+	}
+
+	cert, err := pki.ParseCertificate(pair.CertPEM)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the certificate: %w", err)
+	}
+	return &commonv1alpha1.TLSStatus{
+		SecretName:   secretName,
+		SerialNumber: cert.SerialNumber.Text(16),
+		NotAfter:     metav1.NewTime(cert.NotAfter),
+		RenewTime:    metav1.NewTime(pki.RenewTime(cert)),
+	}, nil
+}
+
+// CertificateRenewAfter returns the delay until the certificate of the given status must be renewed, at least a
+// second, or 0 when there is no certificate.
+func CertificateRenewAfter(status *commonv1alpha1.TLSStatus, now time.Time) time.Duration {
+	if status == nil {
+		return 0
+	}
+	return max(status.RenewTime.Sub(now), time.Second)
+}
+
+// ensureCA returns the CA of the namespace of the owner and the bundle of the CA certificates to trust. The CA is
+// issued when missing or invalid, and again once its renew time is reached: the previous CA then stays trusted
+// until it expires, so that the certificates it signed keep working until they are renewed. The CA Secret is shared
+// by the instances of the namespace and has no owner. It is created and updated with optimistic concurrency, so
+// that concurrent reconciliations cannot issue different CAs.
+func ensureCA(ctx context.Context, cl client.Client, recorder events.EventRecorder, owner client.Object,
+	now time.Time) (pki.KeyPair, []byte, error) {
+	existing := &corev1.Secret{}
+	exists := true
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: owner.GetNamespace(), Name: resources.CASecretName}, existing); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return pki.KeyPair{}, nil, fmt.Errorf("unable to fetch the CA Secret: %w", err)
+		}
+		exists = false
+	}
+
+	ca := pki.KeyPair{CertPEM: existing.Data[resources.CACertKey], KeyPEM: existing.Data[resources.CAKeyKey]}
+	if !pki.NeedsRenewal(ca, nil, nil, now) {
+		return ca, caBundle(now, ca.CertPEM, existing.Data[resources.PreviousCACertKey]), nil
+	}
+
+	var previous []byte
+	if cert, err := pki.ParseCertificate(ca.CertPEM); err == nil && now.Before(cert.NotAfter) {
+		previous = ca.CertPEM
+	}
+	ca, err := pki.NewCA(resources.CASecretName, now)
+	if err != nil {
+		return pki.KeyPair{}, nil, fmt.Errorf("unable to issue the CA: %w", err)
+	}
+	bundle := caBundle(now, ca.CertPEM, previous)
+
+	if plan := PlanFromContext(ctx); plan != nil {
+		action := commonv1alpha1.PendingActionCreate
+		if exists {
+			action = commonv1alpha1.PendingActionUpdate
+		}
+		plan.Add(action, "Secret", resources.CASecretName, "")
+		return ca, bundle, nil
+	}
+
+	desired := resources.GenerateCASecret(owner.GetNamespace(), ca, previous)
+	if exists {
+		desired.ResourceVersion = existing.ResourceVersion
+		err = cl.Update(ctx, desired)
+	} else {
+		err = cl.Create(ctx, desired)
+	}
+	if err != nil {
+		recorder.Eventf(owner, nil, corev1.EventTypeWarning, ReasonResourceApplyError,
+			ReasonResourceApplyError, MessageFormatResourceApplyError, "Secret", err.Error())
+		return pki.KeyPair{}, nil, fmt.Errorf("unable to write the CA Secret: %w", err)
+	}
+
+	log.FromContext(ctx).Info("CA issued", "secret", resources.CASecretName)
+	recorder.Eventf(owner, nil, corev1.EventTypeNormal, ReasonCAIssued,
+		ReasonCAIssued, MessageFormatCAIssued, resources.CASecretName)
+	return ca, bundle, nil
+}
+
+// caBundle returns the given CA certificates concatenated, without the ones expired or invalid.
+func caBundle(now time.Time, certsPEM ...[]byte) []byte {
+	var bundle []byte
+	for _, certPEM := range certsPEM {
+		if cert, err := pki.ParseCertificate(certPEM); err == nil && now.Before(cert.NotAfter) {
+			bundle = slices.Concat(bundle, certPEM)
+		}
+	}
+	return bundle
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/pki"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+// getSecret returns the Secret with the given name in the default namespace.
+func getSecret(t *testing.T, cl client.Client, name string) *corev1.Secret {
+	t.Helper()
+	secret := &corev1.Secret{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, secret))
+	return secret
+}
+
+func TestEnsureCertificate(t *testing.T) {
+	owner := newConfigMap()
+	owner.UID = "owner-uid"
+	now := time.Now()
+	options := GenerateOptions{SetControllerRef: true}
+
+	t.Run("issues the CA and the certificate", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(testScheme(t)).Build()
+		recorder := events.NewFakeRecorder(10)
+
+		status, err := EnsureCertificate(context.Background(), cl, recorder, owner, "test-manager", true, options, now)
+		require.NoError(t, err)
+		require.NotNil(t, status)
+		assert.Equal(t, "test-tls", status.SecretName)
+		assert.NotEmpty(t, status.SerialNumber)
+		assert.True(t, status.RenewTime.Before(&status.NotAfter))
+
+		caSecret := getSecret(t, cl, resources.CASecretName)
+		assert.Empty(t, caSecret.OwnerReferences)
+		caCert, err := pki.ParseCertificate(caSecret.Data[resources.CACertKey])
+		require.NoError(t, err)
+
+		secret := getSecret(t, cl, "test-tls")
+		assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+		assert.True(t, metav1.IsControlledBy(secret, owner))
+		assert.Equal(t, caSecret.Data[resources.CACertKey], secret.Data[resources.CACertKey])
+		pair := pki.KeyPair{CertPEM: secret.Data[corev1.TLSCertKey], KeyPEM: secret.Data[corev1.TLSPrivateKeyKey]}
+		assert.False(t, pki.NeedsRenewal(pair, caCert, resources.CertificateDNSNames(owner), now))
+
+		again, err := EnsureCertificate(context.Background(), cl, recorder, owner, "test-manager", true, options, now)
+		require.NoError(t, err)
+		assert.Equal(t, status.SerialNumber, again.SerialNumber, "a valid certificate must be kept")
+	})
+
+	t.Run("renews the certificate once its renew time is reached", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(testScheme(t)).Build()
+		recorder := events.NewFakeRecorder(10)
+
+		status, err := EnsureCertificate(context.Background(), cl, recorder, owner, "test-manager", true, options, now)
+		require.NoError(t, err)
+		caBefore := getSecret(t, cl, resources.CASecretName).Data[resources.CACertKey]
+
+		renewed, err := EnsureCertificate(context.Background(), cl, recorder, owner, "test-manager", true, options,
+			status.RenewTime.Add(time.Minute))
+		require.NoError(t, err)
+		assert.NotEqual(t, status.SerialNumber, renewed.SerialNumber)
+		assert.Equal(t, caBefore, getSecret(t, cl, resources.CASecretName).Data[resources.CACertKey])
+	})
+
+	t.Run("rotates the CA and keeps trusting the previous one", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(testScheme(t)).Build()
+		recorder := events.NewFakeRecorder(10)
+
+		status, err := EnsureCertificate(context.Background(), cl, recorder, owner, "test-manager", true, options, now)
+		require.NoError(t, err)
+		previousCA := getSecret(t, cl, resources.CASecretName).Data[resources.CACertKey]
+
+		later := now.Add(pki.CAValidity - pki.CARenewBefore + time.Hour)
+		rotated, err := EnsureCertificate(context.Background(), cl, recorder, owner, "test-manager", true, options, later)
+		require.NoError(t, err)
+		assert.NotEqual(t, status.SerialNumber, rotated.SerialNumber, "the certificate must be signed by the new CA")
+
+		caSecret := getSecret(t, cl, resources.CASecretName)
+		assert.NotEqual(t, previousCA, caSecret.Data[resources.CACertKey])
+		assert.Equal(t, previousCA, caSecret.Data[resources.PreviousCACertKey])
+		bundle := getSecret(t, cl, "test-tls").Data[resources.CACertKey]
+		assert.Contains(t, string(bundle), string(caSecret.Data[resources.CACertKey]))
+		assert.Contains(t, string(bundle), string(previousCA))
+	})
+
+	t.Run("disabled deletes the certificate Secret", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(testScheme(t)).Build()
+		recorder := events.NewFakeRecorder(10)
+
+		_, err := EnsureCertificate(context.Background(), cl, recorder, owner, "test-manager", true, options, now)
+		require.NoError(t, err)
+
+		status, err := EnsureCertificate(context.Background(), cl, recorder, owner, "test-manager", false, options, now)
+		require.NoError(t, err)
+		assert.Nil(t, status)
+		err = cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "test-tls"}, &corev1.Secret{})
+		assert.True(t, k8serrors.IsNotFound(err), "certificate Secret must be deleted, got %v", err)
+		getSecret(t, cl, resources.CASecretName)
+	})
+
+	t.Run("plan mode records the changes without writing", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(testScheme(t)).Build()
+		recorder := events.NewFakeRecorder(10)
+		plan := &Plan{}
+
+		_, err := EnsureCertificate(PlanIntoContext(context.Background(), plan), cl, recorder, owner, "test-manager", true,
+			options, now)
+		require.NoError(t, err)
+
+		secrets := &corev1.SecretList{}
+		require.NoError(t, cl.List(context.Background(), secrets))
+		assert.Empty(t, secrets.Items)
+		require.NotEmpty(t, plan.Changes())
+		assert.Equal(t, commonv1alpha1.PendingChange{
+			Action: commonv1alpha1.PendingActionCreate, Kind: "Secret", Name: resources.CASecretName,
+		}, plan.Changes()[0])
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func TestCertificateRenewAfter(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		status *commonv1alpha1.TLSStatus
+		want   time.Duration
+	}{
+		{name: "no certificate", want: 0},
+		{
+			name:   "renew time in the future",
+			status: &commonv1alpha1.TLSStatus{RenewTime: metav1.NewTime(now.Add(time.Hour))},
+			want:   time.Hour,
+		},
+		{
+			name:   "renew time reached",
+			status: &commonv1alpha1.TLSStatus{RenewTime: metav1.NewTime(now.Add(-time.Hour))},
+			want:   time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CertificateRenewAfter(tt.status, now))
+		})
+	}
+}
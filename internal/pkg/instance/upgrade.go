@@ -0,0 +1,87 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/image"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+const (
+	// DefaultUpgradeSoakDuration is the default time the canary pods must stay healthy before promotion.
+	DefaultUpgradeSoakDuration = 5 * time.Minute
+	// DefaultUpgradeProgressDeadline is the default time the canary pods may take to become healthy.
+	DefaultUpgradeProgressDeadline = 10 * time.Minute
+	// UpgradeRequeueInterval is the period at which an upgrade in progress is checked.
+	UpgradeRequeueInterval = 15 * time.Second
+)
+
+// UpgradeInProgress reports whether the upgrade is still moving towards completion.
+func UpgradeInProgress(upgrade *instancev1alpha1.UpgradeStatus) bool {
+	if upgrade == nil {
+		return false
+	}
+	switch upgrade.Phase {
+	case instancev1alpha1.UpgradePhaseCanary, instancev1alpha1.UpgradePhaseSoaking, instancev1alpha1.UpgradePhaseRollingOut:
+		return true
+	default:
+		return false
+	}
+}
+
+// PinVersion makes the workloads of the Falco instance run the given version, whether it comes from
+// spec.version or from the image of the main container of the pod template. When hold is set, the
+// daemonsets do not replace their pods on their own. Only the in-memory copy is changed.
+func PinVersion(falco *instancev1alpha1.Falco, version string, hold bool, defs *resources.InstanceDefaults) {
+	falco.Spec.Version = &version
+	if pts := falco.Spec.PodTemplateSpec; pts != nil {
+		for i := range pts.Spec.Containers {
+			if pts.Spec.Containers[i].Name == defs.ContainerName && pts.Spec.Containers[i].Image != "" {
+				pts.Spec.Containers[i].Image = image.ReplaceVersion(pts.Spec.Containers[i].Image, version)
+			}
+		}
+	}
+	if hold {
+		falco.Spec.UpdateStrategy = &appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+	}
+}
+
+// PodVersion returns the version of the main container of the pod, or an empty string when unknown.
+func PodVersion(pod *corev1.Pod, defs *resources.InstanceDefaults) string {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == defs.ContainerName {
+			return image.VersionFromImage(pod.Spec.Containers[i].Image)
+		}
+	}
+	return ""
+}
+
+// IsPodReady reports whether the pod has its Ready condition set to True.
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
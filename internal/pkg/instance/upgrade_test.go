@@ -0,0 +1,135 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/resources"
+)
+
+func TestUpgradeInProgress(t *testing.T) {
+	tests := []struct {
+		phase instancev1alpha1.UpgradePhase
+		want  bool
+	}{
+		{phase: instancev1alpha1.UpgradePhaseCanary, want: true},
+		{phase: instancev1alpha1.UpgradePhaseSoaking, want: true},
+		{phase: instancev1alpha1.UpgradePhaseRollingOut, want: true},
+		{phase: instancev1alpha1.UpgradePhaseCompleted, want: false},
+		{phase: instancev1alpha1.UpgradePhaseRolledBack, want: false},
+		{phase: instancev1alpha1.UpgradePhaseFailed, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.phase), func(t *testing.T) {
+			assert.Equal(t, tt.want, UpgradeInProgress(&instancev1alpha1.UpgradeStatus{Phase: tt.phase}))
+		})
+	}
+
+	assert.False(t, UpgradeInProgress(nil))
+}
+
+func TestPinVersion(t *testing.T) {
+	defs := resources.FalcoDefaults
+
+	t.Run("sets the version without holding the pods", func(t *testing.T) {
+		falco := &instancev1alpha1.Falco{Spec: instancev1alpha1.FalcoSpec{Version: new("0.40.0")}}
+
+		PinVersion(falco, "0.41.0", false, defs)
+
+		require.NotNil(t, falco.Spec.Version)
+		assert.Equal(t, "0.41.0", *falco.Spec.Version)
+		assert.Nil(t, falco.Spec.UpdateStrategy)
+		assert.Equal(t, "0.41.0", ResolveVersion(falco, defs))
+	})
+
+	t.Run("retags the image of the main container", func(t *testing.T) {
+		falco := &instancev1alpha1.Falco{Spec: instancev1alpha1.FalcoSpec{
+			PodTemplateSpec: &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: defs.ContainerName, Image: "registry.example.com/falco:0.40.0"},
+				{Name: "sidecar", Image: "registry.example.com/sidecar:1.0.0"},
+			}}},
+		}}
+
+		PinVersion(falco, "0.41.0", false, defs)
+
+		containers := falco.Spec.PodTemplateSpec.Spec.Containers
+		assert.Equal(t, "registry.example.com/falco:0.41.0", containers[0].Image)
+		assert.Equal(t, "registry.example.com/sidecar:1.0.0", containers[1].Image)
+		assert.Equal(t, "0.41.0", ResolveVersion(falco, defs))
+	})
+
+	t.Run("hold switches the daemonset to OnDelete", func(t *testing.T) {
+		falco := &instancev1alpha1.Falco{Spec: instancev1alpha1.FalcoSpec{
+			UpdateStrategy: &appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType},
+		}}
+
+		PinVersion(falco, "0.41.0", true, defs)
+
+		require.NotNil(t, falco.Spec.UpdateStrategy)
+		assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, falco.Spec.UpdateStrategy.Type)
+	})
+}
+
+func TestPodVersion(t *testing.T) {
+	defs := resources.FalcoDefaults
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "sidecar", Image: "registry.example.com/sidecar:1.0.0"},
+		{Name: defs.ContainerName, Image: "registry.example.com/falco:0.41.0"},
+	}}}
+
+	assert.Equal(t, "0.41.0", PodVersion(pod, defs))
+	assert.Empty(t, PodVersion(&corev1.Pod{}, defs))
+}
+
+func TestIsPodReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []corev1.PodCondition
+		want       bool
+	}{
+		{name: "no conditions", want: false},
+		{
+			name:       "ready",
+			conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			want:       true,
+		},
+		{
+			name:       "not ready",
+			conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			want:       false,
+		},
+		{
+			name:       "only scheduled",
+			conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}},
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPodReady(&corev1.Pod{Status: corev1.PodStatus{Conditions: tt.conditions}}))
+		})
+	}
+}
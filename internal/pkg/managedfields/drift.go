@@ -0,0 +1,108 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package managedfields
+
+import (
+	"bytes"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+)
+
+// ForeignFields returns the fields of the desired object that other field managers than fieldManager
+// manage on the current object with a value different from the desired one, grouped by field manager.
+// Fields managed through subresources are ignored. Returns nil if no such field is found.
+func ForeignFields(current runtime.Object, desired *unstructured.Unstructured,
+	fieldManager string) (map[string]*fieldpath.Set, error) {
+	objectType, err := GetObjectType(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object type for drift detection: %w", err)
+	}
+
+	currentTyped, err := toTyped(current, objectType)
+	if err != nil {
+		return nil, fmt.Errorf("error converting current object to typed: %w", err)
+	}
+
+	desiredTyped, err := objectType.FromUnstructured(desired.Object)
+	if err != nil {
+		return nil, fmt.Errorf("error converting desired object to typed: %w", err)
+	}
+
+	comparison, err := currentTyped.Compare(desiredTyped)
+	if err != nil {
+		return nil, fmt.Errorf("error comparing current and desired objects: %w", err)
+	}
+	if comparison.Modified.Empty() {
+		return nil, nil
+	}
+
+	accessor, err := apimeta.Accessor(current)
+	if err != nil {
+		return nil, fmt.Errorf("error accessing metadata: %w", err)
+	}
+
+	var foreign map[string]*fieldpath.Set
+	for _, mf := range accessor.GetManagedFields() {
+		if mf.Manager == fieldManager || mf.Subresource != "" || mf.FieldsV1 == nil {
+			continue
+		}
+
+		fieldset := &fieldpath.Set{}
+		if err := fieldset.FromJSON(bytes.NewReader(mf.FieldsV1.GetRawBytes())); err != nil {
+			return nil, fmt.Errorf("error parsing FieldsV1 JSON of %s: %w", mf.Manager, err)
+		}
+
+		changed := fieldset.Leaves().Intersection(comparison.Modified)
+		if changed.Empty() {
+			continue
+		}
+		if foreign == nil {
+			foreign = map[string]*fieldpath.Set{}
+		}
+		if prev, ok := foreign[mf.Manager]; ok {
+			changed = prev.Union(changed)
+		}
+		foreign[mf.Manager] = changed
+	}
+
+	return foreign, nil
+}
+
+// RemoveFields returns a copy of the object without the given fields.
+func RemoveFields(obj *unstructured.Unstructured, fields *fieldpath.Set) (*unstructured.Unstructured, error) {
+	objectType, err := GetObjectType(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object type for field removal: %w", err)
+	}
+
+	typedObj, err := objectType.FromUnstructured(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("error converting obj to typed: %w", err)
+	}
+
+	u := typedObj.RemoveItems(fields).AsValue().Unstructured()
+	m, ok := u.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert object without removed fields to unstructured, expected map, got %T", u)
+	}
+
+	return &unstructured.Unstructured{Object: m}, nil
+}
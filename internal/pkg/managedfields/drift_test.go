@@ -0,0 +1,151 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package managedfields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+)
+
+// driftDeployment returns a Deployment with a single container running the given image.
+func driftDeployment(image string, replicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "test", "namespace": "default"},
+		"spec": map[string]any{
+			"replicas": replicas,
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{map[string]any{"name": "app", "image": image}},
+				},
+			},
+		},
+	}}
+}
+
+// managedFieldsEntry returns a managed fields entry for the given manager and raw FieldsV1 JSON.
+func managedFieldsEntry(manager string, operation metav1.ManagedFieldsOperationType,
+	subresource, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   operation,
+		Subresource: subresource,
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+const (
+	imageFields    = `{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"app\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`
+	replicasFields = `{"f:spec":{"f:replicas":{}}}`
+)
+
+func TestForeignFields(t *testing.T) {
+	imagePath := fieldpath.MakePathOrDie("spec", "template", "spec", "containers",
+		fieldpath.KeyByFields("name", "app"), "image")
+
+	tests := []struct {
+		name          string
+		current       *unstructured.Unstructured
+		managedFields []metav1.ManagedFieldsEntry
+		want          map[string][]fieldpath.Path
+	}{
+		{
+			name:    "field changed by another manager",
+			current: driftDeployment("edited:1.0", 1),
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("test-manager", metav1.ManagedFieldsOperationApply, "", replicasFields),
+				managedFieldsEntry("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", imageFields),
+			},
+			want: map[string][]fieldpath.Path{"kubectl-edit": {imagePath}},
+		},
+		{
+			name:    "field co-owned with the desired value is not drift",
+			current: driftDeployment("app:1.0", 1),
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("test-manager", metav1.ManagedFieldsOperationApply, "", imageFields),
+				managedFieldsEntry("other-applier", metav1.ManagedFieldsOperationApply, "", imageFields),
+			},
+		},
+		{
+			name:    "field changed by our own manager is not drift",
+			current: driftDeployment("edited:1.0", 1),
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("test-manager", metav1.ManagedFieldsOperationApply, "", imageFields),
+			},
+		},
+		{
+			name:    "subresource managers are ignored",
+			current: driftDeployment("app:1.0", 3),
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("hpa-controller", metav1.ManagedFieldsOperationUpdate, "scale", replicasFields),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.current.SetManagedFields(tt.managedFields)
+
+			foreign, err := ForeignFields(tt.current, driftDeployment("app:1.0", 1), "test-manager")
+			require.NoError(t, err)
+
+			if tt.want == nil {
+				assert.Nil(t, foreign)
+				return
+			}
+			require.Len(t, foreign, len(tt.want))
+			for manager, paths := range tt.want {
+				require.Contains(t, foreign, manager)
+				assert.True(t, foreign[manager].Equals(fieldpath.NewSet(paths...)), "got %s", foreign[manager])
+			}
+		})
+	}
+}
+
+func TestForeignFieldsUnknownType(t *testing.T) {
+	current := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "unknown.example.com/v1", "kind": "UnknownKind"}}
+
+	_, err := ForeignFields(current, current, "test-manager")
+	assert.Error(t, err)
+}
+
+func TestRemoveFields(t *testing.T) {
+	obj := driftDeployment("app:1.0", 1)
+	imagePath := fieldpath.MakePathOrDie("spec", "template", "spec", "containers",
+		fieldpath.KeyByFields("name", "app"), "image")
+
+	pruned, err := RemoveFields(obj, fieldpath.NewSet(imagePath))
+	require.NoError(t, err)
+
+	containers, found, err := unstructured.NestedSlice(pruned.Object, "spec", "template", "spec", "containers")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, []any{map[string]any{"name": "app"}}, containers)
+	assert.Equal(t, "Deployment", pruned.GetKind())
+	assert.Equal(t, "test", pruned.GetName())
+
+	// The original object is left untouched.
+	image, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	assert.Equal(t, "app:1.0", image[0].(map[string]any)["image"])
+}
@@ -26,6 +26,13 @@ import (
 	"github.com/falcosecurity/falco-operator/internal/pkg/scheme"
 )
 
+// deducedGroups are the API groups of the custom resources generated by the operator. They have no
+// schema in the Kubernetes one, so their structure is deduced from the objects, as the API server does
+// for custom resources without schema: maps are merged field by field and lists are atomic.
+var deducedGroups = map[string]bool{
+	"monitoring.coreos.com": true,
+}
+
 // GetObjectType returns a ParseableType for the given object using the Kubernetes schema.
 func GetObjectType(obj runtime.Object) (typed.ParseableType, error) {
 	gvk := obj.GetObjectKind().GroupVersionKind()
@@ -38,6 +45,9 @@ func GetObjectType(obj runtime.Object) (typed.ParseableType, error) {
 	parseableType := parser.Type(schemaName)
 
 	if !parseableType.IsValid() {
+		if deducedGroups[gvk.Group] {
+			return typed.DeducedParseableType, nil
+		}
 		return typed.ParseableType{}, fmt.Errorf("schema type not found for %s (schema name: %s)", gvk, schemaName)
 	}
 
@@ -200,6 +200,16 @@ func TestGetObjectType(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "prometheus-operator type is deduced",
+			obj: &unstructured.Unstructured{
+				Object: map[string]any{
+					"apiVersion": "monitoring.coreos.com/v1",
+					"kind":       "ServiceMonitor",
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "unknown type",
 			obj: &unstructured.Unstructured{
@@ -0,0 +1,123 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics defines the Prometheus metrics of the operators, registered in the
+// controller-runtime metrics registry.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "falco_operator"
+
+var (
+	// DriftDetectedTotal counts the drifts of generated resources detected by the instance operator, by resource kind.
+	DriftDetectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "drift_detected_total",
+		Help:      "Number of times fields of a generated resource were found changed by another field manager.",
+	}, []string{"kind"})
+
+	// InstanceReplicas reports the replicas of the Falco and Component instances, as in their status, by state:
+	// desired, available or unavailable.
+	InstanceReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "instance_replicas",
+		Help:      "Replicas of the Falco and Component instances, by state.",
+	}, []string{"kind", "namespace", "name", "state"})
+
+	// ResourceEnsureTotal counts the outcomes of ensuring the generated resources of the instances, by resource kind
+	// and outcome: created, updated, skipped, planned or failed.
+	ResourceEnsureTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "resource_ensure_total",
+		Help:      "Number of times a generated resource was ensured, by outcome.",
+	}, []string{"kind", "outcome"})
+
+	// ArtifactNodes reports the nodes of the artifacts aggregated by the instance operator, by status of their
+	// Programmed condition.
+	ArtifactNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "artifact_nodes",
+		Help:      "Nodes assigned to an artifact, by status of their Programmed condition.",
+	}, []string{"kind", "namespace", "name", "status"})
+
+	// ArtifactPullDurationSeconds observes the duration of the OCI artifact pulls of the artifact operator, by
+	// registry and repository.
+	ArtifactPullDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "artifact_pull_duration_seconds",
+		Help:      "Duration of the OCI artifact pulls, successful or not.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"registry", "repository"})
+
+	// ArtifactPullBytesTotal counts the compressed bytes of the OCI artifacts pulled by the artifact operator, by
+	// registry and repository.
+	ArtifactPullBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "artifact_pull_bytes_total",
+		Help:      "Compressed bytes of the OCI artifact layers pulled.",
+	}, []string{"registry", "repository"})
+
+	// ArtifactPullFailuresTotal counts the failed OCI artifact pulls of the artifact operator, by registry,
+	// repository and reason.
+	ArtifactPullFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "artifact_pull_failures_total",
+		Help:      "Number of OCI artifact pulls that failed.",
+	}, []string{"registry", "repository", "reason"})
+
+	// ArtifactStoreActionsTotal counts the store operations of the artifact operator that found or changed an
+	// artifact on the node, by artifact type, medium and action.
+	ArtifactStoreActionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "artifact_store_actions_total",
+		Help:      "Number of artifact store operations, by resulting action.",
+	}, []string{"type", "medium", "action"})
+
+	// ArtifactInfo is set to 1 for every artifact installed on the node by the artifact operator, labeled with its
+	// kind and the digest of its content.
+	ArtifactInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "artifact_info",
+		Help:      "Artifacts installed on the node, labeled with the digest of their content.",
+	}, []string{"kind", "namespace", "name", "medium", "digest"})
+
+	// StartupGatePending is the number of artifact CRs, by kind, the artifact operator waits for before reporting
+	// ready.
+	StartupGatePending = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "startup_gate_pending",
+		Help:      "Number of artifact CRs not yet reconciled since the artifact operator started.",
+	}, []string{"kind"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		DriftDetectedTotal,
+		InstanceReplicas,
+		ResourceEnsureTotal,
+		ArtifactNodes,
+		ArtifactPullDurationSeconds,
+		ArtifactPullBytesTotal,
+		ArtifactPullFailuresTotal,
+		ArtifactStoreActionsTotal,
+		ArtifactInfo,
+		StartupGatePending,
+	)
+}
@@ -23,15 +23,19 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/attribute"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/content/oci"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 	"oras.land/oras-go/v2/registry/remote/retry"
 
 	"github.com/falcosecurity/falco-operator/internal/pkg/oci/client"
+	"github.com/falcosecurity/falco-operator/internal/pkg/tracing"
 )
 
 // Puller defines the interface for pulling OCI artifacts.
@@ -55,42 +59,24 @@ func NewOciPuller(defaults *RegistryOptions) *OciPuller {
 // Pull resolves ref to its artifact layer and copies the compressed layer payload into dst.
 //
 // Ref format follows: REGISTRY/REPO[:TAG|@DIGEST]. Ex. localhost:5000/hello:latest.
-// When opts is non-nil it overrides the puller defaults entirely.
+// When opts is non-nil it overrides the puller defaults entirely. When opts sets a LayoutPath,
+// the artifact is read from that OCI image layout instead of the registry.
 func (p *OciPuller) Pull(ctx context.Context, ref, os, arch string, creds auth.CredentialFunc, opts *RegistryOptions, dst io.Writer) (*RegistryResult, error) {
 	if dst == nil {
 		return nil, fmt.Errorf("nil destination writer")
 	}
 
-	options := p.defaults
-	if opts != nil {
-		options = opts
-	}
-
-	repo, err := remote.NewRepository(ref)
+	src, copyRef, err := p.source(ctx, ref, creds, opts)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create new repository with ref %s: %w", ref, err)
-	}
-
-	clientOpts := []client.Option{client.WithCredentialFunc(creds)}
-
-	if options != nil {
-		if options.InsecureSkipVerify {
-			tlsConfig := &tls.Config{InsecureSkipVerify: options.InsecureSkipVerify} //nolint:gosec // user-configured
-			httpTransport := &http.Transport{TLSClientConfig: tlsConfig}
-			retryTransport := retry.NewTransport(httpTransport)
-			clientOpts = append(clientOpts, client.WithTransport(retryTransport))
-		}
-		repo.PlainHTTP = options.PlainHTTP
+		return nil, err
 	}
 
-	repo.Client = client.NewClient(clientOpts...)
-
-	if repo.Reference.Reference == "" {
-		repo.Reference.Reference = DefaultTag
+	resolveCtx, span := tracing.Start(ctx, "Resolve OCI reference", attribute.String("oci.reference", copyRef))
+	refDesc, err := src.Resolve(resolveCtx, copyRef)
+	if err == nil {
+		span.SetAttributes(attribute.String("oci.digest", string(refDesc.Digest)))
 	}
-	copyRef := repo.Reference.String()
-
-	refDesc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, err
 	}
@@ -103,10 +89,9 @@ func (p *OciPuller) Pull(ctx context.Context, ref, os, arch string, creds auth.C
 	}
 
 	localTarget := oras.Target(memory.New())
-	desc, err := oras.Copy(ctx, repo, copyRef, localTarget, copyRef, copyOpts)
+	desc, err := oras.Copy(ctx, src, copyRef, localTarget, copyRef, copyOpts)
 	if err != nil {
-		return nil, fmt.Errorf("unable to pull artifact %s with tag %s from repo %s: %w",
-			repo.Reference.Repository, repo.Reference.Reference, repo.Reference.Repository, err)
+		return nil, fmt.Errorf("unable to pull artifact %s: %w", copyRef, err)
 	}
 
 	manifest, err := manifestFromDesc(ctx, localTarget, &desc)
@@ -115,16 +100,9 @@ func (p *OciPuller) Pull(ctx context.Context, ref, os, arch string, creds auth.C
 	}
 
 	layerDesc := manifest.Layers[0]
-	var artifactType ArtifactType
-	switch layerDesc.MediaType {
-	case FalcoPluginLayerMediaType:
-		artifactType = Plugin
-	case FalcoRulesfileLayerMediaType:
-		artifactType = Rulesfile
-	case FalcoAssetLayerMediaType:
-		artifactType = Asset
-	default:
-		return nil, fmt.Errorf("unknown media type: %q", layerDesc.MediaType)
+	artifactType, err := layerArtifactType(layerDesc.MediaType)
+	if err != nil {
+		return nil, err
 	}
 
 	layerReader, err := localTarget.Fetch(ctx, layerDesc)
@@ -143,6 +121,131 @@ func (p *OciPuller) Pull(ctx context.Context, ref, os, arch string, creds auth.C
 	}, nil
 }
 
+// Save copies the artifact ref, with every platform of a multi-platform artifact, into dst where it is tagged with
+// its full reference. Pointing the LayoutPath of RegistryOptions to an OCI image layout holding dst then pulls the
+// artifact from there with the same ref. The returned digests are those of the saved root manifest or index.
+func (p *OciPuller) Save(
+	ctx context.Context, ref string, creds auth.CredentialFunc, opts *RegistryOptions, dst oras.Target,
+) (*RegistryResult, error) {
+	if dst == nil {
+		return nil, fmt.Errorf("nil destination target")
+	}
+
+	src, copyRef, err := p.source(ctx, ref, creds, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	copyOpts := oras.CopyOptions{
+		CopyGraphOptions: oras.CopyGraphOptions{Concurrency: 1},
+	}
+	desc, err := oras.Copy(ctx, src, copyRef, dst, copyRef, copyOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to save artifact %s: %w", copyRef, err)
+	}
+
+	manifestDesc := desc
+	if desc.MediaType == v1.MediaTypeImageIndex {
+		index, err := indexFromDesc(ctx, dst, &desc)
+		if err != nil {
+			return nil, err
+		}
+		manifestDesc = index.Manifests[0]
+	}
+	manifest, err := manifestFromDesc(ctx, dst, &manifestDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	layerDesc := manifest.Layers[0]
+	artifactType, err := layerArtifactType(layerDesc.MediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegistryResult{
+		RootDigest: string(desc.Digest),
+		Digest:     string(desc.Digest),
+		Type:       artifactType,
+		Filename:   layerDesc.Annotations[v1.AnnotationTitle],
+	}, nil
+}
+
+// source returns the target to copy ref from along with the reference of the artifact in it: the OCI image layout
+// at the LayoutPath of the options when set, the remote repository otherwise. A reference without tag or digest
+// points to DefaultTag.
+func (p *OciPuller) source(
+	ctx context.Context, ref string, creds auth.CredentialFunc, opts *RegistryOptions,
+) (oras.ReadOnlyTarget, string, error) {
+	options := p.defaults
+	if opts != nil {
+		options = opts
+	}
+
+	repo, err := remote.NewRepository(ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to create new repository with ref %s: %w", ref, err)
+	}
+	if repo.Reference.Reference == "" {
+		repo.Reference.Reference = DefaultTag
+	}
+	copyRef := repo.Reference.String()
+
+	if options != nil && options.LayoutPath != "" {
+		layout, err := openLayout(ctx, options.LayoutPath)
+		if err != nil {
+			return nil, "", err
+		}
+		return layout, copyRef, nil
+	}
+
+	clientOpts := []client.Option{client.WithCredentialFunc(creds)}
+	if options != nil {
+		if options.InsecureSkipVerify {
+			tlsConfig := &tls.Config{InsecureSkipVerify: options.InsecureSkipVerify} //nolint:gosec // user-configured
+			httpTransport := &http.Transport{TLSClientConfig: tlsConfig}
+			retryTransport := retry.NewTransport(httpTransport)
+			clientOpts = append(clientOpts, client.WithTransport(retryTransport))
+		}
+		repo.PlainHTTP = options.PlainHTTP
+	}
+	repo.Client = client.NewClient(clientOpts...)
+	return repo, copyRef, nil
+}
+
+// openLayout opens the read-only OCI image layout at path, either a directory or a tar archive of it.
+func openLayout(ctx context.Context, path string) (oras.ReadOnlyTarget, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open OCI layout %s: %w", path, err)
+	}
+
+	var layout oras.ReadOnlyTarget
+	if info.IsDir() {
+		layout, err = oci.NewFromFS(ctx, os.DirFS(path))
+	} else {
+		layout, err = oci.NewFromTar(ctx, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open OCI layout %s: %w", path, err)
+	}
+	return layout, nil
+}
+
+// layerArtifactType returns the type of artifact whose layer has the given media type.
+func layerArtifactType(mediaType string) (ArtifactType, error) {
+	switch mediaType {
+	case FalcoPluginLayerMediaType:
+		return Plugin, nil
+	case FalcoRulesfileLayerMediaType:
+		return Rulesfile, nil
+	case FalcoAssetLayerMediaType:
+		return Asset, nil
+	default:
+		return "", fmt.Errorf("unknown media type: %q", mediaType)
+	}
+}
+
 func manifestFromDesc(ctx context.Context, target oras.Target, desc *v1.Descriptor) (*v1.Manifest, error) {
 	descReader, err := target.Fetch(ctx, *desc)
 	if err != nil {
@@ -165,6 +268,28 @@ func manifestFromDesc(ctx context.Context, target oras.Target, desc *v1.Descript
 	return &manifest, nil
 }
 
+func indexFromDesc(ctx context.Context, target oras.Target, desc *v1.Descriptor) (*v1.Index, error) {
+	descReader, err := target.Fetch(ctx, *desc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch descriptor with digest %q: %w", desc.Digest, err)
+	}
+
+	descBytes, err := readAndClose(descReader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read bytes from descriptor: %w", err)
+	}
+
+	var index v1.Index
+	if err := json.Unmarshal(descBytes, &index); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal index: %w", err)
+	}
+	if len(index.Manifests) < 1 {
+		return nil, fmt.Errorf("no manifests in index")
+	}
+
+	return &index, nil
+}
+
 func readAndClose(reader io.ReadCloser) ([]byte, error) {
 	data, readErr := io.ReadAll(reader)
 	closeErr := reader.Close()
@@ -0,0 +1,184 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package puller
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+const testRef = "ghcr.io/falcosecurity/rules/falco-rules:3"
+
+// pushManifest pushes to store a single-layer artifact of the given layer media type and returns its manifest.
+func pushManifest(t *testing.T, store oras.Target, layerMediaType string, payload []byte) v1.Descriptor {
+	t.Helper()
+	ctx := context.Background()
+
+	layer := content.NewDescriptorFromBytes(layerMediaType, payload)
+	layer.Annotations = map[string]string{v1.AnnotationTitle: "falco_rules.yaml.tar.gz"}
+	require.NoError(t, store.Push(ctx, layer, bytes.NewReader(payload)))
+
+	manifest, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.cncf.falco.artifact",
+		oras.PackManifestOptions{Layers: []v1.Descriptor{layer}})
+	require.NoError(t, err)
+	return manifest
+}
+
+// pushIndex pushes to store an image index of the given manifests and returns it.
+func pushIndex(t *testing.T, store oras.Target, manifests ...v1.Descriptor) v1.Descriptor {
+	t.Helper()
+	data, err := json.Marshal(v1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: v1.MediaTypeImageIndex,
+		Manifests: manifests,
+	})
+	require.NoError(t, err)
+	index := content.NewDescriptorFromBytes(v1.MediaTypeImageIndex, data)
+	require.NoError(t, store.Push(context.Background(), index, bytes.NewReader(data)))
+	return index
+}
+
+// newTestLayout creates an OCI image layout directory holding a rulesfile tagged testRef.
+func newTestLayout(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := oci.New(dir)
+	require.NoError(t, err)
+	manifest := pushManifest(t, store, FalcoRulesfileLayerMediaType, []byte("rules"))
+	require.NoError(t, store.Tag(context.Background(), manifest, testRef))
+	return dir
+}
+
+// tarLayout archives the OCI image layout directory dir and returns the path of the archive.
+func tarLayout(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "layout.tar")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	require.NoError(t, tw.AddFS(os.DirFS(dir)))
+	require.NoError(t, tw.Close())
+	return path
+}
+
+func TestPull_OCILayout(t *testing.T) {
+	dir := newTestLayout(t)
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "directory", path: dir},
+		{name: "tar archive", path: tarLayout(t, dir)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst bytes.Buffer
+			res, err := NewOciPuller(nil).Pull(context.Background(), testRef, "linux", "amd64", nil,
+				&RegistryOptions{LayoutPath: tt.path}, &dst)
+			require.NoError(t, err)
+			assert.Equal(t, Rulesfile, res.Type)
+			assert.Equal(t, "falco_rules.yaml.tar.gz", res.Filename)
+			assert.Equal(t, res.RootDigest, res.Digest)
+			assert.Equal(t, "rules", dst.String())
+		})
+	}
+
+	t.Run("unknown reference", func(t *testing.T) {
+		var dst bytes.Buffer
+		_, err := NewOciPuller(nil).Pull(context.Background(), "ghcr.io/falcosecurity/rules/falco-rules:4", "linux", "amd64", nil,
+			&RegistryOptions{LayoutPath: dir}, &dst)
+		require.Error(t, err)
+	})
+
+	t.Run("missing layout", func(t *testing.T) {
+		var dst bytes.Buffer
+		_, err := NewOciPuller(nil).Pull(context.Background(), testRef, "linux", "amd64", nil,
+			&RegistryOptions{LayoutPath: filepath.Join(dir, "missing")}, &dst)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestSave(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("single platform", func(t *testing.T) {
+		src := newTestLayout(t)
+		dstDir := t.TempDir()
+		dst, err := oci.New(dstDir)
+		require.NoError(t, err)
+
+		res, err := NewOciPuller(nil).Save(ctx, testRef, nil, &RegistryOptions{LayoutPath: src}, dst)
+		require.NoError(t, err)
+		assert.Equal(t, Rulesfile, res.Type)
+		assert.Equal(t, "falco_rules.yaml.tar.gz", res.Filename)
+
+		var pulled bytes.Buffer
+		pullRes, err := NewOciPuller(nil).Pull(ctx, testRef, "linux", "amd64", nil, &RegistryOptions{LayoutPath: dstDir}, &pulled)
+		require.NoError(t, err)
+		assert.Equal(t, res.Digest, pullRes.Digest)
+		assert.Equal(t, "rules", pulled.String())
+	})
+
+	t.Run("every platform of a multi-platform artifact", func(t *testing.T) {
+		srcDir := t.TempDir()
+		src, err := oci.New(srcDir)
+		require.NoError(t, err)
+		const pluginRef = "ghcr.io/falcosecurity/plugins/plugin/k8smeta:0.3.0"
+		amd64 := pushManifest(t, src, FalcoPluginLayerMediaType, []byte("amd64"))
+		amd64.Platform = &v1.Platform{OS: "linux", Architecture: "amd64"}
+		arm64 := pushManifest(t, src, FalcoPluginLayerMediaType, []byte("arm64"))
+		arm64.Platform = &v1.Platform{OS: "linux", Architecture: "arm64"}
+		index := pushIndex(t, src, amd64, arm64)
+		require.NoError(t, src.Tag(ctx, index, pluginRef))
+
+		dstDir := t.TempDir()
+		dst, err := oci.New(dstDir)
+		require.NoError(t, err)
+		res, err := NewOciPuller(nil).Save(ctx, pluginRef, nil, &RegistryOptions{LayoutPath: srcDir}, dst)
+		require.NoError(t, err)
+		assert.Equal(t, Plugin, res.Type)
+		assert.Equal(t, string(index.Digest), res.Digest)
+
+		for _, arch := range []string{"amd64", "arm64"} {
+			var pulled bytes.Buffer
+			pullRes, err := NewOciPuller(nil).Pull(ctx, pluginRef, "linux", arch, nil, &RegistryOptions{LayoutPath: dstDir}, &pulled)
+			require.NoError(t, err)
+			assert.Equal(t, arch, pulled.String())
+			assert.Equal(t, string(index.Digest), pullRes.RootDigest)
+		}
+	})
+
+	t.Run("nil destination", func(t *testing.T) {
+		_, err := NewOciPuller(nil).Save(ctx, testRef, nil, &RegistryOptions{LayoutPath: newTestLayout(t)}, nil)
+		require.Error(t, err)
+	})
+}
@@ -117,4 +117,7 @@ type ArtifactDependency struct {
 type RegistryOptions struct {
 	PlainHTTP          bool
 	InsecureSkipVerify bool
+	// LayoutPath is the path of an OCI image layout, a directory or a tar archive of it, to read artifacts from
+	// instead of the registry. The transport options are then ignored.
+	LayoutPath string
 }
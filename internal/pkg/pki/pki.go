@@ -0,0 +1,191 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+)
+
+const (
+	// CAValidity is the validity of the issued CAs.
+	CAValidity = 5 * 365 * 24 * time.Hour
+	// CertificateValidity is the validity of the issued certificates.
+	CertificateValidity = 90 * 24 * time.Hour
+	// CARenewBefore is how long before its expiry a CA is renewed. It is longer than CertificateValidity, so
+	// that every certificate signed by the previous CA is renewed before that CA expires.
+	CARenewBefore = 365 * 24 * time.Hour
+
+	// clockSkew backdates the issued certificates, for the clocks running behind the one of the operator.
+	clockSkew = 5 * time.Minute
+)
+
+// KeyPair is a PEM-encoded certificate and its PEM-encoded private key.
+type KeyPair struct {
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// NewCA issues a self-signed CA with the given common name, valid from now for CAValidity.
+func NewCA(commonName string, now time.Time) (KeyPair, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(CAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return issue(template, nil, nil)
+}
+
+// Issue issues a certificate for the given common name and DNS names, signed by the given CA and valid from now
+// for CertificateValidity, or until the CA expires if it does earlier. The certificate is valid both to serve
+// TLS and to authenticate as a client.
+func Issue(ca KeyPair, commonName string, dnsNames []string, now time.Time) (KeyPair, error) {
+	caCert, err := ParseCertificate(ca.CertPEM)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("parsing CA certificate: %w", err)
+	}
+	caKey, err := parsePrivateKey(ca.KeyPEM)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("parsing CA key: %w", err)
+	}
+
+	notAfter := now.Add(CertificateValidity)
+	if caCert.NotAfter.Before(notAfter) {
+		notAfter = caCert.NotAfter
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	return issue(template, caCert, caKey)
+}
+
+// issue generates a key and signs the certificate of the template with the given parent, or self-signs it
+// when the parent is nil.
+func issue(template, parent *x509.Certificate, parentKey crypto.Signer) (KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("generating key: %w", err)
+	}
+	if template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128)); err != nil {
+		return KeyPair{}, fmt.Errorf("generating serial number: %w", err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("creating certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("marshaling key: %w", err)
+	}
+	return KeyPair{
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// ParseCertificate parses the first certificate of the given PEM data.
+func ParseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+	return nil, errors.New("no certificate found")
+}
+
+// parsePrivateKey parses the PKCS #8 private key of the given PEM data.
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no private key found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// RenewTime returns the time the certificate is renewed at: once two thirds of its validity have elapsed for a
+// certificate, CARenewBefore its expiry for a CA.
+func RenewTime(cert *x509.Certificate) time.Time {
+	if cert.IsCA {
+		return cert.NotAfter.Add(-CARenewBefore)
+	}
+	return cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) * 2 / 3)
+}
+
+// NeedsRenewal reports whether the key pair must be issued again: because it is missing or invalid, was not
+// signed by the given CA (nil for a CA, which must be self-signed), is not valid for the given DNS names,
+// or its renew time is reached.
+func NeedsRenewal(pair KeyPair, ca *x509.Certificate, dnsNames []string, now time.Time) bool {
+	cert, err := ParseCertificate(pair.CertPEM)
+	if err != nil {
+		return true
+	}
+	key, err := parsePrivateKey(pair.KeyPEM)
+	if err != nil || !publicKeyMatches(cert, key) {
+		return true
+	}
+	if ca == nil {
+		ca = cert
+	}
+	if err := cert.CheckSignatureFrom(ca); err != nil {
+		return true
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(cert.DNSNames)), slices.Sorted(slices.Values(dnsNames))) {
+		return true
+	}
+	return !now.Before(RenewTime(cert))
+}
+
+// publicKeyMatches reports whether the private key is the one of the certificate.
+func publicKeyMatches(cert *x509.Certificate, key crypto.Signer) bool {
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return false
+	}
+	certDER, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	return err == nil && bytes.Equal(der, certDER)
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ca, err := NewCA("falco-operator-ca", now)
+	require.NoError(t, err)
+	caCert, err := ParseCertificate(ca.CertPEM)
+	require.NoError(t, err)
+	assert.True(t, caCert.IsCA)
+	assert.Equal(t, now.Add(CAValidity), caCert.NotAfter)
+
+	dnsNames := []string{"sidekick.falco.svc", "sidekick"}
+	pair, err := Issue(ca, "sidekick", dnsNames, now)
+	require.NoError(t, err)
+	cert, err := ParseCertificate(pair.CertPEM)
+	require.NoError(t, err)
+
+	assert.Equal(t, "sidekick", cert.Subject.CommonName)
+	assert.Equal(t, dnsNames, cert.DNSNames)
+	assert.Equal(t, now.Add(CertificateValidity), cert.NotAfter)
+	assert.ElementsMatch(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, cert.ExtKeyUsage)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName: "sidekick.falco.svc", Roots: pool, CurrentTime: now,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	assert.NoError(t, err)
+}
+
+func TestIssueCappedByCA(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ca, err := NewCA("ca", now.Add(-CAValidity+24*time.Hour))
+	require.NoError(t, err)
+
+	pair, err := Issue(ca, "falco", nil, now)
+	require.NoError(t, err)
+	cert, err := ParseCertificate(pair.CertPEM)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(24*time.Hour), cert.NotAfter)
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	dnsNames := []string{"falco", "falco.falco.svc"}
+
+	ca, err := NewCA("ca", now)
+	require.NoError(t, err)
+	caCert, err := ParseCertificate(ca.CertPEM)
+	require.NoError(t, err)
+	otherCA, err := NewCA("other", now)
+	require.NoError(t, err)
+	otherCACert, err := ParseCertificate(otherCA.CertPEM)
+	require.NoError(t, err)
+
+	pair, err := Issue(ca, "falco", dnsNames, now)
+	require.NoError(t, err)
+	otherPair, err := Issue(ca, "falco", dnsNames, now)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		pair     KeyPair
+		ca       *x509.Certificate
+		dnsNames []string
+		now      time.Time
+		want     bool
+	}{
+		{name: "valid certificate", pair: pair, ca: caCert, dnsNames: dnsNames, now: now},
+		{name: "dns names in another order", pair: pair, ca: caCert, dnsNames: []string{"falco.falco.svc", "falco"}, now: now},
+		{name: "missing certificate", pair: KeyPair{}, ca: caCert, dnsNames: dnsNames, now: now, want: true},
+		{name: "key of another certificate", pair: KeyPair{CertPEM: pair.CertPEM, KeyPEM: otherPair.KeyPEM},
+			ca: caCert, dnsNames: dnsNames, now: now, want: true},
+		{name: "signed by another CA", pair: pair, ca: otherCACert, dnsNames: dnsNames, now: now, want: true},
+		{name: "other dns names", pair: pair, ca: caCert, dnsNames: []string{"falco"}, now: now, want: true},
+		{name: "before the renew time", pair: pair, ca: caCert, dnsNames: dnsNames, now: now.Add(59 * 24 * time.Hour)},
+		{name: "renew time reached", pair: pair, ca: caCert, dnsNames: dnsNames, now: now.Add(60 * 24 * time.Hour), want: true},
+		{name: "valid CA", pair: ca, now: now},
+		{name: "CA close to expiry", pair: ca, now: now.Add(CAValidity - CARenewBefore), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NeedsRenewal(tt.pair, tt.ca, tt.dnsNames, tt.now))
+		})
+	}
+}
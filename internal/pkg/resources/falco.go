@@ -19,11 +19,13 @@ package resources
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
 	"github.com/falcosecurity/falco-operator/internal/pkg/image"
 	"github.com/falcosecurity/falco-operator/internal/pkg/mounts"
 	"github.com/falcosecurity/falco-operator/internal/pkg/version"
@@ -34,12 +36,16 @@ const (
 	FalcoTypeName = "falco"
 
 	falcoConfigMapKey = "falco.yaml"
+
+	// falcoCertsDir is the directory the certificate Secret is mounted at when TLS is enabled.
+	falcoCertsDir = "/etc/falco/certs"
 )
 
 var restartPolicy = corev1.ContainerRestartPolicyAlways
 
 // FalcoDefaults holds the default configuration for the Falco instance type.
 var FalcoDefaults = &InstanceDefaults{
+	TypeName:             FalcoTypeName,
 	ResourceType:         ResourceTypeDaemonSet,
 	Replicas:             new(int32(1)),
 	ContainerName:        "falco",
@@ -125,6 +131,33 @@ var FalcoDefaults = &InstanceDefaults{
 	ServicePorts: []corev1.ServicePort{
 		{Name: "web", Protocol: corev1.ProtocolTCP, Port: 8765, TargetPort: intstr.FromInt32(8765)},
 	},
+	MetricsPort: "web",
+
+	// The operator scrapes the webserver of the pods for the health checks. Egress is not restricted, since
+	// the sidecar pulls artifacts from arbitrary registries and Falco sends alerts to arbitrary outputs.
+	NetworkPolicy: &NetworkPolicyDefaults{
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(intstr.FromString("web"), corev1.ProtocolTCP)},
+			From:  []networkingv1.NetworkPolicyPeer{operatorPeer()},
+		}},
+	},
+
+	// The webserver serves TLS, which the kubelet probes don't verify, and the http output authenticates to
+	// Falcosidekick with the certificate.
+	TLS: &TLSDefaults{
+		MountPath:   falcoCertsDir,
+		ProbeScheme: corev1.URISchemeHTTPS,
+		Config: `webserver:
+  ssl_enabled: true
+  ssl_certificate: ` + falcoCertsDir + `/` + TLSCombinedKey + `
+http_output:
+  mtls: true
+  ca_cert: ` + falcoCertsDir + `/` + CACertKey + `
+  client_cert: ` + falcoCertsDir + `/` + corev1.TLSCertKey + `
+  client_key: ` + falcoCertsDir + `/` + corev1.TLSPrivateKeyKey + `
+`,
+	},
+
 	ClusterRoleRules: []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{""},
@@ -155,7 +188,7 @@ var FalcoDefaults = &InstanceDefaults{
 		},
 		{
 			APIGroups: []string{artifactv1alpha1.GroupVersion.Group},
-			Resources: []string{"configs/status", "rulesfiles/status", "plugins/status"},
+			Resources: []string{"configs/status", "rulesfiles/status", "plugins/status", "artifactnodes/status"},
 			Verbs:     []string{"get", "update", "patch"},
 		},
 		{
@@ -163,6 +196,16 @@ var FalcoDefaults = &InstanceDefaults{
 			Resources: []string{"artifactnodes"},
 			Verbs:     []string{"get", "list", "watch"},
 		},
+		{
+			APIGroups: []string{"apps"},
+			Resources: []string{"controllerrevisions"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{instancev1alpha1.GroupVersion.Group},
+			Resources: []string{"falcos"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
 	},
 	VolumeMounts: []corev1.VolumeMount{
 		{Name: "root-falco-fs", MountPath: "/root/.falco"},
@@ -229,6 +272,12 @@ var FalcoDefaults = &InstanceDefaults{
 						FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "spec.nodeName"},
 					},
 				},
+				{
+					Name: "FALCO_INSTANCE",
+					ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.labels['app.kubernetes.io/instance']"},
+					},
+				},
 			},
 			VolumeMounts: []corev1.VolumeMount{
 				{Name: mounts.ConfigMountName, MountPath: mounts.ConfigDirPath},
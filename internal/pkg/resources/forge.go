@@ -84,6 +84,15 @@ func forgeSelectorLabels(name string) map[string]string {
 	}
 }
 
+// forgePodLabels returns the labels of the pods of an instance: the selector labels and the instance type.
+func forgePodLabels(name string, defs *InstanceDefaults) map[string]string {
+	podLabels := forgeSelectorLabels(name)
+	if defs.TypeName != "" {
+		podLabels[InstanceTypeLabel] = defs.TypeName
+	}
+	return podLabels
+}
+
 // forgePodTemplateSpecLabels returns the labels for a pod template spec,
 // merging base labels with the standard selector labels.
 func forgePodTemplateSpecLabels(appName string, baseLabels map[string]string) map[string]string {
@@ -171,7 +171,9 @@ func TestGenerateWorkload(t *testing.T) {
 			}
 
 			assert.Equal(t, forgeSelectorLabels("test"), selector)
-			assert.Equal(t, forgeSelectorLabels("test"), podTemplateLabels)
+			wantPodLabels := forgeSelectorLabels("test")
+			wantPodLabels[InstanceTypeLabel] = tt.defs.TypeName
+			assert.Equal(t, wantPodLabels, podTemplateLabels)
 			assert.Equal(t, "test", podSpec.ServiceAccountName)
 			assert.Len(t, podSpec.Tolerations, tt.wantTolerations)
 			require.Len(t, podSpec.InitContainers, tt.wantInitContainers)
@@ -302,8 +304,10 @@ func TestGenerateUserOverlay(t *testing.T) {
 		replicas        *int32
 		updateStrategy  *appsv1.DaemonSetUpdateStrategy
 		podTemplateSpec *corev1.PodTemplateSpec
+		podAnnotations  map[string]string
 		wantErr         bool
 		wantPodLabels   map[string]string
+		wantPodAnnots   map[string]string
 		wantHasReplicas bool
 	}{
 		{
@@ -334,6 +338,25 @@ func TestGenerateUserOverlay(t *testing.T) {
 			},
 			wantPodLabels: map[string]string{"app": "falco"},
 		},
+		{
+			name:         "DaemonSet pod annotations are merged on top of the PodTemplateSpec ones",
+			resourceType: ResourceTypeDaemonSet,
+			crLabels:     map[string]string{"app": "falco"},
+			podTemplateSpec: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"team": "secops", ConfigHashAnnotation: "user"},
+				},
+			},
+			podAnnotations: map[string]string{ConfigHashAnnotation: "abc"},
+			wantPodLabels:  map[string]string{"app": "falco"},
+			wantPodAnnots:  map[string]string{"team": "secops", ConfigHashAnnotation: "abc"},
+		},
+		{
+			name:           "Deployment without PodTemplateSpec gets pod annotations",
+			resourceType:   ResourceTypeDeployment,
+			podAnnotations: map[string]string{ConfigHashAnnotation: "abc"},
+			wantPodAnnots:  map[string]string{ConfigHashAnnotation: "abc"},
+		},
 		{
 			name:         "unsupported resource type returns error",
 			resourceType: "StatefulSet",
@@ -356,6 +379,9 @@ func TestGenerateUserOverlay(t *testing.T) {
 			if tt.podTemplateSpec != nil {
 				opts = append(opts, WithOverlayPodTemplateSpec(tt.podTemplateSpec))
 			}
+			if tt.podAnnotations != nil {
+				opts = append(opts, WithOverlayPodAnnotations(tt.podAnnotations))
+			}
 
 			overlay, err := GenerateUserOverlay(tt.resourceType, "test", FalcoDefaults, opts...)
 			if tt.wantErr {
@@ -376,6 +402,17 @@ func TestGenerateUserOverlay(t *testing.T) {
 				assert.Equal(t, v, templateLabels[k], "pod template label %s", k)
 			}
 
+			if tt.wantPodAnnots != nil {
+				annotations, _, err := unstructured.NestedStringMap(overlay.Object, "spec", "template", "metadata", "annotations")
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantPodAnnots, annotations)
+			}
+			if tt.podTemplateSpec != nil && tt.podAnnotations != nil {
+				// The user's pod template spec must not be mutated by the merge.
+				assert.NotEqual(t, tt.podAnnotations[ConfigHashAnnotation],
+					tt.podTemplateSpec.Annotations[ConfigHashAnnotation])
+			}
+
 			if tt.wantHasReplicas {
 				replicas, found, _ := unstructured.NestedInt64(overlay.Object, "spec", "replicas")
 				require.True(t, found)
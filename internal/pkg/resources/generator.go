@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -51,7 +52,7 @@ func generateDeployment(meta *metav1.ObjectMeta, defs *InstanceDefaults, nativeS
 		WithNamespace(meta.Namespace).
 		WithSelector(forgeSelectorLabels(meta.Name)).
 		WithReplicas(defs.Replicas).
-		WithPodTemplateLabels(forgeSelectorLabels(meta.Name)).
+		WithPodTemplateLabels(forgePodLabels(meta.Name, defs)).
 		WithTolerations(defs.Tolerations).
 		WithServiceAccount(meta.Name).
 		WithPodSecurityContext(defs.PodSecurityContext).
@@ -71,7 +72,7 @@ func generateDaemonSet(meta *metav1.ObjectMeta, defs *InstanceDefaults, nativeSi
 		WithName(meta.Name).
 		WithNamespace(meta.Namespace).
 		WithSelector(forgeSelectorLabels(meta.Name)).
-		WithPodTemplateLabels(forgeSelectorLabels(meta.Name)).
+		WithPodTemplateLabels(forgePodLabels(meta.Name, defs)).
 		WithTolerations(defs.Tolerations).
 		WithServiceAccount(meta.Name).
 		WithPodSecurityContext(defs.PodSecurityContext).
@@ -121,12 +122,13 @@ func addSidecarContainers(b any, nativeSidecar bool, defs *InstanceDefaults) {
 	}
 }
 
-// GenerateService generates a Service from the given object and defaults.
+// GenerateService generates a Service from the given object and defaults. The Service carries the
+// selector labels of the instance, so that a ServiceMonitor can select it.
 func GenerateService(obj client.Object, defs *InstanceDefaults) runtime.Object {
 	b := builders.NewService().
 		WithName(obj.GetName()).
 		WithNamespace(obj.GetNamespace()).
-		WithLabels(obj.GetLabels()).
+		WithLabels(labels.Merge(obj.GetLabels(), forgeSelectorLabels(obj.GetName()))).
 		WithType(corev1.ServiceTypeClusterIP).
 		WithSelector(forgeSelectorLabels(obj.GetName()))
 
@@ -221,10 +223,25 @@ func GenerateConfigMap(obj client.Object, defs *InstanceDefaults, workloadType s
 		return nil, fmt.Errorf("no ConfigMap data for workload type %q", workloadType)
 	}
 
+	return GenerateConfigMapWithData(obj, data), nil
+}
+
+// GenerateConfigMapWithData generates the ConfigMap of the given object holding the given data.
+func GenerateConfigMapWithData(obj client.Object, data map[string]string) runtime.Object {
 	return builders.NewConfigMap().
 		WithName(obj.GetName()).
 		WithNamespace(obj.GetNamespace()).
 		WithLabels(obj.GetLabels()).
 		WithData(data).
-		Build(), nil
+		Build()
+}
+
+// GenerateNodePoolConfigMap generates the ConfigMap holding the configuration of a node pool of the given object.
+func GenerateNodePoolConfigMap(obj client.Object, pool string, data map[string]string) runtime.Object {
+	return builders.NewConfigMap().
+		WithName(NodePoolName(obj.GetName(), pool)).
+		WithNamespace(obj.GetNamespace()).
+		WithLabels(labels.Merge(obj.GetLabels(), map[string]string{NodePoolLabel: pool})).
+		WithData(data).
+		Build()
 }
@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -88,7 +89,7 @@ func TestGenerateService(t *testing.T) {
 
 			assert.Equal(t, testName, svc.Name)
 			assert.Equal(t, testNamespace, svc.Namespace)
-			assert.Equal(t, testLabels, svc.Labels)
+			assert.Equal(t, map[string]string(labels.Merge(testLabels, forgeSelectorLabels(testName))), svc.Labels)
 			assert.Equal(t, corev1.ServiceTypeClusterIP, svc.Spec.Type)
 			assert.Equal(t, forgeSelectorLabels(testName), svc.Spec.Selector)
 			require.Len(t, svc.Spec.Ports, tt.wantPortCount)
@@ -180,9 +181,9 @@ func TestGenerateRole(t *testing.T) {
 		wantRuleCount int
 	}{
 		{
-			name:          "falco role has 6 rules",
+			name:          "falco role has 8 rules",
 			defs:          FalcoDefaults,
-			wantRuleCount: 6,
+			wantRuleCount: 8,
 		},
 		{
 			name:          "metacollector role has no rules",
@@ -235,6 +236,37 @@ func TestFalcoRoleAllowsArtifactNodeCache(t *testing.T) {
 	assert.ElementsMatch(t, []string{"get", "list", "watch"}, resourceRule.Verbs)
 }
 
+func TestFalcoRoleReadsRevisions(t *testing.T) {
+	role := GenerateRole(testObject(), FalcoDefaults).(*rbacv1.Role) //nolint:forcetypeassert // generator contract
+
+	var resourceRule *rbacv1.PolicyRule
+	for i := range role.Rules {
+		rule := &role.Rules[i]
+		if slices.Contains(rule.Resources, "controllerrevisions") {
+			resourceRule = rule
+		}
+	}
+
+	require.NotNil(t, resourceRule, "the artifact operator reads revisions for rollbacks")
+	assert.ElementsMatch(t, []string{"get", "list", "watch"}, resourceRule.Verbs)
+}
+
+func TestFalcoRoleReadsConfigMaps(t *testing.T) {
+	role := GenerateRole(testObject(), FalcoDefaults).(*rbacv1.Role) //nolint:forcetypeassert // generator contract
+
+	var resourceRule *rbacv1.PolicyRule
+	for i := range role.Rules {
+		rule := &role.Rules[i]
+		if slices.Contains(rule.Resources, "configmaps") {
+			resourceRule = rule
+		}
+	}
+
+	require.NotNil(t, resourceRule, "the artifact operator reads the configuration of the instance and of the artifacts")
+	// The effective configuration and rules index of the node are published by the instance operator.
+	assert.ElementsMatch(t, []string{"get", "list", "watch"}, resourceRule.Verbs)
+}
+
 func TestGenerateRoleBinding(t *testing.T) {
 	obj := testObject()
 	rb := GenerateRoleBinding(obj).(*rbacv1.RoleBinding)
@@ -326,3 +358,16 @@ func TestGenerateConfigMap(t *testing.T) {
 			"DaemonSet and Deployment falco.yaml configs should differ")
 	})
 }
+
+func TestGenerateNodePoolConfigMap(t *testing.T) {
+	obj := testObject()
+	data := map[string]string{"falco.yaml": "engine:\n  kind: kmod\n"}
+
+	cm := GenerateNodePoolConfigMap(obj, "gpu", data).(*corev1.ConfigMap)
+
+	assert.Equal(t, testName+"-gpu", cm.Name)
+	assert.Equal(t, testNamespace, cm.Namespace)
+	assert.Equal(t, map[string]string{"app": "test", "env": "dev", NodePoolLabel: "gpu"}, cm.Labels)
+	assert.Equal(t, data, cm.Data)
+	assert.Equal(t, map[string]string{"app": "test", "env": "dev"}, obj.Labels, "object labels must not be mutated")
+}
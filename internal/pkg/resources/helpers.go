@@ -17,7 +17,11 @@
 package resources
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -35,6 +39,11 @@ func GenerateUniqueName(name, namespace string) string {
 	return fmt.Sprintf("%s%s%s", escapedName, delimiter, escapedNamespace)
 }
 
+// NodePoolName returns the name of the DaemonSet and ConfigMap generated for a node pool of an instance.
+func NodePoolName(name, pool string) string {
+	return name + "-" + pool
+}
+
 // ParseUniqueName reverses the unique name back into the original name and namespace.
 func ParseUniqueName(uniqueName string) (name, namespace string, err error) {
 	// Check if the unique name contains more than one delimiter.
@@ -60,6 +69,16 @@ func ParseUniqueName(uniqueName string) (name, namespace string, err error) {
 	return originalName, originalNamespace, nil
 }
 
+// ComputeConfigMapHash returns a stable sha256 hash of the given ConfigMap data.
+// Keys are hashed in sorted order so the result does not depend on map iteration.
+func ComputeConfigMapHash(data map[string]string) string {
+	h := sha256.New()
+	for _, k := range slices.Sorted(maps.Keys(data)) {
+		_, _ = fmt.Fprintf(h, "%d:%s%d:%s", len(k), k, len(data[k]), data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // removeEmptyContainers removes the nil containers field from the unstructured resource if it exists.
 // This prevents an empty containers field from overriding the default one during structured merge diff.
 func removeEmptyContainers(obj *unstructured.Unstructured) error {
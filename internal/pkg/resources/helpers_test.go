@@ -74,3 +74,17 @@ func TestParseUniqueName(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeConfigMapHash(t *testing.T) {
+	base := map[string]string{"falco.yaml": "engine:\n  kind: modern_ebpf\n", "other": "x"}
+
+	assert.Equal(t, ComputeConfigMapHash(base), ComputeConfigMapHash(map[string]string{
+		"other": "x", "falco.yaml": "engine:\n  kind: modern_ebpf\n",
+	}), "hash must not depend on key order")
+	assert.NotEqual(t, ComputeConfigMapHash(base), ComputeConfigMapHash(map[string]string{
+		"falco.yaml": "engine:\n  kind: nodriver\n", "other": "x",
+	}), "hash must change with the content")
+	assert.NotEqual(t, ComputeConfigMapHash(map[string]string{"ab": "c"}), ComputeConfigMapHash(map[string]string{"a": "bc"}),
+		"hash must not be ambiguous across key/value boundaries")
+	assert.Len(t, ComputeConfigMapHash(nil), 64)
+}
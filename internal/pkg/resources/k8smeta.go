@@ -0,0 +1,91 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/image"
+)
+
+const (
+	// K8smetaPluginConfigName is the name of the k8smeta plugin in the Falco configuration, expected by the
+	// rules using its fields.
+	K8smetaPluginConfigName = "k8smeta"
+	// collectorPortName is the name of the port of the metacollector Service the k8smeta plugin connects to.
+	collectorPortName = "broker-grpc"
+)
+
+// K8smetaPluginName returns the name of the k8smeta Plugin generated for the Falco instance with the given name.
+func K8smetaPluginName(name string) string {
+	return name + "-k8smeta"
+}
+
+// GenerateK8smetaPlugin generates the k8smeta Plugin restricted to the given Falco instance, connecting it to the gRPC
+// broker of the Service of the given metacollector.
+func GenerateK8smetaPlugin(falco, collector client.Object) (*artifactv1alpha1.Plugin, error) {
+	i := slices.IndexFunc(MetacollectorDefaults.ServicePorts, func(p corev1.ServicePort) bool {
+		return p.Name == collectorPortName
+	})
+	if i < 0 {
+		return nil, fmt.Errorf("metacollector has no %s port", collectorPortName)
+	}
+
+	initConfig, err := json.Marshal(map[string]any{
+		"collectorHostname": fmt.Sprintf("%s.%s.svc", collector.GetName(), collector.GetNamespace()),
+		"collectorPort":     MetacollectorDefaults.ServicePorts[i].Port,
+		"nodeName":          "${FALCO_K8S_NODE_NAME}",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the k8smeta init config: %w", err)
+	}
+
+	return &artifactv1alpha1.Plugin{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Plugin",
+			APIVersion: artifactv1alpha1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      K8smetaPluginName(falco.GetName()),
+			Namespace: falco.GetNamespace(),
+			Labels:    falco.GetLabels(),
+		},
+		Spec: artifactv1alpha1.PluginSpec{
+			FalcoRef: &commonv1alpha1.FalcoRef{Name: falco.GetName()},
+			OCIArtifact: &commonv1alpha1.OCIArtifact{
+				Image: commonv1alpha1.ImageSpec{
+					Repository: image.K8smetaPluginRepository,
+					Tag:        image.K8smetaPluginTag,
+				},
+				Registry: &commonv1alpha1.RegistryConfig{Name: image.PluginRegistry},
+			},
+			Config: &artifactv1alpha1.PluginConfig{
+				Name:       K8smetaPluginConfigName,
+				InitConfig: &apiextensionsv1.JSON{Raw: initConfig},
+			},
+		},
+	}, nil
+}
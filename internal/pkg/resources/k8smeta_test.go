@@ -0,0 +1,59 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/builders"
+	"github.com/falcosecurity/falco-operator/internal/pkg/image"
+)
+
+func TestGenerateK8smetaPlugin(t *testing.T) {
+	falco := builders.NewFalco().WithName("falco").WithNamespace("falco-ns").WithLabels(map[string]string{"team": "sec"}).Build()
+	collector := builders.NewComponent().WithName("collector").WithNamespace("falco-ns").Build()
+
+	plugin, err := GenerateK8smetaPlugin(falco, collector)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Plugin", plugin.Kind)
+	assert.Equal(t, "falco-k8smeta", plugin.Name)
+	assert.Equal(t, "falco-ns", plugin.Namespace)
+	assert.Equal(t, map[string]string{"team": "sec"}, plugin.Labels)
+
+	require.NotNil(t, plugin.Spec.FalcoRef)
+	assert.Equal(t, "falco", plugin.Spec.FalcoRef.Name)
+	require.NotNil(t, plugin.Spec.OCIArtifact)
+	assert.Equal(t, image.K8smetaPluginRepository, plugin.Spec.OCIArtifact.Image.Repository)
+	assert.Equal(t, image.K8smetaPluginTag, plugin.Spec.OCIArtifact.Image.Tag)
+	require.NotNil(t, plugin.Spec.OCIArtifact.Registry)
+	assert.Equal(t, image.PluginRegistry, plugin.Spec.OCIArtifact.Registry.Name)
+
+	require.NotNil(t, plugin.Spec.Config)
+	assert.Equal(t, K8smetaPluginConfigName, plugin.Spec.Config.Name)
+	var initConfig map[string]any
+	require.NoError(t, json.Unmarshal(plugin.Spec.Config.InitConfig.Raw, &initConfig))
+	assert.Equal(t, map[string]any{
+		"collectorHostname": "collector.falco-ns.svc",
+		"collectorPort":     float64(45000),
+		"nodeName":          "${FALCO_K8S_NODE_NAME}",
+	}, initConfig)
+}
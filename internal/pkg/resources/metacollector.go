@@ -18,6 +18,7 @@ package resources
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -32,6 +33,7 @@ const (
 
 // MetacollectorDefaults holds the default configuration for the Metacollector instance type.
 var MetacollectorDefaults = &InstanceDefaults{
+	TypeName:        MetacollectorTypeName,
 	ResourceType:    ResourceTypeDeployment,
 	Replicas:        new(int32(1)),
 	ContainerName:   "metacollector",
@@ -94,6 +96,16 @@ var MetacollectorDefaults = &InstanceDefaults{
 		{Name: "health-probe", Protocol: corev1.ProtocolTCP, Port: 8081, TargetPort: intstr.FromInt32(8081)},
 		{Name: "broker-grpc", Protocol: corev1.ProtocolTCP, Port: 45000, TargetPort: intstr.FromInt32(45000)},
 	},
+	MetricsPort: "metrics",
+
+	// Falco connects to the gRPC broker with the k8smeta plugin.
+	NetworkPolicy: &NetworkPolicyDefaults{
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(intstr.FromString("broker-grpc"), corev1.ProtocolTCP)},
+			From:  []networkingv1.NetworkPolicyPeer{instanceTypePeer(FalcoTypeName)},
+		}},
+	},
+
 	ClusterRoleRules: []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{"apps"},
@@ -0,0 +1,208 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+)
+
+// monitoringGroupVersion is the API group and version of the prometheus-operator objects.
+var monitoringGroupVersion = schema.GroupVersion{Group: "monitoring.coreos.com", Version: "v1"}
+
+var (
+	// ServiceMonitorGVK is the GroupVersionKind of the prometheus-operator ServiceMonitor.
+	ServiceMonitorGVK = monitoringGroupVersion.WithKind("ServiceMonitor")
+	// PodMonitorGVK is the GroupVersionKind of the prometheus-operator PodMonitor.
+	PodMonitorGVK = monitoringGroupVersion.WithKind("PodMonitor")
+	// PrometheusRuleGVK is the GroupVersionKind of the prometheus-operator PrometheusRule.
+	PrometheusRuleGVK = monitoringGroupVersion.WithKind("PrometheusRule")
+)
+
+const (
+	// metricsPath is the path the instances serve their Prometheus metrics on.
+	metricsPath = "/metrics"
+
+	// ArtifactOperatorMetricsPort is the name of the port the artifact operator sidecar serves its metrics on,
+	// once enabled by EnableArtifactOperatorMetrics.
+	ArtifactOperatorMetricsPort = "sidecar-metrics"
+	// artifactOperatorMetricsPortNumber is the number of ArtifactOperatorMetricsPort.
+	artifactOperatorMetricsPortNumber int32 = 8080
+)
+
+// GenerateServiceMonitor generates a ServiceMonitor scraping the metrics port of the Service of the given object.
+// It returns nil when the monitor is not enabled or the instance type serves no metrics.
+func GenerateServiceMonitor(obj client.Object, defs *InstanceDefaults, spec *commonv1alpha1.MonitorSpec) *unstructured.Unstructured {
+	if spec == nil || !spec.Enabled || defs.MetricsPort == "" {
+		return nil
+	}
+
+	return newMonitoringObject(obj, ServiceMonitorGVK, spec.Labels, map[string]any{
+		"selector":          map[string]any{"matchLabels": selectorLabels(obj.GetName())},
+		"namespaceSelector": map[string]any{"matchNames": []any{obj.GetNamespace()}},
+		"endpoints":         []any{metricsEndpoint(defs.MetricsPort, spec.Interval)},
+	})
+}
+
+// GeneratePodMonitor generates a PodMonitor scraping the metrics port of the pods of the given object, and the
+// additional ports, e.g. the one of a sidecar. It returns nil when the monitor is not enabled or the instance
+// type serves no metrics.
+func GeneratePodMonitor(obj client.Object, defs *InstanceDefaults, spec *commonv1alpha1.MonitorSpec,
+	additionalPorts ...string) *unstructured.Unstructured {
+	if spec == nil || !spec.Enabled || defs.MetricsPort == "" {
+		return nil
+	}
+
+	endpoints := []any{metricsEndpoint(defs.MetricsPort, spec.Interval)}
+	for _, port := range additionalPorts {
+		endpoints = append(endpoints, metricsEndpoint(port, spec.Interval))
+	}
+
+	return newMonitoringObject(obj, PodMonitorGVK, spec.Labels, map[string]any{
+		"selector":            map[string]any{"matchLabels": selectorLabels(obj.GetName())},
+		"namespaceSelector":   map[string]any{"matchNames": []any{obj.GetNamespace()}},
+		"podMetricsEndpoints": endpoints,
+	})
+}
+
+// GenerateFalcoPrometheusRule generates a PrometheusRule alerting on the event drops and the container restarts
+// of the Falco pods of the given object, and on the artifacts their artifact operator fails to program.
+func GenerateFalcoPrometheusRule(obj client.Object, labels map[string]string) *unstructured.Unstructured {
+	// PromQL raw strings need no escaping but the regular expression does.
+	selector := fmt.Sprintf("namespace=%q, pod=~`%s-.+`", obj.GetNamespace(), regexp.QuoteMeta(obj.GetName()))
+	instance := fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+
+	rules := []any{
+		alertingRule("FalcoEventDrops",
+			fmt.Sprintf("sum by (namespace, pod) (rate(falcosecurity_scap_n_drops_total{%[1]s}[5m]))"+
+				" / sum by (namespace, pod) (rate(falcosecurity_scap_n_evts_total{%[1]s}[5m])) * 100 > 1", selector),
+			"10m",
+			"Falco is dropping syscall events",
+			fmt.Sprintf("Falco %s drops more than 1%% of the syscall events on pod {{ $labels.pod }}.", instance)),
+		alertingRule("FalcoContainerRestarts",
+			fmt.Sprintf("increase(kube_pod_container_status_restarts_total{%s, container=%q}[15m]) > 2",
+				selector, FalcoDefaults.ContainerName),
+			"",
+			"Falco is restarting",
+			fmt.Sprintf("The Falco container of pod {{ $labels.pod }} of Falco %s restarted more than twice in 15 minutes.",
+				instance)),
+		alertingRule("FalcoArtifactsNotProgrammed",
+			fmt.Sprintf("sum by (namespace, pod, controller) "+
+				"(increase(controller_runtime_reconcile_errors_total{%s, controller=~\"artifact-.+\"}[15m])) > 0", selector),
+			"15m",
+			"Falco artifacts are not programmed",
+			fmt.Sprintf("The artifact operator of pod {{ $labels.pod }} of Falco %s fails to program artifacts "+
+				"({{ $labels.controller }}).", instance)),
+	}
+
+	return newMonitoringObject(obj, PrometheusRuleGVK, labels, map[string]any{
+		"groups": []any{map[string]any{"name": fmt.Sprintf("falco.%s.%s", obj.GetNamespace(), obj.GetName()), "rules": rules}},
+	})
+}
+
+// newMonitoringObject returns a prometheus-operator object named and labeled after the given object.
+func newMonitoringObject(obj client.Object, gvk schema.GroupVersionKind, labels map[string]string,
+	spec map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(obj.GetName())
+	u.SetNamespace(obj.GetNamespace())
+
+	objectLabels := maps.Clone(obj.GetLabels())
+	if objectLabels == nil {
+		objectLabels = map[string]string{}
+	}
+	maps.Copy(objectLabels, labels)
+	if len(objectLabels) > 0 {
+		u.SetLabels(objectLabels)
+	}
+	return u
+}
+
+// metricsEndpoint returns a scrape endpoint of the given port.
+func metricsEndpoint(port, interval string) map[string]any {
+	endpoint := map[string]any{"port": port, "path": metricsPath}
+	if interval != "" {
+		endpoint["interval"] = interval
+	}
+	return endpoint
+}
+
+// alertingRule returns a Prometheus alerting rule of warning severity, firing once the expression holds for the
+// given duration, or immediately when it is empty.
+func alertingRule(name, expr, duration, summary, description string) map[string]any {
+	rule := map[string]any{
+		"alert":       name,
+		"expr":        expr,
+		"labels":      map[string]any{"severity": "warning"},
+		"annotations": map[string]any{"summary": summary, "description": description},
+	}
+	if duration != "" {
+		rule["for"] = duration
+	}
+	return rule
+}
+
+// selectorLabels returns the selector labels of the given instance as unstructured content.
+func selectorLabels(name string) map[string]any {
+	labels := map[string]any{}
+	for k, v := range forgeSelectorLabels(name) {
+		labels[k] = v
+	}
+	return labels
+}
+
+// EnableArtifactOperatorMetrics makes the artifact operator sidecar of the given Falco workload serve its metrics
+// over HTTP on ArtifactOperatorMetricsPort, for a PodMonitor to scrape them. They are disabled by default.
+func EnableArtifactOperatorMetrics(workload runtime.Object) {
+	var podSpec *corev1.PodSpec
+	switch w := workload.(type) {
+	case *appsv1.DaemonSet:
+		podSpec = &w.Spec.Template.Spec
+	case *appsv1.Deployment:
+		podSpec = &w.Spec.Template.Spec
+	default:
+		return
+	}
+
+	// The sidecar is an init container when running as a native sidecar.
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			if containers[i].Name != FalcoDefaults.SidecarContainerName {
+				continue
+			}
+			containers[i].Args = append(slices.Clip(containers[i].Args),
+				fmt.Sprintf("--metrics-bind-address=:%d", artifactOperatorMetricsPortNumber), "--metrics-secure=false")
+			containers[i].Ports = append(slices.Clip(containers[i].Ports), corev1.ContainerPort{
+				Name:          ArtifactOperatorMetricsPort,
+				ContainerPort: artifactOperatorMetricsPortNumber,
+				Protocol:      corev1.ProtocolTCP,
+			})
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+)
+
+func TestGenerateServiceMonitor(t *testing.T) {
+	tests := []struct {
+		name         string
+		defs         *InstanceDefaults
+		spec         *commonv1alpha1.MonitorSpec
+		wantNil      bool
+		wantPort     string
+		wantLabels   map[string]string
+		wantInterval string
+	}{
+		{
+			name:    "not configured",
+			defs:    FalcoDefaults,
+			wantNil: true,
+		},
+		{
+			name:    "disabled",
+			defs:    FalcoDefaults,
+			spec:    &commonv1alpha1.MonitorSpec{Labels: map[string]string{"release": "prometheus"}},
+			wantNil: true,
+		},
+		{
+			name:    "instance type without metrics",
+			defs:    FalcosidekickUIDefaults,
+			spec:    &commonv1alpha1.MonitorSpec{Enabled: true},
+			wantNil: true,
+		},
+		{
+			name:       "falco",
+			defs:       FalcoDefaults,
+			spec:       &commonv1alpha1.MonitorSpec{Enabled: true},
+			wantPort:   "web",
+			wantLabels: testLabels,
+		},
+		{
+			name:         "metacollector with labels and interval",
+			defs:         MetacollectorDefaults,
+			spec:         &commonv1alpha1.MonitorSpec{Enabled: true, Interval: "15s", Labels: map[string]string{"release": "prometheus"}},
+			wantPort:     "metrics",
+			wantLabels:   map[string]string{"app": "test", "env": "dev", "release": "prometheus"},
+			wantInterval: "15s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := GenerateServiceMonitor(testObject(), tt.defs, tt.spec)
+			if tt.wantNil {
+				assert.Nil(t, sm)
+				return
+			}
+			require.NotNil(t, sm)
+
+			assert.Equal(t, ServiceMonitorGVK, sm.GroupVersionKind())
+			assert.Equal(t, testName, sm.GetName())
+			assert.Equal(t, testNamespace, sm.GetNamespace())
+			assert.Equal(t, tt.wantLabels, sm.GetLabels())
+
+			selector, _, _ := unstructured.NestedStringMap(sm.Object, "spec", "selector", "matchLabels")
+			assert.Equal(t, forgeSelectorLabels(testName), selector)
+			namespaces, _, _ := unstructured.NestedStringSlice(sm.Object, "spec", "namespaceSelector", "matchNames")
+			assert.Equal(t, []string{testNamespace}, namespaces)
+
+			endpoints, _, _ := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+			require.Len(t, endpoints, 1)
+			endpoint := endpoints[0].(map[string]any)
+			assert.Equal(t, tt.wantPort, endpoint["port"])
+			assert.Equal(t, "/metrics", endpoint["path"])
+			if tt.wantInterval == "" {
+				assert.NotContains(t, endpoint, "interval")
+			} else {
+				assert.Equal(t, tt.wantInterval, endpoint["interval"])
+			}
+		})
+	}
+}
+
+func TestGeneratePodMonitor(t *testing.T) {
+	assert.Nil(t, GeneratePodMonitor(testObject(), FalcoDefaults, &commonv1alpha1.MonitorSpec{}))
+
+	pm := GeneratePodMonitor(testObject(), FalcoDefaults, &commonv1alpha1.MonitorSpec{Enabled: true, Interval: "1m"},
+		ArtifactOperatorMetricsPort)
+	require.NotNil(t, pm)
+	assert.Equal(t, PodMonitorGVK, pm.GroupVersionKind())
+
+	selector, _, _ := unstructured.NestedStringMap(pm.Object, "spec", "selector", "matchLabels")
+	assert.Equal(t, forgeSelectorLabels(testName), selector)
+	endpoints, _, _ := unstructured.NestedSlice(pm.Object, "spec", "podMetricsEndpoints")
+	assert.Equal(t, []any{
+		map[string]any{"port": "web", "path": "/metrics", "interval": "1m"},
+		map[string]any{"port": ArtifactOperatorMetricsPort, "path": "/metrics", "interval": "1m"},
+	}, endpoints)
+}
+
+func TestGenerateFalcoPrometheusRule(t *testing.T) {
+	obj := testObject()
+	obj.Name = "falco.prod"
+
+	rule := GenerateFalcoPrometheusRule(obj, map[string]string{"release": "prometheus"})
+	require.NotNil(t, rule)
+	assert.Equal(t, PrometheusRuleGVK, rule.GroupVersionKind())
+	assert.Equal(t, "falco.prod", rule.GetName())
+	assert.Equal(t, "prometheus", rule.GetLabels()["release"])
+
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	require.Len(t, groups, 1)
+	rules := groups[0].(map[string]any)["rules"].([]any)
+	alerts := map[string]map[string]any{}
+	for _, r := range rules {
+		alerts[r.(map[string]any)["alert"].(string)] = r.(map[string]any)
+	}
+	require.Len(t, alerts, 3)
+
+	for _, name := range []string{"FalcoEventDrops", "FalcoContainerRestarts", "FalcoArtifactsNotProgrammed"} {
+		require.Contains(t, alerts, name)
+		assert.Contains(t, alerts[name]["expr"], "namespace=\"test-namespace\", pod=~`falco\\.prod-.+`")
+	}
+	assert.Contains(t, alerts["FalcoEventDrops"]["expr"], "falcosecurity_scap_n_drops_total")
+	assert.Contains(t, alerts["FalcoContainerRestarts"]["expr"], `container="falco"`)
+	assert.NotContains(t, alerts["FalcoContainerRestarts"], "for")
+	assert.Contains(t, alerts["FalcoArtifactsNotProgrammed"]["expr"], `controller=~"artifact-.+"`)
+}
+
+func TestEnableArtifactOperatorMetrics(t *testing.T) {
+	for _, nativeSidecar := range []bool{true, false} {
+		workload, err := GenerateWorkload(ResourceTypeDaemonSet, &metav1.ObjectMeta{Name: testName}, FalcoDefaults, nativeSidecar)
+		require.NoError(t, err)
+		EnableArtifactOperatorMetrics(workload)
+
+		podSpec := workload.(*appsv1.DaemonSet).Spec.Template.Spec
+		containers := podSpec.Containers
+		if nativeSidecar {
+			containers = podSpec.InitContainers
+		}
+		var sidecar *corev1.Container
+		for i := range containers {
+			if containers[i].Name == FalcoDefaults.SidecarContainerName {
+				sidecar = &containers[i]
+			}
+		}
+		require.NotNil(t, sidecar, "nativeSidecar=%v", nativeSidecar)
+		assert.Equal(t, []string{"--metrics-bind-address=:8080", "--metrics-secure=false"}, sidecar.Args)
+		assert.Equal(t, []corev1.ContainerPort{{Name: ArtifactOperatorMetricsPort, ContainerPort: 8080, Protocol: corev1.ProtocolTCP}},
+			sidecar.Ports)
+	}
+
+	assert.Empty(t, FalcoDefaults.SidecarContainers[0].Args, "the defaults must not be changed")
+	assert.Empty(t, FalcoDefaults.SidecarContainers[0].Ports, "the defaults must not be changed")
+}
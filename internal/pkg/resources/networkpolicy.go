@@ -0,0 +1,102 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+)
+
+// operatorName is the app.kubernetes.io/name label of the operator pods set by the Helm chart.
+const operatorName = "falco-operator"
+
+// GenerateNetworkPolicy generates the NetworkPolicy of the given object from the traffic of its instance type,
+// allowing the extra peers of the spec as well. It returns nil when the policy is disabled or the instance
+// type has no known traffic.
+func GenerateNetworkPolicy(obj client.Object, defs *InstanceDefaults, spec *commonv1alpha1.NetworkPolicySpec) *networkingv1.NetworkPolicy {
+	if spec == nil || !spec.Enabled || defs.NetworkPolicy == nil {
+		return nil
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Labels:    obj.GetLabels(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: forgeSelectorLabels(obj.GetName())},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     slices.Clone(defs.NetworkPolicy.Ingress),
+		},
+	}
+	if len(spec.From) > 0 {
+		policy.Spec.Ingress = append(policy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{From: spec.From})
+	}
+
+	if defs.NetworkPolicy.Egress != nil {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		policy.Spec.Egress = slices.Clone(defs.NetworkPolicy.Egress)
+		if len(spec.To) > 0 {
+			policy.Spec.Egress = append(policy.Spec.Egress, networkingv1.NetworkPolicyEgressRule{To: spec.To})
+		}
+	}
+
+	return policy
+}
+
+// instanceTypePeer returns a peer matching the pods of the instances of the given type in any namespace.
+func instanceTypePeer(typeName string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{InstanceTypeLabel: typeName}},
+		NamespaceSelector: &metav1.LabelSelector{},
+	}
+}
+
+// operatorPeer returns a peer matching the pods of the operator in any namespace.
+func operatorPeer() networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/name": operatorName}},
+		NamespaceSelector: &metav1.LabelSelector{},
+	}
+}
+
+// networkPolicyPort returns a NetworkPolicy port for the given container port name or number.
+func networkPolicyPort(port intstr.IntOrString, protocol corev1.Protocol) networkingv1.NetworkPolicyPort {
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+}
+
+// dnsEgressRule returns the egress rule letting an instance with a restricted egress resolve names.
+func dnsEgressRule() networkingv1.NetworkPolicyEgressRule {
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			networkPolicyPort(intstr.FromInt32(53), corev1.ProtocolUDP),
+			networkPolicyPort(intstr.FromInt32(53), corev1.ProtocolTCP),
+		},
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+)
+
+func TestGenerateNetworkPolicy(t *testing.T) {
+	prometheus := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"}},
+	}
+	redis := networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/24"}}
+
+	tests := []struct {
+		name            string
+		defs            *InstanceDefaults
+		spec            *commonv1alpha1.NetworkPolicySpec
+		wantNil         bool
+		wantPolicyTypes []networkingv1.PolicyType
+		wantIngressFrom [][]networkingv1.NetworkPolicyPeer
+		wantEgress      int
+	}{
+		{
+			name:    "not configured",
+			defs:    FalcoDefaults,
+			wantNil: true,
+		},
+		{
+			name:    "disabled",
+			defs:    FalcoDefaults,
+			spec:    &commonv1alpha1.NetworkPolicySpec{From: []networkingv1.NetworkPolicyPeer{prometheus}},
+			wantNil: true,
+		},
+		{
+			name:    "instance type without known traffic",
+			defs:    &InstanceDefaults{},
+			spec:    &commonv1alpha1.NetworkPolicySpec{Enabled: true},
+			wantNil: true,
+		},
+		{
+			name:            "falco is reached by the operator",
+			defs:            FalcoDefaults,
+			spec:            &commonv1alpha1.NetworkPolicySpec{Enabled: true, To: []networkingv1.NetworkPolicyPeer{redis}},
+			wantPolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			wantIngressFrom: [][]networkingv1.NetworkPolicyPeer{{operatorPeer()}},
+		},
+		{
+			name:            "metacollector with extra peers",
+			defs:            MetacollectorDefaults,
+			spec:            &commonv1alpha1.NetworkPolicySpec{Enabled: true, From: []networkingv1.NetworkPolicyPeer{prometheus}},
+			wantPolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			wantIngressFrom: [][]networkingv1.NetworkPolicyPeer{{instanceTypePeer(FalcoTypeName)}, {prometheus}},
+		},
+		{
+			name:            "falcosidekick is reached by falco",
+			defs:            FalcosidekickDefaults,
+			spec:            &commonv1alpha1.NetworkPolicySpec{Enabled: true},
+			wantPolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			wantIngressFrom: [][]networkingv1.NetworkPolicyPeer{{instanceTypePeer(FalcoTypeName)}},
+		},
+		{
+			name:            "falcosidekick-ui restricts its egress",
+			defs:            FalcosidekickUIDefaults,
+			spec:            &commonv1alpha1.NetworkPolicySpec{Enabled: true, To: []networkingv1.NetworkPolicyPeer{redis}},
+			wantPolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			wantIngressFrom: [][]networkingv1.NetworkPolicyPeer{{instanceTypePeer(FalcosidekickTypeName)}},
+			wantEgress:      3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := GenerateNetworkPolicy(testObject(), tt.defs, tt.spec)
+			if tt.wantNil {
+				assert.Nil(t, policy)
+				return
+			}
+			require.NotNil(t, policy)
+
+			assert.Equal(t, "NetworkPolicy", policy.Kind)
+			assert.Equal(t, testName, policy.Name)
+			assert.Equal(t, testNamespace, policy.Namespace)
+			assert.Equal(t, testLabels, policy.Labels)
+			assert.Equal(t, forgeSelectorLabels(testName), policy.Spec.PodSelector.MatchLabels)
+			assert.Equal(t, tt.wantPolicyTypes, policy.Spec.PolicyTypes)
+
+			from := make([][]networkingv1.NetworkPolicyPeer, 0, len(policy.Spec.Ingress))
+			for _, rule := range policy.Spec.Ingress {
+				from = append(from, rule.From)
+			}
+			assert.Equal(t, tt.wantIngressFrom, from)
+			assert.Len(t, policy.Spec.Egress, tt.wantEgress)
+		})
+	}
+}
+
+func TestGenerateNetworkPolicyDoesNotAlterDefaults(t *testing.T) {
+	spec := &commonv1alpha1.NetworkPolicySpec{
+		Enabled: true,
+		From:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+	}
+
+	GenerateNetworkPolicy(testObject(), MetacollectorDefaults, spec)
+
+	assert.Len(t, MetacollectorDefaults.NetworkPolicy.Ingress, 1)
+}
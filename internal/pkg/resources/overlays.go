@@ -18,6 +18,7 @@ package resources
 
 import (
 	"fmt"
+	"maps"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -38,6 +39,7 @@ type overlayConfig struct {
 	strategy        *appsv1.DeploymentStrategy
 	updateStrategy  *appsv1.DaemonSetUpdateStrategy
 	podTemplateSpec *corev1.PodTemplateSpec
+	podAnnotations  map[string]string
 	version         *string
 }
 
@@ -66,6 +68,12 @@ func WithOverlayPodTemplateSpec(pts *corev1.PodTemplateSpec) OverlayOption {
 	return func(c *overlayConfig) { c.podTemplateSpec = pts }
 }
 
+// WithOverlayPodAnnotations adds annotations to the pod template of the overlay resource.
+// They are merged on top of the annotations set through the pod template spec.
+func WithOverlayPodAnnotations(a map[string]string) OverlayOption {
+	return func(c *overlayConfig) { c.podAnnotations = a }
+}
+
 // WithOverlayVersion sets the version override on the overlay resource.
 func WithOverlayVersion(version *string) OverlayOption {
 	return func(c *overlayConfig) { c.version = version }
@@ -146,6 +154,7 @@ func GenerateUserOverlay(resourceType, name string, defs *InstanceDefaults, opts
 		if cfg.strategy != nil {
 			dep.Spec.Strategy = *cfg.strategy
 		}
+		dep.Spec.Template.Annotations = mergePodAnnotations(dep.Spec.Template.Annotations, cfg.podAnnotations)
 		applyVersionOverride(defs, cfg.version, &dep.Spec.Template)
 		userResource = dep
 	case ResourceTypeDaemonSet:
@@ -168,6 +177,7 @@ func GenerateUserOverlay(resourceType, name string, defs *InstanceDefaults, opts
 		if cfg.updateStrategy != nil {
 			ds.Spec.UpdateStrategy = *cfg.updateStrategy
 		}
+		ds.Spec.Template.Annotations = mergePodAnnotations(ds.Spec.Template.Annotations, cfg.podAnnotations)
 		applyVersionOverride(defs, cfg.version, &ds.Spec.Template)
 		userResource = ds
 	default:
@@ -188,9 +198,26 @@ func GenerateUserOverlay(resourceType, name string, defs *InstanceDefaults, opts
 	return resource, nil
 }
 
+// mergePodAnnotations returns a copy of base with extra layered on top.
+// The base map is never mutated since it may belong to the user's pod template spec.
+func mergePodAnnotations(base, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(extra))
+	maps.Copy(merged, base)
+	maps.Copy(merged, extra)
+	return merged
+}
+
+// applyVersionOverride sets the image of the main container to the given version. A main container
+// already present in the template keeps its image, unless it has none.
 func applyVersionOverride(defs *InstanceDefaults, version *string, template *corev1.PodTemplateSpec) {
 	for i := range template.Spec.Containers {
 		if template.Spec.Containers[i].Name == defs.ContainerName {
+			if template.Spec.Containers[i].Image == "" && version != nil && *version != "" {
+				template.Spec.Containers[i].Image = defs.ImageRepository + ":" + *version
+			}
 			return
 		}
 	}
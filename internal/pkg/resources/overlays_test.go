@@ -373,3 +373,13 @@ func TestApplyVersionOverride(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyVersionOverrideFillsMissingImage(t *testing.T) {
+	template := &corev1.PodTemplateSpec{}
+	template.Spec.Containers = []corev1.Container{{Name: FalcoDefaults.ContainerName}}
+
+	applyVersionOverride(FalcoDefaults, new("0.38.0"), template)
+
+	require.Len(t, template.Spec.Containers, 1)
+	assert.Equal(t, FalcoDefaults.ImageRepository+":0.38.0", template.Spec.Containers[0].Image)
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+)
+
+func TestGeneratePodDisruptionBudget(t *testing.T) {
+	pdb := GeneratePodDisruptionBudget(testObject())
+
+	assert.Equal(t, "PodDisruptionBudget", pdb.Kind)
+	assert.Equal(t, testName, pdb.Name)
+	assert.Equal(t, testNamespace, pdb.Namespace)
+	assert.Equal(t, testLabels, pdb.Labels)
+	assert.Equal(t, new(intstr.FromInt32(1)), pdb.Spec.MaxUnavailable)
+	assert.Nil(t, pdb.Spec.MinAvailable)
+	assert.Equal(t, forgeSelectorLabels(testName), pdb.Spec.Selector.MatchLabels)
+}
+
+func TestGenerateHorizontalPodAutoscaler(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        *instancev1alpha1.AutoscalingSpec
+		wantNil     bool
+		wantTargets map[corev1.ResourceName]int32
+	}{
+		{
+			name:    "not configured",
+			wantNil: true,
+		},
+		{
+			name:    "disabled",
+			spec:    &instancev1alpha1.AutoscalingSpec{MaxReplicas: 5},
+			wantNil: true,
+		},
+		{
+			name:        "CPU target by default",
+			spec:        &instancev1alpha1.AutoscalingSpec{Enabled: true, MaxReplicas: 5},
+			wantTargets: map[corev1.ResourceName]int32{corev1.ResourceCPU: 80},
+		},
+		{
+			name:        "memory target only",
+			spec:        &instancev1alpha1.AutoscalingSpec{Enabled: true, MaxReplicas: 5, TargetMemoryUtilizationPercentage: new(int32(70))},
+			wantTargets: map[corev1.ResourceName]int32{corev1.ResourceMemory: 70},
+		},
+		{
+			name: "CPU and memory targets",
+			spec: &instancev1alpha1.AutoscalingSpec{Enabled: true, MinReplicas: new(int32(2)), MaxReplicas: 5,
+				TargetCPUUtilizationPercentage: new(int32(60)), TargetMemoryUtilizationPercentage: new(int32(70))},
+			wantTargets: map[corev1.ResourceName]int32{corev1.ResourceCPU: 60, corev1.ResourceMemory: 70},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hpa := GenerateHorizontalPodAutoscaler(testObject(), tt.spec)
+			if tt.wantNil {
+				assert.Nil(t, hpa)
+				return
+			}
+			require.NotNil(t, hpa)
+
+			assert.Equal(t, "HorizontalPodAutoscaler", hpa.Kind)
+			assert.Equal(t, testName, hpa.Name)
+			assert.Equal(t, testNamespace, hpa.Namespace)
+			assert.Equal(t, autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: testName},
+				hpa.Spec.ScaleTargetRef)
+			assert.Equal(t, tt.spec.MinReplicas, hpa.Spec.MinReplicas)
+			assert.Equal(t, tt.spec.MaxReplicas, hpa.Spec.MaxReplicas)
+
+			targets := map[corev1.ResourceName]int32{}
+			for _, metric := range hpa.Spec.Metrics {
+				require.Equal(t, autoscalingv2.ResourceMetricSourceType, metric.Type)
+				require.Equal(t, autoscalingv2.UtilizationMetricType, metric.Resource.Target.Type)
+				targets[metric.Resource.Name] = *metric.Resource.Target.AverageUtilization
+			}
+			assert.Equal(t, tt.wantTargets, targets)
+		})
+	}
+}
@@ -19,6 +19,7 @@ package resources
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -28,10 +29,14 @@ import (
 const (
 	// FalcosidekickTypeName is the type name for the Falcosidekick component.
 	FalcosidekickTypeName = "falcosidekick"
+
+	// falcosidekickCertsDir is the directory the certificate Secret is mounted at when TLS is enabled.
+	falcosidekickCertsDir = "/etc/certs"
 )
 
 // FalcosidekickDefaults holds the default configuration for the Falcosidekick component.
 var FalcosidekickDefaults = &InstanceDefaults{
+	TypeName:        FalcosidekickTypeName,
 	ResourceType:    ResourceTypeDeployment,
 	Replicas:        new(int32(2)),
 	ContainerName:   "falcosidekick",
@@ -75,6 +80,35 @@ var FalcosidekickDefaults = &InstanceDefaults{
 	ServicePorts: []corev1.ServicePort{
 		{Name: "http", Protocol: corev1.ProtocolTCP, Port: 2801, TargetPort: intstr.FromString("http")},
 	},
+	MetricsPort: "http",
+
+	// Falco sends its alerts to the http port.
+	NetworkPolicy: &NetworkPolicyDefaults{
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(intstr.FromString("http"), corev1.ProtocolTCP)},
+			From:  []networkingv1.NetworkPolicyPeer{instanceTypePeer(FalcoTypeName)},
+		}},
+	},
+
+	// The http port requires the clients to authenticate with a certificate issued by the operator, and the
+	// probes use the plain port serving /ping only. The outputs trust the CA of the operator too.
+	TLS: &TLSDefaults{
+		MountPath: falcosidekickCertsDir,
+		EnvVars: []corev1.EnvVar{
+			{Name: "TLSSERVER_DEPLOY", Value: "true"},
+			{Name: "TLSSERVER_CERTFILE", Value: falcosidekickCertsDir + "/" + corev1.TLSCertKey},
+			{Name: "TLSSERVER_KEYFILE", Value: falcosidekickCertsDir + "/" + corev1.TLSPrivateKeyKey},
+			{Name: "TLSSERVER_MUTUALTLS", Value: "true"},
+			{Name: "TLSSERVER_CACERTFILE", Value: falcosidekickCertsDir + "/" + CACertKey},
+			{Name: "TLSSERVER_NOTLSPORT", Value: "2810"},
+			{Name: "TLSCLIENT_CACERTFILE", Value: falcosidekickCertsDir + "/" + CACertKey},
+		},
+		Ports: []corev1.ContainerPort{
+			{ContainerPort: 2810, Name: "http-notls", Protocol: corev1.ProtocolTCP},
+		},
+		ProbePort: new(intstr.FromString("http-notls")),
+	},
+
 	// Falcosidekick needs to get endpoints for service discovery.
 	RoleRules: []rbacv1.PolicyRule{
 		{
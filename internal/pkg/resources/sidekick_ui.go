@@ -19,6 +19,7 @@ package resources
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/falcosecurity/falco-operator/internal/pkg/image"
@@ -31,6 +32,9 @@ const (
 	// DefaultRedisAddress is the default Redis service address.
 	// Users must provide a Redis instance at this address, or override via podTemplateSpec.
 	DefaultRedisAddress = "falcosidekick-ui-redis:6379"
+
+	// defaultRedisPort is the port of DefaultRedisAddress.
+	defaultRedisPort = 6379
 )
 
 // FalcosidekickUIDefaults holds the default configuration for the Falcosidekick UI component.
@@ -39,6 +43,7 @@ const (
 // wait-redis init container will block and the pod will stay in Init:0/1 state.
 // Users can override the Redis address via podTemplateSpec.
 var FalcosidekickUIDefaults = &InstanceDefaults{
+	TypeName:        FalcosidekickUITypeName,
 	ResourceType:    ResourceTypeDeployment,
 	Replicas:        new(int32(2)),
 	ContainerName:   "falcosidekick-ui",
@@ -99,6 +104,17 @@ var FalcosidekickUIDefaults = &InstanceDefaults{
 	ServicePorts: []corev1.ServicePort{
 		{Name: "http", Protocol: corev1.ProtocolTCP, Port: 2802, TargetPort: intstr.FromString("http")},
 	},
+	// Falcosidekick forwards the alerts to the http port, and the UI only reaches Redis.
+	NetworkPolicy: &NetworkPolicyDefaults{
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(intstr.FromString("http"), corev1.ProtocolTCP)},
+			From:  []networkingv1.NetworkPolicyPeer{instanceTypePeer(FalcosidekickTypeName)},
+		}},
+		Egress: []networkingv1.NetworkPolicyEgressRule{
+			{Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(intstr.FromInt32(defaultRedisPort), corev1.ProtocolTCP)}},
+			dnsEgressRule(),
+		},
+	},
 	SupportsDaemonSet: false,
 	DeploymentStrategy: &appsv1.DeploymentStrategy{
 		Type: appsv1.RollingUpdateDeploymentStrategyType,
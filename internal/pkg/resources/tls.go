@@ -0,0 +1,188 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/falcosecurity/falco-operator/internal/pkg/pki"
+)
+
+const (
+	// CASecretName is the name of the Secret holding the CA the operator signs the certificates of the
+	// instances of its namespace with.
+	CASecretName = "falco-operator-ca"
+	// CACertKey is the key of the trusted CA certificates in the certificate Secrets, and of the certificate
+	// of the CA in the CA Secret.
+	CACertKey = "ca.crt"
+	// CAKeyKey is the key of the private key of the CA in the CA Secret.
+	CAKeyKey = "ca.key"
+	// PreviousCACertKey is the key of the certificate of the previous CA in the CA Secret. The certificates
+	// it signed stay trusted until it expires.
+	PreviousCACertKey = "ca-previous.crt"
+	// TLSCombinedKey is the key of the certificate followed by its private key in the certificate Secrets.
+	TLSCombinedKey = "tls-combined.pem"
+
+	// CertificateSerialAnnotation is the pod template annotation carrying the serial number of the certificate
+	// of the instance. A renewal rolls the workload pods, so that they load the new certificate.
+	CertificateSerialAnnotation = "instance.falcosecurity.dev/certificate-serial"
+
+	// certificatesVolumeName is the name of the volume of the certificate Secret.
+	certificatesVolumeName = "tls-certificates"
+)
+
+// CertificateSecretName returns the name of the Secret holding the certificate of the instance with the given name.
+func CertificateSecretName(name string) string {
+	return name + "-tls"
+}
+
+// CertificateDNSNames returns the DNS names of the Service of the given object, the certificate is issued for.
+func CertificateDNSNames(obj client.Object) []string {
+	name, namespace := obj.GetName(), obj.GetNamespace()
+	return []string{name, name + "." + namespace, name + "." + namespace + ".svc"}
+}
+
+// GenerateCASecret generates the CA Secret of the given namespace. The certificate of the previous CA is kept when
+// not nil. The Secret is shared by the instances of the namespace, so it has no owner.
+func GenerateCASecret(namespace string, ca pki.KeyPair, previousCertPEM []byte) *corev1.Secret {
+	data := map[string][]byte{CACertKey: ca.CertPEM, CAKeyKey: ca.KeyPEM}
+	if previousCertPEM != nil {
+		data[PreviousCACertKey] = previousCertPEM
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CASecretName,
+			Namespace: namespace,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": operatorName},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// GenerateCertificateSecret generates the Secret holding the certificate of the given object, with the bundle of
+// the CA certificates its peers are trusted with.
+func GenerateCertificateSecret(obj client.Object, caBundle []byte, pair pki.KeyPair) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CertificateSecretName(obj.GetName()),
+			Namespace: obj.GetNamespace(),
+			Labels:    obj.GetLabels(),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			CACertKey:               caBundle,
+			corev1.TLSCertKey:       pair.CertPEM,
+			corev1.TLSPrivateKeyKey: pair.KeyPEM,
+			TLSCombinedKey:          slices.Concat(pair.CertPEM, pair.KeyPEM),
+		},
+	}
+}
+
+// EnableTLS switches the main container of the given workload to TLS as described by the TLS defaults: the
+// certificate Secret of the instance with the given name is mounted, and the environment, ports and probes are
+// set. Nothing is changed when the instance type does not support TLS.
+func EnableTLS(workload runtime.Object, name string, defs *InstanceDefaults) {
+	tls := defs.TLS
+	if tls == nil {
+		return
+	}
+	var podSpec *corev1.PodSpec
+	switch w := workload.(type) {
+	case *appsv1.DaemonSet:
+		podSpec = &w.Spec.Template.Spec
+	case *appsv1.Deployment:
+		podSpec = &w.Spec.Template.Spec
+	default:
+		return
+	}
+
+	podSpec.Volumes = append(slices.Clip(podSpec.Volumes), corev1.Volume{
+		Name: certificatesVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: CertificateSecretName(name)},
+		},
+	})
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name != defs.ContainerName {
+			continue
+		}
+		container.VolumeMounts = append(slices.Clip(container.VolumeMounts), corev1.VolumeMount{
+			Name: certificatesVolumeName, MountPath: tls.MountPath, ReadOnly: true,
+		})
+		container.Env = append(slices.Clip(container.Env), tls.EnvVars...)
+		container.Ports = append(slices.Clip(container.Ports), tls.Ports...)
+		for _, probe := range []**corev1.Probe{&container.StartupProbe, &container.LivenessProbe, &container.ReadinessProbe} {
+			if *probe == nil || (*probe).HTTPGet == nil {
+				continue
+			}
+			// The probes are shared with the defaults: replace them instead of changing them.
+			*probe = (*probe).DeepCopy()
+			if tls.ProbeScheme != "" {
+				(*probe).HTTPGet.Scheme = tls.ProbeScheme
+			}
+			if tls.ProbePort != nil {
+				(*probe).HTTPGet.Port = *tls.ProbePort
+			}
+		}
+	}
+}
+
+// EnableMonitorTLS makes the endpoints of the given ServiceMonitor or PodMonitor scraping the given port of the
+// instance use TLS, with the certificate of the instance to authenticate. Nothing is changed for a nil monitor.
+func EnableMonitorTLS(monitor *unstructured.Unstructured, obj client.Object, port string) error {
+	if monitor == nil {
+		return nil
+	}
+	field := "endpoints"
+	if monitor.GroupVersionKind() == PodMonitorGVK {
+		field = "podMetricsEndpoints"
+	}
+	endpoints, _, err := unstructured.NestedSlice(monitor.Object, "spec", field)
+	if err != nil {
+		return err
+	}
+
+	secretName := CertificateSecretName(obj.GetName())
+	secretKey := func(key string) map[string]any {
+		return map[string]any{"name": secretName, "key": key}
+	}
+	for i := range endpoints {
+		endpoint, ok := endpoints[i].(map[string]any)
+		if !ok || endpoint["port"] != port {
+			continue
+		}
+		endpoint["scheme"] = "https"
+		endpoint["tlsConfig"] = map[string]any{
+			"ca":         map[string]any{"secret": secretKey(CACertKey)},
+			"cert":       map[string]any{"secret": secretKey(corev1.TLSCertKey)},
+			"keySecret":  secretKey(corev1.TLSPrivateKeyKey),
+			"serverName": obj.GetName() + "." + obj.GetNamespace() + ".svc",
+		}
+	}
+	return unstructured.SetNestedSlice(monitor.Object, endpoints, "spec", field)
+}
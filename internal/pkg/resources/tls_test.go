@@ -0,0 +1,147 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/pki"
+)
+
+func TestGenerateCertificateSecret(t *testing.T) {
+	obj := &metav1.ObjectMeta{Name: "sidekick", Namespace: "falco", Labels: map[string]string{"team": "secops"}}
+	pair := pki.KeyPair{CertPEM: []byte("cert\n"), KeyPEM: []byte("key\n")}
+
+	secret := GenerateCertificateSecret(&corev1.Secret{ObjectMeta: *obj}, []byte("ca\n"), pair)
+
+	assert.Equal(t, "sidekick-tls", secret.Name)
+	assert.Equal(t, "falco", secret.Namespace)
+	assert.Equal(t, obj.Labels, secret.Labels)
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	assert.Equal(t, map[string][]byte{
+		CACertKey:               []byte("ca\n"),
+		corev1.TLSCertKey:       []byte("cert\n"),
+		corev1.TLSPrivateKeyKey: []byte("key\n"),
+		TLSCombinedKey:          []byte("cert\nkey\n"),
+	}, secret.Data)
+}
+
+func TestGenerateCASecret(t *testing.T) {
+	ca := pki.KeyPair{CertPEM: []byte("cert"), KeyPEM: []byte("key")}
+
+	secret := GenerateCASecret("falco", ca, nil)
+	assert.Equal(t, CASecretName, secret.Name)
+	assert.Equal(t, "falco", secret.Namespace)
+	assert.Empty(t, secret.OwnerReferences)
+	assert.Equal(t, map[string][]byte{CACertKey: []byte("cert"), CAKeyKey: []byte("key")}, secret.Data)
+
+	secret = GenerateCASecret("falco", ca, []byte("previous"))
+	assert.Equal(t, []byte("previous"), secret.Data[PreviousCACertKey])
+}
+
+func TestEnableTLS(t *testing.T) {
+	t.Run("falco serves TLS to the probes", func(t *testing.T) {
+		workload, err := GenerateWorkload(ResourceTypeDaemonSet, &metav1.ObjectMeta{Name: "falco"}, FalcoDefaults, false)
+		require.NoError(t, err)
+		EnableTLS(workload, "falco", FalcoDefaults)
+
+		podSpec := workload.(*appsv1.DaemonSet).Spec.Template.Spec
+		assert.Contains(t, podSpec.Volumes, corev1.Volume{
+			Name:         certificatesVolumeName,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "falco-tls"}},
+		})
+		main := findContainer(t, podSpec.Containers, FalcoDefaults.ContainerName)
+		assert.Contains(t, main.VolumeMounts, corev1.VolumeMount{Name: certificatesVolumeName, MountPath: "/etc/falco/certs", ReadOnly: true})
+		for _, probe := range []*corev1.Probe{main.StartupProbe, main.LivenessProbe, main.ReadinessProbe} {
+			assert.Equal(t, corev1.URISchemeHTTPS, probe.HTTPGet.Scheme)
+		}
+		assert.Empty(t, FalcoDefaults.LivenessProbe.HTTPGet.Scheme, "the default probes must be left unchanged")
+
+		sidecar := findContainer(t, podSpec.Containers, FalcoDefaults.SidecarContainerName)
+		assert.NotContains(t, sidecar.VolumeMounts, corev1.VolumeMount{Name: certificatesVolumeName, MountPath: "/etc/falco/certs", ReadOnly: true})
+	})
+
+	t.Run("falcosidekick probes the plain port", func(t *testing.T) {
+		workload, err := GenerateWorkload(ResourceTypeDeployment, &metav1.ObjectMeta{Name: "sidekick"}, FalcosidekickDefaults, false)
+		require.NoError(t, err)
+		EnableTLS(workload, "sidekick", FalcosidekickDefaults)
+
+		main := findContainer(t, workload.(*appsv1.Deployment).Spec.Template.Spec.Containers, FalcosidekickDefaults.ContainerName)
+		assert.Contains(t, main.Env, corev1.EnvVar{Name: "TLSSERVER_MUTUALTLS", Value: "true"})
+		assert.Contains(t, main.Ports, corev1.ContainerPort{ContainerPort: 2810, Name: "http-notls", Protocol: corev1.ProtocolTCP})
+		assert.Equal(t, intstr.FromString("http-notls"), main.ReadinessProbe.HTTPGet.Port)
+		assert.Equal(t, intstr.FromString("http-notls"), main.LivenessProbe.HTTPGet.Port)
+		assert.Equal(t, intstr.FromString("http"), FalcosidekickDefaults.LivenessProbe.HTTPGet.Port)
+	})
+
+	t.Run("instance type without TLS", func(t *testing.T) {
+		workload, err := GenerateWorkload(ResourceTypeDeployment, &metav1.ObjectMeta{Name: "ui"}, FalcosidekickUIDefaults, false)
+		require.NoError(t, err)
+		EnableTLS(workload, "ui", FalcosidekickUIDefaults)
+		assert.Len(t, workload.(*appsv1.Deployment).Spec.Template.Spec.Volumes, len(FalcosidekickUIDefaults.Volumes))
+	})
+}
+
+// findContainer returns the container with the given name.
+func findContainer(t *testing.T, containers []corev1.Container, name string) corev1.Container {
+	t.Helper()
+	for _, c := range containers {
+		if c.Name == name {
+			return c
+		}
+	}
+	require.Failf(t, "container not found", "container %q", name)
+	return corev1.Container{}
+}
+
+func TestEnableMonitorTLS(t *testing.T) {
+	obj := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: "ns"}}
+	spec := &commonv1alpha1.MonitorSpec{Enabled: true}
+
+	monitor := GeneratePodMonitor(obj, FalcoDefaults, spec, ArtifactOperatorMetricsPort)
+	require.NoError(t, EnableMonitorTLS(monitor, obj, FalcoDefaults.MetricsPort))
+
+	endpoints, _, err := unstructured.NestedSlice(monitor.Object, "spec", "podMetricsEndpoints")
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	web := endpoints[0].(map[string]any)
+	assert.Equal(t, "https", web["scheme"])
+	assert.Equal(t, map[string]any{
+		"ca":         map[string]any{"secret": map[string]any{"name": "falco-tls", "key": CACertKey}},
+		"cert":       map[string]any{"secret": map[string]any{"name": "falco-tls", "key": corev1.TLSCertKey}},
+		"keySecret":  map[string]any{"name": "falco-tls", "key": corev1.TLSPrivateKeyKey},
+		"serverName": "falco.ns.svc",
+	}, web["tlsConfig"])
+	assert.NotContains(t, endpoints[1], "scheme", "the sidecar metrics are served over HTTP")
+
+	monitor = GenerateServiceMonitor(obj, FalcoDefaults, spec)
+	require.NoError(t, EnableMonitorTLS(monitor, obj, FalcoDefaults.MetricsPort))
+	endpoints, _, err = unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+	require.NoError(t, err)
+	assert.Equal(t, "https", endpoints[0].(map[string]any)["scheme"])
+
+	assert.NoError(t, EnableMonitorTLS(nil, obj, FalcoDefaults.MetricsPort))
+}
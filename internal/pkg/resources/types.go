@@ -19,7 +19,9 @@ package resources
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -27,6 +29,18 @@ const (
 	ResourceTypeDeployment string = "Deployment"
 	// ResourceTypeDaemonSet is the resource type for DaemonSet.
 	ResourceTypeDaemonSet string = "DaemonSet"
+
+	// ConfigHashAnnotation is the pod template annotation carrying the hash of the generated
+	// instance configuration. A change of its value rolls the workload pods.
+	ConfigHashAnnotation = "instance.falcosecurity.dev/config-hash"
+
+	// NodePoolLabel is the label carrying the node pool name on the DaemonSets, pods and ConfigMaps
+	// generated for the node pools of an instance.
+	NodePoolLabel = "instance.falcosecurity.dev/node-pool"
+
+	// InstanceTypeLabel is the label carrying the instance type name on the generated pods, so that
+	// the NetworkPolicies of the other instances can select them as peers.
+	InstanceTypeLabel = "instance.falcosecurity.dev/type"
 )
 
 // ConfigMapVolumeConfig describes how to mount the instance's ConfigMap as a volume.
@@ -37,9 +51,38 @@ type ConfigMapVolumeConfig struct {
 	SubPath    string
 }
 
+// NetworkPolicyDefaults describes the traffic allowed by the NetworkPolicy of an instance type.
+type NetworkPolicyDefaults struct {
+	// Ingress lets the known peers reach the ports of the instance.
+	Ingress []networkingv1.NetworkPolicyIngressRule
+	// Egress lets the instance reach its known peers (nil = egress not restricted). It is left nil for the
+	// instances reaching peers that cannot be listed, such as the API server, registries or outputs.
+	Egress []networkingv1.NetworkPolicyEgressRule
+}
+
+// TLSDefaults describes how an instance type is switched to TLS with the certificate issued by the operator.
+// The certificate Secret is mounted in the main container with the keys of GenerateCertificateSecret.
+type TLSDefaults struct {
+	// MountPath is the directory the certificate Secret is mounted at.
+	MountPath string
+	// EnvVars are added to the main container.
+	EnvVars []corev1.EnvVar
+	// Ports are added to the main container, e.g. a plain HTTP port kept for the probes.
+	Ports []corev1.ContainerPort
+	// ProbeScheme is set on the HTTP probes of the main container ("" = scheme kept).
+	ProbeScheme corev1.URIScheme
+	// ProbePort replaces the port of the HTTP probes of the main container (nil = port kept).
+	ProbePort *intstr.IntOrString
+	// Config is merged on top of the configuration file of the ConfigMap ("" = none).
+	Config string
+}
+
 // InstanceDefaults defines all the default configuration for an instance controller.
 // Each instance type (falco, metacollector, etc.) registers its own defaults.
 type InstanceDefaults struct {
+	// TypeName is the instance type name, set on the pods with the InstanceTypeLabel.
+	TypeName string
+
 	// ResourceType is the default workload kind ("Deployment" or "DaemonSet").
 	ResourceType string
 
@@ -69,6 +112,17 @@ type InstanceDefaults struct {
 	// Service (nil = no Service created)
 	ServicePorts []corev1.ServicePort
 
+	// MetricsPort is the name of the container and Service port serving the Prometheus metrics
+	// on /metrics ("" = no metrics).
+	MetricsPort string
+
+	// NetworkPolicy describes the traffic of the instance with its known peers
+	// (nil = no NetworkPolicy generated).
+	NetworkPolicy *NetworkPolicyDefaults
+
+	// TLS describes how the instance serves TLS (nil = TLS not supported).
+	TLS *TLSDefaults
+
 	// RBAC (nil = no resource created)
 	ClusterRoleRules []rbacv1.PolicyRule
 	RoleRules        []rbacv1.PolicyRule
@@ -25,11 +25,15 @@ import (
 	"sync"
 	"sync/atomic"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/controllerhelper"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
 )
 
 const (
@@ -42,6 +46,9 @@ const (
 type Recorder interface {
 	MarkReconciled(kind, namespace, name string, generation int64)
 	Forget(kind, namespace, name string)
+	// Ready reports whether the startup snapshot has been fully reconciled. Changes written
+	// before that point are loaded by Falco when it starts.
+	Ready() bool
 }
 
 // Gate tracks node-applicable artifact CRs and reports readiness when each has
@@ -50,28 +57,33 @@ type Gate struct {
 	client    client.Client
 	nodeName  string
 	namespace string
+	// instanceName is the name of the Falco instance whose pods run the artifact operator.
+	instanceName string
 
 	cacheSynced atomic.Bool
 
-	labelsMu   sync.RWMutex
-	nodeLabels labels.Set
+	labelsMu       sync.RWMutex
+	nodeLabels     labels.Set
+	instanceFound  bool
+	instanceLabels labels.Set
 
 	mu        sync.Mutex
 	expected  map[string]int64
 	processed map[string]int64
 }
 
-func NewGate(cl client.Client, nodeName, namespace string) *Gate {
+func NewGate(cl client.Client, nodeName, namespace, instanceName string) *Gate {
 	return &Gate{
-		client:    cl,
-		nodeName:  nodeName,
-		namespace: namespace,
-		expected:  make(map[string]int64),
-		processed: make(map[string]int64),
+		client:       cl,
+		nodeName:     nodeName,
+		namespace:    namespace,
+		instanceName: instanceName,
+		expected:     make(map[string]int64),
+		processed:    make(map[string]int64),
 	}
 }
 
-// MarkCacheSynced caches the node labels and builds the startup snapshot.
+// MarkCacheSynced caches the node and Falco instance labels and builds the startup snapshot.
 func (g *Gate) MarkCacheSynced(ctx context.Context) error {
 	node := &metav1.PartialObjectMetadata{
 		TypeMeta: metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
@@ -80,8 +92,20 @@ func (g *Gate) MarkCacheSynced(ctx context.Context) error {
 		return fmt.Errorf("fetching node %q: %w", g.nodeName, err)
 	}
 
+	falco := &instancev1alpha1.Falco{}
+	instanceFound := false
+	if g.instanceName != "" {
+		err := g.client.Get(ctx, client.ObjectKey{Namespace: g.namespace, Name: g.instanceName}, falco)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("fetching Falco %q: %w", g.instanceName, err)
+		}
+		instanceFound = err == nil
+	}
+
 	g.labelsMu.Lock()
 	g.nodeLabels = labels.Set(node.Labels)
+	g.instanceFound = instanceFound
+	g.instanceLabels = labels.Set(falco.Labels)
 	g.labelsMu.Unlock()
 
 	if err := g.snapshot(ctx); err != nil {
@@ -92,6 +116,8 @@ func (g *Gate) MarkCacheSynced(ctx context.Context) error {
 	return nil
 }
 
+// snapshot records the artifact CRs applicable to the node and the Falco instance to wait for. Suspended CRs
+// are skipped since their files are not written until they are resumed.
 func (g *Gate) snapshot(ctx context.Context) error {
 	pluginList := &artifactv1alpha1.PluginList{}
 	if err := g.client.List(ctx, pluginList, client.InNamespace(g.namespace)); err != nil {
@@ -110,22 +136,29 @@ func (g *Gate) snapshot(ctx context.Context) error {
 	defer g.mu.Unlock()
 	for i := range pluginList.Items {
 		p := &pluginList.Items[i]
-		if g.nodeMatches(p.Spec.Selector) {
+		if !p.Spec.Suspend && g.matches(controllerhelper.Target{
+			NodeSelector: p.Spec.Selector, FalcoRef: p.Spec.FalcoRef, InstanceSelector: p.Spec.InstanceSelector,
+		}) {
 			g.expected[key(KindPlugin, p.Namespace, p.Name)] = p.Generation
 		}
 	}
 	for i := range rulesfileList.Items {
 		r := &rulesfileList.Items[i]
-		if g.nodeMatches(r.Spec.Selector) {
+		if !r.Spec.Suspend && g.matches(controllerhelper.Target{
+			NodeSelector: r.Spec.Selector, FalcoRef: r.Spec.FalcoRef, InstanceSelector: r.Spec.InstanceSelector,
+		}) {
 			g.expected[key(KindRulesfile, r.Namespace, r.Name)] = r.Generation
 		}
 	}
 	for i := range configList.Items {
 		c := &configList.Items[i]
-		if g.nodeMatches(c.Spec.Selector) {
+		if !c.Spec.Suspend && g.matches(controllerhelper.Target{
+			NodeSelector: c.Spec.Selector, FalcoRef: c.Spec.FalcoRef, InstanceSelector: c.Spec.InstanceSelector,
+		}) {
 			g.expected[key(KindConfig, c.Namespace, c.Name)] = c.Generation
 		}
 	}
+	g.recordPending()
 	return nil
 }
 
@@ -138,6 +171,7 @@ func (g *Gate) MarkReconciled(kind, namespace, name string, generation int64) {
 		return
 	}
 	g.processed[k] = generation
+	g.recordPending()
 }
 
 // Forget drops a CR from both the snapshot and the processed set.
@@ -147,6 +181,7 @@ func (g *Gate) Forget(kind, namespace, name string) {
 	k := key(kind, namespace, name)
 	delete(g.expected, k)
 	delete(g.processed, k)
+	g.recordPending()
 }
 
 // Check returns nil when every snapshotted CR has been processed.
@@ -155,35 +190,67 @@ func (g *Gate) Check(_ *http.Request) error {
 		return fmt.Errorf("artifact-operator cache not yet synced")
 	}
 
+	pending := g.pending()
+	if len(pending) == 0 {
+		return nil
+	}
+	sort.Strings(pending)
+	return fmt.Errorf("waiting for first reconcile of: %s", strings.Join(pending, ", "))
+}
+
+// Ready reports whether the cache is synced and every snapshotted CR has been processed.
+func (g *Gate) Ready() bool {
+	return g.cacheSynced.Load() && len(g.pending()) == 0
+}
+
+// pending returns the keys of the snapshotted CRs not yet processed at their expected generation.
+func (g *Gate) pending() []string {
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	return g.pendingLocked()
+}
 
+// pendingLocked is pending for callers holding g.mu.
+func (g *Gate) pendingLocked() []string {
 	var pending []string
 	for k, want := range g.expected {
 		if got, ok := g.processed[k]; !ok || got < want {
 			pending = append(pending, k)
 		}
 	}
-	if len(pending) == 0 {
-		return nil
-	}
-	sort.Strings(pending)
-	return fmt.Errorf("waiting for first reconcile of: %s", strings.Join(pending, ", "))
+	return pending
 }
 
-func (g *Gate) nodeMatches(labelSelector *metav1.LabelSelector) bool {
-	if labelSelector == nil {
+// matches reports whether an artifact with the given target applies to the node and the Falco instance. An artifact
+// restricted to some Falco instances matches no unknown or missing instance.
+func (g *Gate) matches(target controllerhelper.Target) bool {
+	g.labelsMu.RLock()
+	defer g.labelsMu.RUnlock()
+	if target.TargetsInstances() && (!g.instanceFound || !target.InstanceTargeted(g.instanceName, g.instanceLabels)) {
+		return false
+	}
+	if target.NodeSelector == nil {
 		return true
 	}
-	sel, err := metav1.LabelSelectorAsSelector(labelSelector)
+	sel, err := metav1.LabelSelectorAsSelector(target.NodeSelector)
 	if err != nil {
 		return false
 	}
-	g.labelsMu.RLock()
-	defer g.labelsMu.RUnlock()
 	return sel.Matches(g.nodeLabels)
 }
 
+// recordPending publishes the number of pending CRs by kind. Callers must hold g.mu.
+func (g *Gate) recordPending() {
+	counts := map[string]int{KindPlugin: 0, KindRulesfile: 0, KindConfig: 0}
+	for _, k := range g.pendingLocked() {
+		kind, _, _ := strings.Cut(k, "/")
+		counts[kind]++
+	}
+	for kind, n := range counts {
+		metrics.StartupGatePending.WithLabelValues(kind).Set(float64(n))
+	}
+}
+
 func key(kind, namespace, name string) string {
 	return kind + "/" + namespace + "/" + name
 }
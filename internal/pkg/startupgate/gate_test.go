@@ -23,6 +23,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -34,11 +35,14 @@ import (
 
 	artifactv1alpha1 "github.com/falcosecurity/falco-operator/api/artifact/v1alpha1"
 	commonv1alpha1 "github.com/falcosecurity/falco-operator/api/common/v1alpha1"
+	instancev1alpha1 "github.com/falcosecurity/falco-operator/api/instance/v1alpha1"
+	"github.com/falcosecurity/falco-operator/internal/pkg/metrics"
 )
 
 const (
-	testNodeName  = "node-1"
-	testNamespace = "falco"
+	testNodeName     = "node-1"
+	testNamespace    = "falco"
+	testInstanceName = "syscall"
 )
 
 var testNodeLabels = map[string]string{"role": "worker"}
@@ -50,6 +54,9 @@ type artifactOpts struct {
 	progStatus metav1.ConditionStatus
 	obsGen     int64
 	selector   *metav1.LabelSelector
+	suspend    bool
+	falcoRef   *commonv1alpha1.FalcoRef
+	instSel    *metav1.LabelSelector
 }
 
 func newScheme(t *testing.T) *runtime.Scheme {
@@ -57,6 +64,7 @@ func newScheme(t *testing.T) *runtime.Scheme {
 	s := runtime.NewScheme()
 	require.NoError(t, corev1.AddToScheme(s))
 	require.NoError(t, artifactv1alpha1.AddToScheme(s))
+	require.NoError(t, instancev1alpha1.AddToScheme(s))
 	return s
 }
 
@@ -83,24 +91,30 @@ func buildConditions(opts artifactOpts) []metav1.Condition {
 func newPlugin(opts artifactOpts) *artifactv1alpha1.Plugin {
 	return &artifactv1alpha1.Plugin{
 		ObjectMeta: metav1.ObjectMeta{Name: opts.name, Namespace: testNamespace, Generation: opts.generation},
-		Spec:       artifactv1alpha1.PluginSpec{Selector: opts.selector},
-		Status:     artifactv1alpha1.PluginStatus{Conditions: buildConditions(opts)},
+		Spec: artifactv1alpha1.PluginSpec{
+			Selector: opts.selector, Suspend: opts.suspend, FalcoRef: opts.falcoRef, InstanceSelector: opts.instSel,
+		},
+		Status: artifactv1alpha1.PluginStatus{Conditions: buildConditions(opts)},
 	}
 }
 
 func newRulesfile(opts artifactOpts) *artifactv1alpha1.Rulesfile {
 	return &artifactv1alpha1.Rulesfile{
 		ObjectMeta: metav1.ObjectMeta{Name: opts.name, Namespace: testNamespace, Generation: opts.generation},
-		Spec:       artifactv1alpha1.RulesfileSpec{Selector: opts.selector},
-		Status:     artifactv1alpha1.RulesfileStatus{Conditions: buildConditions(opts)},
+		Spec: artifactv1alpha1.RulesfileSpec{
+			Selector: opts.selector, Suspend: opts.suspend, FalcoRef: opts.falcoRef, InstanceSelector: opts.instSel,
+		},
+		Status: artifactv1alpha1.RulesfileStatus{Conditions: buildConditions(opts)},
 	}
 }
 
 func newConfig(opts artifactOpts) *artifactv1alpha1.Config {
 	return &artifactv1alpha1.Config{
 		ObjectMeta: metav1.ObjectMeta{Name: opts.name, Namespace: testNamespace, Generation: opts.generation},
-		Spec:       artifactv1alpha1.ConfigSpec{Selector: opts.selector},
-		Status:     artifactv1alpha1.ConfigStatus{Conditions: buildConditions(opts)},
+		Spec: artifactv1alpha1.ConfigSpec{
+			Selector: opts.selector, Suspend: opts.suspend, FalcoRef: opts.falcoRef, InstanceSelector: opts.instSel,
+		},
+		Status: artifactv1alpha1.ConfigStatus{Conditions: buildConditions(opts)},
 	}
 }
 
@@ -113,7 +127,7 @@ func newGateReady(t *testing.T, objects ...client.Object) *Gate {
 	s := newScheme(t)
 	all := append([]client.Object{newNode()}, objects...)
 	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(all...).Build()
-	g := NewGate(cl, testNodeName, testNamespace)
+	g := NewGate(cl, testNodeName, testNamespace, testInstanceName)
 	require.NoError(t, g.MarkCacheSynced(context.Background()))
 	return g
 }
@@ -161,6 +175,41 @@ func TestGate_MarkCacheSynced(t *testing.T) {
 			},
 			wantExpectedKeys: []string{"Rulesfile/falco/r1"},
 		},
+		{
+			name: "skips suspended CRs",
+			objects: []client.Object{
+				newPlugin(artifactOpts{name: "p1", generation: 1, suspend: true}),
+				newRulesfile(artifactOpts{name: "r1", generation: 1, suspend: true}),
+				newConfig(artifactOpts{name: "c1", generation: 1, suspend: true}),
+				newConfig(artifactOpts{name: "c2", generation: 1}),
+			},
+			wantExpectedKeys: []string{"Config/falco/c2"},
+		},
+		{
+			name: "snapshots CRs targeting the Falco instance",
+			objects: []client.Object{
+				&instancev1alpha1.Falco{ObjectMeta: metav1.ObjectMeta{
+					Name: testInstanceName, Namespace: testNamespace, Labels: map[string]string{"source": "syscall"},
+				}},
+				newPlugin(artifactOpts{name: "p1", generation: 1, falcoRef: &commonv1alpha1.FalcoRef{Name: testInstanceName}}),
+				newPlugin(artifactOpts{name: "p2", generation: 1, falcoRef: &commonv1alpha1.FalcoRef{Name: "k8saudit"}}),
+				newRulesfile(artifactOpts{name: "r1", generation: 1,
+					instSel: &metav1.LabelSelector{MatchLabels: map[string]string{"source": "syscall"}}}),
+				newRulesfile(artifactOpts{name: "r2", generation: 1,
+					instSel: &metav1.LabelSelector{MatchLabels: map[string]string{"source": "k8saudit"}}}),
+				newConfig(artifactOpts{name: "c1", generation: 1}),
+			},
+			wantExpectedKeys: []string{"Config/falco/c1", "Plugin/falco/p1", "Rulesfile/falco/r1"},
+		},
+		{
+			name: "skips CRs targeting instances when the Falco instance is missing",
+			objects: []client.Object{
+				newPlugin(artifactOpts{name: "p1", generation: 1, falcoRef: &commonv1alpha1.FalcoRef{Name: testInstanceName}}),
+				newRulesfile(artifactOpts{name: "r1", generation: 1, instSel: &metav1.LabelSelector{}}),
+				newConfig(artifactOpts{name: "c1", generation: 1}),
+			},
+			wantExpectedKeys: []string{"Config/falco/c1"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -172,7 +221,7 @@ func TestGate_MarkCacheSynced(t *testing.T) {
 			}
 			builder = builder.WithObjects(tt.objects...)
 			cl := builder.Build()
-			g := NewGate(cl, testNodeName, testNamespace)
+			g := NewGate(cl, testNodeName, testNamespace, testInstanceName)
 
 			err := g.MarkCacheSynced(context.Background())
 			if tt.wantErr {
@@ -296,7 +345,7 @@ func TestGate_Check(t *testing.T) {
 			s := newScheme(t)
 			objects := append([]client.Object{newNode()}, tt.snapshot...)
 			cl := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build()
-			g := NewGate(cl, testNodeName, testNamespace)
+			g := NewGate(cl, testNodeName, testNamespace, testInstanceName)
 
 			if !tt.skipMarkSynced {
 				require.NoError(t, g.MarkCacheSynced(context.Background()))
@@ -362,7 +411,7 @@ func TestGate_MarkCacheSynced_ListErrors(t *testing.T) {
 				WithInterceptorFuncs(intercept).
 				Build()
 
-			g := NewGate(cl, testNodeName, testNamespace)
+			g := NewGate(cl, testNodeName, testNamespace, testInstanceName)
 			err := g.MarkCacheSynced(context.Background())
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), tt.wantErrText)
@@ -382,6 +431,41 @@ func TestGate_MarkReconciled_MonotonicOnGeneration(t *testing.T) {
 	assert.Equal(t, int64(7), g.processed[key(KindPlugin, testNamespace, "p1")])
 }
 
+func TestGate_Ready(t *testing.T) {
+	s := newScheme(t)
+	cl := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(newNode(), newPlugin(artifactOpts{name: "p1", generation: 1})).Build()
+	g := NewGate(cl, testNodeName, testNamespace, testInstanceName)
+
+	assert.False(t, g.Ready(), "gate must not be ready before the cache is synced")
+
+	require.NoError(t, g.MarkCacheSynced(context.Background()))
+	assert.False(t, g.Ready(), "gate must not be ready while a snapshotted CR is pending")
+
+	g.MarkReconciled(KindPlugin, testNamespace, "p1", 1)
+	assert.True(t, g.Ready())
+}
+
+func TestGate_PendingMetric(t *testing.T) {
+	g := newGateReady(t,
+		newPlugin(artifactOpts{name: "p1", generation: 1}),
+		newRulesfile(artifactOpts{name: "r1", generation: 1}),
+		newRulesfile(artifactOpts{name: "r2", generation: 1}),
+	)
+	pending := func(kind string) float64 {
+		return testutil.ToFloat64(metrics.StartupGatePending.WithLabelValues(kind))
+	}
+
+	assert.Equal(t, float64(1), pending(KindPlugin))
+	assert.Equal(t, float64(2), pending(KindRulesfile))
+	assert.Equal(t, float64(0), pending(KindConfig))
+
+	g.MarkReconciled(KindPlugin, testNamespace, "p1", 1)
+	g.Forget(KindRulesfile, testNamespace, "r1")
+	assert.Equal(t, float64(0), pending(KindPlugin))
+	assert.Equal(t, float64(1), pending(KindRulesfile))
+}
+
 type mark struct {
 	kind, namespace, name string
 	generation            int64
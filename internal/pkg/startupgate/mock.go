@@ -21,11 +21,14 @@ type NoopGateRecorder struct{}
 
 func (NoopGateRecorder) MarkReconciled(string, string, string, int64) {}
 func (NoopGateRecorder) Forget(string, string, string)                {}
+func (NoopGateRecorder) Ready() bool                                  { return false }
 
 // FakeGateRecorder captures Recorder calls.
 type FakeGateRecorder struct {
 	Reconciled []FakeGateCall
 	Forgotten  []FakeGateCall
+	// IsReady is returned by Ready.
+	IsReady bool
 }
 
 type FakeGateCall struct {
@@ -40,3 +43,7 @@ func (r *FakeGateRecorder) MarkReconciled(kind, namespace, name string, generati
 func (r *FakeGateRecorder) Forget(kind, namespace, name string) {
 	r.Forgotten = append(r.Forgotten, FakeGateCall{Kind: kind, Namespace: namespace, Name: name})
 }
+
+func (r *FakeGateRecorder) Ready() bool {
+	return r.IsReady
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing provides the optional OpenTelemetry tracing shared by the instance operator,
+// the artifact aggregators and the artifact operator sidecars. Spans are exported over OTLP/gRPC
+// when an endpoint is configured; otherwise the global no-op tracer provider is kept and every
+// helper in this package is effectively free.
+package tracing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// TracerName is the instrumentation scope of every span emitted by the operators.
+	TracerName = "github.com/falcosecurity/falco-operator"
+
+	// AnnotationTraceParent is the annotation key carrying the W3C traceparent of the span that
+	// last acted on an object, so that the controller picking the object up can continue the trace.
+	AnnotationTraceParent = "tracing.falcosecurity.dev/traceparent"
+	// AnnotationTraceState is the annotation key carrying the W3C tracestate companion of
+	// AnnotationTraceParent, when the producing span has one.
+	AnnotationTraceState = "tracing.falcosecurity.dev/tracestate"
+)
+
+// Options configures the OTLP trace exporter.
+type Options struct {
+	// Endpoint is the host:port of the OTLP/gRPC collector. Tracing is disabled when empty.
+	Endpoint string
+	// Insecure disables TLS towards the collector.
+	Insecure bool
+	// SampleRatio is the fraction of root traces to sample, between 0 and 1. Traces continued
+	// from a remote parent follow the parent's sampling decision.
+	SampleRatio float64
+}
+
+// BindFlags registers the tracing flags on fs.
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Endpoint, "tracing-endpoint", "",
+		"The host:port of the OTLP/gRPC collector spans are exported to. Tracing is disabled when empty. "+
+			"The standard OTEL_EXPORTER_OTLP_* environment variables are honored for the remaining exporter settings.")
+	fs.BoolVar(&o.Insecure, "tracing-insecure", false,
+		"If set, spans are exported to the OTLP collector without TLS.")
+	fs.Float64Var(&o.SampleRatio, "tracing-sample-ratio", 1,
+		"The fraction of traces to sample, between 0 and 1. Traces continued from another component "+
+			"follow its sampling decision.")
+}
+
+// Enabled reports whether the options configure an exporter.
+func (o Options) Enabled() bool {
+	return o.Endpoint != ""
+}
+
+// Setup installs the global tracer provider and the W3C trace context propagator for the given
+// service. It returns a function flushing and shutting down the exporter, to be called on exit.
+// When tracing is disabled it installs nothing and returns a no-op shutdown function.
+func Setup(ctx context.Context, serviceName string, opts Options) (func(context.Context) error, error) {
+	if !opts.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("trace sample ratio %v out of range [0, 1]", opts.SampleRatio)
+	}
+
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span in ctx, using the global tracer provider.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartLinked starts a span named name as a child of the span in ctx, linked to the span carried
+// by link. It is used to continue a remote trace without losing track of the local one.
+func StartLinked(ctx, link context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...), trace.WithLinks(trace.LinkFromContext(link)))
+}
+
+// End records err, if any, on span and ends it. It is meant to be deferred with a named error
+// return: defer func() { tracing.End(span, err) }().
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Reconciler wraps r so that every reconciliation runs inside a "Reconcile <kind>" span carrying
+// the request's namespace and name.
+func Reconciler(kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+		ctx, span := Start(ctx, "Reconcile "+kind,
+			attribute.String("k8s.namespace.name", req.Namespace),
+			attribute.String("k8s.object.name", req.Name),
+		)
+		defer func() { End(span, err) }()
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// annotationCarrier adapts object annotations to a propagation.TextMapCarrier, mapping the W3C
+// header names onto the tracing.falcosecurity.dev annotation keys.
+type annotationCarrier map[string]string
+
+var carrierKeys = map[string]string{
+	"traceparent": AnnotationTraceParent,
+	"tracestate":  AnnotationTraceState,
+}
+
+func (c annotationCarrier) Get(key string) string {
+	return c[carrierKeys[key]]
+}
+
+func (c annotationCarrier) Set(key, value string) {
+	if k, ok := carrierKeys[key]; ok {
+		c[k] = value
+	}
+}
+
+func (c annotationCarrier) Keys() []string {
+	return []string{"traceparent", "tracestate"}
+}
+
+// InjectAnnotations returns the annotations carrying the span context of ctx, or nil when ctx
+// holds no valid span context (e.g. tracing is disabled).
+func InjectAnnotations(ctx context.Context) map[string]string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil
+	}
+	carrier := annotationCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier
+}
+
+// ExtractAnnotations returns a copy of ctx whose remote span context is read from annotations.
+// The second return value is false, and ctx is returned unchanged, when the annotations do not
+// carry a valid span context.
+func ExtractAnnotations(ctx context.Context, annotations map[string]string) (context.Context, bool) {
+	if annotations[AnnotationTraceParent] == "" {
+		return ctx, false
+	}
+	remote := propagation.TraceContext{}.Extract(context.Background(), annotationCarrier(annotations))
+	sc := trace.SpanContextFromContext(remote)
+	if !sc.IsValid() {
+		return ctx, false
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc), true
+}
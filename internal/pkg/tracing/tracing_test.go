@@ -0,0 +1,140 @@
+// Copyright (C) 2026 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// installExporter routes the global tracer provider to an in-memory exporter for the test.
+func installExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "test", Options{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestSetup_InvalidSampleRatio(t *testing.T) {
+	_, err := Setup(context.Background(), "test", Options{Endpoint: "localhost:4317", SampleRatio: 2})
+	assert.Error(t, err)
+}
+
+func TestReconciler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "success", wantStatus: codes.Unset},
+		{name: "error", err: errors.New("boom"), wantStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := installExporter(t)
+
+			var inner trace.SpanContext
+			r := Reconciler("Config", reconcile.Func(func(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+				_, span := Start(ctx, "child")
+				inner = span.SpanContext()
+				span.End()
+				return ctrl.Result{}, tt.err
+			}))
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "cfg"}})
+			assert.Equal(t, tt.err, err)
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 2)
+			child, parent := spans[0], spans[1]
+			assert.Equal(t, "child", child.Name)
+			assert.Equal(t, "Reconcile Config", parent.Name)
+			assert.Equal(t, parent.SpanContext.SpanID(), child.Parent.SpanID())
+			assert.Equal(t, inner.TraceID(), parent.SpanContext.TraceID())
+			assert.Equal(t, tt.wantStatus, parent.Status.Code)
+			assert.Contains(t, parent.Attributes, attribute.String("k8s.object.name", "cfg"))
+		})
+	}
+}
+
+func TestAnnotationsRoundTrip(t *testing.T) {
+	exporter := installExporter(t)
+
+	assert.Nil(t, InjectAnnotations(context.Background()), "no span context, nothing to inject")
+
+	ctx, span := Start(context.Background(), "aggregator")
+	annotations := InjectAnnotations(ctx)
+	span.End()
+	require.Contains(t, annotations, AnnotationTraceParent)
+
+	remoteCtx, ok := ExtractAnnotations(context.Background(), annotations)
+	require.True(t, ok)
+	_, child := Start(remoteCtx, "sidecar")
+	child.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanContext.TraceID(), spans[1].SpanContext.TraceID())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), spans[1].Parent.SpanID())
+	assert.True(t, spans[1].Parent.IsRemote())
+}
+
+func TestExtractAnnotations_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{name: "nil"},
+		{name: "missing", annotations: map[string]string{"other": "value"}},
+		{name: "malformed", annotations: map[string]string{AnnotationTraceParent: "not-a-traceparent"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			got, ok := ExtractAnnotations(ctx, tt.annotations)
+			assert.False(t, ok)
+			assert.Equal(t, ctx, got)
+		})
+	}
+}